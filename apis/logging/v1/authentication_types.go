@@ -0,0 +1,179 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AuthenticationCAKey is the key of the CA certificate of an LDAP server in its secret
+	AuthenticationCAKey = "ca.crt"
+	// LDAPBindPasswordKey is the key of the password of the LDAP bind DN in its secret
+	LDAPBindPasswordKey = "password"
+	// OIDCClientSecretKey is the key of the client secret of Kibana in its secret
+	OIDCClientSecretKey = "clientSecret"
+)
+
+// KibanaLoginType is the way users log in to Kibana
+//
+// +kubebuilder:validation:Enum=OpenShift;Basic;OpenID
+type KibanaLoginType string
+
+const (
+	// KibanaLoginOpenShift logs users in with OpenShift OAuth through the proxy of Kibana
+	KibanaLoginOpenShift KibanaLoginType = "OpenShift"
+	// KibanaLoginBasic logs users in with the user name and password of the LDAP or
+	// internal users realm
+	KibanaLoginBasic KibanaLoginType = "Basic"
+	// KibanaLoginOpenID logs users in with the identity provider of the OIDC realm
+	KibanaLoginOpenID KibanaLoginType = "OpenID"
+)
+
+// ElasticsearchAuthenticationSpec configures realms of the security plugin authenticating
+// users in addition to the OpenShift tokens passed by the proxy of the cluster. The
+// backend roles of the users are mapped to the roles of the security plugin by its roles
+// mapping.
+//
+// +k8s:openapi-gen=true
+type ElasticsearchAuthenticationSpec struct {
+	// Authenticate users with the user name and password of an LDAP directory
+	//
+	// +nullable
+	// +optional
+	LDAP *LDAPRealmSpec `json:"ldap,omitempty"`
+
+	// Authenticate users with the ID tokens of an OpenID Connect identity provider
+	//
+	// +nullable
+	// +optional
+	OIDC *OIDCRealmSpec `json:"oidc,omitempty"`
+
+	// Authenticate internal users with the passwords of a secret
+	//
+	// +nullable
+	// +optional
+	InternalUsers *InternalUsersRealmSpec `json:"internalUsers,omitempty"`
+
+	// The way users log in to Kibana. Basic and OpenID replace the OpenShift login of the
+	// Kibana proxy with the login of the security plugin. Defaults to OpenShift.
+	//
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Kibana Login"
+	KibanaLogin KibanaLoginType `json:"kibanaLogin,omitempty"`
+}
+
+// LDAPRealmSpec is the specification of an LDAP directory authenticating users
+type LDAPRealmSpec struct {
+	// The LDAP servers as host:port, tried in order
+	//
+	// +kubebuilder:validation:MinItems=1
+	Hosts []string `json:"hosts"`
+
+	// Connect to the servers with TLS
+	//
+	// +optional
+	EnableSSL bool `json:"enableSSL,omitempty"`
+
+	// The secret holding the CA certificate of the servers under the key ca.crt. Defaults
+	// to the CAs trusted by the JVM.
+	//
+	// +nullable
+	// +optional
+	CASecret *corev1.LocalObjectReference `json:"caSecret,omitempty"`
+
+	// The DN binding to the directory to search the users. Binds anonymously without.
+	//
+	// +optional
+	BindDN string `json:"bindDN,omitempty"`
+
+	// The secret holding the password of the bind DN under the key password
+	//
+	// +nullable
+	// +optional
+	BindSecret *corev1.LocalObjectReference `json:"bindSecret,omitempty"`
+
+	// The base DN of the user search
+	UserBase string `json:"userBase"`
+
+	// The filter of the user search, {0} is replaced by the user name. Defaults to (uid={0}).
+	//
+	// +optional
+	UserSearch string `json:"userSearch,omitempty"`
+
+	// The attribute holding the user name. Defaults to the DN of the user.
+	//
+	// +optional
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+
+	// The base DN of the role search. The backend roles of the users are only looked up in
+	// the directory with a role base.
+	//
+	// +optional
+	RoleBase string `json:"roleBase,omitempty"`
+
+	// The filter of the role search, {0} is replaced by the DN of the user. Defaults to
+	// (member={0}).
+	//
+	// +optional
+	RoleSearch string `json:"roleSearch,omitempty"`
+
+	// The attribute of the roles used as backend role. Defaults to cn.
+	//
+	// +optional
+	RoleNameAttribute string `json:"roleNameAttribute,omitempty"`
+}
+
+// OIDCRealmSpec is the specification of an OpenID Connect identity provider authenticating
+// users
+type OIDCRealmSpec struct {
+	// The URL of the OpenID Connect discovery document of the identity provider
+	// (e.g. https://idp.example.com/.well-known/openid-configuration)
+	ConnectURL string `json:"connectURL"`
+
+	// The claim of the ID token holding the user name. Defaults to the subject.
+	//
+	// +optional
+	SubjectKey string `json:"subjectKey,omitempty"`
+
+	// The claim of the ID token holding the backend roles
+	//
+	// +optional
+	RolesKey string `json:"rolesKey,omitempty"`
+
+	// The client ID of Kibana registered with the identity provider. Required for the
+	// OpenID login of Kibana.
+	//
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// The secret holding the client secret of Kibana under the key clientSecret. It has to
+	// exist in the namespace of Kibana. Required for the OpenID login of Kibana.
+	//
+	// +nullable
+	// +optional
+	ClientSecret *corev1.LocalObjectReference `json:"clientSecret,omitempty"`
+}
+
+// InternalUsersRealmSpec is the specification of the internal users of the security plugin
+type InternalUsersRealmSpec struct {
+	// The secret holding the passwords of the internal users keyed by user name. Users
+	// removed from the secret are removed from the cluster.
+	Secret corev1.LocalObjectReference `json:"secret"`
+
+	// The backend roles of the internal users
+	//
+	// +optional
+	BackendRoles []string `json:"backendRoles,omitempty"`
+}
+
+// AuthenticationStatus is the state of the realms configured in the security plugin
+type AuthenticationStatus struct {
+	// The authentication domains configured by the operator
+	//
+	// +optional
+	Realms []string `json:"realms,omitempty"`
+
+	// The resource version of the secret of the internal users applied to the cluster
+	//
+	// +optional
+	InternalUsersSecretVersion string `json:"internalUsersSecretVersion,omitempty"`
+}
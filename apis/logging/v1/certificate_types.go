@@ -0,0 +1,129 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateKeyAlgorithm is the algorithm of the private keys of the certificates
+//
+// +kubebuilder:validation:Enum=RSA;ECDSA
+type CertificateKeyAlgorithm string
+
+const (
+	CertificateKeyRSA   CertificateKeyAlgorithm = "RSA"
+	CertificateKeyECDSA CertificateKeyAlgorithm = "ECDSA"
+)
+
+// ElasticsearchCertificatesSpec is the specification of the certificates issued by the
+// operator for the cluster and its components
+//
+// +k8s:openapi-gen=true
+type ElasticsearchCertificatesSpec struct {
+	// The lifetime of the signing CA. Defaults to 43800h (5 years).
+	//
+	// +nullable
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CA Lifetime"
+	CALifetime *metav1.Duration `json:"caLifetime,omitempty"`
+
+	// The lifetime of the certificates signed by the CA. Defaults to 17496h (2 years).
+	//
+	// +nullable
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Lifetime"
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+
+	// The algorithm of the private keys. Defaults to RSA.
+	//
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Key Algorithm"
+	KeyAlgorithm CertificateKeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// The size of the private keys, 2048, 3072 or 4096 bits for RSA and 256 (P-256) or 384
+	// (P-384) for ECDSA. Defaults to 4096 for RSA and 256 for ECDSA.
+	//
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Key Size"
+	KeySize int32 `json:"keySize,omitempty"`
+
+	// How long before their expiry the certificates are rotated (e.g. 720h). The rotation
+	// starts in the first maintenance window of this period, certificates expiring within
+	// the hour are rotated right away. Changing the key algorithm or size rotates the
+	// certificates in the next maintenance window. The rotation of the CA starts 72h
+	// earlier to roll out its phases, a CA expiring within this period is regenerated at
+	// once. Defaults to 1h.
+	//
+	// +nullable
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rotation Lead Time"
+	RotationLead *metav1.Duration `json:"rotationLead,omitempty"`
+
+	// Issue the certificates with cert-manager instead of the CA of the operator. The
+	// lifetime, key and rotation lead settings are passed on to the cert-manager
+	// certificates, which are renewed by cert-manager regardless of the maintenance windows.
+	//
+	// +nullable
+	// +optional
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+}
+
+// CertManagerSpec is the specification of the certificates issued by cert-manager
+type CertManagerSpec struct {
+	// The issuer signing the certificates
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="cert-manager Issuer"
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+// CertManagerIssuerReference is a reference to a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// The name of the issuer
+	Name string `json:"name"`
+
+	// The kind of the issuer, Issuer in the namespace of the cluster or ClusterIssuer.
+	// Defaults to Issuer.
+	//
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// The group of the issuer. Defaults to cert-manager.io.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CertificateStatus is the expiry of a certificate issued by the operator
+type CertificateStatus struct {
+	// The secret holding the certificate
+	Secret string `json:"secret"`
+	// The common name of the certificate
+	CommonName string `json:"commonName"`
+	// The expiry of the certificate
+	NotAfter metav1.Time `json:"notAfter"`
+	// The start of the rotation of the certificate
+	RotationTime metav1.Time `json:"rotationTime"`
+}
+
+// CARotationPhase is a phase of the rotation of the signing CA. Each phase is rolled out to
+// the nodes one at a time before the next one starts.
+type CARotationPhase string
+
+const (
+	// CARotationTrustingNewCA rolls out the trust of the new CA next to the current one
+	CARotationTrustingNewCA CARotationPhase = "TrustingNewCA"
+	// CARotationReissuingCertificates rolls out the certificates signed by the new CA
+	CARotationReissuingCertificates CARotationPhase = "ReissuingCertificates"
+	// CARotationRemovingOldCA rolls out the trust of only the new CA
+	CARotationRemovingOldCA CARotationPhase = "RemovingOldCA"
+)
+
+// CARotationStatus is the progress of the rotation of the signing CA
+type CARotationStatus struct {
+	// The phase being rolled out
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="CA Rotation Phase",xDescriptors="urn:alm:descriptor:text"
+	Phase CARotationPhase `json:"phase"`
+	// The start of the phase
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
@@ -89,6 +89,44 @@ type ElasticsearchSpec struct {
 	// +nullable
 	// +optional
 	IndexManagement *IndexManagementSpec `json:"indexManagement"`
+
+	// Scheduled snapshots of the cluster
+	//
+	// +nullable
+	// +optional
+	Snapshot *SnapshotPolicySpec `json:"snapshot,omitempty"`
+
+	// Remote clusters searchable from this cluster with cross-cluster search
+	//
+	// +optional
+	RemoteClusters []ElasticsearchRemoteCluster `json:"remoteClusters,omitempty"`
+
+	// Flavor of the cluster picking its image, configuration and index templates.
+	// Defaults to Elasticsearch6. An Elasticsearch6 cluster can be upgraded to
+	// Elasticsearch7 or OpenSearch and an Elasticsearch7 cluster to OpenSearch.
+	//
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Flavor"
+	Flavor ElasticsearchFlavor `json:"flavor,omitempty"`
+
+	// Maintenance windows in which the upgrades and redeployments of the nodes are started.
+	// Without windows they are started as soon as they are scheduled.
+	//
+	// +nullable
+	// +optional
+	Maintenance *ElasticsearchMaintenanceSpec `json:"maintenance,omitempty"`
+
+	// Certificates issued by the operator for the cluster and its components
+	//
+	// +nullable
+	// +optional
+	Certificates *ElasticsearchCertificatesSpec `json:"certificates,omitempty"`
+
+	// Realms of the security plugin authenticating users in addition to OpenShift
+	//
+	// +nullable
+	// +optional
+	Authentication *ElasticsearchAuthenticationSpec `json:"authentication,omitempty"`
 }
 
 // ElasticsearchStatus defines the observed state of Elasticsearch
@@ -110,6 +148,37 @@ type ElasticsearchStatus struct {
 	Conditions ClusterConditions `json:"conditions,omitempty"`
 	// +optional
 	IndexManagementStatus *IndexManagementStatus `json:"indexManagement,omitempty"`
+	// +optional
+	Snapshot *SnapshotStatus `json:"snapshot,omitempty"`
+	// +optional
+	RemoteClusters []RemoteClusterStatus `json:"remoteClusters,omitempty"`
+	// The flavor all nodes of the cluster are running
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Flavor",xDescriptors="urn:alm:descriptor:text"
+	Flavor ElasticsearchFlavor `json:"flavor,omitempty"`
+	// The start of the current or next maintenance window
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Next Maintenance Window",xDescriptors="urn:alm:descriptor:text"
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+	// The expiry of the certificates issued by the operator
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+	// The progress of the rotation of the signing CA
+	// +nullable
+	// +optional
+	CARotation *CARotationStatus `json:"caRotation,omitempty"`
+	// The state of the authentication realms of the security plugin
+	// +nullable
+	// +optional
+	Authentication *AuthenticationStatus `json:"authentication,omitempty"`
+	// The nodes removed after moving their shards off them, which stay excluded from shard
+	// allocation until they left the cluster
+	// +optional
+	RemovedNodes []string `json:"removedNodes,omitempty"`
+	// Whether the nodes are restarted one at a time to load a change of the trusted CAs only,
+	// e.g. of the CAs of the remote clusters
+	// +optional
+	TrustedCAsRollout bool `json:"trustedCAsRollout,omitempty"`
 }
 
 type ClusterHealth struct {
@@ -176,6 +245,32 @@ type ElasticsearchNode struct {
 
 	// The resource requirements for the Elasticsearch proxy
 	ProxyResources corev1.ResourceRequirements `json:"proxyResources,omitempty"`
+
+	// Additional elasticsearch.yml settings of the node (e.g. thread_pool.write.queue_size)
+	// taking precedence over the ones of the node spec. Settings owned by the operator are
+	// rejected.
+	//
+	// +optional
+	ESConfig map[string]string `json:"esConfig,omitempty"`
+
+	// Spread the nodes across zones and allocate the copies of a shard to different zones
+	//
+	// +optional
+	ZoneAwareness *ZoneAwarenessSpec `json:"zoneAwareness,omitempty"`
+
+	// The data tier of the node rendered as the tier node attribute. New indices are
+	// allocated to the hot tier if the cluster has hot data nodes.
+	//
+	// +optional
+	Tier ElasticsearchNodeTier `json:"tier,omitempty"`
+}
+
+// ZoneAwarenessSpec defines how the zone of an Elasticsearch node is determined
+type ZoneAwarenessSpec struct {
+	// The label of the Kubernetes nodes holding their zone. Defaults to topology.kubernetes.io/zone
+	//
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
 }
 
 // ElasticsearchNodeSpec represents configuration of an individual Elasticsearch node
@@ -204,6 +299,12 @@ type ElasticsearchNodeSpec struct {
 	// +nullable
 	// +optional
 	ProxyResources corev1.ResourceRequirements `json:"proxyResources,omitempty"`
+
+	// Additional elasticsearch.yml settings of all nodes (e.g. indices.memory.index_buffer_size).
+	// Settings owned by the operator are rejected.
+	//
+	// +optional
+	ESConfig map[string]string `json:"esConfig,omitempty"`
 }
 
 type ElasticsearchStorageSpec struct {
@@ -214,6 +315,12 @@ type ElasticsearchStorageSpec struct {
 
 	// The max storage capacity for the node to provision.
 	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Migrate the nodes to a changed storage class name by replacing them one at a time
+	// and moving their shards to the replacements. Only supported by data nodes without
+	// the master role.
+	// +optional
+	AllowStorageClassMigration bool `json:"allowStorageClassMigration,omitempty"`
 }
 
 // ElasticsearchNodeStatus represents the status of individual Elasticsearch node
@@ -230,8 +337,77 @@ type ElasticsearchNodeStatus struct {
 	Roles []ElasticsearchNodeRole `json:"roles,omitempty"`
 	// +optional
 	Conditions ClusterConditions `json:"conditions,omitempty"`
+	// The progress of the expansion of the volume of the node
+	//
+	// +optional
+	VolumeExpansion *ElasticsearchVolumeExpansionStatus `json:"volumeExpansion,omitempty"`
+	// The progress of the migration of the node to a new storage class
+	//
+	// +optional
+	StorageMigration *ElasticsearchStorageMigrationStatus `json:"storageMigration,omitempty"`
+}
+
+// ElasticsearchVolumeExpansionStatus represents the progress of the expansion of the
+// persistent volume of an Elasticsearch node
+type ElasticsearchVolumeExpansionStatus struct {
+	// The phase of the expansion
+	Phase ElasticsearchVolumeExpansionPhase `json:"phase"`
+	// The size the volume is expanded to
+	Size resource.Quantity `json:"size"`
+	// The capacity of the volume reported by its claim
+	//
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// Human-readable message about the phase
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+	// The time the node was restarted to resize the file system of the volume
+	//
+	// +optional
+	RestartTime *metav1.Time `json:"restartTime,omitempty"`
 }
 
+// ElasticsearchVolumeExpansionPhase is the phase of the expansion of the volume of a node
+type ElasticsearchVolumeExpansionPhase string
+
+const (
+	// VolumeExpansionPending waits for the volumes of the other nodes to be expanded
+	VolumeExpansionPending ElasticsearchVolumeExpansionPhase = "Pending"
+	// VolumeExpansionResizing waits for the volume to be resized by its provisioner
+	VolumeExpansionResizing ElasticsearchVolumeExpansionPhase = "Resizing"
+	// VolumeExpansionFileSystemResizePending waits for the file system of the volume to be resized
+	VolumeExpansionFileSystemResizePending ElasticsearchVolumeExpansionPhase = "FileSystemResizePending"
+	// VolumeExpansionUnsupported reports a storage class which does not allow volume expansion
+	VolumeExpansionUnsupported ElasticsearchVolumeExpansionPhase = "Unsupported"
+)
+
+// ElasticsearchStorageMigrationStatus represents the progress of the migration of an
+// Elasticsearch node to a new storage class
+type ElasticsearchStorageMigrationStatus struct {
+	// The phase of the migration
+	Phase ElasticsearchStorageMigrationPhase `json:"phase"`
+	// The storage class name the node is migrated to
+	StorageClassName string `json:"storageClassName"`
+	// The name of the node replacing this node
+	Replacement string `json:"replacement"`
+	// The number of shards left on the node
+	//
+	// +optional
+	Shards int32 `json:"shards,omitempty"`
+}
+
+// ElasticsearchStorageMigrationPhase is the phase of the migration of a node to a new
+// storage class
+type ElasticsearchStorageMigrationPhase string
+
+const (
+	// StorageMigrationPending waits for the replacement of the node to join the cluster
+	StorageMigrationPending ElasticsearchStorageMigrationPhase = "Pending"
+	// StorageMigrationEvacuating waits for the shards to be moved off the node
+	StorageMigrationEvacuating ElasticsearchStorageMigrationPhase = "Evacuating"
+)
+
 type ElasticsearchNodeUpgradeStatus struct {
 	ScheduledForUpgrade      corev1.ConditionStatus    `json:"scheduledUpgrade,omitempty"`
 	ScheduledForRedeploy     corev1.ConditionStatus    `json:"scheduledRedeploy,omitempty"`
@@ -269,13 +445,36 @@ const (
 	ZeroRedundancy RedundancyPolicyType = "ZeroRedundancy"
 )
 
-// +kubebuilder:validation:Enum:=master;client;data
+// +kubebuilder:validation:Enum:=master;client;data;ingest;coordinating
 type ElasticsearchNodeRole string
 
 const (
 	ElasticsearchRoleClient ElasticsearchNodeRole = "client"
 	ElasticsearchRoleData   ElasticsearchNodeRole = "data"
 	ElasticsearchRoleMaster ElasticsearchNodeRole = "master"
+	ElasticsearchRoleIngest ElasticsearchNodeRole = "ingest"
+	// ElasticsearchRoleCoordinating is the role of coordinating-only nodes serving the
+	// REST API without holding data, being master eligible or running ingest pipelines.
+	// It cannot be combined with other roles.
+	ElasticsearchRoleCoordinating ElasticsearchNodeRole = "coordinating"
+)
+
+// +kubebuilder:validation:Enum:=hot;warm;cold
+type ElasticsearchNodeTier string
+
+const (
+	ElasticsearchTierHot  ElasticsearchNodeTier = "hot"
+	ElasticsearchTierWarm ElasticsearchNodeTier = "warm"
+	ElasticsearchTierCold ElasticsearchNodeTier = "cold"
+)
+
+// +kubebuilder:validation:Enum:=Elasticsearch6;Elasticsearch7;OpenSearch
+type ElasticsearchFlavor string
+
+const (
+	ElasticsearchFlavor6 ElasticsearchFlavor = "Elasticsearch6"
+	ElasticsearchFlavor7 ElasticsearchFlavor = "Elasticsearch7"
+	OpenSearchFlavor     ElasticsearchFlavor = "OpenSearch"
 )
 
 type ShardAllocationState string
@@ -343,4 +542,11 @@ const (
 	StorageClassName         ClusterConditionType = "StorageClassNameChangeIgnored"
 	StorageSize              ClusterConditionType = "StorageSizeChangeIgnored"
 	StorageStructure         ClusterConditionType = "StorageStructureChangeIgnored"
+	RemoteClusterConnected   ClusterConditionType = "RemoteClusterConnected"
+	InvalidESConfig          ClusterConditionType = "InvalidESConfig"
+	ZoneAwarenessUnsatisfied ClusterConditionType = "ZoneAwarenessUnsatisfied"
+	InvalidRoles             ClusterConditionType = "InvalidRoles"
+	ElasticsearchUnavailable ClusterConditionType = "ElasticsearchUnavailable"
+	FlavorUpgrade            ClusterConditionType = "FlavorUpgrade"
+	CertManagementDisabled   ClusterConditionType = "CertManagementDisabled"
 )
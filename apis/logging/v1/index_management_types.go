@@ -24,6 +24,11 @@ type IndexManagementSpec struct {
 // +kubebuilder:validation:Pattern:="^([0-9]+)([wdhHms]{0,1})$"
 type TimeUnit string
 
+// ByteSize is a size with a unit like b, kb, mb, gb, tb, pb (e.g. 50gb)
+//
+// +kubebuilder:validation:Pattern:="^([0-9]+)(b|kb|mb|gb|tb|pb)$"
+type ByteSize string
+
 // IndexManagementPolicySpec is a definition of an index management policy
 // +k8s:openapi-gen=true
 type IndexManagementPolicySpec struct {
@@ -41,6 +46,10 @@ type IndexManagementPhasesSpec struct {
 	// +nullable
 	Hot *IndexManagementHotPhaseSpec `json:"hot,omitempty"`
 	// +nullable
+	Warm *IndexManagementTierPhaseSpec `json:"warm,omitempty"`
+	// +nullable
+	Cold *IndexManagementTierPhaseSpec `json:"cold,omitempty"`
+	// +nullable
 	Delete *IndexManagementDeletePhaseSpec `json:"delete,omitempty"`
 }
 
@@ -89,6 +98,85 @@ type IndexManagementActionsSpec struct {
 type IndexManagementActionSpec struct {
 	// The maximum age of an index before it should be rolled over (e.g. 7d)
 	MaxAge TimeUnit `json:"maxAge"`
+
+	// The maximum size of all primary shards of an index before it should be rolled over (e.g. 150gb)
+	// +optional
+	MaxSize ByteSize `json:"maxSize,omitempty"`
+
+	// The maximum number of documents of an index before it should be rolled over
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxDocs int64 `json:"maxDocs,omitempty"`
+
+	// The maximum size of a single primary shard of an index before it should be rolled over (e.g. 50gb)
+	// +optional
+	MaxPrimaryShardSize ByteSize `json:"maxPrimaryShardSize,omitempty"`
+}
+
+// IndexManagementTierPhaseSpec is a phase (e.g. warm, cold) an index enters
+// after it was rolled over and before it is deleted
+// +k8s:openapi-gen=true
+type IndexManagementTierPhaseSpec struct {
+	// The minimum age of an index before it should enter this phase (e.g. 3d)
+	MinAge TimeUnit `json:"minAge"`
+
+	// +optional
+	Actions IndexManagementTierActionsSpec `json:"actions"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementTierActionsSpec struct {
+	// Force merge the index to the given number of segments per shard
+	// +nullable
+	// +optional
+	ForceMerge *IndexManagementForceMergeActionSpec `json:"forcemerge,omitempty"`
+
+	// Shrink the index to the given number of primary shards
+	// +nullable
+	// +optional
+	Shrink *IndexManagementShrinkActionSpec `json:"shrink,omitempty"`
+
+	// Set the number of replicas of the index
+	// +nullable
+	// +optional
+	Replicas *IndexManagementReplicasActionSpec `json:"replicas,omitempty"`
+
+	// Block writes to the index
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Move the index to nodes with the given node attributes
+	// +nullable
+	// +optional
+	Allocate *IndexManagementAllocateActionSpec `json:"allocate,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementForceMergeActionSpec struct {
+	// The number of segments to merge each shard into (e.g. 1)
+	// +kubebuilder:validation:Minimum=1
+	MaxNumSegments int32 `json:"maxNumSegments"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementShrinkActionSpec struct {
+	// The number of primary shards of the shrunken index. Must be a factor of the
+	// number of primary shards of the source index
+	// +kubebuilder:validation:Minimum=1
+	NumberOfShards int32 `json:"numberOfShards"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementReplicasActionSpec struct {
+	// The number of replicas of the index
+	// +kubebuilder:validation:Minimum=0
+	NumberOfReplicas int32 `json:"numberOfReplicas"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementAllocateActionSpec struct {
+	// Node attributes an index must be allocated to (e.g. box_type: warm)
+	Require map[string]string `json:"require"`
 }
 
 // IndexManagementPolicyMappingSpec maps a management policy to an index
@@ -176,10 +264,38 @@ type IndexManagementMappingStatus struct {
 	// Reasons for the state of the corresponding mapping for this status
 	Conditions []IndexManagementMappingCondition `json:"conditions,omitempty"`
 
+	// Results of the last index management jobs for each alias of the mapping
+	Aliases []IndexManagementAliasStatus `json:"aliases,omitempty"`
+
+	// LastError is the error of the last index management job if it failed
+	LastError string `json:"lastError,omitempty"`
+
 	// LastUpdated represents the last time that the status was updated.
 	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
 }
 
+// IndexManagementAliasStatus is the state of an alias (e.g. app for app-write) as reported
+// by the index management jobs
+type IndexManagementAliasStatus struct {
+	// Name of the alias
+	Name string `json:"name"`
+
+	// WriteIndex is the index currently receiving the writes of the alias
+	WriteIndex string `json:"writeIndex,omitempty"`
+
+	// IndexCount is the number of indices belonging to the alias
+	IndexCount int32 `json:"indexCount,omitempty"`
+
+	// OldestIndexAge is the age of the oldest index of the alias
+	OldestIndexAge *metav1.Duration `json:"oldestIndexAge,omitempty"`
+
+	// LastRolloverTime is the last time the write index was rolled over
+	LastRolloverTime *metav1.Time `json:"lastRolloverTime,omitempty"`
+
+	// LastDeleteTime is the last time indices of the alias were deleted
+	LastDeleteTime *metav1.Time `json:"lastDeleteTime,omitempty"`
+}
+
 func NewIndexManagementMappingStatus(name string) *IndexManagementMappingStatus {
 	return &IndexManagementMappingStatus{
 		Name:        name,
@@ -198,6 +314,22 @@ func (status *IndexManagementMappingStatus) AddPolicyMappingCondition(conditionT
 	})
 }
 
+// SetFailingCondition marks the index management jobs of the mapping as failing
+func (status *IndexManagementMappingStatus) SetFailingCondition(message string) {
+	for i, condition := range status.Conditions {
+		if condition.Type == IndexManagementMappingConditionTypeFailing {
+			status.Conditions[i].Message = message
+			return
+		}
+	}
+	status.Conditions = append(status.Conditions, IndexManagementMappingCondition{
+		Type:    IndexManagementMappingConditionTypeFailing,
+		Reason:  IndexManagementMappingReasonJobsFailing,
+		Status:  corev1.ConditionTrue,
+		Message: message,
+	})
+}
+
 type IndexManagementMappingState string
 
 const (
@@ -227,13 +359,17 @@ type IndexManagementMappingConditionType string
 const (
 	IndexManagementMappingConditionTypeName      IndexManagementMappingConditionType = "Name"
 	IndexManagementMappingConditionTypePolicyRef IndexManagementMappingConditionType = "PolicyRef"
+
+	// IndexManagementMappingConditionTypeFailing when the index management jobs of the mapping fail repeatedly
+	IndexManagementMappingConditionTypeFailing IndexManagementMappingConditionType = "IndexManagementFailing"
 )
 
 type IndexManagementMappingConditionReason string
 
 const (
-	IndexManagementMappingReasonMissing   IndexManagementMappingConditionReason = "Missing"
-	IndexManagementMappingReasonNonUnique IndexManagementMappingConditionReason = "NonUnique"
+	IndexManagementMappingReasonMissing     IndexManagementMappingConditionReason = "Missing"
+	IndexManagementMappingReasonNonUnique   IndexManagementMappingConditionReason = "NonUnique"
+	IndexManagementMappingReasonJobsFailing IndexManagementMappingConditionReason = "JobsFailing"
 )
 
 type IndexManagementPolicyStatus struct {
@@ -252,6 +388,12 @@ type IndexManagementPolicyStatus struct {
 	// Reasons for the state of the corresponding policy for this status
 	Conditions []IndexManagementPolicyCondition `json:"conditions,omitempty"`
 
+	// Status of each phase defined by the corresponding policy
+	Phases []IndexManagementPhaseStatus `json:"phases,omitempty"`
+
+	// The effective conditions an index is rolled over for by the corresponding policy
+	Rollover *IndexManagementRolloverConditionsStatus `json:"rollover,omitempty"`
+
 	// LastUpdated represents the last time that the status was updated.
 	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
 }
@@ -274,6 +416,48 @@ func (status *IndexManagementPolicyStatus) AddPolicyCondition(conditionType Inde
 	})
 }
 
+func (status *IndexManagementPolicyStatus) AddPhase(name IndexManagementPhaseName, state IndexManagementPolicyState, message string) {
+	status.Phases = append(status.Phases, IndexManagementPhaseStatus{
+		Name:    name,
+		State:   state,
+		Message: message,
+	})
+}
+
+type IndexManagementPhaseStatus struct {
+	// Name of the phase (e.g. hot, warm, cold, delete)
+	Name IndexManagementPhaseName `json:"name,omitempty"`
+
+	// State of the phase, Dropped only by the conditions of the phase. The state of the
+	// policy tells whether the policy is applied.
+	State IndexManagementPolicyState `json:"state,omitempty"`
+
+	// Message about the phase
+	Message string `json:"message,omitempty"`
+}
+
+// IndexManagementRolloverConditionsStatus are the conditions calculated from the rollover
+// action and the number of primary shards
+type IndexManagementRolloverConditionsStatus struct {
+	// The maximum age of an index before it is rolled over
+	MaxAge TimeUnit `json:"maxAge,omitempty"`
+
+	// The maximum size of all primary shards of an index before it is rolled over
+	MaxSize ByteSize `json:"maxSize,omitempty"`
+
+	// The maximum number of documents of an index before it is rolled over
+	MaxDocs int64 `json:"maxDocs,omitempty"`
+}
+
+type IndexManagementPhaseName string
+
+const (
+	IndexManagementPhaseHot    IndexManagementPhaseName = "hot"
+	IndexManagementPhaseWarm   IndexManagementPhaseName = "warm"
+	IndexManagementPhaseCold   IndexManagementPhaseName = "cold"
+	IndexManagementPhaseDelete IndexManagementPhaseName = "delete"
+)
+
 type IndexManagementPolicyState string
 
 const (
@@ -304,6 +488,8 @@ const (
 	IndexManagementPolicyConditionTypeName         IndexManagementPolicyConditionType = "Name"
 	IndexManagementPolicyConditionTypePollInterval IndexManagementPolicyConditionType = "PollInterval"
 	IndexManagementPolicyConditionTypeTimeUnit     IndexManagementPolicyConditionType = "TimeUnit"
+	IndexManagementPolicyConditionTypePhaseOrder   IndexManagementPolicyConditionType = "PhaseOrder"
+	IndexManagementPolicyConditionTypeRollover     IndexManagementPolicyConditionType = "RolloverConditions"
 )
 
 type IndexManagementPolicyConditionReason string
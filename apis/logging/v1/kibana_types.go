@@ -35,6 +35,23 @@ type KibanaSpec struct {
 	//
 	// +optional
 	ProxySpec `json:"proxy,omitempty"`
+
+	// Reference to the Elasticsearch cluster used by Kibana. Without it Kibana uses
+	// the Elasticsearch cluster found in its namespace.
+	//
+	// +optional
+	ElasticsearchRef *ElasticsearchReference `json:"elasticsearchRef,omitempty"`
+}
+
+// ElasticsearchReference refers to an Elasticsearch cluster by name
+type ElasticsearchReference struct {
+	// Name of the Elasticsearch cluster
+	Name string `json:"name"`
+
+	// Namespace of the Elasticsearch cluster. Defaults to the namespace of the Kibana.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type ProxySpec struct {
@@ -60,6 +77,9 @@ type KibanaStatus struct {
 	Pods PodStateMap `json:"pods,omitempty"`
 	// +optional
 	Conditions map[string]ClusterConditions `json:"clusterCondition,omitempty"`
+	// The conditions of the Elasticsearch cluster used by Kibana
+	// +optional
+	ElasticsearchConditions ClusterConditions `json:"elasticsearchConditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
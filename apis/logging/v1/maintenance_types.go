@@ -0,0 +1,34 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceOverrideAnnotation starts the upgrades and redeployments of the nodes outside
+// of the maintenance windows while set to "true"
+const MaintenanceOverrideAnnotation = "logging.openshift.io/maintenance-override"
+
+// ElasticsearchMaintenanceSpec is the specification of the maintenance windows of the cluster
+//
+// +k8s:openapi-gen=true
+type ElasticsearchMaintenanceSpec struct {
+	// The windows in which the upgrades and redeployments of the nodes are started. A node
+	// restart started in a window is completed after the window closed. Certificate
+	// redeployments are not deferred to the windows to keep the nodes trusting each other.
+	//
+	// +optional
+	Windows []MaintenanceWindowSpec `json:"windows,omitempty"`
+}
+
+// MaintenanceWindowSpec is a recurring maintenance window
+type MaintenanceWindowSpec struct {
+	// The start of the window in cron format in UTC (e.g. 0 2 * * 6)
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Window Schedule"
+	Schedule string `json:"schedule"`
+
+	// The length of the window (e.g. 4h)
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Window Duration"
+	Duration metav1.Duration `json:"duration"`
+}
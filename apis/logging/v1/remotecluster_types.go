@@ -0,0 +1,50 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RemoteClusterCAKey is the key of the CA certificate in the secret of a remote cluster
+const RemoteClusterCAKey = "ca.crt"
+
+// ElasticsearchRemoteCluster is a remote cluster searchable from this cluster with
+// cross-cluster search
+//
+// +k8s:openapi-gen=true
+type ElasticsearchRemoteCluster struct {
+	// The name of the remote cluster used to prefix its indices in searches (e.g. remote:app-*)
+	//
+	// +kubebuilder:validation:Pattern:="^[a-z0-9]([-_a-z0-9]*[a-z0-9])?$"
+	Name string `json:"name"`
+
+	// The transport addresses of the remote nodes to connect to, either the service DNS name
+	// (e.g. elasticsearch-cluster.other-namespace.svc:9300) or an external address. The
+	// port defaults to 9300.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Seeds []string `json:"seeds"`
+
+	// Ignore the remote cluster in searches while it is disconnected
+	//
+	// +optional
+	SkipUnavailable bool `json:"skipUnavailable,omitempty"`
+
+	// The secret holding the CA certificate of the remote cluster under the key ca.crt. The
+	// CA is added to the trusted CAs of this cluster which restarts the cluster. The remote
+	// cluster must in turn trust the CA of this cluster.
+	//
+	// +nullable
+	// +optional
+	CASecret *corev1.LocalObjectReference `json:"caSecret,omitempty"`
+}
+
+// RemoteClusterStatus is the connection state of a remote cluster
+type RemoteClusterStatus struct {
+	Name string `json:"name"`
+	// +optional
+	Seeds []string `json:"seeds,omitempty"`
+	// +optional
+	NumNodesConnected int32 `json:"numNodesConnected,omitempty"`
+	// +optional
+	Conditions ClusterConditions `json:"conditions,omitempty"`
+}
@@ -0,0 +1,119 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchRestoreSpec defines the indices restored from a snapshot
+//
+// +k8s:openapi-gen=true
+type ElasticsearchRestoreSpec struct {
+	// The name of the Elasticsearch cluster in the same namespace to restore into. The
+	// snapshot repository of its snapshot policy is used.
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Elasticsearch Cluster"
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The name of the snapshot to restore. Defaults to the last successful snapshot.
+	//
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// The index patterns to restore. Defaults to all indices of the snapshot.
+	//
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// A regular expression matching the names of the restored indices to rename, e.g. (.+)
+	//
+	// +optional
+	RenamePattern string `json:"renamePattern,omitempty"`
+
+	// The replacement of the names matched by the rename pattern, e.g. restored-$1
+	//
+	// +optional
+	RenameReplacement string `json:"renameReplacement,omitempty"`
+
+	// Restore the aliases of the indices, disabled by default to not add the restored
+	// indices to the aliases written to by the collectors
+	//
+	// +optional
+	IncludeAliases bool `json:"includeAliases,omitempty"`
+}
+
+// ElasticsearchRestorePhase is the progress of a restore
+type ElasticsearchRestorePhase string
+
+const (
+	// ElasticsearchRestorePhasePending when the restore was not started yet
+	ElasticsearchRestorePhasePending ElasticsearchRestorePhase = "Pending"
+	// ElasticsearchRestorePhaseInProgress when the shards of the indices are recovered from the snapshot
+	ElasticsearchRestorePhaseInProgress ElasticsearchRestorePhase = "InProgress"
+	// ElasticsearchRestorePhaseSucceeded when all shards of the indices are recovered
+	ElasticsearchRestorePhaseSucceeded ElasticsearchRestorePhase = "Succeeded"
+	// ElasticsearchRestorePhaseFailed when the restore was rejected by the cluster
+	ElasticsearchRestorePhaseFailed ElasticsearchRestorePhase = "Failed"
+)
+
+// ElasticsearchRestoreStatus defines the observed state of ElasticsearchRestore
+//
+// +k8s:openapi-gen=true
+type ElasticsearchRestoreStatus struct {
+	// The progress of the restore
+	//
+	// +optional
+	Phase ElasticsearchRestorePhase `json:"phase,omitempty"`
+
+	// The name of the restored snapshot
+	//
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// The time the restore was started
+	//
+	// +nullable
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time the restore completed or failed
+	//
+	// +nullable
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Details about the phase
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories=logging,shortName=esrestore
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="Snapshot",JSONPath=".status.snapshot",type=string
+// +kubebuilder:printcolumn:name="Phase",JSONPath=".status.phase",type=string
+//
+// A restore of indices from a snapshot of an Elasticsearch cluster
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch Restore"
+type ElasticsearchRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRestoreSpec   `json:"spec,omitempty"`
+	Status ElasticsearchRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchRestoreList contains a list of ElasticsearchRestore
+type ElasticsearchRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRestore{}, &ElasticsearchRestoreList{})
+}
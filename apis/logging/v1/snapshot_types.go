@@ -0,0 +1,138 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotPolicySpec is the specification of the scheduled snapshots of the cluster
+//
+// +k8s:openapi-gen=true
+type SnapshotPolicySpec struct {
+	// The repository the snapshots are stored in
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Snapshot Repository"
+	Repository SnapshotRepositorySpec `json:"repository"`
+
+	// The schedule of the snapshots in cron format (e.g. 0 1 * * *)
+	//
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Snapshot Schedule"
+	Schedule string `json:"schedule"`
+
+	// The index patterns included in the snapshots. Defaults to all indices.
+	//
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// The retention of the snapshots taken by the schedule
+	//
+	// +nullable
+	// +optional
+	Retention *SnapshotRetentionSpec `json:"retention,omitempty"`
+}
+
+// SnapshotRepositoryType is the type of a snapshot repository
+//
+// +kubebuilder:validation:Enum=fs;s3
+type SnapshotRepositoryType string
+
+const (
+	// SnapshotRepositoryTypeFS stores the snapshots on a shared filesystem
+	SnapshotRepositoryTypeFS SnapshotRepositoryType = "fs"
+	// SnapshotRepositoryTypeS3 stores the snapshots in a S3 compatible object storage
+	SnapshotRepositoryTypeS3 SnapshotRepositoryType = "s3"
+)
+
+// SnapshotRepositorySpec is the specification of the repository registered in the cluster
+//
+// +k8s:openapi-gen=true
+type SnapshotRepositorySpec struct {
+	// The name the repository is registered with in the cluster
+	Name string `json:"name"`
+
+	// The type of the repository
+	Type SnapshotRepositoryType `json:"type"`
+
+	// The filesystem repository settings, required for type fs
+	//
+	// +nullable
+	// +optional
+	FS *SnapshotFSRepositorySpec `json:"fs,omitempty"`
+
+	// The S3 repository settings, required for type s3
+	//
+	// +nullable
+	// +optional
+	S3 *SnapshotS3RepositorySpec `json:"s3,omitempty"`
+}
+
+// SnapshotFSRepositorySpec is a repository on a persistent volume claim mounted to every
+// Elasticsearch node. The claim must support the ReadWriteMany access mode.
+type SnapshotFSRepositorySpec struct {
+	// The name of the persistent volume claim in the namespace of the cluster
+	ClaimName string `json:"claimName"`
+}
+
+// SnapshotS3RepositorySpec is a repository in a bucket of a S3 compatible object storage
+type SnapshotS3RepositorySpec struct {
+	// The name of the bucket
+	Bucket string `json:"bucket"`
+
+	// The endpoint of a S3 compatible object storage. Defaults to AWS S3.
+	//
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// The path within the bucket the snapshots are stored at
+	//
+	// +optional
+	BasePath string `json:"basePath,omitempty"`
+
+	// Use path style access (e.g. https://endpoint/bucket) instead of virtual hosted buckets
+	//
+	// +optional
+	PathStyleAccess bool `json:"pathStyleAccess,omitempty"`
+
+	// The secret holding the access_key_id and secret_access_key of the bucket
+	Secret corev1.LocalObjectReference `json:"secret"`
+}
+
+// SnapshotRetentionSpec defines which snapshots taken by the schedule are pruned.
+// The last successful snapshot is always kept.
+type SnapshotRetentionSpec struct {
+	// The maximum number of snapshots to keep
+	//
+	// +optional
+	MaxCount int32 `json:"maxCount,omitempty"`
+
+	// The maximum age of the snapshots to keep
+	//
+	// +optional
+	MaxAge TimeUnit `json:"maxAge,omitempty"`
+}
+
+// SnapshotStatus is the state of the scheduled snapshots
+//
+// +k8s:openapi-gen=true
+type SnapshotStatus struct {
+	// The name of the registered repository
+	//
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// The name of the last successful snapshot
+	//
+	// +optional
+	LastSuccessfulSnapshot string `json:"lastSuccessfulSnapshot,omitempty"`
+
+	// The time the last successful snapshot completed
+	//
+	// +nullable
+	// +optional
+	LastSuccessfulSnapshotTime *metav1.Time `json:"lastSuccessfulSnapshotTime,omitempty"`
+
+	// The reason the snapshots are failing if any
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
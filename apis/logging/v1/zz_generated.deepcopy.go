@@ -7,9 +7,94 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthenticationStatus) DeepCopyInto(out *AuthenticationStatus) {
+	*out = *in
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationStatus.
+func (in *AuthenticationStatus) DeepCopy() *AuthenticationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthenticationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationStatus) DeepCopyInto(out *CARotationStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationStatus.
+func (in *CARotationStatus) DeepCopy() *CARotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	in.RotationTime.DeepCopyInto(&out.RotationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -89,6 +174,71 @@ func (in *Elasticsearch) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchAuthenticationSpec) DeepCopyInto(out *ElasticsearchAuthenticationSpec) {
+	*out = *in
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPRealmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCRealmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternalUsers != nil {
+		in, out := &in.InternalUsers, &out.InternalUsers
+		*out = new(InternalUsersRealmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAuthenticationSpec.
+func (in *ElasticsearchAuthenticationSpec) DeepCopy() *ElasticsearchAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchCertificatesSpec) DeepCopyInto(out *ElasticsearchCertificatesSpec) {
+	*out = *in
+	if in.CALifetime != nil {
+		in, out := &in.CALifetime, &out.CALifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RotationLead != nil {
+		in, out := &in.RotationLead, &out.RotationLead
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchCertificatesSpec.
+func (in *ElasticsearchCertificatesSpec) DeepCopy() *ElasticsearchCertificatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchCertificatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchList) DeepCopyInto(out *ElasticsearchList) {
 	*out = *in
@@ -121,6 +271,26 @@ func (in *ElasticsearchList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchMaintenanceSpec) DeepCopyInto(out *ElasticsearchMaintenanceSpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MaintenanceWindowSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchMaintenanceSpec.
+func (in *ElasticsearchMaintenanceSpec) DeepCopy() *ElasticsearchMaintenanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchMaintenanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
 	*out = *in
@@ -151,6 +321,18 @@ func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
 		**out = **in
 	}
 	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+	if in.ESConfig != nil {
+		in, out := &in.ESConfig, &out.ESConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwarenessSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNode.
@@ -182,6 +364,13 @@ func (in *ElasticsearchNodeSpec) DeepCopyInto(out *ElasticsearchNodeSpec) {
 		}
 	}
 	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+	if in.ESConfig != nil {
+		in, out := &in.ESConfig, &out.ESConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeSpec.
@@ -210,6 +399,16 @@ func (in *ElasticsearchNodeStatus) DeepCopyInto(out *ElasticsearchNodeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VolumeExpansion != nil {
+		in, out := &in.VolumeExpansion, &out.VolumeExpansion
+		*out = new(ElasticsearchVolumeExpansionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageMigration != nil {
+		in, out := &in.StorageMigration, &out.StorageMigration
+		*out = new(ElasticsearchStorageMigrationStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeStatus.
@@ -237,6 +436,148 @@ func (in *ElasticsearchNodeUpgradeStatus) DeepCopy() *ElasticsearchNodeUpgradeSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReference) DeepCopyInto(out *ElasticsearchReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReference.
+func (in *ElasticsearchReference) DeepCopy() *ElasticsearchReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRemoteCluster) DeepCopyInto(out *ElasticsearchRemoteCluster) {
+	*out = *in
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CASecret != nil {
+		in, out := &in.CASecret, &out.CASecret
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRemoteCluster.
+func (in *ElasticsearchRemoteCluster) DeepCopy() *ElasticsearchRemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestore) DeepCopyInto(out *ElasticsearchRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestore.
+func (in *ElasticsearchRestore) DeepCopy() *ElasticsearchRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestoreList) DeepCopyInto(out *ElasticsearchRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestoreList.
+func (in *ElasticsearchRestoreList) DeepCopy() *ElasticsearchRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestoreSpec) DeepCopyInto(out *ElasticsearchRestoreSpec) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestoreSpec.
+func (in *ElasticsearchRestoreSpec) DeepCopy() *ElasticsearchRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestoreStatus) DeepCopyInto(out *ElasticsearchRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestoreStatus.
+func (in *ElasticsearchRestoreStatus) DeepCopy() *ElasticsearchRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 	*out = *in
@@ -253,6 +594,33 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		*out = new(IndexManagementSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(SnapshotPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]ElasticsearchRemoteCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(ElasticsearchMaintenanceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(ElasticsearchCertificatesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(ElasticsearchAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSpec.
@@ -313,6 +681,44 @@ func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
 		*out = new(IndexManagementStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(SnapshotStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(AuthenticationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemovedNodes != nil {
+		in, out := &in.RemovedNodes, &out.RemovedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
@@ -325,6 +731,21 @@ func (in *ElasticsearchStatus) DeepCopy() *ElasticsearchStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchStorageMigrationStatus) DeepCopyInto(out *ElasticsearchStorageMigrationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStorageMigrationStatus.
+func (in *ElasticsearchStorageMigrationStatus) DeepCopy() *ElasticsearchStorageMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchStorageMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchStorageSpec) DeepCopyInto(out *ElasticsearchStorageSpec) {
 	*out = *in
@@ -350,6 +771,31 @@ func (in *ElasticsearchStorageSpec) DeepCopy() *ElasticsearchStorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchVolumeExpansionStatus) DeepCopyInto(out *ElasticsearchVolumeExpansionStatus) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RestartTime != nil {
+		in, out := &in.RestartTime, &out.RestartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchVolumeExpansionStatus.
+func (in *ElasticsearchVolumeExpansionStatus) DeepCopy() *ElasticsearchVolumeExpansionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchVolumeExpansionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementActionSpec) DeepCopyInto(out *IndexManagementActionSpec) {
 	*out = *in
@@ -385,6 +831,56 @@ func (in *IndexManagementActionsSpec) DeepCopy() *IndexManagementActionsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementAliasStatus) DeepCopyInto(out *IndexManagementAliasStatus) {
+	*out = *in
+	if in.OldestIndexAge != nil {
+		in, out := &in.OldestIndexAge, &out.OldestIndexAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LastRolloverTime != nil {
+		in, out := &in.LastRolloverTime, &out.LastRolloverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDeleteTime != nil {
+		in, out := &in.LastDeleteTime, &out.LastDeleteTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementAliasStatus.
+func (in *IndexManagementAliasStatus) DeepCopy() *IndexManagementAliasStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementAliasStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementAllocateActionSpec) DeepCopyInto(out *IndexManagementAllocateActionSpec) {
+	*out = *in
+	if in.Require != nil {
+		in, out := &in.Require, &out.Require
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementAllocateActionSpec.
+func (in *IndexManagementAllocateActionSpec) DeepCopy() *IndexManagementAllocateActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementAllocateActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementDeleteNamespaceSpec) DeepCopyInto(out *IndexManagementDeleteNamespaceSpec) {
 	*out = *in
@@ -420,6 +916,21 @@ func (in *IndexManagementDeletePhaseSpec) DeepCopy() *IndexManagementDeletePhase
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementForceMergeActionSpec) DeepCopyInto(out *IndexManagementForceMergeActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementForceMergeActionSpec.
+func (in *IndexManagementForceMergeActionSpec) DeepCopy() *IndexManagementForceMergeActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementForceMergeActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementHotPhaseSpec) DeepCopyInto(out *IndexManagementHotPhaseSpec) {
 	*out = *in
@@ -452,22 +963,44 @@ func (in *IndexManagementMappingCondition) DeepCopy() *IndexManagementMappingCon
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementMappingStatus) DeepCopyInto(out *IndexManagementMappingStatus) {
+func (in *IndexManagementMappingStatus) DeepCopyInto(out *IndexManagementMappingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]IndexManagementMappingCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]IndexManagementAliasStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementMappingStatus.
+func (in *IndexManagementMappingStatus) DeepCopy() *IndexManagementMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPhaseStatus) DeepCopyInto(out *IndexManagementPhaseStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]IndexManagementMappingCondition, len(*in))
-		copy(*out, *in)
-	}
-	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementMappingStatus.
-func (in *IndexManagementMappingStatus) DeepCopy() *IndexManagementMappingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPhaseStatus.
+func (in *IndexManagementPhaseStatus) DeepCopy() *IndexManagementPhaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementMappingStatus)
+	out := new(IndexManagementPhaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -480,6 +1013,16 @@ func (in *IndexManagementPhasesSpec) DeepCopyInto(out *IndexManagementPhasesSpec
 		*out = new(IndexManagementHotPhaseSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Warm != nil {
+		in, out := &in.Warm, &out.Warm
+		*out = new(IndexManagementTierPhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cold != nil {
+		in, out := &in.Cold, &out.Cold
+		*out = new(IndexManagementTierPhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Delete != nil {
 		in, out := &in.Delete, &out.Delete
 		*out = new(IndexManagementDeletePhaseSpec)
@@ -556,6 +1099,16 @@ func (in *IndexManagementPolicyStatus) DeepCopyInto(out *IndexManagementPolicySt
 		*out = make([]IndexManagementPolicyCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]IndexManagementPhaseStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollover != nil {
+		in, out := &in.Rollover, &out.Rollover
+		*out = new(IndexManagementRolloverConditionsStatus)
+		**out = **in
+	}
 	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
 }
 
@@ -569,6 +1122,51 @@ func (in *IndexManagementPolicyStatus) DeepCopy() *IndexManagementPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementReplicasActionSpec) DeepCopyInto(out *IndexManagementReplicasActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementReplicasActionSpec.
+func (in *IndexManagementReplicasActionSpec) DeepCopy() *IndexManagementReplicasActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementReplicasActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementRolloverConditionsStatus) DeepCopyInto(out *IndexManagementRolloverConditionsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementRolloverConditionsStatus.
+func (in *IndexManagementRolloverConditionsStatus) DeepCopy() *IndexManagementRolloverConditionsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementRolloverConditionsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementShrinkActionSpec) DeepCopyInto(out *IndexManagementShrinkActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementShrinkActionSpec.
+func (in *IndexManagementShrinkActionSpec) DeepCopy() *IndexManagementShrinkActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementShrinkActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementSpec) DeepCopyInto(out *IndexManagementSpec) {
 	*out = *in
@@ -628,6 +1226,78 @@ func (in *IndexManagementStatus) DeepCopy() *IndexManagementStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementTierActionsSpec) DeepCopyInto(out *IndexManagementTierActionsSpec) {
+	*out = *in
+	if in.ForceMerge != nil {
+		in, out := &in.ForceMerge, &out.ForceMerge
+		*out = new(IndexManagementForceMergeActionSpec)
+		**out = **in
+	}
+	if in.Shrink != nil {
+		in, out := &in.Shrink, &out.Shrink
+		*out = new(IndexManagementShrinkActionSpec)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(IndexManagementReplicasActionSpec)
+		**out = **in
+	}
+	if in.Allocate != nil {
+		in, out := &in.Allocate, &out.Allocate
+		*out = new(IndexManagementAllocateActionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementTierActionsSpec.
+func (in *IndexManagementTierActionsSpec) DeepCopy() *IndexManagementTierActionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementTierActionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementTierPhaseSpec) DeepCopyInto(out *IndexManagementTierPhaseSpec) {
+	*out = *in
+	in.Actions.DeepCopyInto(&out.Actions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementTierPhaseSpec.
+func (in *IndexManagementTierPhaseSpec) DeepCopy() *IndexManagementTierPhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementTierPhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalUsersRealmSpec) DeepCopyInto(out *InternalUsersRealmSpec) {
+	*out = *in
+	out.Secret = in.Secret
+	if in.BackendRoles != nil {
+		in, out := &in.BackendRoles, &out.BackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalUsersRealmSpec.
+func (in *InternalUsersRealmSpec) DeepCopy() *InternalUsersRealmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalUsersRealmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kibana) DeepCopyInto(out *Kibana) {
 	*out = *in
@@ -716,6 +1386,11 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 		}
 	}
 	in.ProxySpec.DeepCopyInto(&out.ProxySpec)
+	if in.ElasticsearchRef != nil {
+		in, out := &in.ElasticsearchRef, &out.ElasticsearchRef
+		*out = new(ElasticsearchReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
@@ -768,6 +1443,13 @@ func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ElasticsearchConditions != nil {
+		in, out := &in.ElasticsearchConditions, &out.ElasticsearchConditions
+		*out = make(ClusterConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
@@ -780,6 +1462,72 @@ func (in *KibanaStatus) DeepCopy() *KibanaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPRealmSpec) DeepCopyInto(out *LDAPRealmSpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CASecret != nil {
+		in, out := &in.CASecret, &out.CASecret
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.BindSecret != nil {
+		in, out := &in.BindSecret, &out.BindSecret
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPRealmSpec.
+func (in *LDAPRealmSpec) DeepCopy() *LDAPRealmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPRealmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCRealmSpec) DeepCopyInto(out *OIDCRealmSpec) {
+	*out = *in
+	if in.ClientSecret != nil {
+		in, out := &in.ClientSecret, &out.ClientSecret
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRealmSpec.
+func (in *OIDCRealmSpec) DeepCopy() *OIDCRealmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCRealmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in PodStateMap) DeepCopyInto(out *PodStateMap) {
 	{
@@ -849,3 +1597,161 @@ func (in *ProxySpec) DeepCopy() *ProxySpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterStatus) DeepCopyInto(out *RemoteClusterStatus) {
+	*out = *in
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(ClusterConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterStatus.
+func (in *RemoteClusterStatus) DeepCopy() *RemoteClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotFSRepositorySpec) DeepCopyInto(out *SnapshotFSRepositorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotFSRepositorySpec.
+func (in *SnapshotFSRepositorySpec) DeepCopy() *SnapshotFSRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotFSRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotPolicySpec) DeepCopyInto(out *SnapshotPolicySpec) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(SnapshotRetentionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotPolicySpec.
+func (in *SnapshotPolicySpec) DeepCopy() *SnapshotPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepositorySpec) DeepCopyInto(out *SnapshotRepositorySpec) {
+	*out = *in
+	if in.FS != nil {
+		in, out := &in.FS, &out.FS
+		*out = new(SnapshotFSRepositorySpec)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(SnapshotS3RepositorySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepositorySpec.
+func (in *SnapshotRepositorySpec) DeepCopy() *SnapshotRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRetentionSpec) DeepCopyInto(out *SnapshotRetentionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRetentionSpec.
+func (in *SnapshotRetentionSpec) DeepCopy() *SnapshotRetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotS3RepositorySpec) DeepCopyInto(out *SnapshotS3RepositorySpec) {
+	*out = *in
+	out.Secret = in.Secret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotS3RepositorySpec.
+func (in *SnapshotS3RepositorySpec) DeepCopy() *SnapshotS3RepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotS3RepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotStatus) DeepCopyInto(out *SnapshotStatus) {
+	*out = *in
+	if in.LastSuccessfulSnapshotTime != nil {
+		in, out := &in.LastSuccessfulSnapshotTime, &out.LastSuccessfulSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotStatus.
+func (in *SnapshotStatus) DeepCopy() *SnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwarenessSpec) DeepCopyInto(out *ZoneAwarenessSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwarenessSpec.
+func (in *ZoneAwarenessSpec) DeepCopy() *ZoneAwarenessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwarenessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
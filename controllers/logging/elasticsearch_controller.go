@@ -7,17 +7,27 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/console"
 	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	"github.com/openshift/elasticsearch-operator/internal/snapshot"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
 )
 
 // ElasticsearchReconciler reconciles a Elasticsearch object
@@ -33,8 +43,18 @@ var (
 	reconcilePeriod = 30 * time.Second
 	// reconcileResult = reconcile.Result{RequeueAfter: reconcilePeriod}
 	reconcileResult = ctrl.Result{RequeueAfter: reconcilePeriod}
+
+	// changingReconcilePeriod is used while a cluster restarts, upgrades, scales or recovers
+	changingReconcilePeriod = 5 * time.Second
+	// steadyReconcilePeriod is used for a healthy cluster. Changes to the cluster and to the
+	// resources it owns trigger a reconciliation sooner.
+	steadyReconcilePeriod = 5 * time.Minute
 )
 
+// pvcClusterLabel holds the cluster name of the PVCs of a cluster. They are not owned by
+// the cluster to keep the data when it is deleted.
+const pvcClusterLabel = "logging-cluster"
+
 func (r *ElasticsearchReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	// Fetch the Elasticsearch instance
 	cluster := &loggingv1.Elasticsearch{}
@@ -44,6 +64,8 @@ func (r *ElasticsearchReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		if apierrors.IsNotFound(err) {
 			r.Log.Info("Flushing nodes", "objectKey", request.NamespacedName)
 			elasticsearch.FlushNodes(request.NamespacedName.Name, request.NamespacedName.Namespace)
+			esclient.FlushTransport(request.NamespacedName.Name, request.NamespacedName.Namespace)
+			metrics.DeleteCertificateMetrics(request.NamespacedName.Namespace, request.NamespacedName.Name)
 			elasticsearch.RemoveDashboardConfigMap(r.Log, r.Client)
 			if err := console.DeleteKibanaConsoleLink(context.TODO(), r.Client); err != nil {
 				r.Log.Error(err, "failed to delete consolelink")
@@ -85,20 +107,98 @@ func (r *ElasticsearchReconciler) Reconcile(ctx context.Context, request ctrl.Re
 
 	}
 
-	if err = elasticsearch.Reconcile(r.Log, cluster, r.Client); err != nil {
+	if err = elasticsearch.Reconcile(ctx, r.Log, cluster, r.Client); err != nil {
 		return reconcileResult, err
 	}
 
-	if err = indexmanagement.Reconcile(r.Log, cluster, r.Client); err != nil {
+	if err = indexmanagement.Reconcile(ctx, r.Log, cluster, r.Client); err != nil {
 		return reconcileResult, err
 	}
 
-	return reconcileResult, nil
+	if err = snapshot.Reconcile(ctx, r.Log, cluster, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	return r.requeueResult(ctx, request.NamespacedName), nil
+}
+
+// requeueResult adapts the requeue period to the state of the cluster after reconciling it
+func (r *ElasticsearchReconciler) requeueResult(ctx context.Context, key types.NamespacedName) ctrl.Result {
+	cluster := &loggingv1.Elasticsearch{}
+	if err := r.Get(ctx, key, cluster); err != nil {
+		return reconcileResult
+	}
+
+	switch {
+	case elasticsearch.IsClusterChanging(cluster):
+		return ctrl.Result{RequeueAfter: changingReconcilePeriod}
+	case elasticsearch.IsClusterSteady(cluster):
+		return ctrl.Result{RequeueAfter: steadyReconcilePeriod}
+	}
+	return reconcileResult
+}
+
+// isOwnedByElasticsearch returns true if the object is controlled by an elasticsearch CR
+func isOwnedByElasticsearch(obj client.Object) bool {
+	owner := metav1.GetControllerOf(obj)
+	return owner != nil &&
+		owner.Kind == "Elasticsearch" &&
+		owner.APIVersion == loggingv1.GroupVersion.String()
+}
+
+// isElasticsearchPVC returns true if the object is a PVC of an elasticsearch cluster
+func isElasticsearchPVC(obj client.Object) bool {
+	return obj.GetLabels()[pvcClusterLabel] != ""
+}
+
+// getPVCElasticsearchEvent returns a request for the cluster of the PVC
+func getPVCElasticsearchEvent(a client.Object) []reconcile.Request {
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      a.GetLabels()[pvcClusterLabel],
+				Namespace: a.GetNamespace(),
+			},
+		},
+	}
+}
+
+// getRemoteCASecretElasticsearchEvents returns requests for the clusters trusting the CA of
+// a remote cluster in the secret
+func (r *ElasticsearchReconciler) getRemoteCASecretElasticsearchEvents(a client.Object) []reconcile.Request {
+	clusters := &loggingv1.ElasticsearchList{}
+	if err := r.List(context.TODO(), clusters, client.InNamespace(a.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list the clusters of the remote cluster CA secret", "secret", a.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if elasticsearch.IsRemoteClusterCASecret(cluster, a.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+			})
+		}
+	}
+	return requests
 }
 
 func (r *ElasticsearchReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ownedPred := builder.WithPredicates(predicate.NewPredicateFuncs(isOwnedByElasticsearch))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("elasticsearch-controller").
 		For(&loggingv1.Elasticsearch{}).
+		Owns(&appsv1.Deployment{}, ownedPred).
+		Owns(&appsv1.StatefulSet{}, ownedPred).
+		Owns(&v1.Service{}, ownedPred).
+		Owns(&v1.ConfigMap{}, ownedPred).
+		Owns(&batchv1.CronJob{}, ownedPred).
+		Watches(&source.Kind{Type: &v1.PersistentVolumeClaim{}},
+			handler.EnqueueRequestsFromMapFunc(getPVCElasticsearchEvent),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isElasticsearchPVC))).
+		Watches(&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.getRemoteCASecretElasticsearchEvents)).
 		Complete(r)
 }
@@ -3,7 +3,6 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -27,8 +26,8 @@ import (
 
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/constants"
-
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
 	"github.com/openshift/elasticsearch-operator/internal/kibana"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
@@ -80,31 +79,38 @@ func (r *KibanaReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 	// keep track of the fact that we processed this kibana for future events and for mapping
 	registerKibanaNamespacedName(r.Log, request)
 
-	es, err := elasticsearch.GetElasticsearchCR(r.Client, request.Namespace)
+	es, err := kibana.GetElasticsearchCR(r.Client, kibanaInstance)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := kibana.UpdateElasticsearchStatus(r.Client, kibanaInstance, nil); err != nil {
+				r.Log.Error(err, "failed to update kibana status", "namespace", request.Namespace, "name", request.Name)
+			}
+		}
 		r.Log.Info("skipping kibana reconciliation", "namespace", request.Namespace, "error", err)
 		return reconcileResult, nil
 	}
 
+	if err := kibana.UpdateElasticsearchStatus(r.Client, kibanaInstance, es); err != nil {
+		return reconcileResult, err
+	}
+
 	// Check if es has annotation logging.openshift.io/elasticsearch-cert-management: true
-	eoCertManagement := false
-	certOwnerRef := metav1.OwnerReference{}
-	value, ok := es.Annotations[constants.EOCertManagementLabel]
-	if ok {
-		manageBool, _ := strconv.ParseBool(value)
-		if manageBool {
-			eoCertManagement = manageBool
-			certOwnerRef = es.GetOwnerRef()
-		}
+	// A cross-namespace reference is reported in the kibana status instead
+	var certProvider elasticsearch.CertificateProvider
+	if kibana.ManagesCertificates(kibanaInstance, es) {
+		cr := elasticsearch.NewCertificateRequest(r.Log, es.Name, es.Namespace, es.GetOwnerRef(), r.Client)
+		cr.Certificates = es.Spec.Certificates
+		certProvider = cr.Provider()
 	}
 
 	esClient := esclient.NewClient(r.Log, es.Name, es.Namespace, r.Client)
+	esClient.SetContext(ctx)
 	proxyCfg, err := kibana.GetProxyConfig(r.Client)
 	if err != nil {
 		return reconcileResult, err
 	}
 
-	if err := kibana.Reconcile(r.Log, kibanaInstance, r.Client, esClient, proxyCfg, eoCertManagement, certOwnerRef); err != nil {
+	if err := kibana.Reconcile(r.Log, kibanaInstance, r.Client, esClient, proxyCfg, certProvider, es.Spec.Authentication); err != nil {
 		return reconcileResult, err
 	}
 
@@ -242,6 +248,32 @@ func getNamespacedKibanaEvent(a client.Object) []reconcile.Request {
 	return requests
 }
 
+// getElasticsearchKibanaEvents returns requests for all kibana CRs using the elasticsearch CR,
+// i.e. the ones referencing it and the ones without reference in its namespace
+func (r *KibanaReconciler) getElasticsearchKibanaEvents(a client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	kibanas := &loggingv1.KibanaList{}
+	if err := r.List(context.TODO(), kibanas); err != nil {
+		r.Log.Error(err, "failed to list kibanas for elasticsearch event",
+			"namespace", a.GetNamespace(),
+			"name", a.GetName(),
+		)
+		return requests
+	}
+
+	esKey := client.ObjectKeyFromObject(a)
+	for i := range kibanas.Items {
+		kibanaCR := &kibanas.Items[i]
+		key, ok := kibana.ElasticsearchKey(kibanaCR)
+		if (ok && key == esKey) || (!ok && kibanaCR.Namespace == esKey.Namespace) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(kibanaCR)})
+		}
+	}
+
+	return requests
+}
+
 func (r *KibanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Watch for updates to the kibana secret
 	secretPred := predicate.Funcs{
@@ -291,6 +323,18 @@ func (r *KibanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		GenericFunc: func(e event.GenericEvent) bool { return false },
 	}
 
+	// Watch for the elasticsearch clusters used by kibana to appear, disappear or change health
+	esPred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldES, okOld := e.ObjectOld.(*loggingv1.Elasticsearch)
+			newES, okNew := e.ObjectNew.(*loggingv1.Elasticsearch)
+			return okOld && okNew && oldES.Status.Cluster.Status != newES.Status.Cluster.Status
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+
 	// TODO: replace the watches with For and Own
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("kibana-controller").
@@ -304,5 +348,6 @@ func (r *KibanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			IsController: true,
 		}, builder.WithPredicates(routePred)).
 		Watches(&source.Kind{Type: &imagev1.ImageStream{}}, globalMapHandler, builder.WithPredicates(isPred)).
+		Watches(&source.Kind{Type: &loggingv1.Elasticsearch{}}, handler.EnqueueRequestsFromMapFunc(r.getElasticsearchKibanaEvents), builder.WithPredicates(esPred)).
 		Complete(r)
 }
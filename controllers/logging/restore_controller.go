@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/snapshot"
+)
+
+var restoreResult = ctrl.Result{RequeueAfter: 10 * time.Second}
+
+// ElasticsearchRestoreReconciler reconciles a ElasticsearchRestore object
+type ElasticsearchRestoreReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// Reconcile starts the restore of the snapshot and polls it until it completed
+func (r *ElasticsearchRestoreReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	restore := &loggingv1.ElasticsearchRestore{}
+
+	err := r.Get(ctx, request.NamespacedName, restore)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	done, err := snapshot.ReconcileRestore(ctx, r.Log, restore, r.Client)
+	if err != nil {
+		return restoreResult, err
+	}
+	if !done {
+		return restoreResult, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *ElasticsearchRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticsearchrestore-controller").
+		For(&loggingv1.ElasticsearchRestore{}).
+		Complete(r)
+}
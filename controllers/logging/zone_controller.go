@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+)
+
+// ZoneReconciler copies the zone of the Kubernetes node to the pods of zone aware
+// Elasticsearch nodes
+type ZoneReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ZoneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, req.NamespacedName, pod)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, elasticsearch.ReconcileZone(r.Log, pod, r.Client)
+}
+
+// isZoneMissing returns true for the scheduled pods of zone aware nodes without their zone
+func isZoneMissing(obj client.Object) bool {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false
+	}
+	annotations := pod.GetAnnotations()
+	return annotations[elasticsearch.ZoneTopologyKeyAnnotation] != "" &&
+		annotations[elasticsearch.ZoneAnnotation] == "" &&
+		pod.Spec.NodeName != ""
+}
+
+func zonePodPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isZoneMissing(e.ObjectNew)
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isZoneMissing(e.Object)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func (r *ZoneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithEventFilter(zonePodPredicate()).
+		Complete(r)
+}
@@ -12,8 +12,10 @@ const (
 	KibanaTrustedCAName         = "kibana-trusted-ca-bundle"
 	SecretHashPrefix            = "logging.openshift.io/"
 	ElasticsearchDefaultImage   = "quay.io/openshift-logging/elasticsearch6:6.8.1"
+	Elasticsearch7DefaultImage  = "quay.io/openshift-logging/elasticsearch7:7.10.2"
+	OpenSearchDefaultImage      = "quay.io/openshift-logging/opensearch:1.3.6"
 	ProxyDefaultImage           = "quay.io/openshift-logging/elasticsearch-proxy:1.0"
-	CuratorDefaultImage         = "quay.io/openshift-logging/curator5:5.8.1"
+	OperatorDefaultImage        = "quay.io/openshift-logging/elasticsearch-operator:5.6"
 	TheoreticalShardMaxSizeInMB = 40960
 
 	// OcpTemplatePrefix is the prefix all operator generated templates
@@ -27,11 +29,14 @@ const (
 	ConsoleDashboardLabel          = "console.openshift.io/dashboard"
 	LoggingHashLabel               = "logging.openshift.io/hash"
 	ElasticsearchDashboardFileName = "openshift-elasticsearch.json"
+
+	// SnapshotRepositoryPath is the mount path of the filesystem snapshot repository
+	SnapshotRepositoryPath = "/elasticsearch/snapshots"
 )
 
 var (
 	ReconcileForGlobalProxyList = []string{KibanaTrustedCAName}
-	packagedCuratorImage        = utils.LookupEnvWithDefault("RELATED_IMAGE_CURATOR", CuratorDefaultImage)
+	packagedOperatorImage       = utils.LookupEnvWithDefault("RELATED_IMAGE_ELASTICSEARCH_OPERATOR", OperatorDefaultImage)
 	ExpectedSecretKeys          = []string{
 		"admin-ca",
 		"admin-cert",
@@ -43,6 +48,7 @@ var (
 	}
 )
 
-func PackagedCuratorImage() string {
-	return packagedCuratorImage
+// PackagedOperatorImage returns the image of the operator itself which also runs the index management jobs
+func PackagedOperatorImage() string {
+	return packagedOperatorImage
 }
@@ -0,0 +1,417 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	// managedRealmPrefix prefixes the domains of the operator in the security config. The
+	// domains of the image or added by hand are left untouched.
+	managedRealmPrefix     = "eo_"
+	ldapRealmName          = "eo_ldap"
+	oidcRealmName          = "eo_oidc"
+	internalUsersRealmName = "eo_internal_users"
+
+	// the realms are tried after the domains of the image authenticating OpenShift tokens
+	ldapRealmOrder          = 10
+	internalUsersRealmOrder = 11
+	oidcRealmOrder          = 12
+
+	// managedDescription marks the domains and internal users of the operator
+	managedDescription = "Managed by the elasticsearch-operator"
+
+	defaultLDAPUserSearch = "(uid={0})"
+	defaultLDAPRoleSearch = "(member={0})"
+	defaultLDAPRoleName   = "cn"
+)
+
+// securityDomain is an authentication or authorization domain of the security config
+type securityDomain struct {
+	Description           string           `json:"description"`
+	HTTPEnabled           bool             `json:"http_enabled"`
+	TransportEnabled      bool             `json:"transport_enabled"`
+	Order                 *int             `json:"order,omitempty"`
+	HTTPAuthenticator     *securityBackend `json:"http_authenticator,omitempty"`
+	AuthenticationBackend *securityBackend `json:"authentication_backend,omitempty"`
+	AuthorizationBackend  *securityBackend `json:"authorization_backend,omitempty"`
+}
+
+type securityBackend struct {
+	Type      string                 `json:"type"`
+	Challenge *bool                  `json:"challenge,omitempty"`
+	Config    map[string]interface{} `json:"config"`
+}
+
+// updateAuthentication configures the realms of the spec in the security plugin, removes
+// the ones dropped from the spec and syncs the internal users with their secret
+func (er *ElasticsearchRequest) updateAuthentication() {
+	cluster := er.cluster
+	if cluster.Spec.Authentication == nil && cluster.Status.Authentication == nil {
+		return
+	}
+
+	authc, authz, err := er.authenticationDomains()
+	if err != nil {
+		er.ll.Error(err, "failed to render authentication realms")
+		return
+	}
+
+	config, err := er.esClient.GetSecurityConfig()
+	if err != nil {
+		er.ll.Error(err, "failed to get security config")
+		return
+	}
+	if applySecurityDomains(config, authc, authz) {
+		if err := er.esClient.UpdateSecurityConfig(config); err != nil {
+			er.ll.Error(err, "failed to update authentication realms")
+			return
+		}
+		er.ll.Info("Updated authentication realms")
+	}
+
+	applied := ""
+	if cluster.Status.Authentication != nil {
+		applied = cluster.Status.Authentication.InternalUsersSecretVersion
+	}
+	version, err := er.updateInternalUsers(applied)
+	if err != nil {
+		er.ll.Error(err, "failed to update internal users")
+		return
+	}
+
+	var status *api.AuthenticationStatus
+	if cluster.Spec.Authentication != nil {
+		status = &api.AuthenticationStatus{
+			Realms:                     domainNames(authc),
+			InternalUsersSecretVersion: version,
+		}
+	}
+	if err := er.persistAuthenticationStatus(status); err != nil {
+		er.ll.Error(err, "failed to update authentication status")
+	}
+}
+
+// authenticationDomains returns the authentication and authorization domains of the realms
+// of the spec in the format of the security config
+func (er *ElasticsearchRequest) authenticationDomains() (map[string]interface{}, map[string]interface{}, error) {
+	authc, authz := map[string]interface{}{}, map[string]interface{}{}
+	spec := er.cluster.Spec.Authentication
+	if spec == nil {
+		return authc, authz, nil
+	}
+
+	if spec.LDAP != nil {
+		connection, err := er.ldapConnectionConfig(spec.LDAP)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		config := copySettings(connection)
+		config["userbase"] = spec.LDAP.UserBase
+		config["usersearch"] = withDefault(spec.LDAP.UserSearch, defaultLDAPUserSearch)
+		if spec.LDAP.UsernameAttribute != "" {
+			config["username_attribute"] = spec.LDAP.UsernameAttribute
+		}
+		authc[ldapRealmName] = securityDomain{
+			Description:           managedDescription,
+			HTTPEnabled:           true,
+			Order:                 pointer.Int(ldapRealmOrder),
+			HTTPAuthenticator:     basicAuthenticator(),
+			AuthenticationBackend: &securityBackend{Type: "ldap", Config: config},
+		}
+
+		if spec.LDAP.RoleBase != "" {
+			config = copySettings(connection)
+			config["userbase"] = spec.LDAP.UserBase
+			config["usersearch"] = withDefault(spec.LDAP.UserSearch, defaultLDAPUserSearch)
+			config["rolebase"] = spec.LDAP.RoleBase
+			config["rolesearch"] = withDefault(spec.LDAP.RoleSearch, defaultLDAPRoleSearch)
+			config["rolename"] = withDefault(spec.LDAP.RoleNameAttribute, defaultLDAPRoleName)
+			config["resolve_nested_roles"] = false
+			authz[ldapRealmName] = securityDomain{
+				Description:          managedDescription,
+				HTTPEnabled:          true,
+				AuthorizationBackend: &securityBackend{Type: "ldap", Config: config},
+			}
+		}
+	}
+
+	if spec.InternalUsers != nil {
+		authc[internalUsersRealmName] = securityDomain{
+			Description:           managedDescription,
+			HTTPEnabled:           true,
+			Order:                 pointer.Int(internalUsersRealmOrder),
+			HTTPAuthenticator:     basicAuthenticator(),
+			AuthenticationBackend: &securityBackend{Type: "intern", Config: map[string]interface{}{}},
+		}
+	}
+
+	if spec.OIDC != nil {
+		config := map[string]interface{}{
+			"openid_connect_url": spec.OIDC.ConnectURL,
+		}
+		if spec.OIDC.SubjectKey != "" {
+			config["subject_key"] = spec.OIDC.SubjectKey
+		}
+		if spec.OIDC.RolesKey != "" {
+			config["roles_key"] = spec.OIDC.RolesKey
+		}
+		authc[oidcRealmName] = securityDomain{
+			Description:           managedDescription,
+			HTTPEnabled:           true,
+			Order:                 pointer.Int(oidcRealmOrder),
+			HTTPAuthenticator:     &securityBackend{Type: "openid", Challenge: pointer.Bool(false), Config: config},
+			AuthenticationBackend: &securityBackend{Type: "noop", Config: map[string]interface{}{}},
+		}
+	}
+
+	// the domains are compared with the ones returned by the cluster
+	var err error
+	if authc, err = normalizeSettings(authc); err != nil {
+		return nil, nil, err
+	}
+	if authz, err = normalizeSettings(authz); err != nil {
+		return nil, nil, err
+	}
+	return authc, authz, nil
+}
+
+// ldapConnectionConfig returns the settings connecting to the LDAP servers shared by the
+// authentication and authorization backends
+func (er *ElasticsearchRequest) ldapConnectionConfig(spec *api.LDAPRealmSpec) (map[string]interface{}, error) {
+	config := map[string]interface{}{
+		"hosts":            spec.Hosts,
+		"enable_ssl":       spec.EnableSSL,
+		"verify_hostnames": true,
+	}
+	if spec.CASecret != nil && spec.CASecret.Name != "" {
+		ca, err := er.authenticationSecretValue(spec.CASecret.Name, api.AuthenticationCAKey)
+		if err != nil {
+			return nil, err
+		}
+		config["pemtrustedcas_content"] = ca
+	}
+	if spec.BindDN != "" {
+		config["bind_dn"] = spec.BindDN
+		if spec.BindSecret != nil {
+			password, err := er.authenticationSecretValue(spec.BindSecret.Name, api.LDAPBindPasswordKey)
+			if err != nil {
+				return nil, err
+			}
+			config["password"] = password
+		}
+	}
+	return config, nil
+}
+
+// authenticationSecretValue returns the value of the key of a secret of the authentication spec
+func (er *ElasticsearchRequest) authenticationSecretValue(name, key string) (string, error) {
+	s, err := secret.Get(context.TODO(), er.client, client.ObjectKey{Name: name, Namespace: er.cluster.Namespace})
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to get authentication secret", "secret", name)
+	}
+	value, ok := s.Data[key]
+	if !ok || len(value) == 0 {
+		return "", kverrors.New("missing key in authentication secret", "secret", name, "key", key)
+	}
+	return string(value), nil
+}
+
+// applySecurityDomains sets the desired domains in the security config and removes the
+// managed ones not desired anymore. It returns true if the config changed.
+func applySecurityDomains(config *estypes.SecurityConfig, authc, authz map[string]interface{}) bool {
+	if config.Dynamic == nil {
+		config.Dynamic = map[string]interface{}{}
+	}
+
+	changed := false
+	for section, desired := range map[string]map[string]interface{}{"authc": authc, "authz": authz} {
+		current, _ := config.Dynamic[section].(map[string]interface{})
+		if current == nil {
+			current = map[string]interface{}{}
+		}
+		for name := range current {
+			if _, ok := desired[name]; strings.HasPrefix(name, managedRealmPrefix) && !ok {
+				delete(current, name)
+				changed = true
+			}
+		}
+		for name, domain := range desired {
+			if !containsSettings(current[name], domain) {
+				current[name] = domain
+				changed = true
+			}
+		}
+		config.Dynamic[section] = current
+	}
+	return changed
+}
+
+// containsSettings returns true if all settings of desired are set to the same value in
+// current. The cluster returns the defaults of the settings missing from the config.
+func containsSettings(current, desired interface{}) bool {
+	desiredMap, ok := desired.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(current, desired)
+	}
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, value := range desiredMap {
+		if !containsSettings(currentMap[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateInternalUsers creates the internal users of the secret, replaces them when the
+// secret or their backend roles changed and removes the managed users missing from the
+// secret. It returns the version of the secret applied.
+func (er *ElasticsearchRequest) updateInternalUsers(applied string) (string, error) {
+	var realm *api.InternalUsersRealmSpec
+	if er.cluster.Spec.Authentication != nil {
+		realm = er.cluster.Spec.Authentication.InternalUsers
+	}
+	if realm == nil && applied == "" {
+		return "", nil
+	}
+
+	passwords := map[string][]byte{}
+	version := ""
+	backendRoles := []string{}
+	if realm != nil {
+		key := client.ObjectKey{Name: realm.Secret.Name, Namespace: er.cluster.Namespace}
+		s, err := secret.Get(context.TODO(), er.client, key)
+		if err != nil {
+			return applied, kverrors.Wrap(err, "failed to get internal users secret", "secret", key.Name)
+		}
+		passwords, version = s.Data, s.ResourceVersion
+		if realm.BackendRoles != nil {
+			backendRoles = realm.BackendRoles
+		}
+	}
+
+	users, err := er.esClient.GetInternalUsers()
+	if err != nil {
+		return applied, err
+	}
+
+	for name, password := range passwords {
+		current, ok := users[name]
+		if ok && current.Description != managedDescription {
+			er.ll.Info("Skipping internal user not managed by the operator", "user", name)
+			continue
+		}
+		if ok && version == applied && sets.NewString(current.BackendRoles...).Equal(sets.NewString(backendRoles...)) {
+			continue
+		}
+
+		user := estypes.InternalUser{
+			Password:     string(password),
+			BackendRoles: backendRoles,
+			Description:  managedDescription,
+		}
+		if err := er.esClient.PutInternalUser(name, user); err != nil {
+			return applied, err
+		}
+		er.ll.Info("Updated internal user", "user", name)
+	}
+
+	for name, user := range users {
+		if _, ok := passwords[name]; ok || user.Description != managedDescription {
+			continue
+		}
+		if err := er.esClient.DeleteInternalUser(name); err != nil {
+			return applied, err
+		}
+		er.ll.Info("Removed internal user", "user", name)
+	}
+	return version, nil
+}
+
+// persistAuthenticationStatus writes the authentication status if it changed
+func (er *ElasticsearchRequest) persistAuthenticationStatus(status *api.AuthenticationStatus) error {
+	cluster := er.cluster
+	if equality.Semantic.DeepEqual(status, cluster.Status.Authentication) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.Authentication = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update authentication status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
+
+// basicAuthenticator reads the credentials of the basic authorization header. It does not
+// challenge clients without credentials to leave them to the domains tried after it.
+func basicAuthenticator() *securityBackend {
+	return &securityBackend{Type: "basic", Challenge: pointer.Bool(false), Config: map[string]interface{}{}}
+}
+
+// normalizeSettings converts the settings to the types decoded from the responses of the
+// cluster
+func normalizeSettings(settings map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to encode security settings")
+	}
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode security settings")
+	}
+	return normalized, nil
+}
+
+func copySettings(settings map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		copied[key] = value
+	}
+	return copied
+}
+
+func domainNames(domains map[string]interface{}) []string {
+	var names []string
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func withDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
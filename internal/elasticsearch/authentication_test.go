@@ -0,0 +1,190 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestApplySecurityDomains(t *testing.T) {
+	config := &estypes.SecurityConfig{}
+	if err := json.Unmarshal([]byte(`{"dynamic": {"authc": {
+		"openshift_domain": {"order": 0, "http_enabled": true},
+		"eo_oidc": {"order": 12, "http_enabled": true},
+		"eo_internal_users": {"description": "Managed by the elasticsearch-operator", "order": 11,
+			"http_enabled": true, "transport_enabled": false, "authentication_backend": {"type": "intern", "config": {}},
+			"http_authenticator": {"type": "basic", "challenge": false, "config": {}}}
+	}}}`), config); err != nil {
+		t.Fatalf("failed to decode the security config: %v", err)
+	}
+
+	authc, err := normalizeSettings(map[string]interface{}{
+		internalUsersRealmName: securityDomain{
+			Description:           managedDescription,
+			HTTPEnabled:           true,
+			Order:                 pointer.Int(internalUsersRealmOrder),
+			HTTPAuthenticator:     basicAuthenticator(),
+			AuthenticationBackend: &securityBackend{Type: "intern", Config: map[string]interface{}{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to normalize the domains: %v", err)
+	}
+
+	if !applySecurityDomains(config, authc, map[string]interface{}{}) {
+		t.Fatal("Exp. the realm dropped from the spec to be removed")
+	}
+	domains := config.Dynamic["authc"].(map[string]interface{})
+	if _, ok := domains["eo_oidc"]; ok {
+		t.Error("Exp. the managed OIDC realm to be removed")
+	}
+	if _, ok := domains["openshift_domain"]; !ok {
+		t.Error("Exp. the domain of the image to be kept")
+	}
+
+	if applySecurityDomains(config, authc, map[string]interface{}{}) {
+		t.Error("Exp. no change for the realms already configured")
+	}
+}
+
+func TestUpdateAuthentication(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch",
+			Namespace: "openshift-logging",
+		},
+		Spec: api.ElasticsearchSpec{
+			Authentication: &api.ElasticsearchAuthenticationSpec{
+				LDAP: &api.LDAPRealmSpec{
+					Hosts:      []string{"ldap.example.com:636"},
+					EnableSSL:  true,
+					BindDN:     "cn=admin,dc=example,dc=com",
+					BindSecret: &v1.LocalObjectReference{Name: "ldap-bind"},
+					UserBase:   "ou=people,dc=example,dc=com",
+					RoleBase:   "ou=groups,dc=example,dc=com",
+				},
+				InternalUsers: &api.InternalUsersRealmSpec{
+					Secret:       v1.LocalObjectReference{Name: "internal-users"},
+					BackendRoles: []string{"readall"},
+				},
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		cluster.DeepCopy(),
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ldap-bind", Namespace: cluster.Namespace},
+			Data:       map[string][]byte{api.LDAPBindPasswordKey: []byte("secret")},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal-users", Namespace: cluster.Namespace},
+			Data:       map[string][]byte{"alice": []byte("wonderland")},
+		},
+	).Build()
+
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_opendistro/_security/api/securityconfig": {
+			{
+				StatusCode: 200,
+				Body:       `{"config": {"dynamic": {"authc": {"openshift_domain": {"order": 0}}, "authz": {}}}}`,
+			},
+		},
+		"_opendistro/_security/api/securityconfig/config": {
+			{
+				StatusCode: 200,
+				Body:       `{"status": "OK"}`,
+			},
+		},
+		"_opendistro/_security/api/internalusers": {
+			{
+				StatusCode: 200,
+				Body:       `{"bob": {"backend_roles": [], "description": "Managed by the elasticsearch-operator"}, "admin": {"reserved": true}}`,
+			},
+		},
+		"_opendistro/_security/api/internalusers/alice": {
+			{
+				StatusCode: 201,
+				Body:       `{"status": "CREATED"}`,
+			},
+		},
+		"_opendistro/_security/api/internalusers/bob": {
+			{
+				StatusCode: 200,
+				Body:       `{"status": "OK"}`,
+			},
+		},
+	})
+
+	er := ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		ll:       log.NewLogger("authentication-testing"),
+	}
+	er.updateAuthentication()
+
+	req, found := chatter.GetRequest("_opendistro/_security/api/securityconfig/config")
+	if !found {
+		t.Fatal("Exp. the security config to be updated")
+	}
+	config := &estypes.SecurityConfig{}
+	if err := json.Unmarshal([]byte(req.Body), config); err != nil {
+		t.Fatalf("failed to decode the request body: %v", err)
+	}
+	authc := config.Dynamic["authc"].(map[string]interface{})
+	for _, name := range []string{"openshift_domain", ldapRealmName, internalUsersRealmName} {
+		if _, ok := authc[name]; !ok {
+			t.Errorf("Exp. the authentication domain %s in %s", name, req.Body)
+		}
+	}
+	ldap := authc[ldapRealmName].(map[string]interface{})["authentication_backend"].(map[string]interface{})["config"].(map[string]interface{})
+	if ldap["password"] != "secret" || ldap["usersearch"] != defaultLDAPUserSearch {
+		t.Errorf("Exp. the bind password and default user search in the LDAP config but got %v", ldap)
+	}
+	if _, ok := config.Dynamic["authz"].(map[string]interface{})[ldapRealmName]; !ok {
+		t.Errorf("Exp. the LDAP authorization domain for the role base in %s", req.Body)
+	}
+
+	req, found = chatter.GetRequest("_opendistro/_security/api/internalusers/alice")
+	if !found {
+		t.Fatal("Exp. the internal user of the secret to be created")
+	}
+	user := estypes.InternalUser{}
+	if err := json.Unmarshal([]byte(req.Body), &user); err != nil {
+		t.Fatalf("failed to decode the request body: %v", err)
+	}
+	exp := estypes.InternalUser{Password: "wonderland", BackendRoles: []string{"readall"}, Description: managedDescription}
+	if !reflect.DeepEqual(user, exp) {
+		t.Errorf("Exp. internal user %v but got %v", exp, user)
+	}
+	if req, found = chatter.GetRequest("_opendistro/_security/api/internalusers/bob"); !found || req.Method != "DELETE" {
+		t.Error("Exp. the managed user missing from the secret to be removed")
+	}
+	if _, found = chatter.GetRequest("_opendistro/_security/api/internalusers/admin"); found {
+		t.Error("Exp. the users not managed by the operator to be kept")
+	}
+
+	updated := &api.Elasticsearch{}
+	if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), updated); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+	status := updated.Status.Authentication
+	if status == nil || !reflect.DeepEqual(status.Realms, []string{internalUsersRealmName, ldapRealmName}) || status.InternalUsersSecretVersion == "" {
+		t.Errorf("Exp. the configured realms and the applied secret version in the status but got %v", status)
+	}
+}
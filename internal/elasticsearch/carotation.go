@@ -0,0 +1,212 @@
+package elasticsearch
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+)
+
+// rotateCA replaces the signing CA due for rotation in phases, each of them rolled out to all
+// nodes before the next one starts. This keeps the nodes trusting each other while they are
+// restarted one at a time:
+//  1. the new CA is trusted next to the current one
+//  2. the certificates are reissued by the new CA, the previous one is still trusted
+//  3. the previous CA is no longer trusted
+func (cr *CertificateRequest) rotateCA(ca *certCA) error {
+	if !cr.ManageCARotation {
+		return nil
+	}
+
+	tracked := caRotationPhase(cr.CARotation)
+	phase := ca.rotationPhase(tracked)
+	if phase != tracked {
+		// the phase of the signing secret was not tracked yet, wait for it to be rolled out
+		cr.setCARotationPhase(phase)
+		return nil
+	}
+
+	switch phase {
+	case "":
+		if !cr.needsCARotation(&ca.certificate) {
+			return nil
+		}
+		next, err := cr.genCA()
+		if err != nil {
+			return err
+		}
+		ca.next = next
+		return cr.advanceCARotation(ca, api.CARotationTrustingNewCA)
+
+	case api.CARotationTrustingNewCA:
+		if !cr.CARolledOut {
+			return nil
+		}
+		previous, next := ca.cert, ca.next
+		ca.certificate = next.certificate
+		ca.serial = next.serial
+		ca.pubKeySHA1 = next.pubKeySHA1
+		ca.next = nil
+		ca.previous = previous
+		return cr.advanceCARotation(ca, api.CARotationReissuingCertificates)
+
+	case api.CARotationReissuingCertificates:
+		if !cr.CARolledOut {
+			return nil
+		}
+		ca.previous = nil
+		return cr.advanceCARotation(ca, api.CARotationRemovingOldCA)
+
+	case api.CARotationRemovingOldCA:
+		if cr.CARolledOut {
+			cr.Log.Info("Completed the rotation of the signing CA")
+			cr.setCARotationPhase("")
+		}
+	}
+	return nil
+}
+
+// advanceCARotation stores the CAs of the phase in the signing secret
+func (cr *CertificateRequest) advanceCARotation(ca *certCA, phase api.CARotationPhase) error {
+	if err := cr.persistCA(ca); err != nil {
+		return kverrors.Wrap(err, "failed to advance the rotation of the signing CA", "phase", phase)
+	}
+
+	cr.Log.Info("Advancing the rotation of the signing CA", "phase", phase)
+	cr.setCARotationPhase(phase)
+	// the secrets of the new phase are only written by this request
+	cr.CARolledOut = false
+	return nil
+}
+
+func (cr *CertificateRequest) setCARotationPhase(phase api.CARotationPhase) {
+	if phase == "" {
+		cr.CARotation = nil
+		return
+	}
+	cr.CARotation = &api.CARotationStatus{
+		Phase:              phase,
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+func caRotationPhase(status *api.CARotationStatus) api.CARotationPhase {
+	if status == nil {
+		return ""
+	}
+	return status.Phase
+}
+
+// rotationPhase returns the phase of the rotation of the CA. The removal of the previous CA
+// leaves no trace in the signing secret and is only known from the tracked phase.
+func (ca *certCA) rotationPhase(tracked api.CARotationPhase) api.CARotationPhase {
+	switch {
+	case ca.next != nil:
+		return api.CARotationTrustingNewCA
+	case ca.previous != nil:
+		return api.CARotationReissuingCertificates
+	case tracked == api.CARotationRemovingOldCA:
+		return api.CARotationRemovingOldCA
+	}
+	return ""
+}
+
+// bundle returns the PEM encoded CAs trusted during the rotation, the signing CA first
+func (ca *certCA) bundle() []byte {
+	bundle := append([]byte{}, ca.cert...)
+	if ca.next != nil {
+		bundle = appendPEM(bundle, ca.next.cert)
+	}
+	return appendPEM(bundle, ca.previous)
+}
+
+// decodeNextCA returns the CA replacing the signing CA. Its serial starts over as it has not
+// signed any certificates yet.
+func decodeNextCA(certBytes, keyBytes []byte) (*certCA, error) {
+	x509Cert, err := pemDecodeCert(certBytes)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid next CA certificate")
+	}
+	privKey, err := pemDecodePrivateKey(keyBytes)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid next CA key")
+	}
+	if !isValidCA(x509Cert, privKey) {
+		return nil, kverrors.New("invalid next CA")
+	}
+
+	return &certCA{
+		certificate: certificate{
+			certBytes,
+			keyBytes,
+			x509Cert,
+			privKey,
+		},
+		serial:     big.NewInt(0),
+		pubKeySHA1: subjectKeyID(privKey.Public()),
+	}, nil
+}
+
+// secretRolledOut returns whether all nodes were restarted with the current secret of the
+// cluster, which allows the rotation of the signing CA to advance to its next phase and
+// completes the rollout of changed trusted CAs
+func (er *ElasticsearchRequest) secretRolledOut() bool {
+	cluster := er.cluster
+
+	clusterNodes := nodes[nodeMapKey(cluster.Name, cluster.Namespace)]
+	if len(clusterNodes) == 0 {
+		return false
+	}
+
+	key := client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}
+	secretHash := secret.GetDataSHA256(context.TODO(), er.client, key)
+	for _, node := range clusterNodes {
+		if node.getSecretHash() != secretHash {
+			return false
+		}
+	}
+
+	for _, nodeStatus := range cluster.Status.Nodes {
+		if nodeStatus.UpgradeStatus.ScheduledForCertRedeploy == v1.ConditionTrue ||
+			nodeStatus.UpgradeStatus.UnderUpgrade == v1.ConditionTrue {
+			return false
+		}
+	}
+
+	return !containsClusterCondition(api.Restarting, v1.ConditionTrue, &cluster.Status) &&
+		!containsClusterCondition(api.Recovering, v1.ConditionTrue, &cluster.Status)
+}
+
+// persistCARotationStatus writes the phase of the CA rotation if it changed
+func (er *ElasticsearchRequest) persistCARotationStatus(status *api.CARotationStatus) error {
+	cluster := er.cluster
+	if equality.Semantic.DeepEqual(status, cluster.Status.CARotation) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.CARotation = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update CA rotation status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
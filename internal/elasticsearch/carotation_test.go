@@ -0,0 +1,195 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+)
+
+func decodeCABundle(t *testing.T, bundle []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("failed to parse the CA bundle: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+func TestRotateCAInPhases(t *testing.T) {
+	cr := newTestCertificateRequest(&api.ElasticsearchCertificatesSpec{KeyAlgorithm: api.CertificateKeyECDSA})
+	cr.ManageCARotation = true
+
+	getSecret := func() (*x509.Certificate, []*x509.Certificate) {
+		s, err := secret.Get(context.TODO(), cr.K8sClient, client.ObjectKey{Name: "elasticsearch", Namespace: cr.Namespace})
+		if err != nil {
+			t.Fatalf("failed to get the secret: %v", err)
+		}
+		cert, err := pemDecodeCert(s.Data[esComponentCertName])
+		if err != nil {
+			t.Fatalf("failed to decode the certificate: %v", err)
+		}
+		return cert, decodeCABundle(t, s.Data[esAdminCAName])
+	}
+	expectPhase := func(step string, exp api.CARotationPhase) {
+		if got := caRotationPhase(cr.CARotation); got != exp {
+			t.Fatalf("%s: Exp. the CA rotation phase %q but got %q", step, exp, got)
+		}
+	}
+
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	expectPhase("initial", "")
+	_, bundle := getSecret()
+	if len(bundle) != 1 {
+		t.Fatalf("Exp. the initial CA to be trusted but got %d CAs", len(bundle))
+	}
+	oldCA := bundle[0]
+
+	// changing the key size in a maintenance window rotates the CA
+	cr.Certificates.KeySize = 384
+	cr.RotationAllowed = true
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	expectPhase("start", api.CARotationTrustingNewCA)
+	cert, bundle := getSecret()
+	if len(bundle) != 2 || !bundle[0].Equal(oldCA) {
+		t.Fatalf("Exp. the current CA to be trusted first next to the new one but got %d CAs", len(bundle))
+	}
+	newCA := bundle[1]
+	if err := cert.CheckSignatureFrom(oldCA); err != nil {
+		t.Errorf("Exp. the certificate to be signed by the current CA: %v", err)
+	}
+
+	// the phase is kept until it was rolled out
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	expectPhase("not rolled out", api.CARotationTrustingNewCA)
+
+	cr.CARolledOut = true
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	expectPhase("reissue", api.CARotationReissuingCertificates)
+	cert, bundle = getSecret()
+	if len(bundle) != 2 || !bundle[0].Equal(newCA) || !bundle[1].Equal(oldCA) {
+		t.Fatalf("Exp. the new CA to be trusted first next to the previous one but got %d CAs", len(bundle))
+	}
+	if err := cert.CheckSignatureFrom(newCA); err != nil {
+		t.Errorf("Exp. the certificate to be reissued by the new CA: %v", err)
+	}
+
+	cr.CARolledOut = true
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	expectPhase("remove", api.CARotationRemovingOldCA)
+	_, bundle = getSecret()
+	if len(bundle) != 1 || !bundle[0].Equal(newCA) {
+		t.Fatalf("Exp. only the new CA to be trusted but got %d CAs", len(bundle))
+	}
+
+	cr.CARolledOut = true
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	expectPhase("completed", "")
+}
+
+func TestRotateCAFollowsSigningSecret(t *testing.T) {
+	cr := newTestCertificateRequest(&api.ElasticsearchCertificatesSpec{KeyAlgorithm: api.CertificateKeyECDSA})
+	ca, err := cr.genCA()
+	if err != nil {
+		t.Fatalf("failed to generate the CA: %v", err)
+	}
+	if ca.next, err = cr.genCA(); err != nil {
+		t.Fatalf("failed to generate the next CA: %v", err)
+	}
+	if err := cr.persistCA(ca); err != nil {
+		t.Fatalf("failed to persist the CA: %v", err)
+	}
+
+	// requests not managing the rotation trust both CAs without advancing it
+	kibanaCA := &certCA{}
+	if err := cr.ensureCA(kibanaCA); err != nil {
+		t.Fatalf("failed to ensure the CA: %v", err)
+	}
+	if len(decodeCABundle(t, kibanaCA.bundle())) != 2 {
+		t.Errorf("Exp. both CAs to be trusted")
+	}
+	if cr.CARotation != nil {
+		t.Errorf("Exp. the rotation to be only tracked by the managing request but got %v", cr.CARotation)
+	}
+
+	// a phase missing from the status is tracked before it is advanced
+	cr.ManageCARotation = true
+	cr.CARolledOut = true
+	cr.CARotation = &api.CARotationStatus{Phase: api.CARotationRemovingOldCA, LastTransitionTime: metav1.Now()}
+	managedCA := &certCA{}
+	if err := cr.ensureCA(managedCA); err != nil {
+		t.Fatalf("failed to ensure the CA: %v", err)
+	}
+	if got := caRotationPhase(cr.CARotation); got != api.CARotationTrustingNewCA {
+		t.Errorf("Exp. the phase of the signing secret to be tracked but got %q", got)
+	}
+	if !bytes.Equal(managedCA.cert, ca.cert) {
+		t.Errorf("Exp. the signing CA to be kept until the phase was rolled out")
+	}
+}
+
+func TestRotateCAWithinRotationLead(t *testing.T) {
+	cr := newTestCertificateRequest(&api.ElasticsearchCertificatesSpec{
+		KeyAlgorithm: api.CertificateKeyECDSA,
+		CALifetime:   &metav1.Duration{Duration: 2 * time.Hour},
+		Lifetime:     &metav1.Duration{Duration: 90 * time.Minute},
+	})
+	cr.ManageCARotation = true
+
+	signingCA := func() []byte {
+		s, err := secret.Get(context.TODO(), cr.K8sClient, client.ObjectKey{Name: cr.getSigningSecretName(), Namespace: cr.Namespace})
+		if err != nil {
+			t.Fatalf("failed to get the signing secret: %v", err)
+		}
+		return s.Data[esCACertName]
+	}
+
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	oldCA := signingCA()
+
+	// the rotation of a CA within its rotation lead time starts outside of maintenance windows
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	if got := caRotationPhase(cr.CARotation); got != api.CARotationTrustingNewCA {
+		t.Fatalf("Exp. the CA rotation to start but got phase %q", got)
+	}
+
+	// the CA outside of its expiry margin is kept while the rotation is in progress
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	if got := caRotationPhase(cr.CARotation); got != api.CARotationTrustingNewCA {
+		t.Fatalf("Exp. the phase to be kept until it was rolled out but got %q", got)
+	}
+	if !bytes.Equal(signingCA(), oldCA) {
+		t.Fatalf("Exp. the CA to be kept during the rotation")
+	}
+
+	cr.CARolledOut = true
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	if got := caRotationPhase(cr.CARotation); got != api.CARotationReissuingCertificates {
+		t.Fatalf("Exp. the rotation to advance but got phase %q", got)
+	}
+	rotatedCA := signingCA()
+	if bytes.Equal(rotatedCA, oldCA) {
+		t.Fatalf("Exp. the next CA to sign the certificates")
+	}
+
+	// a CA expiring within the rotation lead time of the certificates is regenerated at once
+	cr.Certificates.RotationLead = &metav1.Duration{Duration: 3 * time.Hour}
+	cr.GenerateElasticsearchCerts("elasticsearch")
+	if bytes.Equal(signingCA(), rotatedCA) {
+		t.Errorf("Exp. the CA within its expiry margin to be regenerated")
+	}
+	if cr.CARotation != nil {
+		t.Errorf("Exp. the rotation to be reset by the regenerated CA but got %v", cr.CARotation)
+	}
+}
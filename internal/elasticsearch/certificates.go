@@ -3,9 +3,10 @@ package elasticsearch
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/rsa"
-	"crypto/sha1"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/ViaQ/logerr/v2/kverrors"
 	"github.com/go-logr/logr"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,13 +33,19 @@ type certificate struct {
 	cert     []byte
 	key      []byte
 	x509Cert *x509.Certificate
-	privKey  *rsa.PrivateKey
+	privKey  crypto.Signer
 }
 
 type certCA struct {
 	certificate
 	serial     *big.Int
 	pubKeySHA1 []byte
+
+	// next is the CA replacing this one during a rotation, trusted but not yet signing
+	next *certCA
+	// previous is the PEM encoded certificate of the CA replaced by this one, still trusted
+	// until the certificates it signed were reissued
+	previous []byte
 }
 
 type x509v3Ext struct {
@@ -52,12 +60,9 @@ type sessionSecret struct {
 }
 
 const (
-	rsaKeyLength      = 4096
-	caCN              = "Logging Signing CA"
-	caNotAfterYears   = 5
-	compNotAfterYears = 2
-	nameTypeDNS       = 2
-	nameTypeIP        = 7
+	caCN        = "Logging Signing CA"
+	nameTypeDNS = 2
+	nameTypeIP  = 7
 
 	componentKeyName  = "tls.key"
 	componentCertName = "tls.crt"
@@ -66,6 +71,10 @@ const (
 	esCACertName      = "cert"
 	esCASerialName    = "serial"
 
+	esCANextKeyName      = "next-key"
+	esCANextCertName     = "next-cert"
+	esCAPreviousCertName = "previous-cert"
+
 	esComponentName     = "elasticsearch"
 	esComponentKeyName  = "elasticsearch.key"
 	esComponentCertName = "elasticsearch.crt"
@@ -127,6 +136,32 @@ type CertificateRequest struct {
 	OwnerRef    metav1.OwnerReference
 	K8sClient   client.Client
 
+	// TrustedCAs are PEM encoded CAs trusted by the cluster in addition to its own CA
+	TrustedCAs []byte
+
+	// Certificates are the settings of the issued certificates, the defaults if nil
+	Certificates *api.ElasticsearchCertificatesSpec
+	// RotationAllowed rotates the certificates within their rotation lead time. Otherwise
+	// they are only rotated when expiring within the hour.
+	RotationAllowed bool
+	// Status is the expiry of the certificates ensured by the request
+	Status []api.CertificateStatus
+
+	// ManageCARotation starts and advances the rotation of the signing CA. Requests without
+	// it use the CAs of the signing secret as they are.
+	ManageCARotation bool
+	// CARolledOut is whether all nodes loaded the secrets of the current CA rotation phase
+	CARolledOut bool
+	// CARotation is the phase of the rotation of the signing CA, nil if none is in progress
+	CARotation *api.CARotationStatus
+
+	// TrustedCAsRollout is whether the nodes are restarted one at a time for a change of the
+	// trusted CAs of the cluster secret only. It is cleared by any other change of the secret.
+	TrustedCAsRollout bool
+	// CertRedeployPending is whether nodes are scheduled to load a previous change of the
+	// cluster secret, which keeps a trusted CAs change from restarting them one at a time
+	CertRedeployPending bool
+
 	Extensions map[string]x509v3Ext
 }
 
@@ -174,13 +209,13 @@ func (cr *CertificateRequest) GenerateComponentCerts(secretName, cn string) {
 	key := client.ObjectKey{Name: secretName, Namespace: cr.Namespace}
 	s, err := secret.Get(context.TODO(), cr.K8sClient, key)
 	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
-		cr.Log.Error(err, "unable to get secret")
+		cr.logFailure(err, secretName, "unable to get secret")
 		return
 	}
 
 	ca := &certCA{}
 	if err = cr.ensureCA(ca); err != nil {
-		cr.Log.Error(err, "Unable to get CA")
+		cr.logFailure(err, secretName, "Unable to get CA")
 		return
 	}
 
@@ -191,20 +226,21 @@ func (cr *CertificateRequest) GenerateComponentCerts(secretName, cn string) {
 
 	err = cr.EnsureCert(cn, componentCert, ca)
 	if err != nil {
-		cr.Log.Error(err, "Unable to generate cert for component")
+		cr.logFailure(err, secretName, "Unable to generate cert for component")
 		return
 	}
 
 	componentSecretData := map[string][]byte{
 		componentKeyName:  componentCert.key,
 		componentCertName: componentCert.cert,
-		componentCAName:   ca.cert,
+		componentCAName:   ca.bundle(),
 	}
 
 	if err := CreateOrUpdateSecretWithOwnerRef(secretName, cr.Namespace, componentSecretData, cr.K8sClient, cr.OwnerRef); err != nil {
-		cr.Log.Error(err, "Unable to create secret for component")
+		cr.logFailure(err, secretName, "Unable to create secret for component")
 		return
 	}
+	cr.recordCertificate(secretName, componentCert)
 }
 
 func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
@@ -213,13 +249,13 @@ func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
 	key := client.ObjectKey{Name: kibanaSecretName, Namespace: cr.Namespace}
 	s, err := secret.Get(context.TODO(), cr.K8sClient, key)
 	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
-		cr.Log.Error(err, "unable to get secret")
+		cr.logFailure(err, kibanaSecretName, "unable to get secret")
 		return
 	}
 
 	ca := &certCA{}
 	if err = cr.ensureCA(ca); err != nil {
-		cr.Log.Error(err, "Unable to get CA")
+		cr.logFailure(err, kibanaSecretName, "Unable to get CA")
 		return
 	}
 
@@ -230,25 +266,26 @@ func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
 
 	err = cr.EnsureCert(kibanaComponentName, kibanaCert, ca)
 	if err != nil {
-		cr.Log.Error(err, "Unable to generate cert for kibana")
+		cr.logFailure(err, kibanaSecretName, "Unable to generate cert for kibana")
 		return
 	}
 
 	kibanaSecretData := map[string][]byte{
 		kibanaComponentKeyName:  kibanaCert.key,
 		kibanaComponentCertName: kibanaCert.cert,
-		kibanaComponentCAName:   ca.cert,
+		kibanaComponentCAName:   ca.bundle(),
 	}
 
 	if err = CreateOrUpdateSecretWithOwnerRef(kibanaSecretName, cr.Namespace, kibanaSecretData, cr.K8sClient, cr.OwnerRef); err != nil {
-		cr.Log.Error(err, "Unable to create secret for kibana component")
+		cr.logFailure(err, kibanaSecretName, "Unable to create secret for kibana component")
 		return
 	}
+	cr.recordCertificate(kibanaSecretName, kibanaCert)
 
 	key = client.ObjectKey{Name: getKibanaProxySecretName(kibanaSecretName), Namespace: cr.Namespace}
 	s, err = secret.Get(context.TODO(), cr.K8sClient, key)
 	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
-		cr.Log.Error(err, "unable to get secret")
+		cr.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "unable to get secret")
 		return
 	}
 
@@ -259,7 +296,7 @@ func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
 
 	err = cr.EnsureCert(kibanaInternalComponentName, kibanaProxyCert, ca)
 	if err != nil {
-		cr.Log.Error(err, "Unable to generate cert for kibana-internal")
+		cr.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "Unable to generate cert for kibana-internal")
 		return
 	}
 
@@ -269,7 +306,7 @@ func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
 	// ensure session secret here
 	err = ensureSessionSecret(kibanaSessionSecretLength, allowedRunes, kibanaProxySessionSecret)
 	if err != nil {
-		cr.Log.Error(err, "Unable to ensure session secret for kibana proxy")
+		cr.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "Unable to ensure session secret for kibana proxy")
 		return
 	}
 
@@ -280,9 +317,10 @@ func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
 	}
 
 	if err = CreateOrUpdateSecretWithOwnerRef(getKibanaProxySecretName(kibanaSecretName), cr.Namespace, secretData, cr.K8sClient, cr.OwnerRef); err != nil {
-		cr.Log.Error(err, "Unable to create secret for kibana-proxy")
+		cr.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "Unable to create secret for kibana-proxy")
 		return
 	}
+	cr.recordCertificate(getKibanaProxySecretName(kibanaSecretName), kibanaProxyCert)
 }
 
 func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
@@ -292,13 +330,13 @@ func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
 	key := client.ObjectKey{Name: clusterName, Namespace: cr.Namespace}
 	s, err := secret.Get(context.TODO(), cr.K8sClient, key)
 	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
-		cr.Log.Error(err, "unable to get secret")
+		cr.logFailure(err, clusterName, "unable to get secret")
 		return
 	}
 
 	ca := &certCA{}
 	if err = cr.ensureCA(ca); err != nil {
-		cr.Log.Error(err, "Unable to get CA")
+		cr.logFailure(err, clusterName, "Unable to get CA")
 		return
 	}
 
@@ -309,7 +347,7 @@ func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
 
 	err = cr.EnsureCert(esAdminComponentName, adminCert, ca)
 	if err != nil {
-		cr.Log.Error(err, "Unable to generate cert for admin user")
+		cr.logFailure(err, clusterName, "Unable to generate cert for admin user")
 		return
 	}
 
@@ -320,7 +358,7 @@ func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
 
 	err = cr.EnsureCert(esComponentName, elasticsearchCert, ca)
 	if err != nil {
-		cr.Log.Error(err, "Unable to generate cert for elasticsearch")
+		cr.logFailure(err, clusterName, "Unable to generate cert for elasticsearch")
 		return
 	}
 
@@ -331,7 +369,7 @@ func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
 
 	err = cr.EnsureCert(esInternalComponentName, loggingESCert, ca)
 	if err != nil {
-		cr.Log.Error(err, "Unable to generate cert for logging-es")
+		cr.logFailure(err, clusterName, "Unable to generate cert for logging-es")
 		return
 	}
 
@@ -342,13 +380,16 @@ func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
 		esInternalCertname:  loggingESCert.cert,
 		esAdminKeyName:      adminCert.key,
 		esAdminCertName:     adminCert.cert,
-		esAdminCAName:       ca.cert,
+		esAdminCAName:       appendPEM(ca.bundle(), cr.TrustedCAs),
 	}
 
-	if err := CreateOrUpdateSecretWithOwnerRef(clusterName, cr.Namespace, secretData, cr.K8sClient, cr.OwnerRef); err != nil {
-		cr.Log.Error(err, "Unable to create secret for elasticsearch component")
+	if err := cr.writeClusterSecret(clusterName, s.Data, secretData); err != nil {
+		cr.logFailure(err, clusterName, "Unable to create secret for elasticsearch component")
 		return
 	}
+	cr.recordCertificate(clusterName, adminCert)
+	cr.recordCertificate(clusterName, elasticsearchCert)
+	cr.recordCertificate(clusterName, loggingESCert)
 }
 
 func (cr *CertificateRequest) EnsureCert(componentName string, cert *certificate, ca *certCA) error {
@@ -357,8 +398,8 @@ func (cr *CertificateRequest) EnsureCert(componentName string, cert *certificate
 		isSignedCorrectly = cert.x509Cert.CheckSignatureFrom(ca.x509Cert) == nil
 	}
 
-	// validate that the cert isn't expired and is signed correctly
-	if !isValidCert(cert.x509Cert, cert.privKey, componentName, true) || !isSignedCorrectly {
+	// validate that the cert isn't due for rotation and is signed correctly
+	if !isValidCert(cert.x509Cert, cert.privKey, componentName, true) || !isSignedCorrectly || cr.needsRotation(cert) {
 		err := cr.generateCert(componentName, cert, ca)
 		if err != nil {
 			return err
@@ -394,12 +435,15 @@ func (cr *CertificateRequest) ensureCA(caCert *certCA) error {
 		caCert.key = ca.key
 		caCert.pubKeySHA1 = ca.pubKeySHA1
 		caCert.serial = ca.serial
+		caCert.next = ca.next
+		caCert.previous = ca.previous
 	}
 
-	// check if the CA cert is invalid
-	if !isValidCA(caCert.x509Cert, caCert.privKey) {
+	// check if the CA cert is invalid or about to expire, a valid one is replaced by the
+	// phases of a rotation
+	if !isValidCA(caCert.x509Cert, caCert.privKey) || certWillExpireSoon(caCert.x509Cert, cr.caExpiryMargin()) {
 		// generate new CLO CA and populate the CA secret with it
-		ca, err := genCA()
+		ca, err := cr.genCA()
 		if err != nil {
 			return err
 		}
@@ -410,10 +454,20 @@ func (cr *CertificateRequest) ensureCA(caCert *certCA) error {
 		caCert.key = ca.key
 		caCert.pubKeySHA1 = ca.pubKeySHA1
 		caCert.serial = ca.serial
+		caCert.next = nil
+		caCert.previous = nil
 
-		return cr.persistCA(caCert)
+		if err := cr.persistCA(caCert); err != nil {
+			return err
+		}
+		if cr.ManageCARotation {
+			cr.setCARotationPhase("")
+		}
+	} else if err := cr.rotateCA(caCert); err != nil {
+		return err
 	}
 
+	cr.recordCertificate(secretName, &caCert.certificate)
 	return nil
 }
 
@@ -425,17 +479,24 @@ func (cr *CertificateRequest) persistCA(caCert *certCA) error {
 		esCAKeyName:    caCert.key,
 		esCASerialName: []byte(caCert.serial.Text(10)),
 	}
+	if caCert.next != nil {
+		secretData[esCANextCertName] = caCert.next.cert
+		secretData[esCANextKeyName] = caCert.next.key
+	}
+	if caCert.previous != nil {
+		secretData[esCAPreviousCertName] = caCert.previous
+	}
 
 	return CreateOrUpdateSecretWithOwnerRef(secretName, cr.Namespace, secretData, cr.K8sClient, cr.OwnerRef)
 }
 
 func (cr *CertificateRequest) generateCert(componentName string, cert *certificate, ca *certCA) error {
-	privKey, err := rsa.GenerateKey(rand.Reader, rsaKeyLength)
+	privKey, err := cr.generateKey()
 	if err != nil {
 		return err
 	}
 
-	pubKeySHA1 := sha1.Sum(x509.MarshalPKCS1PublicKey(&privKey.PublicKey))
+	pubKeySHA1 := subjectKeyID(privKey.Public())
 
 	serial, err := cr.incrementCertSerial(ca)
 	if err != nil {
@@ -444,18 +505,18 @@ func (cr *CertificateRequest) generateCert(componentName string, cert *certifica
 
 	x509Cert := &x509.Certificate{
 		SerialNumber:       serial,
-		SignatureAlgorithm: x509.SHA512WithRSA,
+		SignatureAlgorithm: signatureAlgorithm(ca.privKey),
 		Subject: pkix.Name{
 			Organization:       certOrganization,
 			OrganizationalUnit: componentOrganizationUnit,
 			CommonName:         componentName,
 		},
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(compNotAfterYears, 0, -1),
+		NotAfter:              time.Now().Add(cr.lifetime()),
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 		BasicConstraintsValid: true,
-		SubjectKeyId:          pubKeySHA1[:],
-		AuthorityKeyId:        ca.pubKeySHA1[:],
+		SubjectKeyId:          pubKeySHA1,
+		AuthorityKeyId:        ca.pubKeySHA1,
 	}
 
 	ext := cr.Extensions[componentName]
@@ -499,7 +560,7 @@ func (cr *CertificateRequest) generateCert(componentName string, cert *certifica
 		x509Cert.ExtraExtensions = append(x509Cert.ExtraExtensions, sanExtension)
 	}
 
-	certBytes, err := x509.CreateCertificate(rand.Reader, x509Cert, ca.x509Cert, &privKey.PublicKey, ca.privKey)
+	certBytes, err := x509.CreateCertificate(rand.Reader, x509Cert, ca.x509Cert, privKey.Public(), ca.privKey)
 	if err != nil {
 		return err
 	}
@@ -571,6 +632,61 @@ func ensureSessionSecret(secretLength int, runes []rune, session *sessionSecret)
 	return nil
 }
 
+// writeClusterSecret writes the data of the cluster secret. A change of the trusted CAs only
+// is rolled out to the nodes one at a time, as they keep trusting each other in between.
+func (cr *CertificateRequest) writeClusterSecret(clusterName string, current, data map[string][]byte) error {
+	if err := CreateOrUpdateSecretWithOwnerRef(clusterName, cr.Namespace, data, cr.K8sClient, cr.OwnerRef); err != nil {
+		return err
+	}
+
+	changed, trustedCAsOnly := trustedCAsChange(current, data)
+	if !changed {
+		return nil
+	}
+	cr.TrustedCAsRollout = trustedCAsOnly && (cr.TrustedCAsRollout || !cr.CertRedeployPending)
+	if cr.TrustedCAsRollout {
+		cr.Log.Info("Restarting the nodes one at a time to trust the changed CAs", "secret", clusterName)
+	}
+	return nil
+}
+
+// trustedCAsChange returns whether the data of the cluster secret changes and whether only
+// its trusted CAs do
+func trustedCAsChange(current, data map[string][]byte) (changed, trustedCAsOnly bool) {
+	if len(current) == 0 {
+		return len(data) > 0, false
+	}
+
+	trustedCAsOnly = true
+	for key, value := range data {
+		if bytes.Equal(current[key], value) {
+			continue
+		}
+		changed = true
+		if key != esAdminCAName {
+			trustedCAsOnly = false
+		}
+	}
+	for key := range current {
+		if _, ok := data[key]; !ok {
+			changed = true
+			trustedCAsOnly = false
+		}
+	}
+	return changed, changed && trustedCAsOnly
+}
+
+// appendPEM appends the PEM encoded blocks to the bundle keeping them on separate lines
+func appendPEM(bundle, blocks []byte) []byte {
+	if len(blocks) == 0 {
+		return bundle
+	}
+	if len(bundle) > 0 && !bytes.HasSuffix(bundle, []byte("\n")) {
+		bundle = append(bundle, '\n')
+	}
+	return append(bundle, blocks...)
+}
+
 func unmarshalCert(cert, key []byte, unmarshalledCert *certificate) error {
 	// if we are providing empty cert or key, just return
 	if len(cert) == 0 || len(key) == 0 {
@@ -582,7 +698,7 @@ func unmarshalCert(cert, key []byte, unmarshalledCert *certificate) error {
 		return err
 	}
 
-	rsaKey, err := pemDecodePrivateKey(key)
+	privKey, err := pemDecodePrivateKey(key)
 	if err != nil {
 		return err
 	}
@@ -590,30 +706,30 @@ func unmarshalCert(cert, key []byte, unmarshalledCert *certificate) error {
 	unmarshalledCert.cert = cert
 	unmarshalledCert.key = key
 	unmarshalledCert.x509Cert = x509Cert
-	unmarshalledCert.privKey = rsaKey
+	unmarshalledCert.privKey = privKey
 
 	return nil
 }
 
-func isValidCert(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey, commonName string, isComponent bool) bool {
+func isValidCert(x509Cert *x509.Certificate, privKey crypto.Signer, commonName string, isComponent bool) bool {
 	if x509Cert == nil {
 		return false
 	}
 
-	if rsaPrivKey == nil {
+	if privKey == nil {
 		return false
 	}
 
-	if certWillExpireSoon(x509Cert) {
+	if certWillExpireSoon(x509Cert, certExpiryRotationPeriod) {
 		return false
 	}
 
-	rsaPubKey, ok := x509Cert.PublicKey.(*rsa.PublicKey)
+	pubKey, ok := x509Cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
 	if !ok {
 		return false
 	}
 
-	if rsaPubKey.N.Cmp(rsaPrivKey.N) != 0 {
+	if !pubKey.Equal(privKey.Public()) {
 		return false
 	}
 
@@ -642,21 +758,22 @@ func isValidCert(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey, commonN
 	return true
 }
 
-func certWillExpireSoon(cert *x509.Certificate) bool {
+// certWillExpireSoon returns whether the certificate expires within the lead time
+func certWillExpireSoon(cert *x509.Certificate, lead time.Duration) bool {
 	certExpiration := cert.NotAfter
-	return time.Now().After(certExpiration.Add(time.Hour * -1))
+	return time.Now().After(certExpiration.Add(-lead))
 }
 
-func genCA() (*certCA, error) {
-	caPrivKey, err := rsa.GenerateKey(rand.Reader, rsaKeyLength)
+func (cr *CertificateRequest) genCA() (*certCA, error) {
+	caPrivKey, err := cr.generateKey()
 	if err != nil {
 		return nil, err
 	}
-	caPubKeySHA1 := sha1.Sum(x509.MarshalPKCS1PublicKey(&caPrivKey.PublicKey))
+	caPubKeySHA1 := subjectKeyID(caPrivKey.Public())
 	serial := big.NewInt(0)
 	ca := &x509.Certificate{
 		SerialNumber:       serial,
-		SignatureAlgorithm: x509.SHA512WithRSA,
+		SignatureAlgorithm: signatureAlgorithm(caPrivKey),
 		Subject: pkix.Name{
 			Country:            caCountry,
 			Organization:       certOrganization,
@@ -664,14 +781,14 @@ func genCA() (*certCA, error) {
 			CommonName:         caCN,
 		},
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(caNotAfterYears, 0, 0),
+		NotAfter:              time.Now().Add(cr.caLifetime()),
 		IsCA:                  true,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		BasicConstraintsValid: true,
-		SubjectKeyId:          caPubKeySHA1[:],
-		AuthorityKeyId:        caPubKeySHA1[:],
+		SubjectKeyId:          caPubKeySHA1,
+		AuthorityKeyId:        caPubKeySHA1,
 	}
-	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &caPrivKey.PublicKey, caPrivKey)
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, caPrivKey.Public(), caPrivKey)
 	if err != nil {
 		return nil, err
 	}
@@ -684,19 +801,19 @@ func genCA() (*certCA, error) {
 		return nil, err
 	}
 	return &certCA{
-		certificate{
+		certificate: certificate{
 			caPEMBytes,
 			keyPEMBytes,
 			ca,
 			caPrivKey,
 		},
-		serial,
-		caPubKeySHA1[:],
+		serial:     serial,
+		pubKeySHA1: caPubKeySHA1,
 	}, nil
 }
 
-func isValidCA(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey) bool {
-	if !isValidCert(x509Cert, rsaPrivKey, caCN, false) {
+func isValidCA(x509Cert *x509.Certificate, privKey crypto.Signer) bool {
+	if !isValidCert(x509Cert, privKey, caCN, false) {
 		return false
 	}
 
@@ -720,7 +837,7 @@ func validateCASecret(secret *v1.Secret) (*certCA, error) {
 	}
 	x509Cert = decodedCert
 
-	var rsaKey *rsa.PrivateKey
+	var privKey crypto.Signer
 	keyBytes, keyOK := secret.Data[esCAKeyName]
 	if !keyOK {
 		return nil, fmt.Errorf("missing key key from secret")
@@ -729,9 +846,9 @@ func validateCASecret(secret *v1.Secret) (*certCA, error) {
 	if err != nil {
 		return nil, err
 	}
-	rsaKey = decodedKey
+	privKey = decodedKey
 
-	pubKeySHA1 := sha1.Sum(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))
+	pubKeySHA1 := subjectKeyID(privKey.Public())
 	serial := big.NewInt(0)
 	serialBytes, serialOK := secret.Data[esCASerialName]
 
@@ -742,33 +859,54 @@ func validateCASecret(secret *v1.Secret) (*certCA, error) {
 		return nil, err
 	}
 
-	if !isValidCA(x509Cert, rsaKey) {
+	if !isValidCA(x509Cert, privKey) {
 		return nil, fmt.Errorf("invalid CA")
 	}
+
+	var next *certCA
+	if nextCertBytes, ok := secret.Data[esCANextCertName]; ok {
+		if next, err = decodeNextCA(nextCertBytes, secret.Data[esCANextKeyName]); err != nil {
+			return nil, err
+		}
+	}
+
 	return &certCA{
-		certificate{
+		certificate: certificate{
 			certBytes,
 			keyBytes,
 			x509Cert,
-			rsaKey,
+			privKey,
 		},
-		serial,
-		pubKeySHA1[:],
+		serial:     serial,
+		pubKeySHA1: pubKeySHA1,
+		next:       next,
+		previous:   secret.Data[esCAPreviousCertName],
 	}, nil
 }
 
-func pemEncodePrivateKey(privKey *rsa.PrivateKey) ([]byte, error) {
+// pemEncodePrivateKey encodes RSA keys in PKCS #1 and other keys in PKCS #8
+func pemEncodePrivateKey(privKey crypto.Signer) ([]byte, error) {
+	block := &pem.Block{}
+	if rsaKey, ok := privKey.(*rsa.PrivateKey); ok {
+		block.Type = `RSA PRIVATE KEY`
+		block.Bytes = x509.MarshalPKCS1PrivateKey(rsaKey)
+	} else {
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(privKey)
+		if err != nil {
+			return nil, err
+		}
+		block.Type = `PRIVATE KEY`
+		block.Bytes = keyBytes
+	}
+
 	pemBuffer := &bytes.Buffer{}
-	if err := pem.Encode(pemBuffer, &pem.Block{
-		Type:  `RSA PRIVATE KEY`,
-		Bytes: x509.MarshalPKCS1PrivateKey(privKey),
-	}); err != nil {
+	if err := pem.Encode(pemBuffer, block); err != nil {
 		return nil, err
 	}
 	return pemBuffer.Bytes(), nil
 }
 
-func pemDecodePrivateKey(keyBytes []byte) (*rsa.PrivateKey, error) {
+func pemDecodePrivateKey(keyBytes []byte) (crypto.Signer, error) {
 	block, _ := pem.Decode(keyBytes)
 	if block == nil {
 		return nil, fmt.Errorf("failed to decode PEM block containing private key")
@@ -778,14 +916,20 @@ func pemDecodePrivateKey(keyBytes []byte) (*rsa.PrivateKey, error) {
 		return x509.ParsePKCS1PrivateKey(block.Bytes)
 	}
 
+	if block.Type == "EC PRIVATE KEY" {
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
 	if block.Type == "PRIVATE KEY" {
 		pkcs8Key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 		if err != nil {
 			return nil, err
 		}
 
-		privateKey, ok := pkcs8Key.(*rsa.PrivateKey)
-		if ok {
+		switch privateKey := pkcs8Key.(type) {
+		case *rsa.PrivateKey:
+			return privateKey, nil
+		case *ecdsa.PrivateKey:
 			return privateKey, nil
 		}
 	}
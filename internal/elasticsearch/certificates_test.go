@@ -0,0 +1,114 @@
+package elasticsearch
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+)
+
+func newTestCertificateRequest(spec *api.ElasticsearchCertificatesSpec) *CertificateRequest {
+	cr := NewCertificateRequest(log.NewLogger("certificates-testing"), "elasticsearch", "openshift-logging",
+		metav1.OwnerReference{Name: "elasticsearch"}, fake.NewClientBuilder().Build())
+	cr.Certificates = spec
+	return cr
+}
+
+func TestGenerateElasticsearchCertsWithECDSAKeys(t *testing.T) {
+	cr := newTestCertificateRequest(&api.ElasticsearchCertificatesSpec{
+		Lifetime:     &metav1.Duration{Duration: 720 * time.Hour},
+		KeyAlgorithm: api.CertificateKeyECDSA,
+		KeySize:      384,
+	})
+	cr.GenerateElasticsearchCerts("elasticsearch")
+
+	s, err := secret.Get(context.TODO(), cr.K8sClient, client.ObjectKey{Name: "elasticsearch", Namespace: "openshift-logging"})
+	if err != nil {
+		t.Fatalf("Exp. the elasticsearch secret to be created: %v", err)
+	}
+	cert := &certificate{}
+	if err := unmarshalCert(s.Data[esInternalCertname], s.Data[esInternalKeyName], cert); err != nil {
+		t.Fatalf("failed to decode the logging-es certificate: %v", err)
+	}
+	if key, ok := cert.privKey.(*ecdsa.PrivateKey); !ok || key.Curve.Params().BitSize != 384 {
+		t.Errorf("Exp. a P-384 key but got %T", cert.privKey)
+	}
+	if expiry := time.Until(cert.x509Cert.NotAfter); expiry > 720*time.Hour || expiry < 719*time.Hour {
+		t.Errorf("Exp. the certificate to expire in 720h but got %s", expiry)
+	}
+
+	// the CA and the admin, elasticsearch and logging-es certificates
+	if len(cr.Status) != 4 {
+		t.Fatalf("Exp. the expiry of 4 certificates in the status but got %v", cr.Status)
+	}
+	for _, status := range cr.Status {
+		if exp := status.NotAfter.Add(-defaultCertRotationLead); !status.RotationTime.Time.Equal(exp) {
+			t.Errorf("%s: Exp. the rotation at %s but got %s", status.CommonName, exp, status.RotationTime)
+		}
+	}
+}
+
+func TestNeedsRotation(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lifetime time.Duration
+		keySize  int32
+		allowed  bool
+		exp      bool
+	}{
+		{desc: "valid certificate", lifetime: 4 * time.Hour, keySize: 256, allowed: true},
+		{desc: "within the rotation lead time", lifetime: 2 * time.Hour, keySize: 256, allowed: true, exp: true},
+		{desc: "within the rotation lead time outside of the maintenance window", lifetime: 2 * time.Hour, keySize: 256},
+		{desc: "expiring within the hour", lifetime: 30 * time.Minute, keySize: 256, exp: true},
+		{desc: "changed key size", lifetime: 4 * time.Hour, keySize: 384, allowed: true, exp: true},
+		{desc: "changed key size outside of the maintenance window", lifetime: 4 * time.Hour, keySize: 384},
+	}
+	for _, test := range tests {
+		cr := newTestCertificateRequest(&api.ElasticsearchCertificatesSpec{
+			Lifetime:     &metav1.Duration{Duration: test.lifetime},
+			KeyAlgorithm: api.CertificateKeyECDSA,
+			RotationLead: &metav1.Duration{Duration: 3 * time.Hour},
+		})
+		ca, err := cr.genCA()
+		if err != nil {
+			t.Fatalf("failed to generate the CA: %v", err)
+		}
+		cert := &certificate{}
+		if err := cr.generateCert(esInternalComponentName, cert, ca); err != nil {
+			t.Fatalf("failed to generate the certificate: %v", err)
+		}
+
+		cr.Certificates.KeySize = test.keySize
+		cr.RotationAllowed = test.allowed
+		if got := cr.needsRotation(cert); got != test.exp {
+			t.Errorf("%s: Exp. the rotation to be needed %t", test.desc, test.exp)
+		}
+	}
+}
+
+func TestCertificateComponent(t *testing.T) {
+	cr := newTestCertificateRequest(nil)
+	tests := []struct {
+		secret string
+		exp    string
+	}{
+		{secret: "signing-elasticsearch", exp: certComponentCA},
+		{secret: "elasticsearch", exp: certComponentElasticsearch},
+		{secret: "kibana", exp: certComponentKibana},
+		{secret: "kibana-proxy", exp: certComponentKibana},
+		{secret: "fluentd", exp: certComponentClient},
+	}
+	for _, test := range tests {
+		if got := cr.certificateComponent(test.secret); got != test.exp {
+			t.Errorf("%s: Exp. the component %q but got %q", test.secret, test.exp, got)
+		}
+	}
+}
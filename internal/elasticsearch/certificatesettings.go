@@ -0,0 +1,289 @@
+package elasticsearch
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"sort"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+)
+
+const (
+	defaultRSAKeySize        = 4096
+	defaultECDSAKeySize      = 256
+	defaultCALifetime        = 5 * 365 * 24 * time.Hour
+	defaultCertLifetime      = (2*365 - 1) * 24 * time.Hour
+	defaultCertRotationLead  = time.Hour
+	certExpiryRotationPeriod = time.Hour
+	// caRotationRolloutPeriod is how much earlier than the certificates the rotation of the
+	// CA starts, leaving its phases time to be rolled out before the CA expires
+	caRotationRolloutPeriod = 72 * time.Hour
+
+	certComponentCA            = "ca"
+	certComponentElasticsearch = "elasticsearch"
+	certComponentKibana        = "kibana"
+	certComponentClient        = "client"
+)
+
+var (
+	validRSAKeySizes   = []string{"2048", "3072", "4096"}
+	validECDSAKeySizes = []string{"256", "384"}
+)
+
+func (cr *CertificateRequest) caLifetime() time.Duration {
+	return certificateCALifetime(cr.Certificates)
+}
+
+func (cr *CertificateRequest) lifetime() time.Duration {
+	return certificateLifetime(cr.Certificates)
+}
+
+func (cr *CertificateRequest) rotationLead() time.Duration {
+	return certificateRotationLead(cr.Certificates)
+}
+
+// caRotationLead returns how long before its expiry the phased rotation of the CA starts
+func (cr *CertificateRequest) caRotationLead() time.Duration {
+	return cr.rotationLead() + caRotationRolloutPeriod
+}
+
+// caExpiryMargin returns how long before its expiry the CA is regenerated at once, when its
+// phased rotation did not complete in time
+func (cr *CertificateRequest) caExpiryMargin() time.Duration {
+	if lead := cr.rotationLead(); lead > certExpiryRotationPeriod {
+		return lead
+	}
+	return certExpiryRotationPeriod
+}
+
+func certificateCALifetime(spec *api.ElasticsearchCertificatesSpec) time.Duration {
+	if spec == nil || spec.CALifetime == nil {
+		return defaultCALifetime
+	}
+	return spec.CALifetime.Duration
+}
+
+func certificateLifetime(spec *api.ElasticsearchCertificatesSpec) time.Duration {
+	if spec == nil || spec.Lifetime == nil {
+		return defaultCertLifetime
+	}
+	return spec.Lifetime.Duration
+}
+
+func certificateRotationLead(spec *api.ElasticsearchCertificatesSpec) time.Duration {
+	if spec == nil || spec.RotationLead == nil {
+		return defaultCertRotationLead
+	}
+	return spec.RotationLead.Duration
+}
+
+// certificateKeyType returns the algorithm and size of the private keys
+func certificateKeyType(spec *api.ElasticsearchCertificatesSpec) (api.CertificateKeyAlgorithm, int) {
+	algorithm, size := api.CertificateKeyRSA, 0
+	if spec != nil {
+		if spec.KeyAlgorithm != "" {
+			algorithm = spec.KeyAlgorithm
+		}
+		size = int(spec.KeySize)
+	}
+
+	if size == 0 {
+		size = defaultRSAKeySize
+		if algorithm == api.CertificateKeyECDSA {
+			size = defaultECDSAKeySize
+		}
+	}
+	return algorithm, size
+}
+
+// generateKey returns a new private key of the configured algorithm and size
+func (cr *CertificateRequest) generateKey() (crypto.Signer, error) {
+	algorithm, size := certificateKeyType(cr.Certificates)
+	if algorithm == api.CertificateKeyECDSA {
+		curve := elliptic.P256()
+		if size == 384 {
+			curve = elliptic.P384()
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	}
+	return rsa.GenerateKey(rand.Reader, size)
+}
+
+// hasKeyType returns whether the private key has the configured algorithm and size
+func (cr *CertificateRequest) hasKeyType(privKey crypto.Signer) bool {
+	algorithm, size := certificateKeyType(cr.Certificates)
+	switch key := privKey.(type) {
+	case *rsa.PrivateKey:
+		return algorithm == api.CertificateKeyRSA && key.N.BitLen() == size
+	case *ecdsa.PrivateKey:
+		return algorithm == api.CertificateKeyECDSA && key.Curve.Params().BitSize == size
+	}
+	return false
+}
+
+// needsRotation returns whether the valid certificate has to be replaced. A certificate
+// expiring within the hour always is, one within its rotation lead time or with a changed key
+// type only when the rotation is allowed.
+func (cr *CertificateRequest) needsRotation(cert *certificate) bool {
+	if certWillExpireSoon(cert.x509Cert, certExpiryRotationPeriod) {
+		return true
+	}
+	if !cr.RotationAllowed {
+		return false
+	}
+	if certWillExpireSoon(cert.x509Cert, cr.rotationLead()) {
+		cr.Log.Info("Rotating certificate within its rotation lead time",
+			"commonName", cert.x509Cert.Subject.CommonName, "notAfter", cert.x509Cert.NotAfter)
+		return true
+	}
+	// keys of the default type are kept without settings to not rotate the certificates
+	// of a cluster configured with a different key type
+	if cr.Certificates != nil && !cr.hasKeyType(cert.privKey) {
+		cr.Log.Info("Rotating certificate to change its key type", "commonName", cert.x509Cert.Subject.CommonName)
+		return true
+	}
+	return false
+}
+
+// needsCARotation returns whether the phased rotation of the valid CA has to start. Within
+// its rotation lead time it starts regardless of the maintenance windows, otherwise the CA
+// could expire before all phases were rolled out.
+func (cr *CertificateRequest) needsCARotation(ca *certificate) bool {
+	if certWillExpireSoon(ca.x509Cert, cr.caRotationLead()) {
+		cr.Log.Info("Rotating the CA within its rotation lead time",
+			"notAfter", ca.x509Cert.NotAfter, "rotationLead", cr.caRotationLead())
+		return true
+	}
+	return cr.needsRotation(ca)
+}
+
+// recordCertificate adds the expiry of the certificate to the status of the request
+func (cr *CertificateRequest) recordCertificate(secretName string, cert *certificate) {
+	if cert.x509Cert == nil {
+		return
+	}
+
+	// certificates store their expiry in seconds
+	notAfter := cert.x509Cert.NotAfter.UTC().Truncate(time.Second)
+	status := api.CertificateStatus{
+		Secret:       secretName,
+		CommonName:   cert.x509Cert.Subject.CommonName,
+		NotAfter:     metav1.NewTime(notAfter),
+		RotationTime: metav1.NewTime(notAfter.Add(-cr.rotationLead())),
+	}
+
+	// the CA is ensured for every secret
+	for i, recorded := range cr.Status {
+		if recorded.Secret == status.Secret && recorded.CommonName == status.CommonName {
+			cr.Status[i] = status
+			return
+		}
+	}
+
+	cr.Status = append(cr.Status, status)
+	sort.Slice(cr.Status, func(i, j int) bool {
+		if cr.Status[i].Secret != cr.Status[j].Secret {
+			return cr.Status[i].Secret < cr.Status[j].Secret
+		}
+		return cr.Status[i].CommonName < cr.Status[j].CommonName
+	})
+}
+
+// setCertificateMetrics sets the expiry metrics of the certificates recorded in the status
+// of the request, the series of the certificates no longer recorded are deleted
+func (cr *CertificateRequest) setCertificateMetrics() {
+	expiries := make([]metrics.CertificateExpiry, 0, len(cr.Status))
+	for _, status := range cr.Status {
+		expiries = append(expiries, metrics.CertificateExpiry{
+			Secret:       status.Secret,
+			Component:    cr.certificateComponent(status.Secret),
+			CommonName:   status.CommonName,
+			NotAfter:     status.NotAfter.Time,
+			RotationTime: status.RotationTime.Time,
+		})
+	}
+	metrics.SetCertificateExpiries(cr.Namespace, cr.ClusterName, expiries)
+}
+
+// certificateComponent returns the component using the certificates of the secret
+func (cr *CertificateRequest) certificateComponent(secretName string) string {
+	switch secretName {
+	case cr.getSigningSecretName():
+		return certComponentCA
+	case cr.ClusterName:
+		return certComponentElasticsearch
+	case kibanaSecretName, getKibanaProxySecretName(kibanaSecretName):
+		return certComponentKibana
+	}
+	return certComponentClient
+}
+
+// logFailure logs the failed generation of the certificates of the secret and counts it
+func (cr *CertificateRequest) logFailure(err error, secretName, msg string) {
+	cr.Log.Error(err, msg, "secret", secretName)
+	metrics.IncrementCertificateFailureCounter(cr.Namespace, cr.ClusterName, secretName, cr.certificateComponent(secretName))
+}
+
+// subjectKeyID returns the SHA-1 hash of the encoded public key
+func subjectKeyID(pubKey crypto.PublicKey) []byte {
+	var keyBytes []byte
+	switch key := pubKey.(type) {
+	case *rsa.PublicKey:
+		keyBytes = x509.MarshalPKCS1PublicKey(key)
+	case *ecdsa.PublicKey:
+		keyBytes = elliptic.Marshal(key.Curve, key.X, key.Y)
+	}
+	sum := sha1.Sum(keyBytes)
+	return sum[:]
+}
+
+// signatureAlgorithm returns the algorithm of the signatures of the CA key
+func signatureAlgorithm(caKey crypto.Signer) x509.SignatureAlgorithm {
+	key, ok := caKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return x509.SHA512WithRSA
+	}
+	if key.Curve.Params().BitSize == 384 {
+		return x509.ECDSAWithSHA384
+	}
+	return x509.ECDSAWithSHA256
+}
+
+// persistCertificateStatus writes the certificate status if it changed
+func (er *ElasticsearchRequest) persistCertificateStatus(status []api.CertificateStatus) error {
+	cluster := er.cluster
+	if equality.Semantic.DeepEqual(status, cluster.Status.Certificates) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.Certificates = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update certificate status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	cmcertificate "github.com/openshift/elasticsearch-operator/internal/manifests/certificate"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+)
+
+const (
+	certManagerCAName      = "ca.crt"
+	certManagerDefaultKind = "Issuer"
+	certManagerGroup       = "cert-manager.io"
+)
+
+// CertificateProvider issues the certificates of the cluster and its components to the
+// secrets they expect
+type CertificateProvider interface {
+	GenerateElasticsearchCerts(clusterName string)
+	GenerateKibanaCerts(componentName string)
+	GenerateComponentCerts(secretName, cn string)
+}
+
+// Provider returns the provider issuing the certificates of the request, the CA of the
+// operator or cert-manager
+func (cr *CertificateRequest) Provider() CertificateProvider {
+	if cr.Certificates != nil && cr.Certificates.CertManager != nil {
+		return &certManagerRequest{CertificateRequest: cr}
+	}
+	return cr
+}
+
+// certManagerRequest issues the certificates of the request with cert-manager. A cert-manager
+// certificate is created for every certificate and the secrets of the components are
+// assembled from the secrets issued by cert-manager once all of their certificates are.
+type certManagerRequest struct {
+	*CertificateRequest
+}
+
+func (cm *certManagerRequest) GenerateElasticsearchCerts(clusterName string) {
+	certMutex.Lock()
+	defer certMutex.Unlock()
+
+	certs, ca, ok := cm.ensureCertificates(clusterName, esAdminComponentName, esComponentName, esInternalComponentName)
+	if !ok {
+		return
+	}
+	adminCert, elasticsearchCert, loggingESCert := certs[esAdminComponentName], certs[esComponentName], certs[esInternalComponentName]
+
+	secretData := map[string][]byte{
+		esComponentKeyName:  elasticsearchCert.key,
+		esComponentCertName: elasticsearchCert.cert,
+		esInternalKeyName:   loggingESCert.key,
+		esInternalCertname:  loggingESCert.cert,
+		esAdminKeyName:      adminCert.key,
+		esAdminCertName:     adminCert.cert,
+		esAdminCAName:       appendPEM(append([]byte{}, ca...), cm.TrustedCAs),
+	}
+
+	current, err := secret.Get(context.TODO(), cm.K8sClient, client.ObjectKey{Name: clusterName, Namespace: cm.Namespace})
+	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
+		cm.logFailure(err, clusterName, "unable to get secret")
+		return
+	}
+	if err := cm.writeClusterSecret(clusterName, current.Data, secretData); err != nil {
+		cm.logFailure(err, clusterName, "Unable to create secret for elasticsearch component")
+		return
+	}
+	cm.recordCertificate(clusterName, adminCert)
+	cm.recordCertificate(clusterName, elasticsearchCert)
+	cm.recordCertificate(clusterName, loggingESCert)
+}
+
+func (cm *certManagerRequest) GenerateKibanaCerts(componentName string) {
+	certMutex.Lock()
+	defer certMutex.Unlock()
+
+	certs, ca, ok := cm.ensureCertificates(kibanaSecretName, kibanaComponentName, kibanaInternalComponentName)
+	if !ok {
+		return
+	}
+	kibanaCert, kibanaProxyCert := certs[kibanaComponentName], certs[kibanaInternalComponentName]
+
+	kibanaSecretData := map[string][]byte{
+		kibanaComponentKeyName:  kibanaCert.key,
+		kibanaComponentCertName: kibanaCert.cert,
+		kibanaComponentCAName:   ca,
+	}
+
+	if err := CreateOrUpdateSecretWithOwnerRef(kibanaSecretName, cm.Namespace, kibanaSecretData, cm.K8sClient, cm.OwnerRef); err != nil {
+		cm.logFailure(err, kibanaSecretName, "Unable to create secret for kibana component")
+		return
+	}
+	cm.recordCertificate(kibanaSecretName, kibanaCert)
+
+	key := client.ObjectKey{Name: getKibanaProxySecretName(kibanaSecretName), Namespace: cm.Namespace}
+	s, err := secret.Get(context.TODO(), cm.K8sClient, key)
+	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
+		cm.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "unable to get secret")
+		return
+	}
+
+	kibanaProxySessionSecret := &sessionSecret{}
+	kibanaProxySessionSecret.secret = s.Data[kibanaInternalSessionSecretName]
+
+	if err = ensureSessionSecret(kibanaSessionSecretLength, allowedRunes, kibanaProxySessionSecret); err != nil {
+		cm.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "Unable to ensure session secret for kibana proxy")
+		return
+	}
+
+	secretData := map[string][]byte{
+		kibanaInternalSessionSecretName: kibanaProxySessionSecret.secret,
+		kibanaInternalCertName:          kibanaProxyCert.cert,
+		kibanaInternalKeyName:           kibanaProxyCert.key,
+	}
+
+	if err = CreateOrUpdateSecretWithOwnerRef(getKibanaProxySecretName(kibanaSecretName), cm.Namespace, secretData, cm.K8sClient, cm.OwnerRef); err != nil {
+		cm.logFailure(err, getKibanaProxySecretName(kibanaSecretName), "Unable to create secret for kibana-proxy")
+		return
+	}
+	cm.recordCertificate(getKibanaProxySecretName(kibanaSecretName), kibanaProxyCert)
+}
+
+func (cm *certManagerRequest) GenerateComponentCerts(secretName, cn string) {
+	certMutex.Lock()
+	defer certMutex.Unlock()
+
+	certs, ca, ok := cm.ensureCertificates(secretName, cn)
+	if !ok {
+		return
+	}
+	componentCert := certs[cn]
+
+	componentSecretData := map[string][]byte{
+		componentKeyName:  componentCert.key,
+		componentCertName: componentCert.cert,
+		componentCAName:   ca,
+	}
+
+	if err := CreateOrUpdateSecretWithOwnerRef(secretName, cm.Namespace, componentSecretData, cm.K8sClient, cm.OwnerRef); err != nil {
+		cm.logFailure(err, secretName, "Unable to create secret for component")
+		return
+	}
+	cm.recordCertificate(secretName, componentCert)
+}
+
+// ensureCertificates ensures the cert-manager certificates of the common names in the secret
+// and returns the issued certificates with the CA of the issuer once all of them are issued
+func (cm *certManagerRequest) ensureCertificates(secretName string, commonNames ...string) (map[string]*certificate, []byte, bool) {
+	certs := map[string]*certificate{}
+	var ca []byte
+	issued := true
+	for _, commonName := range commonNames {
+		cert, issuerCA, err := cm.ensureCertificate(commonName)
+		if err != nil {
+			cm.logFailure(err, secretName, "Unable to ensure cert-manager certificate")
+			return nil, nil, false
+		}
+		if cert == nil {
+			issued = false
+			continue
+		}
+		certs[commonName] = cert
+		if ca == nil {
+			ca = issuerCA
+		}
+	}
+	return certs, ca, issued
+}
+
+// ensureCertificate ensures the cert-manager certificate of the common name and returns the
+// issued certificate with the CA of the issuer, or nil while it is not issued yet
+func (cm *certManagerRequest) ensureCertificate(commonName string) (*certificate, []byte, error) {
+	name := cm.getCertManagerCertificateName(commonName)
+	desired := cm.newCertManagerCertificate(name, commonName)
+
+	if err := cmcertificate.CreateOrUpdate(context.TODO(), cm.K8sClient, desired, cmcertificate.SpecEqual, cmcertificate.MutateSpecOnly); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to create cert-manager certificate", "certificate", name)
+	}
+
+	key := client.ObjectKey{Name: name, Namespace: cm.Namespace}
+	s, err := secret.Get(context.TODO(), cm.K8sClient, key)
+	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
+		return nil, nil, kverrors.Wrap(err, "failed to get the secret issued by cert-manager", "certificate", name)
+	}
+
+	cert := &certificate{}
+	if err := unmarshalCert(s.Data[componentCertName], s.Data[componentKeyName], cert); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to unmarshal cert issued by cert-manager", "certificate", name)
+	}
+	if cert.x509Cert == nil {
+		cm.Log.Info("Waiting for cert-manager to issue the certificate", "certificate", name)
+		return nil, nil, nil
+	}
+
+	return cert, s.Data[certManagerCAName], nil
+}
+
+// getCertManagerCertificateName returns the name of the cert-manager certificate and of the
+// secret it is issued to
+func (cm *certManagerRequest) getCertManagerCertificateName(commonName string) string {
+	return fmt.Sprintf("%s-%s", cm.ClusterName, strings.ReplaceAll(commonName, ".", "-"))
+}
+
+func (cm *certManagerRequest) newCertManagerCertificate(name, commonName string) *unstructured.Unstructured {
+	issuerRef := cm.Certificates.CertManager.IssuerRef
+	kind, group := issuerRef.Kind, issuerRef.Group
+	if kind == "" {
+		kind = certManagerDefaultKind
+	}
+	if group == "" {
+		group = certManagerGroup
+	}
+
+	ext := cm.Extensions[commonName]
+	ips := make([]string, 0, len(ext.ips))
+	for _, ip := range ext.ips {
+		ips = append(ips, ip.String())
+	}
+
+	algorithm, size := certificateKeyType(cm.Certificates)
+	cert := cmcertificate.New(name, cm.Namespace, name, commonName).
+		WithSubject(certOrganization, componentOrganizationUnit).
+		WithDNSNames(ext.dns).
+		WithIPAddresses(ips).
+		WithUsages("digital signature", "key encipherment", "server auth", "client auth").
+		WithIssuerRef(issuerRef.Name, kind, group).
+		WithDuration(cm.lifetime(), cm.rotationLead()).
+		WithPrivateKey(string(algorithm), size).
+		Build()
+	cert.SetOwnerReferences([]metav1.OwnerReference{cm.OwnerRef})
+	return cert
+}
+
+// usesCertManager returns whether the certificates of the cluster are issued by cert-manager
+func usesCertManager(cluster *api.Elasticsearch) bool {
+	return cluster.Spec.Certificates != nil && cluster.Spec.Certificates.CertManager != nil
+}
+
+// certManagerNodesDN are the distinguished names of the node certificates, which cert-manager
+// cannot mark as such with the 1.2.3.4.5.5 registered ID
+var certManagerNodesDN = []string{fmt.Sprintf("CN=%s,OU=%s,O=%s", esComponentName, componentOrganizationUnit[0], certOrganization[0])}
@@ -0,0 +1,115 @@
+package elasticsearch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	cmcertificate "github.com/openshift/elasticsearch-operator/internal/manifests/certificate"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+)
+
+// issueCertManagerSecret creates the secret cert-manager issues for the certificate
+func issueCertManagerSecret(t *testing.T, cr *CertificateRequest, ca *certCA, name, commonName string) {
+	cert := &certificate{}
+	if err := cr.generateCert(commonName, cert, ca); err != nil {
+		t.Fatalf("failed to generate the certificate: %v", err)
+	}
+	s := secret.New(name, cr.Namespace, map[string][]byte{
+		componentCertName: cert.cert,
+		componentKeyName:  cert.key,
+		certManagerCAName: ca.cert,
+	})
+	if err := cr.K8sClient.Create(context.TODO(), s); err != nil {
+		t.Fatalf("failed to create the secret: %v", err)
+	}
+}
+
+func TestCertManagerGenerateElasticsearchCerts(t *testing.T) {
+	cr := newTestCertificateRequest(&api.ElasticsearchCertificatesSpec{
+		KeyAlgorithm: api.CertificateKeyECDSA,
+		CertManager: &api.CertManagerSpec{
+			IssuerRef: api.CertManagerIssuerReference{Name: "security", Kind: "ClusterIssuer"},
+		},
+	})
+	provider := cr.Provider()
+	if _, ok := provider.(*certManagerRequest); !ok {
+		t.Fatalf("Exp. the cert-manager provider but got %T", provider)
+	}
+
+	provider.GenerateElasticsearchCerts("elasticsearch")
+
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(cmcertificate.GroupVersionKind)
+	key := client.ObjectKey{Name: "elasticsearch-logging-es", Namespace: cr.Namespace}
+	if err := cr.K8sClient.Get(context.TODO(), key, cert); err != nil {
+		t.Fatalf("Exp. the cert-manager certificate of logging-es to be created: %v", err)
+	}
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	if exp := cr.Extensions[esInternalComponentName].dns; !reflect.DeepEqual(dnsNames, exp) {
+		t.Errorf("Exp. the DNS names %v but got %v", exp, dnsNames)
+	}
+	issuerRef, _, _ := unstructured.NestedStringMap(cert.Object, "spec", "issuerRef")
+	if exp := map[string]string{"name": "security", "kind": "ClusterIssuer", "group": "cert-manager.io"}; !reflect.DeepEqual(issuerRef, exp) {
+		t.Errorf("Exp. the issuer %v but got %v", exp, issuerRef)
+	}
+	algorithm, _, _ := unstructured.NestedString(cert.Object, "spec", "privateKey", "algorithm")
+	if algorithm != "ECDSA" {
+		t.Errorf("Exp. ECDSA keys but got %q", algorithm)
+	}
+
+	// the secret of the cluster waits for all certificates to be issued
+	esKey := client.ObjectKey{Name: "elasticsearch", Namespace: cr.Namespace}
+	if err := cr.K8sClient.Get(context.TODO(), esKey, &v1.Secret{}); err == nil {
+		t.Fatal("Exp. no secret before cert-manager issued the certificates")
+	}
+
+	ca, err := cr.genCA()
+	if err != nil {
+		t.Fatalf("failed to generate the CA: %v", err)
+	}
+	issueCertManagerSecret(t, cr, ca, "elasticsearch-system-admin", esAdminComponentName)
+	issueCertManagerSecret(t, cr, ca, "elasticsearch-elasticsearch", esComponentName)
+	issueCertManagerSecret(t, cr, ca, "elasticsearch-logging-es", esInternalComponentName)
+
+	provider.GenerateElasticsearchCerts("elasticsearch")
+
+	s, err := secret.Get(context.TODO(), cr.K8sClient, esKey)
+	if err != nil {
+		t.Fatalf("Exp. the secret of the cluster to be assembled: %v", err)
+	}
+	if !reflect.DeepEqual(s.Data[esAdminCAName], ca.cert) {
+		t.Errorf("Exp. the CA of the issuer to be trusted")
+	}
+	loggingES := &certificate{}
+	if err := unmarshalCert(s.Data[esInternalCertname], s.Data[esInternalKeyName], loggingES); err != nil || loggingES.x509Cert == nil {
+		t.Fatalf("Exp. the logging-es certificate issued by cert-manager: %v", err)
+	}
+	if loggingES.x509Cert.Subject.CommonName != esInternalComponentName {
+		t.Errorf("Exp. the common name %s but got %s", esInternalComponentName, loggingES.x509Cert.Subject.CommonName)
+	}
+	if len(cr.Status) != 3 {
+		t.Errorf("Exp. the expiry of the 3 certificates in the status but got %v", cr.Status)
+	}
+}
+
+func TestNewFlavorConfigNodesDN(t *testing.T) {
+	cluster := &api.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch"}}
+	if config := newFlavorConfig(cluster); config.NodesDN != nil {
+		t.Errorf("Exp. the nodes identified by their registered ID but got %v", config.NodesDN)
+	}
+
+	cluster.Spec.Certificates = &api.ElasticsearchCertificatesSpec{
+		CertManager: &api.CertManagerSpec{IssuerRef: api.CertManagerIssuerReference{Name: "security"}},
+	}
+	exp := []string{"CN=elasticsearch,OU=OpenShift,O=Logging"}
+	if config := newFlavorConfig(cluster); !reflect.DeepEqual(config.NodesDN, exp) {
+		t.Errorf("Exp. the nodes DN %v but got %v", exp, config.NodesDN)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/metrics"
 
 	"github.com/openshift/elasticsearch-operator/internal/utils"
-	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -19,8 +18,6 @@ import (
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 )
 
-const expectedMinVersion = "6.0"
-
 var (
 	wrongConfig bool
 	nodes       map[string][]NodeTypeInterface
@@ -75,7 +72,19 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 
 	certRestartNodes := er.getScheduledCertRedeployNodes()
 	stillRecovering := containsClusterCondition(api.Recovering, v1.ConditionTrue, &er.cluster.Status)
-	if len(certRestartNodes) > 0 || stillRecovering {
+	rollingRestart := er.cluster.Status.CARotation != nil || er.cluster.Status.TrustedCAsRollout
+	if rollingRestart && len(certRestartNodes) > 0 && !stillRecovering {
+		// during a CA rotation the nodes trust the CAs of the previous and the current phase,
+		// and a change of the trusted CAs keeps the nodes trusting each other, so they are
+		// restarted one at a time
+		if err := er.PerformRollingRestart(certRestartNodes); err != nil {
+			er.ll.Error(err, "unable to complete rolling restart for the changed CAs")
+			return er.UpdateClusterStatus()
+		}
+
+		metrics.IncrementRestartCounterRolling()
+		_ = er.UpdateClusterStatus()
+	} else if len(certRestartNodes) > 0 || stillRecovering {
 		if err := er.PerformFullClusterCertRestart(certRestartNodes); err != nil {
 			er.ll.Error(err, "unable to complete full cluster restart")
 			return er.UpdateClusterStatus()
@@ -111,26 +120,33 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 		_ = er.UpdateClusterStatus()
 	}
 
+	// Scheduled upgrades are only started in a maintenance window, unlike the
+	// certificate restarts above which keep the nodes trusting each other
+	maintenanceAllowed := er.checkMaintenanceWindow()
+	if len(scheduledNodes) > 0 && !maintenanceAllowed {
+		er.ll.Info("Deferring the scheduled node upgrades to the next maintenance window",
+			"nodes", len(scheduledNodes), "nextWindow", er.cluster.Status.NextMaintenanceWindow)
+	}
+
 	// We didn't have any in progress, but we have ones scheduled to be updated
-	if len(scheduledNodes) > 0 {
+	if len(scheduledNodes) > 0 && maintenanceAllowed {
 
-		// get the current ES version
-		version, err := esClient.GetLowestClusterVersion()
+		// get the current ES versions
+		versions, err := esClient.GetClusterNodeVersions()
 		if err != nil {
 			// this can be because we couldn't get a valid response from ES
-			er.ll.Error(err, "failed to get LowestClusterVersion")
+			er.ll.Error(err, "failed to get cluster node versions")
 			return er.UpdateClusterStatus()
 		}
 
-		// Skip the error here. This version should have already been
-		// compared in `GetLowestClusterVersion`. So this should already
-		// be a valid version number.
-		versionArray, _ := comparators.Version(version).ToArray()
-		// Skip the error here. This is a controlled number. It should always pass.
-		minVersionArray, _ := comparators.Version(expectedMinVersion).ToArray()
+		fullClusterUpdate, err := requiresFullClusterUpdate(er.cluster, versions)
+		if err != nil {
+			er.ll.Error(err, "failed to compare cluster node versions")
+			return er.UpdateClusterStatus()
+		}
 
-		// if it is < what we expect (6.0) then do full cluster update:
-		if comparators.CompareVersionArrays(versionArray, minVersionArray) > 0 {
+		// if a version is < what we expect (6.0, or 6.8 to change the flavor) then do full cluster update:
+		if fullClusterUpdate {
 			// perform a full cluster update
 			if err := er.PerformFullClusterUpdate(scheduledNodes); err != nil {
 				er.ll.Error(err, "failed to perform full cluster update")
@@ -170,6 +186,18 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 			}
 		}
 
+		// expand the volumes of the nodes whose storage size was increased
+		er.expandVolumes()
+
+		// include the removed nodes that left the cluster in shard allocation again
+		er.includeRemovedNodes()
+
+		// drain and remove the data nodes of a lowered node count
+		er.scaleDownDataNodes()
+
+		// replace the data nodes whose storage class name was changed
+		er.migrateStorageClasses()
+
 		// ensure that MinMasters is (n / 2 + 1)
 		er.updateMinMasters()
 
@@ -198,6 +226,12 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 
 			// check if nodes are below watermark threshold and unblock indices if it's marked as read only
 			er.checkWatermarkAndUnblockIndices()
+
+			er.updateRemoteClusters()
+
+			er.updateAuthentication()
+
+			er.updateZoneAwareness()
 		}
 	}
 
@@ -240,11 +240,23 @@ func (er *ElasticsearchRequest) PerformRollingUpdate(nodes []NodeTypeInterface)
 	return nil
 }
 
+// PerformRollingRestart restarts the nodes one at a time. The restarted nodes are no longer
+// scheduled for a cert redeploy as they loaded the current secret.
 func (er *ElasticsearchRequest) PerformRollingRestart(nodes []NodeTypeInterface) error {
 	for _, node := range nodes {
 		if err := er.PerformNodeRestart(node); err != nil {
 			return err
 		}
+
+		clusterStatus := er.cluster.Status.DeepCopy()
+		index, nodeStatus := getNodeStatus(node.name(), clusterStatus)
+		if index == NotFoundIndex || nodeStatus.UpgradeStatus.ScheduledForCertRedeploy != v1.ConditionTrue {
+			continue
+		}
+		nodeStatus.UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionFalse
+		if err := er.setNodeStatus(node, nodeStatus, clusterStatus); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -71,6 +71,8 @@ func getNodeRoleMap(node api.ElasticsearchNode) map[api.ElasticsearchNodeRole]bo
 	isClient := false
 	isData := false
 	isMaster := false
+	isIngest := false
+	isCoordinating := false
 
 	for _, role := range node.Roles {
 		if role == api.ElasticsearchRoleClient {
@@ -84,11 +86,23 @@ func getNodeRoleMap(node api.ElasticsearchNode) map[api.ElasticsearchNodeRole]bo
 		if role == api.ElasticsearchRoleMaster {
 			isMaster = true
 		}
+
+		if role == api.ElasticsearchRoleIngest {
+			isIngest = true
+		}
+
+		// coordinating-only nodes serve the REST API like client nodes
+		if role == api.ElasticsearchRoleCoordinating {
+			isClient = true
+			isCoordinating = true
+		}
 	}
 	return map[api.ElasticsearchNodeRole]bool{
-		api.ElasticsearchRoleClient: isClient,
-		api.ElasticsearchRoleData:   isData,
-		api.ElasticsearchRoleMaster: isMaster,
+		api.ElasticsearchRoleClient:       isClient,
+		api.ElasticsearchRoleData:         isData,
+		api.ElasticsearchRoleMaster:       isMaster,
+		api.ElasticsearchRoleIngest:       isIngest,
+		api.ElasticsearchRoleCoordinating: isCoordinating,
 	}
 }
 
@@ -112,6 +126,26 @@ func isDataNode(node api.ElasticsearchNode) bool {
 	return false
 }
 
+func isIngestNode(node api.ElasticsearchNode) bool {
+	for _, role := range node.Roles {
+		if role == api.ElasticsearchRoleIngest {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isCoordinatingNode(node api.ElasticsearchNode) bool {
+	for _, role := range node.Roles {
+		if role == api.ElasticsearchRoleCoordinating {
+			return true
+		}
+	}
+
+	return false
+}
+
 func newAffinity(roleMap map[api.ElasticsearchNodeRole]bool) *v1.Affinity {
 	labelSelectorReqs := []metav1.LabelSelectorRequirement{}
 	if roleMap[api.ElasticsearchRoleClient] {
@@ -338,7 +372,7 @@ func newEnvVars(nodeName, clusterName, instanceRAM string, roleMap map[api.Elast
 
 // TODO: add isChanged check for labels and label selector
 func newLabels(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRole]bool) map[string]string {
-	return map[string]string{
+	labels := map[string]string{
 		"es-node-client": strconv.FormatBool(roleMap[api.ElasticsearchRoleClient]),
 		"es-node-data":   strconv.FormatBool(roleMap[api.ElasticsearchRoleData]),
 		"es-node-master": strconv.FormatBool(roleMap[api.ElasticsearchRoleMaster]),
@@ -346,8 +380,19 @@ func newLabels(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRo
 		"component":      "elasticsearch",
 		"node-name":      nodeName,
 	}
+	// the labels of the ingest and coordinating roles are only set on the nodes requesting
+	// them, so that the pods of existing nodes are not changed
+	if roleMap[api.ElasticsearchRoleIngest] {
+		labels["es-node-ingest"] = "true"
+	}
+	if roleMap[api.ElasticsearchRoleCoordinating] {
+		labels["es-node-coordinating"] = "true"
+	}
+	return labels
 }
 
+// newLabelSelector must not change for existing nodes, so it does not include the
+// ingest and coordinating roles of the labels
 func newLabelSelector(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRole]bool) map[string]string {
 	return map[string]string{
 		"es-node-client": strconv.FormatBool(roleMap[api.ElasticsearchRoleClient]),
@@ -406,10 +451,12 @@ func newPodTemplateSpec(ctx context.Context, logger logr.Logger, nodeName, clust
 }
 
 // createUpdatablePodTemplateSpec creates a pod template from a copy of the update with
-// some aspects of the current
+// some aspects of the current. Only the snapshot and config volumes are taken from the update.
 func createUpdatablePodTemplateSpec(current, desired v1.PodTemplateSpec) v1.PodTemplateSpec {
 	desiredCopy := desired
-	desiredCopy.Spec.Volumes = current.Spec.Volumes
+	desiredCopy.Spec.Volumes = syncSnapshotVolume(current.Spec.Volumes, desired.Spec.Volumes)
+	desiredCopy.Spec.Volumes = syncConfigVolume(desiredCopy.Spec.Volumes, desired.Spec.Volumes)
+	desiredCopy.Spec.Volumes = syncZoneVolume(desiredCopy.Spec.Volumes, desired.Spec.Volumes)
 
 	return desiredCopy
 }
@@ -565,6 +565,110 @@ func TestPodSpecHasTaintTolerations(t *testing.T) {
 	}
 }
 
+func TestSnapshotVolume(t *testing.T) {
+	podTemplate := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	snapshot := &api.SnapshotPolicySpec{
+		Repository: api.SnapshotRepositorySpec{
+			Name: "backups",
+			Type: api.SnapshotRepositoryTypeFS,
+			FS:   &api.SnapshotFSRepositorySpec{ClaimName: "snapshots"},
+		},
+	}
+	addSnapshotVolume(&podTemplate, snapshot)
+
+	expectedMount := v1.VolumeMount{Name: "elasticsearch-snapshots", MountPath: "/elasticsearch/snapshots"}
+	mounts := podTemplate.Spec.Containers[0].VolumeMounts
+	if diff := cmp.Diff(mounts[len(mounts)-1], expectedMount); diff != "" {
+		t.Errorf("snapshot volume mount error: %s", diff)
+	}
+	for _, mount := range podTemplate.Spec.Containers[1].VolumeMounts {
+		if mount.Name == "elasticsearch-snapshots" {
+			t.Errorf("Exp. the snapshot volume to be mounted to the elasticsearch container only")
+		}
+	}
+
+	current := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	current.Spec.Volumes[1].VolumeSource = v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+	updated := createUpdatablePodTemplateSpec(current, podTemplate)
+
+	volumes := updated.Spec.Volumes
+	if len(volumes) != len(current.Spec.Volumes)+1 {
+		t.Fatalf("Exp. the snapshot volume to be added to the current volumes but was %v", volumes)
+	}
+	if volumes[1].EmptyDir == nil {
+		t.Errorf("Exp. the current storage volume to be kept but was %v", volumes[1])
+	}
+	if claim := volumes[len(volumes)-1].PersistentVolumeClaim; claim == nil || claim.ClaimName != "snapshots" {
+		t.Errorf("Exp. the snapshot volume to use claim snapshots but was %v", volumes[len(volumes)-1])
+	}
+
+	removed := createUpdatablePodTemplateSpec(updated, current)
+	if len(removed.Spec.Volumes) != len(current.Spec.Volumes) {
+		t.Errorf("Exp. the snapshot volume to be removed but was %v", removed.Spec.Volumes)
+	}
+}
+
+func TestSnapshotKeystore(t *testing.T) {
+	snapshot := &api.SnapshotPolicySpec{
+		Repository: api.SnapshotRepositorySpec{
+			Name: "backups",
+			Type: api.SnapshotRepositoryTypeS3,
+			S3: &api.SnapshotS3RepositorySpec{
+				Bucket: "logs",
+				Secret: v1.LocalObjectReference{Name: "s3-credentials"},
+			},
+		},
+	}
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "openshift-logging"},
+		Data: map[string][]byte{
+			SnapshotAccessKeyIDKey:     []byte("key"),
+			SnapshotSecretAccessKeyKey: []byte("secret"),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(credentials).Build()
+
+	podTemplate := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addSnapshotKeystore(&podTemplate, "openshift-logging", snapshot, k8sClient)
+
+	if len(podTemplate.Spec.InitContainers) != 1 {
+		t.Fatalf("Exp. an init container creating the keystore but got %v", podTemplate.Spec.InitContainers)
+	}
+	expectedMount := v1.VolumeMount{Name: keystoreVolumeName, MountPath: "/etc/elasticsearch/elasticsearch.keystore", SubPath: "elasticsearch.keystore", ReadOnly: true}
+	mounts := podTemplate.Spec.Containers[0].VolumeMounts
+	if diff := cmp.Diff(mounts[len(mounts)-1], expectedMount); diff != "" {
+		t.Errorf("keystore volume mount error: %s", diff)
+	}
+
+	hash := func(template v1.PodTemplateSpec) string {
+		for _, env := range template.Spec.Containers[0].Env {
+			if env.Name == snapshotCredentialsHashEnvVar {
+				return env.Value
+			}
+		}
+		return ""
+	}
+	if hash(podTemplate) == "" {
+		t.Fatalf("Exp. the elasticsearch container to have the %s env var", snapshotCredentialsHashEnvVar)
+	}
+
+	credentials.Data[SnapshotSecretAccessKeyKey] = []byte("rotated")
+	if err := k8sClient.Update(context.TODO(), credentials); err != nil {
+		t.Fatalf("failed to update the credentials: %v", err)
+	}
+	rotated := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addSnapshotKeystore(&rotated, "openshift-logging", snapshot, k8sClient)
+	if hash(rotated) == hash(podTemplate) {
+		t.Errorf("Exp. the %s env var to change with the credentials", snapshotCredentialsHashEnvVar)
+	}
+
+	current := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	updated := createUpdatablePodTemplateSpec(current, podTemplate)
+	if len(updated.Spec.Volumes) != len(current.Spec.Volumes)+2 {
+		t.Errorf("Exp. the credentials and keystore volumes to be added but was %v", updated.Spec.Volumes)
+	}
+}
+
 func TestElasticSearchSecurityContext(t *testing.T) {
 	podTemplate := newPodTemplateSpec(context.Background(), log.NewLogger("common-testing"), "test-node-name", "test-cluster-name", "test-namespace-name", api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, LogConfig{})
 
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"fmt"
 	"html/template"
 	"io"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/configmap"
 	v1 "k8s.io/api/core/v1"
 )
@@ -27,12 +30,20 @@ type esYmlStruct struct {
 	NodeQuorum           string
 	RecoverExpectedNodes string
 	SystemCallFilter     string
+	PathRepo             string
+	S3Endpoint           string
+	S3Protocol           string
+	MaxHeaderSize        template.HTML
+	ESConfig             []esConfigSetting
+	Flavor               flavorConfig
 }
 
 type log4j2PropertiesStruct struct {
 	RootLogger       string
 	LogLevel         string
 	SecurityLogLevel string
+	LoggerPrefix     string
+	SecurityLogger   string
 }
 
 type indexSettingsStruct struct {
@@ -53,6 +64,14 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() error {
 
 	logConfig := getLogConfig(dpl.GetAnnotations())
 
+	// a new cluster is bootstrapped from the names of its master nodes
+	if needsBootstrap(dpl) {
+		if err := er.recoverOrphanedCluster(); err != nil {
+			return err
+		}
+		er.setUUIDs()
+	}
+
 	cm := newConfigMap(
 		dpl.Name,
 		dpl.Namespace,
@@ -65,6 +84,9 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() error {
 		strconv.Itoa(CalculateReplicaCount(dpl)),
 		strconv.FormatBool(runtime.GOARCH == "amd64"),
 		logConfig,
+		dpl.Spec.Snapshot,
+		esConfigFiles(dpl),
+		newFlavorConfig(dpl),
 	)
 
 	dpl.AddOwnerRefTo(cm)
@@ -101,19 +123,64 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() error {
 		)
 	}
 
+	if err := er.updateInvalidESConfigCondition(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig) (map[string]string, error) {
+// updateInvalidESConfigCondition reports the esConfig settings rejected because the
+// operator owns them
+func (er *ElasticsearchRequest) updateInvalidESConfigCondition() error {
+	value := v1.ConditionFalse
+	message := ""
+	if rejected := rejectedESConfig(er.cluster); len(rejected) > 0 {
+		value = v1.ConditionTrue
+		message = fmt.Sprintf("The following settings are invalid or owned by the operator and are ignored: %s", strings.Join(rejected, ", "))
+	}
+
+	return updateConditionWithRetry(
+		er.cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			reason := ""
+			if value == v1.ConditionTrue {
+				reason = "Invalid Settings"
+			}
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidESConfig,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		er.client,
+	)
+}
+
+func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig, snapshot *api.SnapshotPolicySpec, esConfigs map[string]map[string]string, flavor flavorConfig) (map[string]string, error) {
 	data := map[string]string{}
 	buf := &bytes.Buffer{}
-	if err := renderEsYml(buf, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter); err != nil {
+	if err := renderEsYml(buf, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter, snapshot, esConfigs[esConfig], flavor); err != nil {
 		return data, err
 	}
 	data[esConfig] = buf.String()
 
+	// nodes with settings of their own get an elasticsearch.yml of their own
+	for name, config := range esConfigs {
+		if name == esConfig {
+			continue
+		}
+		buf = &bytes.Buffer{}
+		if err := renderEsYml(buf, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter, snapshot, config, flavor); err != nil {
+			return data, err
+		}
+		data[name] = buf.String()
+	}
+
 	buf = &bytes.Buffer{}
-	if err := renderLog4j2Properties(buf, logConfig); err != nil {
+	if err := renderLog4j2Properties(buf, logConfig, flavor); err != nil {
 		return data, err
 	}
 	data[log4jConfig] = buf.String()
@@ -129,8 +196,8 @@ func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes
 
 // newConfigMap returns a v1.ConfigMap object
 func newConfigMap(configMapName, namespace string, labels map[string]string,
-	kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig) *v1.ConfigMap {
-	data, err := renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter, logConfig)
+	kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig, snapshot *api.SnapshotPolicySpec, esConfigs map[string]map[string]string, flavor flavorConfig) *v1.ConfigMap {
+	data, err := renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter, logConfig, snapshot, esConfigs, flavor)
 	if err != nil {
 		return nil
 	}
@@ -160,13 +227,23 @@ func configMapContentEqual(old, new *v1.ConfigMap) bool {
 		return false
 	}
 
+	// compare the elasticsearch.yml of the nodes with settings of their own
+	if len(old.Data) != len(new.Data) {
+		return false
+	}
+	for key, value := range new.Data {
+		if oldValue, ok := old.Data[key]; !ok || oldValue != value {
+			return false
+		}
+	}
+
 	return true
 }
 
-func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter string) error {
+func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter string, snapshot *api.SnapshotPolicySpec, config map[string]string, flavor flavorConfig) error {
 	t := template.New("elasticsearch.yml")
-	config := esYmlTmpl
-	t, err := t.Parse(config)
+	tmpl := esYmlTmpl
+	t, err := t.Parse(tmpl)
 	if err != nil {
 		return err
 	}
@@ -176,12 +253,27 @@ func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recove
 		NodeQuorum:           nodeQuorum,
 		RecoverExpectedNodes: recoverExpectedNodes,
 		SystemCallFilter:     systemCallFilter,
+		PathRepo:             snapshotPathRepo(snapshot),
+		MaxHeaderSize:        defaultMaxHeaderSize,
+		Flavor:               flavor,
+	}
+	esy.S3Endpoint, esy.S3Protocol = snapshotS3Client(snapshot)
+
+	// the max header size has a default of the operator and can be overridden in place
+	settings := map[string]string{}
+	for key, value := range config {
+		if key == maxHeaderSizeSetting {
+			esy.MaxHeaderSize = template.HTML(strconv.Quote(value))
+			continue
+		}
+		settings[key] = value
 	}
+	esy.ESConfig = newESConfigSettings(settings)
 
 	return t.Execute(w, esy)
 }
 
-func renderLog4j2Properties(w io.Writer, logConfig LogConfig) error {
+func renderLog4j2Properties(w io.Writer, logConfig LogConfig, flavor flavorConfig) error {
 	t := template.New("log4j2.properties")
 	t, err := t.Parse(log4j2PropertiesTmpl)
 	if err != nil {
@@ -192,6 +284,8 @@ func renderLog4j2Properties(w io.Writer, logConfig LogConfig) error {
 		RootLogger:       logConfig.ServerAppender,
 		LogLevel:         logConfig.ServerLoglevel,
 		SecurityLogLevel: logConfig.LogLevel,
+		LoggerPrefix:     flavor.LoggerPrefix,
+		SecurityLogger:   flavor.SecurityLogger,
 	}
 
 	return t.Execute(w, log4jProp)
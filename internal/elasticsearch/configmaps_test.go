@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/test/helpers"
 )
 
@@ -17,7 +18,7 @@ var _ = Describe("configmaps.go", func() {
 		It("should create a well-formed file without error", func() {
 			out := bytes.NewBufferString("")
 			logConfig := LogConfig{"debug", "trace", "mylogger"}
-			if err := renderLog4j2Properties(out, logConfig); err != nil {
+			if err := renderLog4j2Properties(out, logConfig, newFlavorConfig(&api.Elasticsearch{})); err != nil {
 				Fail(fmt.Sprintf("unable to render Log4J properties. %s\r\n", err.Error()))
 			}
 			Expect(out.String()).To(Equal(`
@@ -111,7 +112,7 @@ var _ = Describe("configmaps", func() {
 	Describe("#renderEsYml", func() {
 		It("should produce an elasticsearch.yml for our managed elasticsearch instance", func() {
 			result := &bytes.Buffer{}
-			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false")).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, nil, newFlavorConfig(&api.Elasticsearch{}))).To(BeNil(), "Exp. no errors when rendering the configuration")
 			helpers.ExpectYaml(result.String()).ToEqual(`
 cluster:
   name: ${CLUSTER_NAME}
@@ -178,5 +179,63 @@ opendistro_security:
       truststore_filepath: /etc/elasticsearch/secret/truststore.p12
       truststore_password: tspass`)
 		})
+
+		It("should allow the path of a filesystem snapshot repository", func() {
+			snapshot := &api.SnapshotPolicySpec{
+				Repository: api.SnapshotRepositorySpec{
+					Name: "backups",
+					Type: api.SnapshotRepositoryTypeFS,
+					FS:   &api.SnapshotFSRepositorySpec{ClaimName: "snapshots"},
+				},
+			}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", snapshot, nil, newFlavorConfig(&api.Elasticsearch{}))).To(BeNil())
+			Expect(result.String()).To(ContainSubstring(`
+path:
+  data: /elasticsearch/persistent/${CLUSTER_NAME}/data
+  logs: /elasticsearch/persistent/${CLUSTER_NAME}/logs
+  repo: /elasticsearch/snapshots
+`))
+			Expect(result.String()).ToNot(ContainSubstring("s3.client.default"))
+		})
+
+		It("should configure the s3 client of a S3 compatible snapshot repository", func() {
+			snapshot := &api.SnapshotPolicySpec{
+				Repository: api.SnapshotRepositorySpec{
+					Name: "backups",
+					Type: api.SnapshotRepositoryTypeS3,
+					S3: &api.SnapshotS3RepositorySpec{
+						Bucket:   "logs",
+						Endpoint: "http://minio.storage.svc:9000",
+					},
+				},
+			}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", snapshot, nil, newFlavorConfig(&api.Elasticsearch{}))).To(BeNil())
+			Expect(result.String()).To(ContainSubstring(`
+s3.client.default:
+  endpoint: minio.storage.svc:9000
+  protocol: http
+`))
+			Expect(result.String()).ToNot(ContainSubstring("repo:"))
+		})
+
+		It("should render the additional settings and override the max header size", func() {
+			config := map[string]string{
+				"thread_pool.write.queue_size":     "500",
+				"indices.memory.index_buffer_size": "20%",
+				"http.max_header_size":             "64kb",
+			}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, config, newFlavorConfig(&api.Elasticsearch{}))).To(BeNil())
+			Expect(result.String()).To(ContainSubstring("\nhttp.max_header_size: \"64kb\"\n"))
+			Expect(result.String()).ToNot(ContainSubstring("128kb"))
+			Expect(result.String()).To(HaveSuffix(`
+      truststore_password: tspass
+
+# settings of the esConfig of the custom resource
+indices.memory.index_buffer_size: "20%"
+thread_pool.write.queue_size: "500"`))
+		})
 	})
 })
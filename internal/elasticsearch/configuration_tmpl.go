@@ -18,10 +18,22 @@ action.auto_create_index: "-*-write,+*"
 network:
   publish_host: ${POD_IP}
   bind_host: ["${POD_IP}",_local_]
+{{- if .Flavor.ZenDiscovery}}
 
 discovery.zen:
   ping.unicast.hosts: {{.EsUnicastHost}}
   minimum_master_nodes: {{.NodeQuorum}}
+{{- else}}
+
+discovery.seed_hosts: {{.EsUnicastHost}}
+{{- end}}
+{{- if .Flavor.InitialMasterNodes}}
+
+cluster.initial_master_nodes:
+{{- range .Flavor.InitialMasterNodes}}
+- {{.}}
+{{- end}}
+{{- end}}
 
 gateway:
   recover_after_nodes: {{.NodeQuorum}}
@@ -31,21 +43,39 @@ gateway:
 path:
   data: /elasticsearch/persistent/${CLUSTER_NAME}/data
   logs: /elasticsearch/persistent/${CLUSTER_NAME}/logs
+{{- if .PathRepo}}
+  repo: {{.PathRepo}}
+{{- end}}
+{{- if .S3Endpoint}}
+
+s3.client.default:
+  endpoint: {{.S3Endpoint}}
+  protocol: {{.S3Protocol}}
+{{- end}}
 
 prometheus:
   indices: false
   query.metrics: true
 
 # increase the max header size above 8kb default
-http.max_header_size: 128kb
+http.max_header_size: {{.MaxHeaderSize}}
 
-opendistro_security:
+{{.Flavor.SecurityPrefix}}:
   authcz.admin_dn:
   - CN=system.admin,OU=OpenShift,O=Logging
   - CN=system.admin,OU=Logging,O=OpenShift
+{{- if .Flavor.NodesDN}}
+  nodes_dn:
+{{- range .Flavor.NodesDN}}
+  - {{.}}
+{{- end}}
+{{- end}}
   config_index_name: ".security"
   restapi:
     roles_enabled: ["kibana_server"]
+{{- if .Flavor.SecurityConfigModification}}
+  unsupported.restapi.allow_securityconfig_modification: true
+{{- end}}
   ssl:
     transport:
       enabled: true
@@ -64,16 +94,23 @@ opendistro_security:
       clientauth_mode: OPTIONAL
       truststore_type: PKCS12
       truststore_filepath: /etc/elasticsearch/secret/truststore.p12
-      truststore_password: tspass`
+      truststore_password: tspass
+{{- if .ESConfig}}
+
+# settings of the esConfig of the custom resource
+{{- range .ESConfig}}
+{{.Key}}: {{.Value}}
+{{- end}}
+{{- end}}`
 
 const log4j2PropertiesTmpl = `
 status = error
 
 # log action execution errors for easier debugging
-logger.action.name = org.elasticsearch.action
+logger.action.name = {{.LoggerPrefix}}.action
 logger.action.level = debug
 
-logger.security.name = com.amazon.opendistroforelasticsearch.security
+logger.security.name = {{.SecurityLogger}}
 logger.security.level = {{.SecurityLogLevel}}
 
 appender.console.type = Console
@@ -111,7 +148,7 @@ appender.deprecation_rolling.policies.size.size = 1GB
 appender.deprecation_rolling.strategy.type = DefaultRolloverStrategy
 appender.deprecation_rolling.strategy.max = 4
 
-logger.deprecation.name = org.elasticsearch.deprecation
+logger.deprecation.name = {{.LoggerPrefix}}.deprecation
 logger.deprecation.level = warn
 logger.deprecation.appenderRef.deprecation_rolling.ref = deprecation_rolling
 logger.deprecation.additivity = false
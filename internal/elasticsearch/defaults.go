@@ -50,7 +50,7 @@ func esUnicastHost(clusterName, namespace string) string {
 }
 
 func CalculatePrimaryCount(dpl *api.Elasticsearch) int {
-	dataNodeCount := int(GetDataCount(dpl))
+	dataNodeCount := int(getIndexingDataCount(dpl))
 	if dataNodeCount > maxPrimaryShardCount {
 		return maxPrimaryShardCount
 	}
@@ -61,7 +61,7 @@ func CalculatePrimaryCount(dpl *api.Elasticsearch) int {
 }
 
 func CalculateReplicaCount(dpl *api.Elasticsearch) int {
-	dataNodeCount := int(GetDataCount(dpl))
+	dataNodeCount := int(getIndexingDataCount(dpl))
 	repType := dpl.Spec.RedundancyPolicy
 	switch repType {
 	case api.FullRedundancy:
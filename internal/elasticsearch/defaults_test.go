@@ -59,4 +59,38 @@ var _ = Describe("defaults", func() {
 			Expect(CalculatePrimaryCount(dpl)).To(Equal(dataNodeCount))
 		})
 	})
+
+	Describe("#getPrimaryShardCount and #getReplicaShardCount with data tiers", func() {
+		JustBeforeEach(func() {
+			hotNode := dataNode
+			hotNode.NodeCount = 2
+			hotNode.Tier = api.ElasticsearchTierHot
+
+			warmNode := dataNode
+			warmNode.NodeCount = 4
+			warmNode.Tier = api.ElasticsearchTierWarm
+
+			dpl = &api.Elasticsearch{
+				Spec: api.ElasticsearchSpec{
+					RedundancyPolicy: api.FullRedundancy,
+					Nodes: []api.ElasticsearchNode{
+						hotNode,
+						warmNode,
+					},
+				},
+			}
+		})
+		It("should count the hot data nodes only", func() {
+			Expect(CalculatePrimaryCount(dpl)).To(Equal(2))
+			Expect(CalculateReplicaCount(dpl)).To(Equal(1))
+		})
+		It("should require new indices to be allocated to the hot tier", func() {
+			Expect(IndexRoutingRequire(dpl)).To(Equal(map[string]string{"tier": "hot"}))
+		})
+		It("should count all data nodes without a hot tier", func() {
+			dpl.Spec.Nodes = dpl.Spec.Nodes[1:]
+			Expect(CalculatePrimaryCount(dpl)).To(Equal(4))
+			Expect(IndexRoutingRequire(dpl)).To(BeNil())
+		})
+	})
 })
@@ -44,6 +44,11 @@ func (node *deploymentNode) populateReference(nodeName string, n api.Elasticsear
 	progressDeadlineSeconds := int32(1800)
 	logConfig := getLogConfig(cluster.GetAnnotations())
 	template := newPodTemplateSpec(context.TODO(), node.log, nodeName, cluster.Name, cluster.Namespace, n, cluster.Spec.Spec, labels, roleMap, client, logConfig)
+	addFlavor(&template, cluster, false)
+	addSnapshotVolume(&template, cluster.Spec.Snapshot)
+	addSnapshotKeystore(&template, cluster.Namespace, cluster.Spec.Snapshot, client)
+	addESConfig(&template, cluster, n)
+	addZoneAwareness(&template, cluster.Name, n, roleMap)
 
 	dpl := deployment.New(nodeName, cluster.Namespace, labels, replicas).
 		WithSelector(metav1.LabelSelector{
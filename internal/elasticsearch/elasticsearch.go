@@ -41,6 +41,11 @@ func (er *ElasticsearchRequest) AnyNodeReady() bool {
 }
 
 func (er *ElasticsearchRequest) updateMinMasters() {
+	// Elasticsearch 7 and OpenSearch manage the master quorum on their own
+	if !usesZenDiscovery(er.cluster) {
+		return
+	}
+
 	// do as best effort -- whenever we create a node update min masters (if required)
 	if !er.AnyNodeReady() {
 		return
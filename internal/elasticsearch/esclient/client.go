@@ -3,32 +3,22 @@ package esclient
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
-	"net/url"
-	"os"
-	"path"
-	"time"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
 	"github.com/go-logr/logr"
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
-	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	certLocalPath = "/tmp/"
-	k8sTokenFile  = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	k8sTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 )
 
 type Client interface {
@@ -37,7 +27,8 @@ type Client interface {
 	// Cluster Settings API
 	GetClusterNodeVersions() ([]string, error)
 	GetThresholdEnabled() (bool, error)
-	GetDiskWatermarks() (interface{}, interface{}, interface{}, error)
+	GetClusterSettings() (*estypes.ClusterSettingsResponse, error)
+	GetDiskWatermarks() (estypes.DiskWatermarks, error)
 	GetMinMasterNodes() (int32, error)
 	SetMinMasterNodes(numberMasters int32) (bool, error)
 	DoSynchronizedFlush() (bool, error)
@@ -50,34 +41,53 @@ type Client interface {
 	GetClusterHealth() (api.ClusterHealth, error)
 	GetClusterHealthStatus() (string, error)
 	GetClusterNodeCount() (int32, error)
+	WaitForIndexHealth(name, status, timeout string) error
 
 	// Index API
 	GetIndex(name string) (*estypes.Index, error)
 	CreateIndex(name string, index *estypes.Index) error
 	ReIndex(src, dst, script, lang string) error
 	GetAllIndices(name string) (estypes.CatIndicesResponses, error)
+	ListIndicesWithCreationDate(pattern string) (estypes.CatIndicesResponses, error)
+	DeleteIndices(names ...string) error
+	DeleteByQuery(pattern string, query interface{}) error
+	ShrinkIndex(source, target string, settings map[string]interface{}, aliases map[string]estypes.IndexAlias) error
+	ForceMerge(name string, maxNumSegments int32) error
+
+	// Rollover API
+	Rollover(alias string, conditions estypes.RolloverConditions) (*estypes.RolloverResponse, error)
 
 	// Index Alias API
 	ListIndicesForAlias(aliasPattern string) ([]string, error)
+	GetAliases(aliasPattern string) (estypes.AliasesResponse, error)
+	GetIndexAliases(pattern string) (estypes.AliasesResponse, error)
 	UpdateAlias(actions estypes.AliasActions) error
 	AddAliasForOldIndices() bool
 
 	// Index Settings API
 	GetIndexSettings(name string) (*estypes.Index, error)
 	UpdateIndexSettings(name string, settings *estypes.IndexSettings) error
+	PutIndexSettings(name string, settings map[string]interface{}) error
 
 	// Nodes API
 	GetNodeDiskUsage(nodeName string) (string, float64, error)
+	GetDiskTotal() (uint64, error)
 
 	// Replicas
 	UpdateReplicaCount(replicaCount int32) error
-	GetIndexReplicaCounts() (map[string]interface{}, error)
+	GetIndexReplicaCounts() (estypes.IndicesSettingsResponse, error)
 	GetLowestReplicaValue() (int32, error)
 
 	// Shards API
 	ClearTransientShardAllocation() (bool, error)
 	GetShardAllocation() (string, error)
 	SetShardAllocation(state api.ShardAllocationState) (bool, error)
+	GetIndexShards(name string) (estypes.CatShardsResponses, error)
+	GetAllocationAwarenessAttributes() (string, error)
+	SetAllocationAwarenessAttributes(attributes string) (bool, error)
+	GetAllocationExcludedNodes() ([]string, error)
+	SetAllocationExcludedNodes(names []string) (bool, error)
+	GetNodeShardCount(nodeName string) (int, error)
 
 	// Index Templates API
 	CreateIndexTemplate(name string, template *estypes.IndexTemplate) error
@@ -86,17 +96,44 @@ type Client interface {
 	GetIndexTemplates() (map[string]estypes.GetIndexTemplate, error)
 	UpdateTemplatePrimaryShards(shardCount int32) error
 
+	// Snapshot API
+	GetSnapshotRepository(name string) (*estypes.SnapshotRepository, error)
+	CreateSnapshotRepository(name string, repository *estypes.SnapshotRepository) error
+	CreateSnapshot(repository, name string, request *estypes.CreateSnapshotRequest) error
+	GetSnapshot(repository, name string) (*estypes.SnapshotInfo, error)
+	ListSnapshots(repository string) ([]estypes.SnapshotInfo, error)
+	DeleteSnapshot(repository, name string) error
+	RestoreSnapshot(repository, name string, request *estypes.RestoreSnapshotRequest) error
+	GetActiveRestores(repository, name string) (estypes.CatRecoveryResponses, error)
+
+	// Remote Cluster API
+	GetRemoteInfo() (estypes.RemoteInfoResponse, error)
+	UpdateRemoteClusters(remotes map[string]estypes.RemoteClusterSettings) error
+
+	// Security Plugin API
+	GetSecurityConfig() (*estypes.SecurityConfig, error)
+	UpdateSecurityConfig(config *estypes.SecurityConfig) error
+	GetInternalUsers() (estypes.InternalUsersResponse, error)
+	PutInternalUser(name string, user estypes.InternalUser) error
+	DeleteInternalUser(name string) error
+
 	SetSendRequestFn(fn FnEsSendRequest)
+	SetFlavor(flavor api.ElasticsearchFlavor)
+	SetContext(ctx context.Context)
 }
 
-type FnEsSendRequest func(log logr.Logger, cluster, namespace string, payload *EsRequest, client k8sclient.Client)
+// FnEsSendRequest sends the request to the cluster and sets the response on the payload.
+// The error is returned and set on the payload.
+type FnEsSendRequest func(ctx context.Context, log logr.Logger, cluster, namespace string, payload *EsRequest, client k8sclient.Client) error
 
 type esClient struct {
+	ctx             context.Context
 	log             logr.Logger
 	cluster         string
 	namespace       string
 	k8sClient       k8sclient.Client
 	fnSendEsRequest FnEsSendRequest
+	flavor          api.ElasticsearchFlavor
 }
 
 type EsRequest struct {
@@ -111,6 +148,7 @@ type EsRequest struct {
 
 func NewClient(log logr.Logger, cluster, namespace string, client k8sclient.Client) Client {
 	return &esClient{
+		ctx:             context.Background(),
 		log:             log,
 		cluster:         cluster,
 		namespace:       namespace,
@@ -123,10 +161,29 @@ func (ec *esClient) SetSendRequestFn(fn FnEsSendRequest) {
 	ec.fnSendEsRequest = fn
 }
 
+// SetFlavor sets the flavor all nodes of the cluster are running. It picks the APIs
+// which differ between the flavors.
+func (ec *esClient) SetFlavor(flavor api.ElasticsearchFlavor) {
+	ec.flavor = flavor
+}
+
+// SetContext sets the context of the requests, e.g. the one of the reconcile. Cancelling it
+// aborts the request in flight and its retries.
+func (ec *esClient) SetContext(ctx context.Context) {
+	ec.ctx = ctx
+}
+
 func (ec *esClient) ClusterName() string {
 	return ec.cluster
 }
 
+// send sends the request with the transport of the client. Errors are set on the payload.
+func (ec *esClient) send(payload *EsRequest) {
+	if err := ec.fnSendEsRequest(ec.ctx, ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient); err != nil && payload.Error == nil {
+		payload.Error = err
+	}
+}
+
 func (ec *esClient) errorCtx() kverrors.Context {
 	return kverrors.NewContext(
 		"namespace", ec.namespace,
@@ -134,159 +191,34 @@ func (ec *esClient) errorCtx() kverrors.Context {
 	)
 }
 
-// FIXME: this needs to return an error instead of swallowing
-func sendEsRequest(log logr.Logger, cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
-	u := fmt.Sprintf("https://%s.%s.svc:9200/%s", cluster, namespace, payload.URI)
-	urlURL, err := url.Parse(u)
-	if err != nil {
-		log.Error(err, "failed to parse URL", "url", u)
-		return
+// decodeResponse decodes the body of a successful response into v. Failed requests and
+// undecodable bodies are returned as errors.
+func (ec *esClient) decodeResponse(payload *EsRequest, v interface{}) error {
+	if payload.Error != nil {
+		return payload.Error
 	}
-
-	request := &http.Request{
-		Method: payload.Method,
-		URL:    urlURL,
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("unexpected response status",
+			"method", payload.Method,
+			"uri", payload.URI,
+			"response_status", payload.StatusCode,
+			"response_body", payload.RawResponseBody)
 	}
-
-	switch payload.Method {
-	case http.MethodGet:
-		// no more to do to request...
-	case http.MethodPost:
-		if payload.RequestBody != "" {
-			// add to the request
-			request.Header = map[string][]string{
-				"Content-Type": {
-					"application/json",
-				},
-			}
-			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(payload.RequestBody)))
-		}
-
-	case http.MethodPut:
-		if payload.RequestBody != "" {
-			// add to the request
-			request.Header = map[string][]string{
-				"Content-Type": {
-					"application/json",
-				},
-			}
-			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(payload.RequestBody)))
-		}
-
-	default:
-		// unsupported method -- do nothing
-		return
-	}
-
-	request.Header = ensureTokenHeader(log, request.Header)
-	// we use the insecure TLS client here because we are providing the SA token.
-	httpClient := getTLSClient(log, cluster, namespace, client)
-	resp, err := httpClient.Do(request)
-	if err != nil {
-		if resp == nil {
-			payload.Error = err
-			return
-		}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), v); err != nil {
+		return ec.errorCtx().Wrap(err, "failed to decode response body",
+			"uri", payload.URI,
+			"destination_type", fmt.Sprintf("%T", v))
 	}
-	defer resp.Body.Close()
-
-	if resp != nil {
-		// TODO: eventually remove after all ES images have been updated to use SA token auth for EO?
-		if resp.StatusCode == http.StatusForbidden ||
-			resp.StatusCode == http.StatusUnauthorized {
-			log.Info("failed sending payload using bearer token", "method", payload.Method, "url", payload.URI)
-			// if we get a 401 that means that we couldn't read from the token and provided
-			// no header.
-			// if we get a 403 that means the ES cluster doesn't allow us to use
-			// our SA token.
-			// in both cases, try the old way.
-
-			// Not sure why, but just trying to reuse the request with the old client
-			// resulted in a 400 every time. Doing it this way got a 200 response as expected.
-			sendRequestWithMTlsClient(log, cluster, namespace, payload, client)
-			return
-		}
-
-		payload.StatusCode = resp.StatusCode
-		if payload.RawResponseBody, err = getRawBody(resp.Body); err != nil {
-			log.Error(err, "failed to get raw response body")
-		}
-		if payload.ResponseBody, err = getMapFromBody(payload.RawResponseBody); err != nil {
-			log.Error(err, "getMapFromBody failed")
-		}
-	}
-
-	payload.Error = err
+	return nil
 }
 
-func sendRequestWithMTlsClient(log logr.Logger, clusterName, namespace string, payload *EsRequest, client k8sclient.Client) {
-	u := fmt.Sprintf("https://%s.%s.svc:9200/%s", clusterName, namespace, payload.URI)
-	urlURL, err := url.Parse(u)
-	if err != nil {
-		log.Error(err, "unable to parse URL", "url", u)
-		return
-	}
-
-	request := &http.Request{
-		Method: payload.Method,
-		URL:    urlURL,
-	}
-
-	switch payload.Method {
-	case http.MethodGet:
-		// no more to do to request...
-	case http.MethodPost:
-		if payload.RequestBody != "" {
-			// add to the request
-			request.Header = map[string][]string{
-				"Content-Type": {
-					"application/json",
-				},
-			}
-			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(payload.RequestBody)))
-		}
-
-	case http.MethodPut:
-		if payload.RequestBody != "" {
-			// add to the request
-			request.Header = map[string][]string{
-				"Content-Type": {
-					"application/json",
-				},
-			}
-			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(payload.RequestBody)))
-		}
-
-	default:
-		// unsupported method -- do nothing
-		return
+// acknowledged returns true if the change of the request was acknowledged by the cluster
+func (ec *esClient) acknowledged(payload *EsRequest) (bool, error) {
+	response := estypes.AcknowledgedResponse{}
+	if err := ec.decodeResponse(payload, &response); err != nil {
+		return false, err
 	}
-
-	httpClient := getMTlsClient(log, clusterName, namespace, client)
-	resp, err := httpClient.Do(request)
-	if err != nil {
-		if resp == nil {
-			payload.Error = err
-			return
-		}
-	}
-	defer resp.Body.Close()
-
-	if resp != nil {
-		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized {
-			log.Info("failed sending payload using mTLS PKI", "method", payload.Method, "url", payload.URI)
-		}
-
-		payload.StatusCode = resp.StatusCode
-		if payload.RawResponseBody, err = getRawBody(resp.Body); err != nil {
-			log.Error(err, "failed to get raw response body")
-		}
-		if payload.ResponseBody, err = getMapFromBody(payload.RawResponseBody); err != nil {
-			log.Error(err, "getMapFrombody failed")
-		}
-	}
-
-	payload.Error = err
+	return response.Acknowledged, nil
 }
 
 func ensureTokenHeader(log logr.Logger, header http.Header) http.Header {
@@ -319,92 +251,6 @@ func readSAToken(log logr.Logger, tokenFile string) (string, bool) {
 	return string(token), true
 }
 
-// this client is used with the SA token, it does not present any client certs
-func getTLSClient(log logr.Logger, clusterName, namespace string, client k8sclient.Client) *http.Client {
-	// get the contents of the secret
-	extractSecret(log, clusterName, namespace, client)
-
-	// http.Transport sourced from go 1.10.7
-	return &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: false,
-				MinVersion:         tls.VersionTLS12,
-				RootCAs:            getRootCA(log, clusterName, namespace),
-			},
-		},
-	}
-}
-
-// this client is used in the case where the SA token is not honored. it presents client certs
-// and validates the ES cluster CA cert
-func getMTlsClient(log logr.Logger, clusterName, namespace string, client k8sclient.Client) *http.Client {
-	// get the contents of the secret
-	extractSecret(log, clusterName, namespace, client)
-
-	// http.Transport sourced from go 1.10.7
-	return &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: false,
-				MinVersion:         tls.VersionTLS12,
-				RootCAs:            getRootCA(log, clusterName, namespace),
-				Certificates:       getClientCertificates(clusterName, namespace),
-			},
-		},
-	}
-}
-
-func getRootCA(log logr.Logger, clusterName, namespace string) *x509.CertPool {
-	certPool := x509.NewCertPool()
-
-	// load cert into []byte
-	f := path.Join(certLocalPath, namespace, clusterName, "admin-ca")
-	caPem, err := ioutil.ReadFile(f)
-	if err != nil {
-		log.Error(err, "Unable to read file to get contents", "file", f)
-		return nil
-	}
-
-	certPool.AppendCertsFromPEM(caPem)
-
-	return certPool
-}
-
-func getClientCertificates(clusterName, namespace string) []tls.Certificate {
-	certificate, err := tls.LoadX509KeyPair(
-		path.Join(certLocalPath, namespace, clusterName, "admin-cert"),
-		path.Join(certLocalPath, namespace, clusterName, "admin-key"),
-	)
-	if err != nil {
-		return []tls.Certificate{}
-	}
-
-	return []tls.Certificate{
-		certificate,
-	}
-}
-
 func getRawBody(body io.ReadCloser) (string, error) {
 	buf := new(bytes.Buffer)
 	if _, err := buf.ReadFrom(body); err != nil {
@@ -426,34 +272,3 @@ func getMapFromBody(rawBody string) (map[string]interface{}, error) {
 
 	return results, nil
 }
-
-func extractSecret(log logr.Logger, secretName, namespace string, client k8sclient.Client) {
-	key := types.NamespacedName{Name: secretName, Namespace: namespace}
-	s, err := secret.Get(context.TODO(), client, key)
-	if err != nil {
-		log.Error(err, "Error reading secret", "secret", secretName)
-	}
-
-	// make sure that the dir === secretName exists
-	secretDir := path.Join(certLocalPath, namespace, secretName)
-	if _, err := os.Stat(secretDir); os.IsNotExist(err) {
-		if err = os.MkdirAll(secretDir, 0o755); err != nil {
-			log.Error(err, "Error creating dir", "dir", secretDir)
-		}
-	}
-
-	for _, key := range []string{"admin-ca", "admin-cert", "admin-key"} {
-
-		value, ok := s.Data[key]
-
-		// check to see if the map value exists
-		if !ok {
-			log.Error(nil, "secret key not found", "key", key)
-		}
-
-		secretFile := path.Join(certLocalPath, namespace, secretName, key)
-		if err := ioutil.WriteFile(secretFile, value, 0o644); err != nil {
-			log.Error(err, "failed to write value to file", "value", value, "file", secretFile)
-		}
-	}
-}
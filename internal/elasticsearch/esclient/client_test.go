@@ -1,9 +1,13 @@
 package esclient
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
 	"github.com/ViaQ/logerr/v2/log"
+	"github.com/go-logr/logr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestHeaderGenEmptyToken(t *testing.T) {
@@ -44,3 +48,26 @@ func TestHeaderGenWithNoToken(t *testing.T) {
 		t.Errorf("Expected to be unable to read file [%s]", tokenFile)
 	}
 }
+
+func TestSendUsesTheClientContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	c := NewClient(log.NewLogger("client-testing"), "elasticsearch", "openshift-logging", nil)
+	c.SetContext(ctx)
+	c.SetSendRequestFn(func(ctx context.Context, _ logr.Logger, _, _ string, payload *EsRequest, _ k8sclient.Client) error {
+		payload.StatusCode, payload.RawResponseBody, payload.Error = retryRequest(ctx, payload.Method, func(ctx context.Context) (int, string, error) {
+			attempts++
+			return http.StatusServiceUnavailable, "", nil
+		})
+		return payload.Error
+	})
+
+	if _, err := c.GetClusterHealthStatus(); err == nil {
+		t.Errorf("Exp. the request to fail")
+	}
+	if attempts != 1 {
+		t.Errorf("Exp. the cancelled context to abort the retries but got %d attempts", attempts)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
@@ -18,154 +17,74 @@ func (ec *esClient) GetClusterNodeVersions() ([]string, error) {
 		URI:    "_cluster/stats",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	var nodeVersions []string
-	if versions := walkInterfaceMap("nodes.versions", payload.ResponseBody); versions != nil {
-		for _, value := range versions.([]interface{}) {
-			version := value.(string)
-			nodeVersions = append(nodeVersions, version)
-		}
+	res := estypes.StatsNodesResponse{}
+	if err := ec.decodeResponse(payload, &res); err != nil {
+		return nil, err
 	}
-
-	return nodeVersions, nil
+	return res.Nodes.Versions, nil
 }
 
-func (ec *esClient) GetThresholdEnabled() (bool, error) {
-	payload := &EsRequest{
-		Method: http.MethodGet,
-		URI:    "_cluster/settings?include_defaults=true",
-	}
-
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
-
-	var enabled interface{}
-
-	if value := walkInterfaceMap(
-		"defaults.cluster.routing.allocation.disk.threshold_enabled",
-		payload.ResponseBody); value != nil {
-		enabled = value
-	}
-
-	if value := walkInterfaceMap(
-		"persistent.cluster.routing.allocation.disk.threshold_enabled",
-		payload.ResponseBody); value != nil {
-		enabled = value
-	}
-
-	if value := walkInterfaceMap(
-		"transient.cluster.routing.allocation.disk.threshold_enabled",
-		payload.ResponseBody); value != nil {
-		enabled = value
-	}
-
-	enabledBool := false
-	if enabledString, ok := enabled.(string); ok {
-		if enabledTemp, err := strconv.ParseBool(enabledString); err == nil {
-			enabledBool = enabledTemp
-		}
-	}
-
-	return enabledBool, payload.Error
+// GetClusterSettings returns the persistent, transient and default settings of the cluster
+func (ec *esClient) GetClusterSettings() (*estypes.ClusterSettingsResponse, error) {
+	return ec.getClusterSettings("_cluster/settings?include_defaults=true")
 }
 
-func (ec *esClient) GetDiskWatermarks() (interface{}, interface{}, interface{}, error) {
+func (ec *esClient) getClusterSettings(uri string) (*estypes.ClusterSettingsResponse, error) {
 	payload := &EsRequest{
 		Method: http.MethodGet,
-		URI:    "_cluster/settings?include_defaults=true",
+		URI:    uri,
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
-
-	var low interface{}
-	var high interface{}
-	var flood interface{}
-
-	if value := walkInterfaceMap(
-		"defaults.cluster.routing.allocation.disk.watermark.low",
-		payload.ResponseBody); value != nil {
-		low = value
-	}
-
-	if value := walkInterfaceMap(
-		"defaults.cluster.routing.allocation.disk.watermark.high",
-		payload.ResponseBody); value != nil {
-		high = value
-	}
-
-	if value := walkInterfaceMap(
-		"defaults.cluster.routing.allocation.disk.watermark.flood_stage",
-		payload.ResponseBody); value != nil {
-		flood = value
-	}
-
-	if value := walkInterfaceMap(
-		"persistent.cluster.routing.allocation.disk.watermark.low",
-		payload.ResponseBody); value != nil {
-		low = value
-	}
+	ec.send(payload)
 
-	if value := walkInterfaceMap(
-		"persistent.cluster.routing.allocation.disk.watermark.high",
-		payload.ResponseBody); value != nil {
-		high = value
-	}
-
-	if value := walkInterfaceMap(
-		"persistent.cluster.routing.allocation.disk.watermark.flood_stage",
-		payload.ResponseBody); value != nil {
-		flood = value
+	res := &estypes.ClusterSettingsResponse{}
+	if err := ec.decodeResponse(payload, res); err != nil {
+		return nil, err
 	}
+	return res, nil
+}
 
-	if value := walkInterfaceMap(
-		"transient.cluster.routing.allocation.disk.watermark.low",
-		payload.ResponseBody); value != nil {
-		low = value
+func (ec *esClient) GetThresholdEnabled() (bool, error) {
+	settings, err := ec.GetClusterSettings()
+	if err != nil {
+		return false, err
 	}
 
-	if value := walkInterfaceMap(
-		"transient.cluster.routing.allocation.disk.watermark.high",
-		payload.ResponseBody); value != nil {
-		high = value
+	value, ok := settings.Setting("cluster.routing.allocation.disk.threshold_enabled")
+	if !ok {
+		return false, nil
 	}
-
-	if value := walkInterfaceMap(
-		"transient.cluster.routing.allocation.disk.watermark.flood_stage",
-		payload.ResponseBody); value != nil {
-		flood = value
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, ec.errorCtx().Wrap(err, "failed to parse disk threshold setting", "value", value)
 	}
+	return enabled, nil
+}
 
-	if lowString, ok := low.(string); ok {
-		if strings.HasSuffix(lowString, "%") {
-			low, _ = strconv.ParseFloat(strings.TrimSuffix(lowString, "%"), 64)
-		} else {
-			if strings.HasSuffix(lowString, "b") {
-				low = strings.TrimSuffix(lowString, "b")
-			}
-		}
+func (ec *esClient) GetDiskWatermarks() (estypes.DiskWatermarks, error) {
+	watermarks := estypes.DiskWatermarks{}
+	settings, err := ec.GetClusterSettings()
+	if err != nil {
+		return watermarks, err
 	}
 
-	if highString, ok := high.(string); ok {
-		if strings.HasSuffix(highString, "%") {
-			high, _ = strconv.ParseFloat(strings.TrimSuffix(highString, "%"), 64)
-		} else {
-			if strings.HasSuffix(highString, "b") {
-				high = strings.TrimSuffix(highString, "b")
-			}
+	for setting, watermark := range map[string]*estypes.DiskWatermark{
+		"cluster.routing.allocation.disk.watermark.low":         &watermarks.Low,
+		"cluster.routing.allocation.disk.watermark.high":        &watermarks.High,
+		"cluster.routing.allocation.disk.watermark.flood_stage": &watermarks.FloodStage,
+	} {
+		value, ok := settings.Setting(setting)
+		if !ok {
+			return watermarks, ec.errorCtx().New("missing disk watermark setting", "setting", setting)
 		}
-	}
-
-	if floodString, ok := flood.(string); ok {
-		if strings.HasSuffix(floodString, "%") {
-			flood, _ = strconv.ParseFloat(strings.TrimSuffix(floodString, "%"), 64)
-		} else {
-			if strings.HasSuffix(floodString, "b") {
-				flood = strings.TrimSuffix(floodString, "b")
-			}
+		if *watermark, err = estypes.ParseDiskWatermark(value); err != nil {
+			return watermarks, ec.errorCtx().Wrap(err, "failed to parse disk watermark setting", "setting", setting)
 		}
 	}
 
-	return low, high, flood, payload.Error
+	return watermarks, nil
 }
 
 func (ec *esClient) SetMinMasterNodes(numberMasters int32) (bool, error) {
@@ -175,33 +94,26 @@ func (ec *esClient) SetMinMasterNodes(numberMasters int32) (bool, error) {
 		RequestBody: fmt.Sprintf("{%q:{%q:%d}}", "persistent", "discovery.zen.minimum_master_nodes", numberMasters),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
-
-	acknowledged := false
-	if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
-		acknowledged = acknowledgedBool
-	}
+	ec.send(payload)
 
-	return payload.StatusCode == 200 && acknowledged, payload.Error
+	return ec.acknowledged(payload)
 }
 
 func (ec *esClient) GetMinMasterNodes() (int32, error) {
-	payload := &EsRequest{
-		Method: http.MethodGet,
-		URI:    "_cluster/settings",
+	settings, err := ec.getClusterSettings("_cluster/settings")
+	if err != nil {
+		return 0, err
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
-
-	masterCount := int32(0)
-	if payload.ResponseBody["persistent"] != nil {
-		persistentBody := payload.ResponseBody["persistent"].(map[string]interface{})
-		if masterCountFloat, ok := persistentBody["discovery.zen.minimum_master_nodes"].(float64); ok {
-			masterCount = int32(masterCountFloat)
-		}
+	value, ok := settings.Persistent.Get("discovery.zen.minimum_master_nodes")
+	if !ok {
+		return 0, nil
 	}
-
-	return masterCount, payload.Error
+	masterCount, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, ec.errorCtx().Wrap(err, "failed to parse minimum master nodes setting", "value", value)
+	}
+	return int32(masterCount), nil
 }
 
 // TODO: also check that the number of shards in the response > 0?
@@ -211,21 +123,24 @@ func (ec *esClient) DoSynchronizedFlush() (bool, error) {
 		URI:    "_flush/synced",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
+	if payload.Error != nil {
+		return false, payload.Error
+	}
 
-	failed := 0
-	if shards, ok := payload.ResponseBody["_shards"].(map[string]interface{}); ok {
-		if failedFload, ok := shards["failed"].(float64); ok {
-			failed = int(failedFload)
-		}
+	// failed shards are reported with a conflict status so the body is decoded for any status
+	res := estypes.FlushResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return false, ec.errorCtx().Wrap(err, "failed to decode raw response body into `estypes.FlushResponse`",
+			"response_status", payload.StatusCode)
 	}
 
-	if payload.Error == nil && failed != 0 {
-		payload.Error = kverrors.New("failed to flush shards in preparation for cluster restart",
-			"num_failed_shards", failed)
+	if res.Shards.Failed != 0 {
+		return false, kverrors.New("failed to flush shards in preparation for cluster restart",
+			"num_failed_shards", res.Shards.Failed)
 	}
 
-	return payload.StatusCode == 200, payload.Error
+	return payload.StatusCode == 200, nil
 }
 
 func (ec *esClient) GetLowestClusterVersion() (string, error) {
@@ -234,7 +149,7 @@ func (ec *esClient) GetLowestClusterVersion() (string, error) {
 		URI:    "_cluster/stats/nodes/_all",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return "", payload.Error
 	}
@@ -285,7 +200,7 @@ func (ec *esClient) IsNodeInCluster(nodeName string) (bool, error) {
 		URI:    "_cluster/state/nodes",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return false, payload.Error
 	}
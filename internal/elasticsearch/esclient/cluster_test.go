@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/openshift/elasticsearch-operator/test/helpers"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestGetClusterNodeVersion(t *testing.T) {
@@ -135,3 +136,96 @@ func TestIsNodeInCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDiskWatermarks(t *testing.T) {
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings?include_defaults=true": {
+			{
+				StatusCode: 200,
+				Body: `{"persistent": {"cluster.routing.allocation.disk.watermark.low": "80%"},
+				        "transient": {"cluster": {"routing": {"allocation": {"disk": {"watermark": {"high": "500mb"}}}}}},
+				        "defaults": {"cluster": {"routing": {"allocation": {"disk": {"watermark": {"low": "85%", "high": "90%", "flood_stage": "0.95"}}}}}}}`,
+			},
+			{
+				StatusCode: 200,
+				Body:       `{"persistent": {}, "transient": {}, "defaults": {"cluster.routing.allocation.disk.watermark.low": "85%"}}`,
+			},
+			{
+				StatusCode: 200,
+				Body:       `{"persistent": [], "transient": {}}`,
+			},
+		},
+	})
+	esClient := helpers.NewFakeElasticsearchClient("elasticsearch", "test-namespace", fakeClient, chatter)
+
+	watermarks, err := esClient.GetDiskWatermarks()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+	if watermarks.Low.Percentage == nil || *watermarks.Low.Percentage != 80 {
+		t.Errorf("Exp. the persistent low watermark of 80%% but got %#v", watermarks.Low)
+	}
+	if exp := resource.MustParse("500Mi"); watermarks.High.Quantity == nil || watermarks.High.Quantity.Cmp(exp) != 0 {
+		t.Errorf("Exp. the transient high watermark of %s but got %#v", exp.String(), watermarks.High)
+	}
+	if watermarks.FloodStage.Percentage == nil || *watermarks.FloodStage.Percentage != 95 {
+		t.Errorf("Exp. the default flood stage watermark of 95%% but got %#v", watermarks.FloodStage)
+	}
+
+	if _, err := esClient.GetDiskWatermarks(); err == nil {
+		t.Errorf("Exp. an error for missing watermarks")
+	}
+	if _, err := esClient.GetDiskWatermarks(); err == nil {
+		t.Errorf("Exp. an error for an undecodable response")
+	}
+}
+
+func TestGetMinMasterNodes(t *testing.T) {
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings": {
+			{
+				StatusCode: 200,
+				Body:       `{"persistent": {"discovery": {"zen": {"minimum_master_nodes": "2"}}}, "transient": {}}`,
+			},
+			{
+				StatusCode: 200,
+				Body:       `{"persistent": {}, "transient": {}}`,
+			},
+			{
+				StatusCode: 500,
+				Body:       `{"error": "internal"}`,
+			},
+		},
+	})
+	esClient := helpers.NewFakeElasticsearchClient("elasticsearch", "test-namespace", fakeClient, chatter)
+
+	tests := []struct {
+		desc    string
+		want    int32
+		wantErr bool
+	}{
+		{
+			desc: "persistent setting",
+			want: 2,
+		},
+		{
+			desc: "no setting",
+			want: 0,
+		},
+		{
+			desc:    "failed request",
+			want:    0,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := esClient.GetMinMasterNodes()
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got err: %v, want err: %v", test.desc, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %d, want %d", test.desc, got, test.want)
+		}
+	}
+}
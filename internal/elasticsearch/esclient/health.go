@@ -1,69 +1,91 @@
 package esclient
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 )
 
-func (ec *esClient) GetClusterHealth() (api.ClusterHealth, error) {
-	clusterHealth := api.ClusterHealth{}
-
+func (ec *esClient) getClusterHealth() (*estypes.ClusterHealthResponse, error) {
 	payload := &EsRequest{
 		Method: http.MethodGet,
 		URI:    "_cluster/health",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	if payload.Error != nil {
-		return clusterHealth, payload.Error
+	health := &estypes.ClusterHealthResponse{}
+	if err := ec.decodeResponse(payload, health); err != nil {
+		return nil, err
 	}
+	return health, nil
+}
 
-	clusterHealth.Status = parseString("status", payload.ResponseBody)
-	clusterHealth.NumNodes = parseInt32("number_of_nodes", payload.ResponseBody)
-	clusterHealth.NumDataNodes = parseInt32("number_of_data_nodes", payload.ResponseBody)
-	clusterHealth.ActivePrimaryShards = parseInt32("active_primary_shards", payload.ResponseBody)
-	clusterHealth.ActiveShards = parseInt32("active_shards", payload.ResponseBody)
-	clusterHealth.RelocatingShards = parseInt32("relocating_shards", payload.ResponseBody)
-	clusterHealth.InitializingShards = parseInt32("initializing_shards", payload.ResponseBody)
-	clusterHealth.UnassignedShards = parseInt32("unassigned_shards", payload.ResponseBody)
-	clusterHealth.PendingTasks = parseInt32("number_of_pending_tasks", payload.ResponseBody)
+func (ec *esClient) GetClusterHealth() (api.ClusterHealth, error) {
+	health, err := ec.getClusterHealth()
+	if err != nil {
+		return api.ClusterHealth{}, err
+	}
 
-	return clusterHealth, nil
+	return api.ClusterHealth{
+		Status:              health.Status,
+		NumNodes:            health.NumberOfNodes,
+		NumDataNodes:        health.NumberOfDataNodes,
+		ActivePrimaryShards: health.ActivePrimaryShards,
+		ActiveShards:        health.ActiveShards,
+		RelocatingShards:    health.RelocatingShards,
+		InitializingShards:  health.InitializingShards,
+		UnassignedShards:    health.UnassignedShards,
+		PendingTasks:        health.NumberOfPendingTasks,
+	}, nil
 }
 
 func (ec *esClient) GetClusterHealthStatus() (string, error) {
-	payload := &EsRequest{
-		Method: http.MethodGet,
-		URI:    "_cluster/health",
+	health, err := ec.getClusterHealth()
+	if err != nil {
+		return "", err
 	}
+	return health.Status, nil
+}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
-
-	status := ""
-	if payload.ResponseBody["status"] != nil {
-		if statusString, ok := payload.ResponseBody["status"].(string); ok {
-			status = statusString
-		}
+func (ec *esClient) GetClusterNodeCount() (int32, error) {
+	health, err := ec.getClusterHealth()
+	if err != nil {
+		return 0, err
 	}
-
-	return status, payload.Error
+	return health.NumberOfNodes, nil
 }
 
-func (ec *esClient) GetClusterNodeCount() (int32, error) {
+// WaitForIndexHealth blocks until the index reaches the given status and has no relocating shards
+// or the timeout (e.g. 10m) expires
+func (ec *esClient) WaitForIndexHealth(name, status, timeout string) error {
 	payload := &EsRequest{
 		Method: http.MethodGet,
-		URI:    "_cluster/health",
+		URI:    fmt.Sprintf("_cluster/health/%s?wait_for_status=%s&wait_for_no_relocating_shards=true&timeout=%s", name, status, timeout),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	nodeCount := int32(0)
-	if nodeCountFloat, ok := payload.ResponseBody["number_of_nodes"].(float64); ok {
-		// we expect at most double digit numbers here, eg cluster with 15 nodes
-		nodeCount = int32(nodeCountFloat)
+	if payload.Error != nil {
+		return payload.Error
 	}
 
-	return nodeCount, payload.Error
+	// a timeout is reported with a request timeout status so the body is decoded for any status
+	health := estypes.ClusterHealthResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &health); err != nil {
+		return ec.errorCtx().Wrap(err, "failed to decode raw response body into `estypes.ClusterHealthResponse`",
+			"index", name,
+			"response_status", payload.StatusCode)
+	}
+	if payload.StatusCode != http.StatusOK || health.TimedOut {
+		return ec.errorCtx().New("timed out waiting for index health",
+			"index", name,
+			"status", status,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
 }
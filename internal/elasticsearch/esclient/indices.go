@@ -16,7 +16,7 @@ func (ec *esClient) GetIndex(name string) (*estypes.Index, error) {
 		Method: http.MethodGet,
 		URI:    name,
 	}
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return nil, payload.Error
 	}
@@ -45,7 +45,7 @@ func (ec *esClient) GetAllIndices(name string) (estypes.CatIndicesResponses, err
 		Method: http.MethodGet,
 		URI:    fmt.Sprintf("_cat/indices/%s?format=json", name),
 	}
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.StatusCode == http.StatusNotFound {
 		return nil, nil
 	}
@@ -60,8 +60,7 @@ func (ec *esClient) GetAllIndices(name string) (estypes.CatIndicesResponses, err
 	}
 
 	res := estypes.CatIndicesResponses{}
-	raw := payload.ResponseBody["results"].(string)
-	err := json.Unmarshal([]byte(raw), &res)
+	err := json.Unmarshal([]byte(payload.RawResponseBody), &res)
 	if err != nil {
 		return nil, kverrors.Wrap(err, "failed to parse _cat/indices response body",
 			"index", name)
@@ -79,7 +78,7 @@ func (ec *esClient) CreateIndex(name string, index *estypes.Index) error {
 		URI:         name,
 		RequestBody: body,
 	}
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return payload.Error
 	}
@@ -97,7 +96,7 @@ func (ec *esClient) GetIndexSettings(name string) (*estypes.Index, error) {
 		Method: http.MethodGet,
 		URI:    fmt.Sprintf("%s/_settings", name),
 	}
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return nil, payload.Error
 	}
@@ -108,20 +107,14 @@ func (ec *esClient) GetIndexSettings(name string) (*estypes.Index, error) {
 			"response_body", payload.ResponseBody)
 	}
 
-	settings := &estypes.Index{}
-	indexSettings, err := json.Marshal(payload.ResponseBody[name])
-	if err != nil {
-		return nil, kverrors.Wrap(err, "failed to decode response body",
-			"destination_type", "estypes.IndexSettings",
-			"index", name)
-	}
-	err = json.Unmarshal(indexSettings, settings)
-	if err != nil {
+	res := estypes.IndicesSettingsResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
 		return nil, kverrors.Wrap(err, "failed to decode response body",
-			"destination_type", "estypes.IndexSettings",
+			"destination_type", "estypes.IndicesSettingsResponse",
 			"index", name)
 	}
-	return settings, nil
+	settings := res[name]
+	return &settings, nil
 }
 
 func (ec *esClient) UpdateIndexSettings(name string, settings *estypes.IndexSettings) error {
@@ -134,7 +127,7 @@ func (ec *esClient) UpdateIndexSettings(name string, settings *estypes.IndexSett
 		URI:         fmt.Sprintf("%s/_settings", name),
 		RequestBody: body,
 	}
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return payload.Error
 	}
@@ -166,7 +159,7 @@ func (ec *esClient) ReIndex(src, dst, script, lang string) error {
 		URI:         "_reindex",
 		RequestBody: body,
 	}
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil || payload.StatusCode != http.StatusOK {
 		return ec.errorCtx().New("failed to reindex",
 			"from", src,
@@ -190,7 +183,7 @@ func (ec *esClient) UpdateAlias(actions estypes.AliasActions) error {
 		RequestBody: body,
 	}
 	ec.log.Info("Updating aliases", "payload", actions)
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil {
 		return payload.Error
 	}
@@ -210,7 +203,7 @@ func (ec *esClient) ListIndicesForAlias(aliasPattern string) ([]string, error) {
 		URI:    fmt.Sprintf("_alias/%s", aliasPattern),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.StatusCode == 404 {
 		return []string{}, nil
 	}
@@ -221,8 +214,13 @@ func (ec *esClient) ListIndicesForAlias(aliasPattern string) ([]string, error) {
 			"response_status", payload.StatusCode,
 			"response_body", payload.ResponseBody)
 	}
+	aliases := estypes.AliasesResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &aliases); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.AliasesResponse`",
+			"alias", aliasPattern)
+	}
 	var response []string
-	for index := range payload.ResponseBody {
+	for index := range aliases {
 		response = append(response, index)
 	}
 	return response, nil
@@ -251,10 +249,20 @@ func (ec *esClient) AddAliasForOldIndices() bool {
 		URI:    "project.*,.operations.*/_alias",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
+	if payload.Error == nil && payload.StatusCode == http.StatusNotFound {
+		// there are no old indices
+		return true
+	}
+
+	indices := map[string]*estypes.IndexAliases{}
+	if err := ec.decodeResponse(payload, &indices); err != nil {
+		ec.log.Error(err, "unable to get aliases of old indices", "cluster", ec.cluster)
+		return false
+	}
 
 	// alias name choice based on https://github.com/openshift/enhancements/blob/master/enhancements/cluster-logging/cluster-logging-es-rollover-data-design.md#data-model
-	for index := range payload.ResponseBody {
+	for index, indexBody := range indices {
 		// iterate over each index, if they have no aliases that match the new format
 		// then PUT the alias
 
@@ -267,60 +275,262 @@ func (ec *esClient) AddAliasForOldIndices() bool {
 			indexAlias = "infra"
 		}
 
-		if payload.ResponseBody[index] != nil {
-			indexBody, ok := payload.ResponseBody[index].(map[string]interface{})
-			if !ok {
-				ec.log.Error(nil, "unable to unmarshal index",
-					"index", index,
-					"cluster", ec.cluster,
-					"type", fmt.Sprintf("%T", payload.ResponseBody[index]),
-				)
-				continue
+		// if for some reason we received a response without an index or an "aliases" field
+		// we want to retry -- es may not be in a good state?
+		if indexBody == nil || indexBody.Aliases == nil {
+			successful = false
+			continue
+		}
+
+		if _, found := indexBody.Aliases[indexAlias]; !found {
+			// put <index>/_alias/<alias>
+			putPayload := &EsRequest{
+				Method: http.MethodPut,
+				URI:    fmt.Sprintf("%s/_alias/%s", index, indexAlias),
 			}
-			if indexBody["aliases"] != nil {
-				aliasBody, ok := indexBody["aliases"].(map[string]interface{})
-				if !ok {
-					ec.log.Error(nil, "unable to unmarshal alias index",
-						"index", index,
-						"cluster", ec.cluster,
-						"type", fmt.Sprintf("%T", indexBody["aliases"]),
-					)
-					continue
-				}
-
-				found := false
-				for alias := range aliasBody {
-					if alias == indexAlias {
-						found = true
-						break
-					}
-				}
-
-				if !found {
-					// put <index>/_alias/<alias>
-					putPayload := &EsRequest{
-						Method: http.MethodPut,
-						URI:    fmt.Sprintf("%s/_alias/%s", index, indexAlias),
-					}
-					ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, putPayload, ec.k8sClient)
-
-					// check the response here -- if any failed then we want to return "false"
-					// but want to continue trying to process as many as we can now.
-					if putPayload.Error != nil || !parseBool("acknowledged", putPayload.ResponseBody) {
-						successful = false
-					}
-				}
-			} else {
-				// if for some reason we received a response without an "aliases" field
-				// we want to retry -- es may not be in a good state?
+			ec.send(putPayload)
+
+			// check the response here -- if any failed then we want to return "false"
+			// but want to continue trying to process as many as we can now.
+			if ack, _ := ec.acknowledged(putPayload); !ack {
 				successful = false
 			}
-		} else {
-			// if for some reason we received a response without an index field
-			// we want to retry -- es may not be in a good state?
-			successful = false
 		}
 	}
 
 	return successful
 }
+
+// GetAliases returns the aliases of all indices matching the given alias pattern (e.g. app*-write)
+func (ec *esClient) GetAliases(aliasPattern string) (estypes.AliasesResponse, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_alias/%s", aliasPattern),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return estypes.AliasesResponse{}, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get aliases",
+			"alias", aliasPattern,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	aliases := estypes.AliasesResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &aliases); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.AliasesResponse`",
+			"alias", aliasPattern)
+	}
+	return aliases, nil
+}
+
+// GetIndexAliases returns all aliases of the indices matching the given pattern. Aliases
+// are resolved to their indices (e.g. app-write returns every alias of the app indices).
+func (ec *esClient) GetIndexAliases(pattern string) (estypes.AliasesResponse, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_alias", pattern),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return estypes.AliasesResponse{}, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index aliases",
+			"index", pattern,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	aliases := estypes.AliasesResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &aliases); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.AliasesResponse`",
+			"index", pattern)
+	}
+	return aliases, nil
+}
+
+// Rollover rolls the given alias over to a new index if any of the conditions are met
+func (ec *esClient) Rollover(alias string, conditions estypes.RolloverConditions) (*estypes.RolloverResponse, error) {
+	body, err := utils.ToJSON(estypes.RolloverRequest{Conditions: conditions})
+	if err != nil {
+		return nil, err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_rollover", alias),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to rollover alias",
+			"alias", alias,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	response := &estypes.RolloverResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.RolloverResponse`",
+			"alias", alias)
+	}
+	return response, nil
+}
+
+// ListIndicesWithCreationDate returns the name, creation date and store size in bytes of the
+// indices matching the given pattern sorted by creation date
+func (ec *esClient) ListIndicesWithCreationDate(pattern string) (estypes.CatIndicesResponses, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_cat/indices/%s?format=json&h=index,pri,creation.date,store.size&bytes=b&s=creation.date", pattern),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return estypes.CatIndicesResponses{}, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list indices",
+			"pattern", pattern,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.CatIndicesResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/indices response body",
+			"pattern", pattern)
+	}
+	return res, nil
+}
+
+// DeleteIndices deletes all given indices in a single request
+func (ec *esClient) DeleteIndices(names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    strings.Join(names, ","),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to delete indices",
+			"indices", names,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// DeleteByQuery deletes all documents of the indices matching the pattern which match the given query
+func (ec *esClient) DeleteByQuery(pattern string, query interface{}) error {
+	body, err := utils.ToJSON(query)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_delete_by_query?conflicts=proceed", pattern),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to delete by query",
+			"pattern", pattern,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// PutIndexSettings updates the index with settings given in flat form (e.g. index.blocks.write).
+// A nil value resets the setting to its default.
+func (ec *esClient) PutIndexSettings(name string, settings map[string]interface{}) error {
+	body, err := utils.ToJSON(settings)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/_settings", name),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to put index settings",
+			"index", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// ShrinkIndex shrinks the source index into the target index with the given settings and aliases
+func (ec *esClient) ShrinkIndex(source, target string, settings map[string]interface{}, aliases map[string]estypes.IndexAlias) error {
+	body, err := utils.ToJSON(map[string]interface{}{
+		"settings": settings,
+		"aliases":  aliases,
+	})
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_shrink/%s", source, target),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to shrink index",
+			"index", source,
+			"target", target,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// ForceMerge merges the shards of the index into at most the given number of segments
+func (ec *esClient) ForceMerge(name string, maxNumSegments int32) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_forcemerge?max_num_segments=%d", name, maxNumSegments),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to force merge index",
+			"index", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
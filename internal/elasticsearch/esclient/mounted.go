@@ -0,0 +1,109 @@
+package esclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/go-logr/logr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewMountedCredentialsSendRequestFn returns a FnEsSendRequest for workloads running next to
+// the cluster (e.g. the index management jobs). Requests are sent to the given service URL
+// using the mounted service account token and validated against the mounted CA file instead
+// of reading the cluster secret through the API server.
+func NewMountedCredentialsSendRequestFn(serviceURL, caFile string) FnEsSendRequest {
+	httpClient := &cachedHTTPClient{
+		newClient: func() (*http.Client, error) {
+			return getMountedCATLSClient(caFile, nil)
+		},
+	}
+	return func(ctx context.Context, log logr.Logger, _, _ string, payload *EsRequest, _ k8sclient.Client) error {
+		return sendMountedRequest(ctx, log, serviceURL, payload, true, httpClient)
+	}
+}
+
+// NewMountedCertificatesSendRequestFn returns a FnEsSendRequest for workloads running next to
+// the cluster which require the privileges of the admin user (e.g. the snapshot jobs). Requests
+// are authenticated with the admin client certificate of the mounted cluster secret.
+func NewMountedCertificatesSendRequestFn(serviceURL, certsDir string) FnEsSendRequest {
+	httpClient := &cachedHTTPClient{
+		newClient: func() (*http.Client, error) {
+			certFile := filepath.Join(certsDir, "admin-cert")
+			keyFile := filepath.Join(certsDir, "admin-key")
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, kverrors.Wrap(err, "failed to load client certificate",
+					"cert_file", certFile,
+					"key_file", keyFile)
+			}
+			return getMountedCATLSClient(filepath.Join(certsDir, "admin-ca"), []tls.Certificate{cert})
+		},
+	}
+	return func(ctx context.Context, log logr.Logger, _, _ string, payload *EsRequest, _ k8sclient.Client) error {
+		return sendMountedRequest(ctx, log, serviceURL, payload, false, httpClient)
+	}
+}
+
+// cachedHTTPClient builds the TLS client from the mounted files on the first request and
+// reuses it for the following ones. A failed build is retried on the next request.
+type cachedHTTPClient struct {
+	lock      sync.Mutex
+	client    *http.Client
+	newClient func() (*http.Client, error)
+}
+
+func (c *cachedHTTPClient) get() (*http.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.client == nil {
+		client, err := c.newClient()
+		if err != nil {
+			return nil, err
+		}
+		c.client = client
+	}
+	return c.client, nil
+}
+
+func sendMountedRequest(ctx context.Context, log logr.Logger, serviceURL string, payload *EsRequest, withToken bool, cache *cachedHTTPClient) error {
+	httpClient, err := cache.get()
+	if err != nil {
+		payload.Error = err
+		return err
+	}
+
+	var header http.Header
+	if withToken {
+		header = ensureTokenHeader(log, nil)
+	}
+
+	u := fmt.Sprintf("%s/%s", serviceURL, payload.URI)
+	statusCode, rawBody, err := retryRequest(ctx, payload.Method, func(ctx context.Context) (int, string, error) {
+		return doRequest(ctx, httpClient, u, payload, header)
+	})
+
+	setResponse(log, payload, statusCode, rawBody)
+	payload.Error = err
+	return err
+}
+
+func getMountedCATLSClient(caFile string, certificates []tls.Certificate) (*http.Client, error) {
+	caPem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to read CA file", "file", caFile)
+	}
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(caPem); !ok {
+		return nil, kverrors.New("failed to parse CA file", "file", caFile)
+	}
+
+	return newHTTPClient(certPool, certificates), nil
+}
@@ -0,0 +1,41 @@
+package esclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCachedHTTPClientBuildsTheClientOnce(t *testing.T) {
+	builds := 0
+	fail := true
+	cache := &cachedHTTPClient{
+		newClient: func() (*http.Client, error) {
+			builds++
+			if fail {
+				return nil, errors.New("missing CA file")
+			}
+			return &http.Client{}, nil
+		},
+	}
+
+	if _, err := cache.get(); err == nil {
+		t.Fatal("Exp. the build error to be returned")
+	}
+
+	fail = false
+	first, err := cache.get()
+	if err != nil {
+		t.Fatalf("Exp. the client to be built after a failed build: %v", err)
+	}
+	second, err := cache.get()
+	if err != nil {
+		t.Fatalf("Exp. no error for the cached client: %v", err)
+	}
+	if first != second {
+		t.Error("Exp. the client to be reused for the following requests")
+	}
+	if builds != 2 {
+		t.Errorf("Exp. 2 builds but got %d", builds)
+	}
+}
@@ -1,11 +1,16 @@
 package esclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/ViaQ/logerr/v2/kverrors"
 	"github.com/inhies/go-bytesize"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 )
 
 func (ec *esClient) GetNodeDiskUsage(nodeName string) (string, float64, error) {
@@ -14,25 +19,67 @@ func (ec *esClient) GetNodeDiskUsage(nodeName string) (string, float64, error) {
 		URI:    "_nodes/stats/fs",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
 	usage := ""
 	percentUsage := float64(-1)
 
-	if payload, ok := payload.ResponseBody["nodes"].(map[string]interface{}); ok {
-		for _, stats := range payload {
-			// ignore the key name here, it is the node UUID
-			if parseString("name", stats.(map[string]interface{})) == nodeName {
-				total := parseFloat64("fs.total.total_in_bytes", stats.(map[string]interface{}))
-				available := parseFloat64("fs.total.available_in_bytes", stats.(map[string]interface{}))
-
-				percentUsage = (total - available) / total * 100.00
-				usage = strings.TrimSuffix(fmt.Sprintf("%s", bytesize.New(total)-bytesize.New(available)), "B")
+	res := estypes.NodesStatsResponse{}
+	if err := ec.decodeResponse(payload, &res); err != nil {
+		return usage, percentUsage, err
+	}
 
-				break
-			}
+	// ignore the key name here, it is the node UUID
+	for _, stats := range res.Nodes {
+		if stats.Name != nodeName {
+			continue
 		}
+		total := float64(stats.FS.Total.TotalInBytes)
+		available := float64(stats.FS.Total.AvailableInBytes)
+		if total <= 0 {
+			break
+		}
+
+		percentUsage = (total - available) / total * 100.00
+		usage = strings.TrimSuffix(fmt.Sprintf("%s", bytesize.New(total)-bytesize.New(available)), "B")
+		break
+	}
+
+	return usage, percentUsage, nil
+}
+
+// GetDiskTotal returns the sum of the disk space in bytes of all data nodes
+func (ec *esClient) GetDiskTotal() (uint64, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/allocation?format=json&h=node,disk.total&bytes=b",
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return 0, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return 0, ec.errorCtx().New("failed to get disk allocation",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
 	}
 
-	return usage, percentUsage, payload.Error
+	allocations := estypes.CatAllocationResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &allocations); err != nil {
+		return 0, kverrors.Wrap(err, "failed to parse _cat/allocation response body")
+	}
+
+	total := uint64(0)
+	for _, allocation := range allocations {
+		// unassigned shards are reported without a node and disk
+		if allocation.DiskTotal == "" {
+			continue
+		}
+		size, err := strconv.ParseUint(allocation.DiskTotal, 10, 64)
+		if err != nil {
+			return 0, kverrors.Wrap(err, "failed to parse disk total", "node", allocation.Node)
+		}
+		total += size
+	}
+	return total, nil
 }
@@ -0,0 +1,63 @@
+package esclient
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// GetRemoteInfo returns the connection state of the configured remote clusters
+func (ec *esClient) GetRemoteInfo() (estypes.RemoteInfoResponse, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_remote/info",
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get remote cluster info",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.RemoteInfoResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse remote cluster info response body")
+	}
+	return res, nil
+}
+
+// UpdateRemoteClusters sets the persistent settings of the given remote clusters
+func (ec *esClient) UpdateRemoteClusters(remotes map[string]estypes.RemoteClusterSettings) error {
+	settings := map[string]interface{}{
+		"persistent": map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"remote": remotes,
+			},
+		},
+	}
+	body, err := utils.ToJSON(settings)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update remote cluster settings",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
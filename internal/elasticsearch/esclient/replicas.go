@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"math"
 	"net/http"
-	"strconv"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 )
 
 // This will idempotently update the index templates and update indices' replica count
@@ -18,78 +19,69 @@ func (ec *esClient) UpdateReplicaCount(replicaCount int32) error {
 }
 
 func (ec *esClient) updateAllIndexReplicas(replicaCount int32) (bool, error) {
-	indexHealth, _ := ec.GetIndexReplicaCounts()
+	indexSettings, err := ec.GetIndexReplicaCounts()
+	if err != nil {
+		return false, err
+	}
 
 	// get list of indices and call updateIndexReplicas for each one
-	for index, health := range indexHealth {
-		if healthMap, ok := health.(map[string]interface{}); ok {
-			// only update replicas for indices that don't have same replica count
-			if numberOfReplicas := parseString("settings.index.number_of_replicas", healthMap); numberOfReplicas != "" {
-				currentReplicas, err := strconv.ParseInt(numberOfReplicas, 10, 32)
-				if err != nil {
-					return false, err
-				}
-
-				if int32(currentReplicas) != replicaCount {
-					// best effort initially?
-					if ack, err := ec.updateIndexReplicas(index, replicaCount); err != nil {
-						return ack, err
-					}
-				}
+	for index, settings := range indexSettings {
+		currentReplicas, ok := numberOfReplicas(settings)
+		if !ok {
+			continue
+		}
+
+		// only update replicas for indices that don't have same replica count
+		if currentReplicas != replicaCount {
+			// best effort initially?
+			if ack, err := ec.updateIndexReplicas(index, replicaCount); err != nil {
+				return ack, err
 			}
-		} else {
-			return false, ec.errorCtx().New("unable to evaluate the number of replicas for index",
-				"index", index,
-				"health", health,
-			)
 		}
 	}
 
 	return true, nil
 }
 
-func (ec *esClient) GetIndexReplicaCounts() (map[string]interface{}, error) {
+func (ec *esClient) GetIndexReplicaCounts() (estypes.IndicesSettingsResponse, error) {
 	payload := &EsRequest{
 		Method: http.MethodGet,
 		URI:    "app-*,infra-*,audit-*/_settings/index.number_of_replicas",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	return payload.ResponseBody, payload.Error
+	res := estypes.IndicesSettingsResponse{}
+	if err := ec.decodeResponse(payload, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (ec *esClient) GetLowestReplicaValue() (int32, error) {
 	lowestReplica := int32(math.MaxInt32)
-	indexHealth, err := ec.GetIndexReplicaCounts()
+	indexSettings, err := ec.GetIndexReplicaCounts()
 	if err != nil {
 		return lowestReplica, err
 	}
 
-	// get list of indices and call updateIndexReplicas for each one
-	for index, health := range indexHealth {
-		if healthMap, ok := health.(map[string]interface{}); ok {
-			if numberOfReplicas := parseString("settings.index.number_of_replicas", healthMap); numberOfReplicas != "" {
-				currentReplicas, err := strconv.ParseInt(numberOfReplicas, 10, 32)
-				if err != nil {
-					return lowestReplica, err
-				}
-
-				if int32(currentReplicas) < lowestReplica {
-					lowestReplica = int32(currentReplicas)
-				}
-			}
-		} else {
-			return lowestReplica, ec.errorCtx().New("unable to evaluate the number of replicas for index",
-				"index", index,
-				"health", health,
-			)
+	for _, settings := range indexSettings {
+		if currentReplicas, ok := numberOfReplicas(settings); ok && currentReplicas < lowestReplica {
+			lowestReplica = currentReplicas
 		}
 	}
 
 	return lowestReplica, nil
 }
 
+// numberOfReplicas returns the number of replicas of the index settings if present
+func numberOfReplicas(index estypes.Index) (int32, bool) {
+	if index.Settings == nil || index.Settings.Index == nil {
+		return 0, false
+	}
+	return index.Settings.Index.NumberOfReplicas, true
+}
+
 func (ec *esClient) updateIndexReplicas(index string, replicaCount int32) (bool, error) {
 	payload := &EsRequest{
 		Method:      http.MethodPut,
@@ -97,11 +89,7 @@ func (ec *esClient) updateIndexReplicas(index string, replicaCount int32) (bool,
 		RequestBody: fmt.Sprintf("{%q:\"%d\"}}", "index.number_of_replicas", replicaCount),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	acknowledged := false
-	if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
-		acknowledged = acknowledgedBool
-	}
-	return payload.StatusCode == 200 && acknowledged, payload.Error
+	return ec.acknowledged(payload)
 }
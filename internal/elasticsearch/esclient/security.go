@@ -0,0 +1,134 @@
+package esclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// securityURI returns the URI of the REST API of the security plugin of the flavor
+func (ec *esClient) securityURI(path string) string {
+	if ec.flavor == api.OpenSearchFlavor {
+		return fmt.Sprintf("_plugins/_security/api/%s", path)
+	}
+	return fmt.Sprintf("_opendistro/_security/api/%s", path)
+}
+
+// GetSecurityConfig returns the config of the security plugin
+func (ec *esClient) GetSecurityConfig() (*estypes.SecurityConfig, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    ec.securityURI("securityconfig"),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get security config",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := &estypes.SecurityConfigResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse security config response body")
+	}
+	return &res.Config, nil
+}
+
+// UpdateSecurityConfig replaces the config of the security plugin. The nodes have to allow
+// the modification of the security config with the REST API.
+func (ec *esClient) UpdateSecurityConfig(config *estypes.SecurityConfig) error {
+	body, err := utils.ToJSON(config)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         ec.securityURI("securityconfig/config"),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update security config",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetInternalUsers returns the users of the internal users database of the security plugin
+func (ec *esClient) GetInternalUsers() (estypes.InternalUsersResponse, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    ec.securityURI("internalusers"),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get internal users",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.InternalUsersResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse internal users response body")
+	}
+	return res, nil
+}
+
+// PutInternalUser creates or replaces the internal user
+func (ec *esClient) PutInternalUser(name string, user estypes.InternalUser) error {
+	body, err := utils.ToJSON(user)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         ec.securityURI("internalusers/" + url.PathEscape(name)),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated {
+		return ec.errorCtx().New("failed to put internal user",
+			"user", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// DeleteInternalUser removes the internal user, a missing user is not an error
+func (ec *esClient) DeleteInternalUser(name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    ec.securityURI("internalusers/" + url.PathEscape(name)),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusNotFound {
+		return ec.errorCtx().New("failed to delete internal user",
+			"user", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
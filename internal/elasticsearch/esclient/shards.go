@@ -1,10 +1,14 @@
 package esclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/ViaQ/logerr/v2/kverrors"
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 )
 
 func (ec *esClient) ClearTransientShardAllocation() (bool, error) {
@@ -14,13 +18,10 @@ func (ec *esClient) ClearTransientShardAllocation() (bool, error) {
 		RequestBody: fmt.Sprintf("{%q:{%q:null}}", "transient", "cluster.routing.allocation.enable"),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	acknowledged := false
-	if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
-		acknowledged = acknowledgedBool
-	}
-	return payload.StatusCode == 200 && acknowledged, ec.errorCtx().Wrap(payload.Error, "failed to clear shard allocation",
+	ack, err := ec.acknowledged(payload)
+	return ack, ec.errorCtx().Wrap(err, "failed to clear shard allocation",
 		"response", payload.RawResponseBody)
 }
 
@@ -31,47 +32,130 @@ func (ec *esClient) SetShardAllocation(state api.ShardAllocationState) (bool, er
 		RequestBody: fmt.Sprintf("{%q:{%q:%q}}", "persistent", "cluster.routing.allocation.enable", state),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
-	acknowledged := false
-	if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
-		acknowledged = acknowledgedBool
-	}
-	return payload.StatusCode == 200 && acknowledged, ec.errorCtx().Wrap(payload.Error, "failed to set shard allocation")
+	ack, err := ec.acknowledged(payload)
+	return ack, ec.errorCtx().Wrap(err, "failed to set shard allocation")
 }
 
 func (ec *esClient) GetShardAllocation() (string, error) {
+	settings, err := ec.GetClusterSettings()
+	if err != nil {
+		return "", err
+	}
+
+	allocation, _ := settings.Setting("cluster.routing.allocation.enable")
+	return allocation, nil
+}
+
+// GetIndexShards returns the shards of the given index and the nodes they are allocated to
+func (ec *esClient) GetIndexShards(name string) (estypes.CatShardsResponses, error) {
 	payload := &EsRequest{
 		Method: http.MethodGet,
-		URI:    "_cluster/settings?include_defaults=true",
+		URI:    fmt.Sprintf("_cat/shards/%s?format=json&h=index,shard,prirep,state,node", name),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get shards",
+			"index", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	shards := estypes.CatShardsResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &shards); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/shards response body",
+			"index", name)
+	}
+	return shards, nil
+}
 
-	var allocation interface{}
+func (ec *esClient) GetAllocationAwarenessAttributes() (string, error) {
+	settings, err := ec.getClusterSettings("_cluster/settings")
+	if err != nil {
+		return "", ec.errorCtx().Wrap(err, "failed to get allocation awareness attributes")
+	}
+
+	attributes, _ := settings.Persistent.Get("cluster.routing.allocation.awareness.attributes")
+	return attributes, nil
+}
 
-	if value := walkInterfaceMap(
-		"defaults.cluster.routing.allocation.enable",
-		payload.ResponseBody); value != nil {
-		allocation = value
+// SetAllocationAwarenessAttributes sets the node attributes the copies of a shard are spread
+// across. Empty attributes remove the setting.
+func (ec *esClient) SetAllocationAwarenessAttributes(attributes string) (bool, error) {
+	value := "null"
+	if attributes != "" {
+		value = fmt.Sprintf("%q", attributes)
 	}
 
-	if value := walkInterfaceMap(
-		"persistent.cluster.routing.allocation.enable",
-		payload.ResponseBody); value != nil {
-		allocation = value
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: fmt.Sprintf("{%q:{%q:%s}}", "persistent", "cluster.routing.allocation.awareness.attributes", value),
 	}
 
-	if value := walkInterfaceMap(
-		"transient.cluster.routing.allocation.enable",
-		payload.ResponseBody); value != nil {
-		allocation = value
+	ec.send(payload)
+
+	ack, err := ec.acknowledged(payload)
+	return ack, ec.errorCtx().Wrap(err, "failed to set allocation awareness attributes")
+}
+
+// GetAllocationExcludedNodes returns the names of the nodes excluded from shard allocation
+func (ec *esClient) GetAllocationExcludedNodes() ([]string, error) {
+	settings, err := ec.getClusterSettings("_cluster/settings")
+	if err != nil {
+		return nil, ec.errorCtx().Wrap(err, "failed to get allocation excluded nodes")
 	}
 
-	allocationString, ok := allocation.(string)
-	if !ok {
-		allocationString = ""
+	names, _ := settings.Persistent.Get("cluster.routing.allocation.exclude._name")
+	if names == "" {
+		return []string{}, nil
 	}
+	return strings.Split(names, ","), nil
+}
+
+// SetAllocationExcludedNodes excludes the nodes from shard allocation, which moves their
+// shards to the other nodes. No names remove the setting.
+func (ec *esClient) SetAllocationExcludedNodes(names []string) (bool, error) {
+	value := "null"
+	if len(names) > 0 {
+		value = fmt.Sprintf("%q", strings.Join(names, ","))
+	}
+
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: fmt.Sprintf("{%q:{%q:%s}}", "persistent", "cluster.routing.allocation.exclude._name", value),
+	}
+
+	ec.send(payload)
 
-	return allocationString, payload.Error
+	return ec.acknowledged(payload)
+}
+
+// GetNodeShardCount returns the number of shards allocated to the node, including the ones
+// relocating away from it
+func (ec *esClient) GetNodeShardCount(nodeName string) (int, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/shards?format=json&h=index,shard,prirep,state,node",
+	}
+	ec.send(payload)
+
+	shards := estypes.CatShardsResponses{}
+	if err := ec.decodeResponse(payload, &shards); err != nil {
+		return 0, ec.errorCtx().Wrap(err, "failed to get shards", "node", nodeName)
+	}
+
+	count := 0
+	for _, shard := range shards {
+		// the node of a relocating shard reads "<source> -> <address> <id> <target>"
+		if fields := strings.Fields(shard.Node); len(fields) > 0 && fields[0] == nodeName {
+			count++
+		}
+	}
+	return count, nil
 }
@@ -0,0 +1,215 @@
+package esclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// GetSnapshotRepository returns the registered repository or nil if it does not exist
+func (ec *esClient) GetSnapshotRepository(name string) (*estypes.SnapshotRepository, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s", name),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get snapshot repository",
+			"repository", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.SnapshotRepositoriesResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse snapshot repository response body",
+			"repository", name)
+	}
+	repository, ok := res[name]
+	if !ok {
+		return nil, nil
+	}
+	return &repository, nil
+}
+
+// CreateSnapshotRepository registers or updates the repository
+func (ec *esClient) CreateSnapshotRepository(name string, repository *estypes.SnapshotRepository) error {
+	body, err := utils.ToJSON(repository)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_snapshot/%s", name),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to register snapshot repository",
+			"repository", name,
+			"type", repository.Type,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// CreateSnapshot starts a snapshot without waiting for its completion
+func (ec *esClient) CreateSnapshot(repository, name string, request *estypes.CreateSnapshotRequest) error {
+	body, err := utils.ToJSON(request)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_snapshot/%s/%s", repository, name),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to create snapshot",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetSnapshot returns the snapshot of the repository or nil if it does not exist
+func (ec *esClient) GetSnapshot(repository, name string) (*estypes.SnapshotInfo, error) {
+	snapshots, err := ec.getSnapshots(repository, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Snapshot == name {
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// ListSnapshots returns all snapshots of the repository
+func (ec *esClient) ListSnapshots(repository string) ([]estypes.SnapshotInfo, error) {
+	return ec.getSnapshots(repository, "_all")
+}
+
+func (ec *esClient) getSnapshots(repository, name string) ([]estypes.SnapshotInfo, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s/%s", repository, name),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get snapshots",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.SnapshotsResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse snapshots response body",
+			"repository", repository)
+	}
+	return res.Snapshots, nil
+}
+
+// DeleteSnapshot removes the snapshot from the repository
+func (ec *esClient) DeleteSnapshot(repository, name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("_snapshot/%s/%s", repository, name),
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusNotFound {
+		return ec.errorCtx().New("failed to delete snapshot",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// RestoreSnapshot starts restoring the indices of the snapshot without waiting for its completion
+func (ec *esClient) RestoreSnapshot(repository, name string, request *estypes.RestoreSnapshotRequest) error {
+	body, err := utils.ToJSON(request)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("_snapshot/%s/%s/_restore", repository, name),
+		RequestBody: body,
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusAccepted {
+		return ec.errorCtx().New("failed to restore snapshot",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetActiveRestores returns the shard recoveries in progress restoring from the snapshot
+func (ec *esClient) GetActiveRestores(repository, name string) (estypes.CatRecoveryResponses, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/recovery?format=json&active_only=true&h=index,type,stage,repository,snapshot",
+	}
+	ec.send(payload)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get active recoveries",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	recoveries := estypes.CatRecoveryResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &recoveries); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/recovery response body")
+	}
+
+	res := estypes.CatRecoveryResponses{}
+	for _, recovery := range recoveries {
+		if recovery.Type == "snapshot" && recovery.Repository == repository && recovery.Snapshot == name {
+			res = append(res, recovery)
+		}
+	}
+	return res, nil
+}
@@ -4,25 +4,51 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/constants"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// composableTemplatePriority is the priority of the composable index templates of the
+// operator. Overlapping composable templates must not share a priority.
+const composableTemplatePriority = 100
+
+// composableTemplates returns true if the cluster is managed with the composable index
+// template API instead of the legacy one
+func (ec *esClient) composableTemplates() bool {
+	return ec.flavor != "" && ec.flavor != api.ElasticsearchFlavor6
+}
+
+// templateURI returns the URI of the named index template for the template API of the cluster
+func (ec *esClient) templateURI(name string) string {
+	if ec.composableTemplates() {
+		return fmt.Sprintf("_index_template/%s", name)
+	}
+	return fmt.Sprintf("_template/%s", name)
+}
+
 func (ec *esClient) CreateIndexTemplate(name string, template *estypes.IndexTemplate) error {
-	body, err := utils.ToJSON(template)
+	var body string
+	var err error
+	if ec.composableTemplates() {
+		body, err = utils.ToJSON(estypes.NewComposableIndexTemplate(template, composableTemplatePriority))
+	} else {
+		body, err = utils.ToJSON(template)
+	}
 	if err != nil {
 		return err
 	}
 	payload := &EsRequest{
 		Method:      http.MethodPut,
-		URI:         fmt.Sprintf("_template/%s", name),
+		URI:         ec.templateURI(name),
 		RequestBody: body,
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil || (payload.StatusCode != 200 && payload.StatusCode != 201) {
 		return ec.errorCtx().New("failed to create index template",
 			"template", name,
@@ -31,16 +57,25 @@ func (ec *esClient) CreateIndexTemplate(name string, template *estypes.IndexTemp
 			"response_error", payload.Error,
 		)
 	}
+
+	if ec.composableTemplates() {
+		// the legacy template of a cluster upgraded from Elasticsearch 6 is replaced
+		return ec.deleteIndexTemplate(fmt.Sprintf("_template/%s", name), name)
+	}
 	return nil
 }
 
 func (ec *esClient) DeleteIndexTemplate(name string) error {
+	return ec.deleteIndexTemplate(ec.templateURI(name), name)
+}
+
+func (ec *esClient) deleteIndexTemplate(uri, name string) error {
 	payload := &EsRequest{
 		Method: http.MethodDelete,
-		URI:    fmt.Sprintf("_template/%s", name),
+		URI:    uri,
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error == nil && (payload.StatusCode == 404 || payload.StatusCode < 300) {
 		return nil
 	}
@@ -54,32 +89,68 @@ func (ec *esClient) DeleteIndexTemplate(name string) error {
 
 // ListTemplates returns a list of templates
 func (ec *esClient) ListTemplates() (sets.String, error) {
+	if ec.composableTemplates() {
+		templates, err := ec.getComposableIndexTemplates()
+		if err != nil {
+			return nil, err
+		}
+		response := sets.NewString()
+		for _, template := range templates {
+			response.Insert(template.Name)
+		}
+		return response, nil
+	}
+
 	payload := &EsRequest{
 		Method: http.MethodGet,
 		URI:    "_template",
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 	if payload.Error != nil || payload.StatusCode != 200 {
 		return nil, ec.errorCtx().New("failed to get list of index templates",
 			"response_status", payload.StatusCode,
 			"response_body", payload.ResponseBody,
 			"response_error", payload.Error)
 	}
+	templates := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &templates); err != nil {
+		return nil, ec.errorCtx().Wrap(err, "failed to decode list of index templates")
+	}
 	response := sets.NewString()
-	for name := range payload.ResponseBody {
+	for name := range templates {
 		response.Insert(name)
 	}
 	return response, nil
 }
 
 func (ec *esClient) GetIndexTemplates() (map[string]estypes.GetIndexTemplate, error) {
+	if ec.composableTemplates() {
+		composable, err := ec.getComposableIndexTemplates()
+		if err != nil {
+			return map[string]estypes.GetIndexTemplate{}, err
+		}
+		templates := map[string]estypes.GetIndexTemplate{}
+		for _, template := range composable {
+			if !isOperatorTemplate(template.Name) {
+				continue
+			}
+			templates[template.Name] = estypes.GetIndexTemplate{
+				Order:         template.IndexTemplate.Priority,
+				IndexPatterns: template.IndexTemplate.IndexPatterns,
+				Settings:      template.IndexTemplate.Template.Settings,
+				Aliases:       template.IndexTemplate.Template.Aliases,
+			}
+		}
+		return templates, nil
+	}
+
 	payload := &EsRequest{
 		Method: http.MethodGet,
 		URI:    fmt.Sprintf("_template/common.*,%s-*", constants.OcpTemplatePrefix),
 	}
 
-	ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	ec.send(payload)
 
 	// unmarshal response body and return that
 	templates := map[string]estypes.GetIndexTemplate{}
@@ -91,86 +162,106 @@ func (ec *esClient) GetIndexTemplates() (map[string]estypes.GetIndexTemplate, er
 	return templates, payload.Error
 }
 
-func (ec *esClient) updateAllIndexTemplateReplicas(replicaCount int32) (bool, error) {
-	// get the index template and then update the replica and put it
-	indexTemplates, err := ec.GetIndexTemplates()
-	if err != nil {
-		return false, err
+// getComposableIndexTemplates returns all composable index templates of the cluster
+func (ec *esClient) getComposableIndexTemplates() ([]estypes.NamedComposableIndexTemplate, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_index_template",
 	}
 
-	replicaString := fmt.Sprintf("%d", replicaCount)
-
-	for templateName, template := range indexTemplates {
-
-		currentReplicas := template.Settings.Index.NumberOfReplicas
-		if currentReplicas != replicaString {
-			template.Settings.Index.NumberOfReplicas = replicaString
-
-			templateJSON, err := json.Marshal(template)
-			if err != nil {
-				return false, err
-			}
-
-			payload := &EsRequest{
-				Method:      http.MethodPut,
-				URI:         fmt.Sprintf("_template/%s", templateName),
-				RequestBody: string(templateJSON),
-			}
+	ec.send(payload)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
 
-			ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	res := estypes.ComposableIndexTemplatesResponse{}
+	if err := ec.decodeResponse(payload, &res); err != nil {
+		return nil, err
+	}
+	return res.IndexTemplates, nil
+}
 
-			acknowledged := false
-			if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
-				acknowledged = acknowledgedBool
-			}
+// isOperatorTemplate returns true for the templates the replica and shard counts are managed of
+func isOperatorTemplate(name string) bool {
+	return strings.HasPrefix(name, "common.") || strings.HasPrefix(name, constants.OcpTemplatePrefix+"-")
+}
 
-			if !(payload.StatusCode == 200 && acknowledged) {
-				ec.log.Error(payload.Error, "unable to update template", "cluster", ec.cluster, "namespace", ec.namespace, "template", templateName)
+// updateTemplateSettings puts the index templates the settings of which are changed by update
+func (ec *esClient) updateTemplateSettings(update func(settings *estypes.IndexTemplateSettings) bool) error {
+	if ec.composableTemplates() {
+		templates, err := ec.getComposableIndexTemplates()
+		if err != nil {
+			return err
+		}
+		for _, template := range templates {
+			if !isOperatorTemplate(template.Name) || !update(&template.IndexTemplate.Template.Settings.Index) {
+				continue
 			}
+			ec.putTemplate(template.Name, template.IndexTemplate)
 		}
+		return nil
 	}
 
-	return true, nil
-}
-
-func (ec *esClient) UpdateTemplatePrimaryShards(shardCount int32) error {
-	// get the index template and then update the shards and put it
 	indexTemplates, err := ec.GetIndexTemplates()
 	if err != nil {
 		return err
 	}
-
-	shardString := fmt.Sprintf("%d", shardCount)
-
 	for templateName, template := range indexTemplates {
+		if !update(&template.Settings.Index) {
+			continue
+		}
+		ec.putTemplate(templateName, template)
+	}
+	return nil
+}
 
-		currentShards := template.Settings.Index.NumberOfShards
-		if currentShards != shardString {
-			template.Settings.Index.NumberOfShards = shardString
+func (ec *esClient) putTemplate(name string, template interface{}) {
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		ec.log.Error(err, "unable to encode template", "cluster", ec.cluster, "namespace", ec.namespace, "template", name)
+		return
+	}
 
-			templateJSON, err := json.Marshal(template)
-			if err != nil {
-				return err
-			}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         ec.templateURI(name),
+		RequestBody: string(templateJSON),
+	}
 
-			payload := &EsRequest{
-				Method:      http.MethodPut,
-				URI:         fmt.Sprintf("_template/%s", templateName),
-				RequestBody: string(templateJSON),
-			}
+	ec.send(payload)
 
-			ec.fnSendEsRequest(ec.log, ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if ack, err := ec.acknowledged(payload); !ack {
+		ec.log.Error(err, "unable to update template", "cluster", ec.cluster, "namespace", ec.namespace, "template", name)
+	}
+}
 
-			acknowledged := false
-			if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
-				acknowledged = acknowledgedBool
-			}
+func (ec *esClient) updateAllIndexTemplateReplicas(replicaCount int32) (bool, error) {
+	// get the index template and then update the replica and put it
+	replicaString := fmt.Sprintf("%d", replicaCount)
 
-			if !(payload.StatusCode == 200 && acknowledged) {
-				ec.log.Error(payload.Error, "unable to update template", "cluster", ec.cluster, "namespace", ec.namespace, "template", templateName)
-			}
+	err := ec.updateTemplateSettings(func(settings *estypes.IndexTemplateSettings) bool {
+		if settings.NumberOfReplicas == replicaString {
+			return false
 		}
+		settings.NumberOfReplicas = replicaString
+		return true
+	})
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	return true, nil
+}
+
+func (ec *esClient) UpdateTemplatePrimaryShards(shardCount int32) error {
+	// get the index template and then update the shards and put it
+	shardString := fmt.Sprintf("%d", shardCount)
+
+	return ec.updateTemplateSettings(func(settings *estypes.IndexTemplateSettings) bool {
+		if settings.NumberOfShards == shardString {
+			return false
+		}
+		settings.NumberOfShards = shardString
+		return true
+	})
 }
@@ -2,9 +2,11 @@ package esclient_test
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -88,3 +90,79 @@ func TestCreateIndexTemplateWhenResponse201(t *testing.T) {
 		t.Errorf("Exp. to not return an error %v", err)
 	}
 }
+
+func TestCreateComposableIndexTemplate(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_index_template/foo": {
+				{
+					StatusCode: 200,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+			"_template/foo": {
+				{
+					StatusCode: 404,
+					Body:       "{}",
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+	esClient.SetFlavor(api.ElasticsearchFlavor7)
+
+	if err := esClient.CreateIndexTemplate("foo", indexTemplate); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+
+	request, found := chatter.GetRequest("_index_template/foo")
+	if !found {
+		t.Fatalf("Exp. the composable index template to be put")
+	}
+	exp := `{"index_patterns":["abc-**"],"priority":100,"template":{"settings":{"index":{"number_of_shards":"1"}},"aliases":{"foo":{}}}}`
+	if request.Body != exp {
+		t.Errorf("Exp. the composable index template %s but got %s", exp, request.Body)
+	}
+	if request, found := chatter.GetRequest("_template/foo"); !found || request.Method != http.MethodDelete {
+		t.Errorf("Exp. the legacy index template to be deleted but got %v", request)
+	}
+}
+
+func TestUpdateTemplatePrimaryShardsOfComposableIndexTemplates(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_index_template": {
+				{
+					StatusCode: 200,
+					Body: `{"index_templates": [
+						{"name": "ocp-gen-app", "index_template": {"index_patterns": ["app*"], "priority": 100, "template": {"settings": {"index": {"number_of_shards": "1"}}, "mappings": {"properties": {"message": {"type": "text"}}}}}},
+						{"name": "other", "index_template": {"index_patterns": ["other*"], "template": {"settings": {"index": {"number_of_shards": "1"}}}}}
+					]}`,
+				},
+			},
+			"_index_template/ocp-gen-app": {
+				{
+					StatusCode: 200,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+	esClient.SetFlavor(api.OpenSearchFlavor)
+
+	if err := esClient.UpdateTemplatePrimaryShards(3); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+
+	request, found := chatter.GetRequest("_index_template/ocp-gen-app")
+	if !found {
+		t.Fatalf("Exp. the composable index template of the operator to be updated")
+	}
+	for _, exp := range []string{`"number_of_shards":"3"`, `"mappings":{"properties":{"message":{"type":"text"}}}`} {
+		if !strings.Contains(request.Body, exp) {
+			t.Errorf("Exp. the updated index template to contain %s but got %s", exp, request.Body)
+		}
+	}
+	if _, found := chatter.GetRequest("_index_template/other"); found {
+		t.Errorf("Exp. the index templates not managed by the operator to be kept")
+	}
+}
@@ -0,0 +1,262 @@
+package esclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/go-logr/logr"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requestTimeout bounds a single attempt of a request to the cluster
+var requestTimeout = 30 * time.Second
+
+// requestBackoff is used to retry the idempotent requests failing with a connection error
+// or with a temporarily unavailable cluster
+var requestBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    3,
+}
+
+// clusterTransport holds the HTTP clients of a cluster built from the cluster secret
+type clusterTransport struct {
+	secretVersion string
+	// tokenClient is used with the SA token, it does not present any client certs
+	tokenClient *http.Client
+	// mTLSClient is used in the case where the SA token is not honored. It presents
+	// the admin client certs.
+	mTLSClient *http.Client
+}
+
+// transports caches the HTTP clients by cluster until the cluster secret changes
+var transports = struct {
+	sync.Mutex
+	clusters map[types.NamespacedName]*clusterTransport
+}{
+	clusters: map[types.NamespacedName]*clusterTransport{},
+}
+
+// getClusterTransport returns the cached HTTP clients of the cluster or builds new ones
+// if the cluster secret changed
+func getClusterTransport(ctx context.Context, log logr.Logger, clusterName, namespace string, client k8sclient.Client) (*clusterTransport, error) {
+	key := types.NamespacedName{Name: clusterName, Namespace: namespace}
+	s, err := secret.Get(ctx, client, key)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to get cluster secret")
+	}
+
+	transports.Lock()
+	defer transports.Unlock()
+
+	current, ok := transports.clusters[key]
+	if ok && current.secretVersion == s.ResourceVersion {
+		return current, nil
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(s.Data["admin-ca"]) {
+		return nil, kverrors.New("failed to parse admin-ca of cluster secret",
+			"secret", key.String(),
+		)
+	}
+
+	var certificates []tls.Certificate
+	certificate, err := tls.X509KeyPair(s.Data["admin-cert"], s.Data["admin-key"])
+	if err != nil {
+		log.Error(err, "failed to parse admin client certificate of cluster secret", "secret", key.String())
+	} else {
+		certificates = append(certificates, certificate)
+	}
+
+	if ok {
+		current.tokenClient.CloseIdleConnections()
+		current.mTLSClient.CloseIdleConnections()
+	}
+
+	transport := &clusterTransport{
+		secretVersion: s.ResourceVersion,
+		tokenClient:   newHTTPClient(rootCAs, nil),
+		mTLSClient:    newHTTPClient(rootCAs, certificates),
+	}
+	transports.clusters[key] = transport
+	return transport, nil
+}
+
+// FlushTransport drops the cached HTTP clients of a cluster
+func FlushTransport(clusterName, namespace string) {
+	transports.Lock()
+	defer transports.Unlock()
+
+	key := types.NamespacedName{Name: clusterName, Namespace: namespace}
+	if current, ok := transports.clusters[key]; ok {
+		current.tokenClient.CloseIdleConnections()
+		current.mTLSClient.CloseIdleConnections()
+		delete(transports.clusters, key)
+	}
+}
+
+func newHTTPClient(rootCAs *x509.CertPool, certificates []tls.Certificate) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			TLSClientConfig: &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				RootCAs:      rootCAs,
+				Certificates: certificates,
+			},
+		},
+	}
+}
+
+// sendEsRequest sends the request to the cluster service with the SA token and falls back
+// to the admin client certs if the token is not honored. The error is returned and set on
+// the payload.
+func sendEsRequest(ctx context.Context, log logr.Logger, cluster, namespace string, payload *EsRequest, client k8sclient.Client) error {
+	transport, err := getClusterTransport(ctx, log, cluster, namespace, client)
+	if err != nil {
+		payload.Error = err
+		return err
+	}
+
+	u := fmt.Sprintf("https://%s.%s.svc:9200/%s", cluster, namespace, payload.URI)
+	statusCode, rawBody, err := retryRequest(ctx, payload.Method, func(ctx context.Context) (int, string, error) {
+		statusCode, rawBody, err := doRequest(ctx, transport.tokenClient, u, payload, ensureTokenHeader(log, nil))
+		if err != nil {
+			return statusCode, rawBody, err
+		}
+
+		// TODO: eventually remove after all ES images have been updated to use SA token auth for EO?
+		if statusCode == http.StatusForbidden || statusCode == http.StatusUnauthorized {
+			log.Info("failed sending payload using bearer token", "method", payload.Method, "url", payload.URI)
+			// if we get a 401 that means that we couldn't read from the token and provided
+			// no header.
+			// if we get a 403 that means the ES cluster doesn't allow us to use
+			// our SA token.
+			// in both cases, try the old way.
+			statusCode, rawBody, err = doRequest(ctx, transport.mTLSClient, u, payload, nil)
+			if err == nil && (statusCode == http.StatusForbidden || statusCode == http.StatusUnauthorized) {
+				log.Info("failed sending payload using mTLS PKI", "method", payload.Method, "url", payload.URI)
+			}
+		}
+		return statusCode, rawBody, err
+	})
+
+	setResponse(log, payload, statusCode, rawBody)
+	payload.Error = err
+	return err
+}
+
+// doRequest sends a single attempt of the request within the request timeout and reads
+// the response
+func doRequest(ctx context.Context, httpClient *http.Client, u string, payload *EsRequest, header http.Header) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var body io.Reader
+	if payload.RequestBody != "" {
+		body = strings.NewReader(payload.RequestBody)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, payload.Method, u, body)
+	if err != nil {
+		return 0, "", kverrors.Wrap(err, "failed to create request",
+			"method", payload.Method,
+			"uri", payload.URI,
+		)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	if payload.RequestBody != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(request)
+	if err != nil {
+		return 0, "", kverrors.Wrap(err, "failed to send request",
+			"method", payload.Method,
+			"uri", payload.URI,
+		)
+	}
+	defer resp.Body.Close()
+
+	rawBody, err := getRawBody(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", kverrors.Wrap(err, "failed to read response body",
+			"method", payload.Method,
+			"uri", payload.URI,
+		)
+	}
+	return resp.StatusCode, rawBody, nil
+}
+
+// retryRequest retries the idempotent requests with backoff as long as they fail with a
+// connection error or a temporarily unavailable cluster
+func retryRequest(ctx context.Context, method string, attempt func(ctx context.Context) (int, string, error)) (int, string, error) {
+	backoff := requestBackoff
+	for {
+		statusCode, rawBody, err := attempt(ctx)
+		if !isIdempotent(method) || (err == nil && !isRetryableStatus(statusCode)) || backoff.Steps <= 1 {
+			return statusCode, rawBody, err
+		}
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statusCode, rawBody, err
+		case <-timer.C:
+		}
+	}
+}
+
+// isIdempotent returns true for the methods which can be safely retried
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus returns true for the responses of a temporarily unavailable cluster
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// setResponse sets the status code and the response body on the payload
+func setResponse(log logr.Logger, payload *EsRequest, statusCode int, rawBody string) {
+	payload.StatusCode = statusCode
+	payload.RawResponseBody = rawBody
+
+	var err error
+	if payload.ResponseBody, err = getMapFromBody(rawBody); err != nil {
+		log.Error(err, "getMapFromBody failed")
+	}
+}
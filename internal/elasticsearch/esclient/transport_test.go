@@ -0,0 +1,138 @@
+package esclient
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDoRequestSupportsAllMethods(t *testing.T) {
+	var gotMethod, gotBody string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		body, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(body)
+		_, _ = w.Write([]byte(`{"acknowledged": true}`))
+	}))
+	defer server.Close()
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		payload := &EsRequest{Method: method, URI: "_template/test", RequestBody: `{"a":"b"}`}
+		statusCode, _, err := doRequest(context.TODO(), server.Client(), server.URL+"/"+payload.URI, payload, nil)
+		if err != nil {
+			t.Fatalf("Exp. no error for %s but got %v", method, err)
+		}
+		if statusCode != http.StatusOK || gotMethod != method {
+			t.Errorf("Exp. a %s request to succeed but got status %d for method %s", method, statusCode, gotMethod)
+		}
+		if gotBody != payload.RequestBody {
+			t.Errorf("Exp. the %s request body %s but got %s", method, payload.RequestBody, gotBody)
+		}
+	}
+}
+
+func TestDoRequestTimesOut(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	defaultTimeout := requestTimeout
+	requestTimeout = 50 * time.Millisecond
+	defer func() { requestTimeout = defaultTimeout }()
+
+	payload := &EsRequest{Method: http.MethodGet, URI: "_cluster/health"}
+	if _, _, err := doRequest(context.TODO(), server.Client(), server.URL+"/"+payload.URI, payload, nil); err == nil {
+		t.Errorf("Exp. the request to time out")
+	}
+}
+
+func TestRetryRequest(t *testing.T) {
+	defaultBackoff := requestBackoff
+	requestBackoff.Duration = time.Millisecond
+	defer func() { requestBackoff = defaultBackoff }()
+
+	attempts := 0
+	unavailableTwice := func(ctx context.Context) (int, string, error) {
+		attempts++
+		if attempts < 3 {
+			return http.StatusServiceUnavailable, "", nil
+		}
+		return http.StatusOK, "{}", nil
+	}
+
+	statusCode, _, err := retryRequest(context.TODO(), http.MethodGet, unavailableTwice)
+	if err != nil || statusCode != http.StatusOK || attempts != 3 {
+		t.Errorf("Exp. a GET request to succeed after 3 attempts but got status %d after %d attempts: %v", statusCode, attempts, err)
+	}
+
+	attempts = 0
+	statusCode, _, _ = retryRequest(context.TODO(), http.MethodPost, unavailableTwice)
+	if statusCode != http.StatusServiceUnavailable || attempts != 1 {
+		t.Errorf("Exp. a POST request not to be retried but got status %d after %d attempts", statusCode, attempts)
+	}
+
+	attempts = 0
+	statusCode, _, _ = retryRequest(context.TODO(), http.MethodGet, func(ctx context.Context) (int, string, error) {
+		attempts++
+		return http.StatusNotFound, "", nil
+	})
+	if statusCode != http.StatusNotFound || attempts != 1 {
+		t.Errorf("Exp. a not found response not to be retried but got status %d after %d attempts", statusCode, attempts)
+	}
+}
+
+func TestClusterTransportIsCachedUntilSecretChanges(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	caPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		Data:       map[string][]byte{"admin-ca": caPem},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(s).Build()
+	logger := log.NewLogger("transport-testing")
+	defer FlushTransport(s.Name, s.Namespace)
+
+	first, err := getClusterTransport(context.TODO(), logger, s.Name, s.Namespace, k8sClient)
+	if err != nil {
+		t.Fatalf("Failed to get transport: %v", err)
+	}
+	second, err := getClusterTransport(context.TODO(), logger, s.Name, s.Namespace, k8sClient)
+	if err != nil {
+		t.Fatalf("Failed to get transport: %v", err)
+	}
+	if first != second {
+		t.Errorf("Exp. the transport to be reused while the secret is unchanged")
+	}
+
+	s.Data["admin-key"] = []byte("rotated")
+	if err := k8sClient.Update(context.TODO(), s); err != nil {
+		t.Fatalf("Failed to update secret: %v", err)
+	}
+	third, err := getClusterTransport(context.TODO(), logger, s.Name, s.Namespace, k8sClient)
+	if err != nil {
+		t.Fatalf("Failed to get transport: %v", err)
+	}
+	if third == first {
+		t.Errorf("Exp. the transport to be rebuilt after the secret changed")
+	}
+
+	s.Data["admin-ca"] = []byte("invalid")
+	if err := k8sClient.Update(context.TODO(), s); err != nil {
+		t.Fatalf("Failed to update secret: %v", err)
+	}
+	if _, err := getClusterTransport(context.TODO(), logger, s.Name, s.Namespace, k8sClient); err == nil {
+		t.Errorf("Exp. an error for an invalid admin-ca")
+	}
+}
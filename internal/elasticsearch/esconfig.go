@@ -0,0 +1,229 @@
+package elasticsearch
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html/template"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+const (
+	configVolumeName = "elasticsearch-config"
+
+	// esConfigHashEnvVar carries the hash of the additional settings of a node to
+	// restart the node when they change
+	esConfigHashEnvVar = "ES_CONFIG_HASH"
+
+	ingestSetting = "node.ingest"
+
+	defaultMaxHeaderSize = "128kb"
+	maxHeaderSizeSetting = "http.max_header_size"
+)
+
+// operatorOwnedSettings are the elasticsearch.yml settings rendered by the operator.
+// They and the settings below them cannot be set by esConfig.
+var operatorOwnedSettings = []string{
+	"action.auto_create_index",
+	"bootstrap.system_call_filter",
+	"cluster.initial_master_nodes",
+	"cluster.name",
+	"cluster.remote",
+	"cluster.routing.allocation.awareness",
+	"discovery",
+	"gateway",
+	"network",
+	"node.attr.tier",
+	"node.attr.zone",
+	"node.data",
+	"node.ingest",
+	"node.master",
+	"node.max_local_storage_nodes",
+	"node.name",
+	"opendistro_security",
+	"path",
+	"plugins.security",
+	"prometheus",
+	"s3.client.default",
+}
+
+var esConfigKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]+(\.[a-zA-Z0-9_\-]+)*$`)
+
+// esConfigSetting is a setting rendered into elasticsearch.yml
+type esConfigSetting struct {
+	Key string
+	// Value is quoted as a YAML string and must not be escaped
+	Value template.HTML
+}
+
+// isOperatorOwnedSetting returns true if the setting is owned by the operator
+func isOperatorOwnedSetting(key string) bool {
+	for _, owned := range operatorOwnedSettings {
+		if key == owned || strings.HasPrefix(key, owned+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateESConfig splits the settings into the valid ones and the keys of the rejected ones
+func validateESConfig(config map[string]string) (map[string]string, []string) {
+	valid := map[string]string{}
+	var rejected []string
+	for key, value := range config {
+		if !esConfigKeyRegexp.MatchString(key) || isOperatorOwnedSetting(key) {
+			rejected = append(rejected, key)
+			continue
+		}
+		valid[key] = value
+	}
+	sort.Strings(rejected)
+	return valid, rejected
+}
+
+// rejectedESConfig returns the rejected settings of the node spec and the nodes
+func rejectedESConfig(cluster *api.Elasticsearch) []string {
+	var rejected []string
+	_, keys := validateESConfig(cluster.Spec.Spec.ESConfig)
+	for _, key := range keys {
+		rejected = append(rejected, fmt.Sprintf("nodeSpec.esConfig[%s]", key))
+	}
+	for i, node := range cluster.Spec.Nodes {
+		_, keys := validateESConfig(node.ESConfig)
+		for _, key := range keys {
+			rejected = append(rejected, fmt.Sprintf("nodes[%d].esConfig[%s]", i, key))
+		}
+	}
+	return rejected
+}
+
+// nodeOwnESConfig returns the valid settings of the node, the zone and tier attributes of
+// the node and the ingest role of the nodes not running ingest pipelines
+func nodeOwnESConfig(cluster *api.Elasticsearch, node api.ElasticsearchNode) map[string]string {
+	config, _ := validateESConfig(node.ESConfig)
+	if !isIngestingNode(cluster, node) {
+		config[ingestSetting] = "false"
+	}
+	if node.ZoneAwareness != nil {
+		config[zoneAttributeSetting] = fmt.Sprintf("${%s}", zoneEnvVar)
+	}
+	if node.Tier != "" {
+		config[tierAttributeSetting] = string(node.Tier)
+	}
+	return config
+}
+
+// nodeESConfig returns the valid settings of the node spec merged with the ones of the node
+func nodeESConfig(cluster *api.Elasticsearch, node api.ElasticsearchNode) map[string]string {
+	config, _ := validateESConfig(cluster.Spec.Spec.ESConfig)
+	for key, value := range nodeOwnESConfig(cluster, node) {
+		config[key] = value
+	}
+	return config
+}
+
+// esConfigFileName returns the configmap key of the elasticsearch.yml of a node with
+// settings of its own. It is empty for the nodes using the common elasticsearch.yml.
+func esConfigFileName(cluster *api.Elasticsearch, node api.ElasticsearchNode) string {
+	if node.GenUUID == nil {
+		return ""
+	}
+	if len(nodeOwnESConfig(cluster, node)) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("elasticsearch-%s.yml", *node.GenUUID)
+}
+
+// esConfigFiles returns the additional settings by elasticsearch.yml configmap key
+func esConfigFiles(cluster *api.Elasticsearch) map[string]map[string]string {
+	common, _ := validateESConfig(cluster.Spec.Spec.ESConfig)
+	files := map[string]map[string]string{
+		esConfig: common,
+	}
+	for _, node := range cluster.Spec.Nodes {
+		if name := esConfigFileName(cluster, node); name != "" {
+			files[name] = nodeESConfig(cluster, node)
+		}
+	}
+	return files
+}
+
+// newESConfigSettings returns the settings sorted by key with the values quoted
+func newESConfigSettings(config map[string]string) []esConfigSetting {
+	settings := make([]esConfigSetting, 0, len(config))
+	for key, value := range config {
+		settings = append(settings, esConfigSetting{
+			Key:   key,
+			Value: template.HTML(strconv.Quote(value)),
+		})
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Key < settings[j].Key
+	})
+	return settings
+}
+
+// esConfigHash returns a hash of the settings independent of their order
+func esConfigHash(config map[string]string) string {
+	h := sha256.New()
+	for _, setting := range newESConfigSettings(config) {
+		fmt.Fprintf(h, "%s=%s\n", setting.Key, setting.Value)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// addESConfig mounts the elasticsearch.yml of a node with settings of its own and sets
+// the hash of the additional settings on the elasticsearch container to restart the
+// node when they change
+func addESConfig(podTemplate *v1.PodTemplateSpec, cluster *api.Elasticsearch, node api.ElasticsearchNode) {
+	config := nodeESConfig(cluster, node)
+	if len(config) == 0 {
+		return
+	}
+
+	if name := esConfigFileName(cluster, node); name != "" {
+		for i, volume := range podTemplate.Spec.Volumes {
+			if volume.Name != configVolumeName || volume.ConfigMap == nil {
+				continue
+			}
+			podTemplate.Spec.Volumes[i].ConfigMap.Items = []v1.KeyToPath{
+				{Key: name, Path: esConfig},
+				{Key: log4jConfig, Path: log4jConfig},
+				{Key: indexSettingsConfig, Path: indexSettingsConfig},
+			}
+		}
+	}
+
+	for i, container := range podTemplate.Spec.Containers {
+		if container.Name != "elasticsearch" {
+			continue
+		}
+		podTemplate.Spec.Containers[i].Env = append(container.Env, v1.EnvVar{
+			Name:  esConfigHashEnvVar,
+			Value: esConfigHash(config),
+		})
+	}
+}
+
+// syncConfigVolume replaces the config volume of the current volumes with the desired one
+// to mount the elasticsearch.yml of the node. The other volumes are kept as is.
+func syncConfigVolume(current, desired []v1.Volume) []v1.Volume {
+	volumes := make([]v1.Volume, 0, len(current))
+	for _, volume := range current {
+		if volume.Name == configVolumeName {
+			for _, d := range desired {
+				if d.Name == configVolumeName {
+					volume = d
+				}
+			}
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
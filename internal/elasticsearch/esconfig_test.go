@@ -0,0 +1,214 @@
+package elasticsearch
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+func TestValidateESConfig(t *testing.T) {
+	valid, rejected := validateESConfig(map[string]string{
+		"thread_pool.write.queue_size":                        "500",
+		"indices.breaker.total.limit":                         "70%",
+		"discovery.zen.minimum_master_nodes":                  "1",
+		"opendistro_security.ssl.http.enabled":                "false",
+		"cluster.name":                                        "other",
+		"cluster.routing.allocation.disk.watermark.low":       "80%",
+		"node.name.suffix":                                    "x",
+		"node.attr.rack":                                      "r1",
+		"bad key":                                             "x",
+		"network":                                             "x",
+		"networking.something":                                "x",
+		"http.max_content_length":                             "200mb",
+		"action.auto_create_index":                            "true",
+		"opendistro_security.ssl.transport.keystore_filepath": "/tmp/key.p12",
+	})
+
+	expValid := map[string]string{
+		"thread_pool.write.queue_size":                  "500",
+		"indices.breaker.total.limit":                   "70%",
+		"cluster.routing.allocation.disk.watermark.low": "80%",
+		"node.attr.rack":                                "r1",
+		"networking.something":                          "x",
+		"http.max_content_length":                       "200mb",
+	}
+	if !reflect.DeepEqual(valid, expValid) {
+		t.Errorf("Exp. valid settings %v but got %v", expValid, valid)
+	}
+
+	expRejected := []string{
+		"action.auto_create_index",
+		"bad key",
+		"cluster.name",
+		"discovery.zen.minimum_master_nodes",
+		"network",
+		"node.name.suffix",
+		"opendistro_security.ssl.http.enabled",
+		"opendistro_security.ssl.transport.keystore_filepath",
+	}
+	if !reflect.DeepEqual(rejected, expRejected) {
+		t.Errorf("Exp. rejected settings %v but got %v", expRejected, rejected)
+	}
+}
+
+func TestRejectedESConfig(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			Spec: api.ElasticsearchNodeSpec{
+				ESConfig: map[string]string{"discovery.zen.ping.unicast.hosts": "x"},
+			},
+			Nodes: []api.ElasticsearchNode{
+				{ESConfig: map[string]string{"thread_pool.write.queue_size": "500"}},
+				{ESConfig: map[string]string{"path.data": "/tmp"}},
+			},
+		},
+	}
+
+	exp := []string{
+		"nodeSpec.esConfig[discovery.zen.ping.unicast.hosts]",
+		"nodes[1].esConfig[path.data]",
+	}
+	if rejected := rejectedESConfig(cluster); !reflect.DeepEqual(rejected, exp) {
+		t.Errorf("Exp. rejected settings %v but got %v", exp, rejected)
+	}
+}
+
+func TestESConfigFiles(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			Spec: api.ElasticsearchNodeSpec{
+				ESConfig: map[string]string{
+					"thread_pool.write.queue_size":     "500",
+					"indices.memory.index_buffer_size": "20%",
+				},
+			},
+			Nodes: []api.ElasticsearchNode{
+				{GenUUID: pointer.String("abc"), ESConfig: map[string]string{"thread_pool.write.queue_size": "1000"}},
+				{GenUUID: pointer.String("def")},
+				{ESConfig: map[string]string{"thread_pool.write.queue_size": "1000"}},
+			},
+		},
+	}
+
+	exp := map[string]map[string]string{
+		"elasticsearch.yml": {
+			"thread_pool.write.queue_size":     "500",
+			"indices.memory.index_buffer_size": "20%",
+		},
+		"elasticsearch-abc.yml": {
+			"thread_pool.write.queue_size":     "1000",
+			"indices.memory.index_buffer_size": "20%",
+		},
+	}
+	if files := esConfigFiles(cluster); !reflect.DeepEqual(files, exp) {
+		t.Errorf("Exp. config files %v but got %v", exp, files)
+	}
+}
+
+func TestESConfigFilesOfIngestRoles(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			Nodes: []api.ElasticsearchNode{
+				{GenUUID: pointer.String("abc"), Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData, api.ElasticsearchRoleMaster}},
+				{GenUUID: pointer.String("def"), Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient}},
+			},
+		},
+	}
+
+	// without dedicated ingest nodes the nodes keep the default ingest role
+	exp := map[string]map[string]string{"elasticsearch.yml": {}}
+	if files := esConfigFiles(cluster); !reflect.DeepEqual(files, exp) {
+		t.Errorf("Exp. config files %v but got %v", exp, files)
+	}
+
+	cluster.Spec.Nodes = append(cluster.Spec.Nodes,
+		api.ElasticsearchNode{GenUUID: pointer.String("ghi"), Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleIngest}, NodeCount: 1},
+		api.ElasticsearchNode{GenUUID: pointer.String("jkl"), Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}},
+	)
+	exp = map[string]map[string]string{
+		"elasticsearch.yml":     {},
+		"elasticsearch-abc.yml": {"node.ingest": "false"},
+		"elasticsearch-def.yml": {"node.ingest": "false"},
+		"elasticsearch-jkl.yml": {"node.ingest": "false"},
+	}
+	if files := esConfigFiles(cluster); !reflect.DeepEqual(files, exp) {
+		t.Errorf("Exp. config files %v but got %v", exp, files)
+	}
+}
+
+func TestAddESConfig(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			Spec: api.ElasticsearchNodeSpec{
+				ESConfig: map[string]string{"indices.memory.index_buffer_size": "20%"},
+			},
+		},
+	}
+	node := api.ElasticsearchNode{
+		GenUUID:  pointer.String("abc"),
+		ESConfig: map[string]string{"thread_pool.write.queue_size": "1000"},
+	}
+
+	podTemplate := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addESConfig(&podTemplate, cluster, node)
+
+	var configVolume *v1.Volume
+	for i, volume := range podTemplate.Spec.Volumes {
+		if volume.Name == configVolumeName {
+			configVolume = &podTemplate.Spec.Volumes[i]
+		}
+	}
+	if configVolume == nil {
+		t.Fatalf("Exp. the config volume to exist")
+	}
+	expItems := []v1.KeyToPath{
+		{Key: "elasticsearch-abc.yml", Path: "elasticsearch.yml"},
+		{Key: "log4j2.properties", Path: "log4j2.properties"},
+		{Key: "index_settings", Path: "index_settings"},
+	}
+	if !reflect.DeepEqual(configVolume.ConfigMap.Items, expItems) {
+		t.Errorf("Exp. the config volume to mount the elasticsearch.yml of the node but was %v", configVolume.ConfigMap.Items)
+	}
+
+	hash := ""
+	for _, env := range podTemplate.Spec.Containers[0].Env {
+		if env.Name == esConfigHashEnvVar {
+			hash = env.Value
+		}
+	}
+	if hash == "" {
+		t.Fatalf("Exp. the elasticsearch container to have the %s env var", esConfigHashEnvVar)
+	}
+	for _, env := range podTemplate.Spec.Containers[1].Env {
+		if env.Name == esConfigHashEnvVar {
+			t.Errorf("Exp. the %s env var to be set on the elasticsearch container only", esConfigHashEnvVar)
+		}
+	}
+
+	// a changed setting must change the pod template to restart the node
+	node.ESConfig["thread_pool.write.queue_size"] = "2000"
+	changed := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addESConfig(&changed, cluster, node)
+	if reflect.DeepEqual(podTemplate.Spec.Containers[0].Env, changed.Spec.Containers[0].Env) {
+		t.Errorf("Exp. the %s env var to change with the settings", esConfigHashEnvVar)
+	}
+
+	// nodes without additional settings are left as is
+	plain := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	unchanged := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addESConfig(&unchanged, &api.Elasticsearch{}, api.ElasticsearchNode{GenUUID: pointer.String("abc")})
+	if !reflect.DeepEqual(plain, unchanged) {
+		t.Errorf("Exp. the pod template to be unchanged without additional settings")
+	}
+
+	updated := createUpdatablePodTemplateSpec(plain, podTemplate)
+	for _, volume := range updated.Spec.Volumes {
+		if volume.Name == configVolumeName && !reflect.DeepEqual(volume.ConfigMap.Items, expItems) {
+			t.Errorf("Exp. the config volume to be taken from the update but was %v", volume.ConfigMap.Items)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/persistentvolume"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// excludeFromAllocation excludes the node from shard allocation, which moves its shards to
+// the other nodes of the cluster. The nodes already excluded are kept.
+func (er *ElasticsearchRequest) excludeFromAllocation(nodeName string) error {
+	excluded, err := er.esClient.GetAllocationExcludedNodes()
+	if err != nil {
+		return err
+	}
+	if utils.Contains(excluded, nodeName) {
+		return nil
+	}
+
+	if _, err := er.esClient.SetAllocationExcludedNodes(append(excluded, nodeName)); err != nil {
+		return err
+	}
+	er.ll.Info("Excluded node from shard allocation", "node", nodeName)
+	return nil
+}
+
+// includeInAllocation removes the node from the nodes excluded from shard allocation
+func (er *ElasticsearchRequest) includeInAllocation(nodeName string) error {
+	excluded, err := er.esClient.GetAllocationExcludedNodes()
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, name := range excluded {
+		if name != nodeName {
+			names = append(names, name)
+		}
+	}
+	if len(names) == len(excluded) {
+		return nil
+	}
+
+	if _, err := er.esClient.SetAllocationExcludedNodes(names); err != nil {
+		return err
+	}
+	er.ll.Info("Included node in shard allocation", "node", nodeName)
+	return nil
+}
+
+// removeEvacuatedNode deletes the node and its volume once all its shards were moved off
+// it. The node stays excluded from shard allocation until it left the cluster, which is
+// recorded in the removed nodes of the status and retried on the next reconciles.
+func (er *ElasticsearchRequest) removeEvacuatedNode(node NodeTypeInterface, status *api.ElasticsearchStatus) error {
+	if err := node.delete(); err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
+		return err
+	}
+
+	key := nodeMapKey(er.cluster.Name, er.cluster.Namespace)
+	if index, ok := containsNodeTypeInterface(node, nodes[key]); ok {
+		nodes[key] = append(nodes[key][:index], nodes[key][index+1:]...)
+	}
+
+	claimKey := client.ObjectKey{Name: fmt.Sprintf("%s-%s", er.cluster.Name, node.name()), Namespace: er.cluster.Namespace}
+	if err := persistentvolume.DeletePVC(context.TODO(), er.client, claimKey); err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
+		return err
+	}
+
+	if !utils.Contains(status.RemovedNodes, node.name()) {
+		status.RemovedNodes = append(status.RemovedNodes, node.name())
+	}
+	er.includeLeftNodes(status)
+	return nil
+}
+
+// includeRemovedNodes includes the removed nodes that left the cluster in shard allocation
+// again
+func (er *ElasticsearchRequest) includeRemovedNodes() {
+	if len(er.cluster.Status.RemovedNodes) == 0 {
+		return
+	}
+
+	status := er.cluster.Status.DeepCopy()
+	er.includeLeftNodes(status)
+	if err := er.updateNodeStatus(*status); err != nil {
+		er.ll.Error(err, "failed to update the removed nodes")
+	}
+}
+
+// includeLeftNodes clears the exclude of the removed nodes of the status that left the
+// cluster. The nodes still in the cluster are kept to be checked again without waiting.
+func (er *ElasticsearchRequest) includeLeftNodes(status *api.ElasticsearchStatus) {
+	var remaining []string
+	for _, name := range status.RemovedNodes {
+		inCluster, err := er.esClient.IsNodeInCluster(name)
+		if err != nil {
+			er.ll.Error(err, "failed to check if the removed node left the cluster", "node", name)
+			remaining = append(remaining, name)
+			continue
+		}
+		if inCluster {
+			er.ll.Info("Waiting for the removed node to leave the cluster to include it in shard allocation", "node", name)
+			remaining = append(remaining, name)
+			continue
+		}
+		if err := er.includeInAllocation(name); err != nil {
+			er.ll.Error(err, "failed to include the removed node in shard allocation", "node", name)
+			remaining = append(remaining, name)
+		}
+	}
+	status.RemovedNodes = remaining
+}
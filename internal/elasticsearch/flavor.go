@@ -0,0 +1,297 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
+)
+
+const (
+	flavorUpgradingReason   = "Upgrading"
+	flavorUnsupportedReason = "Unsupported"
+
+	// minVersion is the lowest version a rolling update is performed from
+	minVersion = "6.0"
+	// minFlavorUpgradeVersion is the lowest version a rolling update to another flavor
+	// is performed from
+	minFlavorUpgradeVersion = "6.8"
+	// openSearchMaxMajor bounds the major versions of OpenSearch, which restarted from 1
+	openSearchMaxMajor = 5
+)
+
+// Flavor returns the flavor requested for the cluster
+func Flavor(cluster *api.Elasticsearch) api.ElasticsearchFlavor {
+	if cluster.Spec.Flavor == "" {
+		return api.ElasticsearchFlavor6
+	}
+	return cluster.Spec.Flavor
+}
+
+// RunningFlavor returns the flavor all nodes of the cluster are running. Clusters created
+// before the flavor was introduced run Elasticsearch 6.
+func RunningFlavor(cluster *api.Elasticsearch) api.ElasticsearchFlavor {
+	if cluster.Status.Flavor == "" {
+		return api.ElasticsearchFlavor6
+	}
+	return cluster.Status.Flavor
+}
+
+// isSupportedFlavorUpgrade returns true if a cluster running the flavor from can be
+// upgraded to the flavor to. Downgrades are not supported.
+func isSupportedFlavorUpgrade(from, to api.ElasticsearchFlavor) bool {
+	switch {
+	case from == to:
+		return true
+	case from == api.ElasticsearchFlavor6:
+		return true
+	case from == api.ElasticsearchFlavor7 && to == api.OpenSearchFlavor:
+		return true
+	}
+	return false
+}
+
+// targetFlavor returns the flavor the nodes of the cluster are deployed with. This is the
+// requested flavor unless the cluster cannot be upgraded to it.
+func targetFlavor(cluster *api.Elasticsearch) api.ElasticsearchFlavor {
+	running, requested := RunningFlavor(cluster), Flavor(cluster)
+	if !isSupportedFlavorUpgrade(running, requested) {
+		return running
+	}
+	return requested
+}
+
+// usesZenDiscovery returns true as long as nodes of the cluster may run Elasticsearch 6,
+// which only knows the zen discovery. Elasticsearch 7 and OpenSearch still read its
+// settings while they join a cluster upgraded from Elasticsearch 6.
+func usesZenDiscovery(cluster *api.Elasticsearch) bool {
+	return RunningFlavor(cluster) == api.ElasticsearchFlavor6 || targetFlavor(cluster) == api.ElasticsearchFlavor6
+}
+
+func getFlavorImage(flavor api.ElasticsearchFlavor) string {
+	switch flavor {
+	case api.ElasticsearchFlavor7:
+		return utils.LookupEnvWithDefault("RELATED_IMAGE_ELASTICSEARCH7", constants.Elasticsearch7DefaultImage)
+	case api.OpenSearchFlavor:
+		return utils.LookupEnvWithDefault("RELATED_IMAGE_OPENSEARCH", constants.OpenSearchDefaultImage)
+	}
+	return getESImage()
+}
+
+// addFlavor sets the image of the elasticsearch container to the one of the target flavor.
+// Elasticsearch 7 and OpenSearch bootstrap the cluster from the names of the master nodes,
+// so the pods of a statefulset are given their own names.
+func addFlavor(podTemplate *v1.PodTemplateSpec, cluster *api.Elasticsearch, isStatefulSet bool) {
+	flavor := targetFlavor(cluster)
+	if flavor == api.ElasticsearchFlavor6 {
+		return
+	}
+
+	for i, container := range podTemplate.Spec.Containers {
+		if container.Name != "elasticsearch" {
+			continue
+		}
+		podTemplate.Spec.Containers[i].Image = getFlavorImage(flavor)
+		if !isStatefulSet {
+			continue
+		}
+		for j, env := range container.Env {
+			if env.Name != "DC_NAME" {
+				continue
+			}
+			podTemplate.Spec.Containers[i].Env[j] = v1.EnvVar{
+				Name: "DC_NAME",
+				ValueFrom: &v1.EnvVarSource{
+					FieldRef: &v1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			}
+		}
+	}
+}
+
+// flavorConfig holds the settings of elasticsearch.yml and log4j2.properties which differ
+// between the flavors
+type flavorConfig struct {
+	ZenDiscovery       bool
+	InitialMasterNodes []string
+	SecurityPrefix     string
+	LoggerPrefix       string
+	SecurityLogger     string
+	NodesDN            []string
+	// SecurityConfigModification allows the security config to be changed with the REST
+	// API to configure the authentication realms
+	SecurityConfigModification bool
+}
+
+func newFlavorConfig(cluster *api.Elasticsearch) flavorConfig {
+	config := flavorConfig{
+		ZenDiscovery:   usesZenDiscovery(cluster),
+		SecurityPrefix: "opendistro_security",
+		LoggerPrefix:   "org.elasticsearch",
+		SecurityLogger: "com.amazon.opendistroforelasticsearch.security",
+	}
+	if RunningFlavor(cluster) == api.OpenSearchFlavor {
+		config.SecurityPrefix = "plugins.security"
+		config.LoggerPrefix = "org.opensearch"
+		config.SecurityLogger = "org.opensearch.security"
+	}
+	if needsBootstrap(cluster) {
+		config.InitialMasterNodes = masterNodeNames(cluster)
+	}
+	if usesCertManager(cluster) {
+		config.NodesDN = certManagerNodesDN
+	}
+	// the realms of a removed authentication spec are cleaned up before the modification
+	// is disallowed again
+	config.SecurityConfigModification = cluster.Spec.Authentication != nil || cluster.Status.Authentication != nil
+	return config
+}
+
+// needsBootstrap returns true for a new Elasticsearch 7 or OpenSearch cluster, which has
+// to be bootstrapped from its master nodes until it formed
+func needsBootstrap(cluster *api.Elasticsearch) bool {
+	if usesZenDiscovery(cluster) {
+		return false
+	}
+	health := cluster.Status.Cluster.Status
+	return health == "" || health == healthUnknown
+}
+
+// masterNodeNames returns the node names of the master nodes of the cluster. The pods of a
+// statefulset are named after their ordinal.
+func masterNodeNames(cluster *api.Elasticsearch) []string {
+	names := []string{}
+	for _, node := range cluster.Spec.Nodes {
+		if !isMasterNode(node) || node.GenUUID == nil {
+			continue
+		}
+		nodeName := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+		for i := int32(0); i < node.NodeCount; i++ {
+			if isDataNode(node) {
+				names = append(names, addDataNodeSuffix(nodeName, i+1))
+			} else {
+				names = append(names, fmt.Sprintf("%s-%d", nodeName, i))
+			}
+		}
+	}
+	return names
+}
+
+// isFlavorVersion returns true if the version of a node is one of the flavor
+func isFlavorVersion(flavor api.ElasticsearchFlavor, version string) bool {
+	versionArray, err := comparators.Version(version).ToArray()
+	if err != nil || len(versionArray) == 0 {
+		return false
+	}
+	switch flavor {
+	case api.ElasticsearchFlavor7:
+		return versionArray[0] == 7
+	case api.OpenSearchFlavor:
+		return versionArray[0] < openSearchMaxMajor
+	}
+	return versionArray[0] == 6
+}
+
+// requiresFullClusterUpdate returns true if a node version is too old to be updated one
+// node at a time. An upgrade to another flavor requires all nodes to run at least 6.8.
+func requiresFullClusterUpdate(cluster *api.Elasticsearch, versions []string) (bool, error) {
+	if len(versions) == 0 {
+		return false, kverrors.New("received no node versions from cluster")
+	}
+
+	expected := minVersion
+	if RunningFlavor(cluster) != targetFlavor(cluster) {
+		expected = minFlavorUpgradeVersion
+	}
+	// Skip the error here. This is a controlled number. It should always pass.
+	minVersionArray, _ := comparators.Version(expected).ToArray()
+
+	for _, version := range versions {
+		// the versions of OpenSearch are not comparable with the ones of Elasticsearch
+		if targetFlavor(cluster) == api.OpenSearchFlavor && isFlavorVersion(api.OpenSearchFlavor, version) {
+			continue
+		}
+		versionArray, err := comparators.Version(version).ToArray()
+		if err != nil {
+			return false, kverrors.Wrap(err, "failed to parse node version number", "version", version)
+		}
+		if comparators.CompareVersionArrays(versionArray, minVersionArray) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updateFlavorStatus records the flavor of a new cluster and follows the upgrade of an
+// existing one to the requested flavor. The running flavor is only changed once all
+// nodes run the requested one.
+func (er *ElasticsearchRequest) updateFlavorStatus() {
+	cluster := er.cluster
+	running, requested := RunningFlavor(cluster), Flavor(cluster)
+	if cluster.Status.Flavor == "" && len(cluster.Status.Nodes) == 0 {
+		running = requested
+	}
+
+	value := v1.ConditionFalse
+	reason := ""
+	message := ""
+	switch {
+	case running == requested:
+	case !isSupportedFlavorUpgrade(running, requested):
+		value = v1.ConditionTrue
+		reason = flavorUnsupportedReason
+		message = fmt.Sprintf("Upgrading from %s to %s is not supported, the cluster keeps running %s", running, requested, running)
+	case er.isFlavorUpgradeComplete(requested):
+		er.ll.Info("Completed flavor upgrade", "from", running, "to", requested)
+		running = requested
+	default:
+		value = v1.ConditionTrue
+		reason = flavorUpgradingReason
+		message = fmt.Sprintf("Upgrading from %s to %s", running, requested)
+	}
+
+	err := updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			changed := status.Flavor != running
+			status.Flavor = running
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.FlavorUpgrade,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			}) || changed
+		},
+		er.client,
+	)
+	if err != nil {
+		er.ll.Error(err, "failed to update flavor status")
+	}
+}
+
+// isFlavorUpgradeComplete returns true if no node is left to be upgraded and all nodes
+// report a version of the flavor
+func (er *ElasticsearchRequest) isFlavorUpgradeComplete(flavor api.ElasticsearchFlavor) bool {
+	if er.getNodeUpgradeInProgress() != nil || len(er.getScheduledUpgradeNodes()) > 0 {
+		return false
+	}
+
+	versions, err := er.esClient.GetClusterNodeVersions()
+	if err != nil || len(versions) == 0 {
+		return false
+	}
+	for _, version := range versions {
+		if !isFlavorVersion(flavor, version) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+)
+
+func TestTargetFlavor(t *testing.T) {
+	tests := []struct {
+		running, requested, exp api.ElasticsearchFlavor
+	}{
+		{running: "", requested: "", exp: api.ElasticsearchFlavor6},
+		{running: api.ElasticsearchFlavor6, requested: api.ElasticsearchFlavor7, exp: api.ElasticsearchFlavor7},
+		{running: api.ElasticsearchFlavor6, requested: api.OpenSearchFlavor, exp: api.OpenSearchFlavor},
+		{running: api.ElasticsearchFlavor7, requested: api.OpenSearchFlavor, exp: api.OpenSearchFlavor},
+		{running: api.ElasticsearchFlavor7, requested: api.ElasticsearchFlavor6, exp: api.ElasticsearchFlavor7},
+		{running: api.OpenSearchFlavor, requested: api.ElasticsearchFlavor7, exp: api.OpenSearchFlavor},
+	}
+	for _, test := range tests {
+		cluster := &api.Elasticsearch{
+			Spec:   api.ElasticsearchSpec{Flavor: test.requested},
+			Status: api.ElasticsearchStatus{Flavor: test.running},
+		}
+		if flavor := targetFlavor(cluster); flavor != test.exp {
+			t.Errorf("Exp. the target flavor from %q to %q to be %s but was %s", test.running, test.requested, test.exp, flavor)
+		}
+	}
+}
+
+func TestRequiresFullClusterUpdate(t *testing.T) {
+	tests := []struct {
+		desc               string
+		running, requested api.ElasticsearchFlavor
+		versions           []string
+		exp                bool
+	}{
+		{
+			desc:     "rolling update of elasticsearch 6",
+			versions: []string{"6.2.4", "6.8.1"},
+		},
+		{
+			desc:     "full update of elasticsearch 5",
+			versions: []string{"5.6.16", "6.8.1"},
+			exp:      true,
+		},
+		{
+			desc:      "rolling upgrade from elasticsearch 6.8",
+			requested: api.ElasticsearchFlavor7,
+			versions:  []string{"6.8.1", "7.10.2"},
+		},
+		{
+			desc:      "full upgrade from elasticsearch before 6.8",
+			requested: api.ElasticsearchFlavor7,
+			versions:  []string{"6.2.4"},
+			exp:       true,
+		},
+		{
+			desc:      "rolling upgrade to opensearch",
+			running:   api.ElasticsearchFlavor7,
+			requested: api.OpenSearchFlavor,
+			versions:  []string{"7.10.2", "1.3.6"},
+		},
+		{
+			desc:      "rolling update of opensearch",
+			running:   api.OpenSearchFlavor,
+			requested: api.OpenSearchFlavor,
+			versions:  []string{"1.3.6"},
+		},
+	}
+	for _, test := range tests {
+		cluster := &api.Elasticsearch{
+			Spec:   api.ElasticsearchSpec{Flavor: test.requested},
+			Status: api.ElasticsearchStatus{Flavor: test.running},
+		}
+		full, err := requiresFullClusterUpdate(cluster, test.versions)
+		if err != nil {
+			t.Errorf("%s: Exp. no error but got %v", test.desc, err)
+		}
+		if full != test.exp {
+			t.Errorf("%s: Exp. a full cluster update to be %t but was %t", test.desc, test.exp, full)
+		}
+	}
+
+	if _, err := requiresFullClusterUpdate(&api.Elasticsearch{}, nil); err == nil {
+		t.Errorf("Exp. an error without node versions")
+	}
+}
+
+func TestRenderEsYmlForFlavor(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch"},
+		Spec: api.ElasticsearchSpec{
+			Flavor: api.OpenSearchFlavor,
+			Nodes: []api.ElasticsearchNode{
+				{
+					Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+					NodeCount: 2,
+					GenUUID:   pointer.String("abc"),
+				},
+				{
+					Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster},
+					NodeCount: 1,
+					GenUUID:   pointer.String("def"),
+				},
+			},
+		},
+		Status: api.ElasticsearchStatus{Flavor: api.OpenSearchFlavor},
+	}
+
+	expMasters := []string{"elasticsearch-dm-abc-1", "elasticsearch-dm-abc-2", "elasticsearch-m-def-0"}
+	flavor := newFlavorConfig(cluster)
+	if !reflect.DeepEqual(flavor.InitialMasterNodes, expMasters) {
+		t.Errorf("Exp. the initial master nodes %v but got %v", expMasters, flavor.InitialMasterNodes)
+	}
+
+	result := &bytes.Buffer{}
+	if err := renderEsYml(result, "", "my.unicast.host", "2", "2", "false", nil, nil, flavor); err != nil {
+		t.Fatalf("Exp. no errors when rendering the configuration but got %v", err)
+	}
+	for _, exp := range []string{
+		"discovery.seed_hosts: my.unicast.host",
+		"cluster.initial_master_nodes:\n- elasticsearch-dm-abc-1\n- elasticsearch-dm-abc-2\n- elasticsearch-m-def-0\n",
+		"plugins.security:\n  authcz.admin_dn:",
+	} {
+		if !strings.Contains(result.String(), exp) {
+			t.Errorf("Exp. the configuration to contain %q but got:\n%s", exp, result.String())
+		}
+	}
+	if strings.Contains(result.String(), "discovery.zen") {
+		t.Errorf("Exp. no zen discovery settings for OpenSearch but got:\n%s", result.String())
+	}
+
+	cluster.Status.Cluster.Status = "green"
+	if flavor := newFlavorConfig(cluster); len(flavor.InitialMasterNodes) != 0 {
+		t.Errorf("Exp. no initial master nodes once the cluster formed but got %v", flavor.InitialMasterNodes)
+	}
+
+	upgrading := &api.Elasticsearch{Spec: api.ElasticsearchSpec{Flavor: api.ElasticsearchFlavor7}}
+	if flavor := newFlavorConfig(upgrading); !flavor.ZenDiscovery || flavor.SecurityPrefix != "opendistro_security" {
+		t.Errorf("Exp. the zen discovery and the opendistro security settings while upgrading from Elasticsearch 6 but got %+v", flavor)
+	}
+}
+
+func TestAddFlavor(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{Flavor: api.ElasticsearchFlavor7},
+	}
+
+	podTemplate := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addFlavor(&podTemplate, cluster, true)
+
+	container := podTemplate.Spec.Containers[0]
+	if container.Image != constants.Elasticsearch7DefaultImage {
+		t.Errorf("Exp. the elasticsearch container to run %s but was %s", constants.Elasticsearch7DefaultImage, container.Image)
+	}
+	for _, env := range container.Env {
+		if env.Name == "DC_NAME" && (env.ValueFrom == nil || env.ValueFrom.FieldRef.FieldPath != "metadata.name") {
+			t.Errorf("Exp. the node name of a statefulset pod to be the pod name but got %v", env)
+		}
+	}
+
+	unchanged := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addFlavor(&unchanged, &api.Elasticsearch{}, true)
+	if unchanged.Spec.Containers[0].Image != getESImage() {
+		t.Errorf("Exp. the elasticsearch 6 image to be kept but was %s", unchanged.Spec.Containers[0].Image)
+	}
+}
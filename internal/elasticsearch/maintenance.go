@@ -0,0 +1,121 @@
+package elasticsearch
+
+import (
+	"context"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils/cron"
+)
+
+// checkMaintenanceWindow records the start of the current or next maintenance window in the
+// status and returns whether the scheduled upgrades of the nodes can be started
+func (er *ElasticsearchRequest) checkMaintenanceWindow() bool {
+	allowed, next := er.getMaintenanceState()
+	if err := er.persistNextMaintenanceWindow(next); err != nil {
+		er.ll.Error(err, "failed to update the next maintenance window")
+	}
+	return allowed
+}
+
+// getMaintenanceState returns whether maintenance can be started and the start of the current
+// or next maintenance window. It can without maintenance windows, in an open window or with
+// the override annotation.
+func (er *ElasticsearchRequest) getMaintenanceState() (bool, *metav1.Time) {
+	cluster := er.cluster
+	if cluster.Spec.Maintenance == nil || len(cluster.Spec.Maintenance.Windows) == 0 {
+		return true, nil
+	}
+
+	now := time.Now().UTC()
+	open, next, err := getMaintenanceWindow(cluster.Spec.Maintenance.Windows, now)
+	if err != nil {
+		// an invalid schedule cannot be relied on to ever open a window
+		er.ll.Error(err, "failed to parse the maintenance windows")
+		return true, nil
+	}
+
+	var nextWindow *metav1.Time
+	if !next.IsZero() {
+		nextWindow = &metav1.Time{Time: next}
+	}
+	if open {
+		return true, nextWindow
+	}
+	if cluster.GetAnnotations()[api.MaintenanceOverrideAnnotation] == "true" {
+		er.ll.Info("Starting the scheduled node upgrades outside of the maintenance windows because of the override annotation",
+			"annotation", api.MaintenanceOverrideAnnotation)
+		return true, nextWindow
+	}
+	return false, nextWindow
+}
+
+// isMaintenanceAllowed returns whether the scheduled upgrades of the nodes can be started at
+// the time without logging or recording the state of the windows
+func isMaintenanceAllowed(cluster *api.Elasticsearch, now time.Time) bool {
+	if cluster.Spec.Maintenance == nil || len(cluster.Spec.Maintenance.Windows) == 0 {
+		return true
+	}
+	open, _, err := getMaintenanceWindow(cluster.Spec.Maintenance.Windows, now)
+	if err != nil || open {
+		return true
+	}
+	return cluster.GetAnnotations()[api.MaintenanceOverrideAnnotation] == "true"
+}
+
+// getMaintenanceWindow returns whether one of the windows is open at the time and the start of
+// the window that is open or opens next. The start is the zero time if no window ever opens.
+func getMaintenanceWindow(windows []api.MaintenanceWindowSpec, now time.Time) (bool, time.Time, error) {
+	open := false
+	var next time.Time
+	for _, window := range windows {
+		schedule, err := cron.Parse(window.Schedule)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+
+		// the latest start of the window that would still be open now
+		start := schedule.Next(now.Add(-window.Duration.Duration))
+		if start.IsZero() {
+			continue
+		}
+		if !start.After(now) {
+			open = true
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return open, next, nil
+}
+
+// persistNextMaintenanceWindow writes the start of the next maintenance window if it changed
+func (er *ElasticsearchRequest) persistNextMaintenanceWindow(next *metav1.Time) error {
+	cluster := er.cluster
+	if equality.Semantic.DeepEqual(next, cluster.Status.NextMaintenanceWindow) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.NextMaintenanceWindow = next
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update the next maintenance window for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package elasticsearch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+func TestGetMaintenanceWindow(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2022, time.August, 10, 3, 0, 0, 0, time.UTC)
+	nightly := api.MaintenanceWindowSpec{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}}
+	weekend := api.MaintenanceWindowSpec{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+
+	tests := []struct {
+		desc    string
+		windows []api.MaintenanceWindowSpec
+		now     time.Time
+		expOpen bool
+		expNext time.Time
+	}{
+		{
+			desc:    "open window",
+			windows: []api.MaintenanceWindowSpec{weekend, nightly},
+			now:     now,
+			expOpen: true,
+			expNext: time.Date(2022, time.August, 10, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:    "closed window",
+			windows: []api.MaintenanceWindowSpec{weekend},
+			now:     now,
+			expNext: time.Date(2022, time.August, 13, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:    "window closed at its end",
+			windows: []api.MaintenanceWindowSpec{nightly},
+			now:     time.Date(2022, time.August, 10, 4, 0, 0, 0, time.UTC),
+			expNext: time.Date(2022, time.August, 11, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		open, next, err := getMaintenanceWindow(test.windows, test.now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.desc, err)
+		}
+		if open != test.expOpen {
+			t.Errorf("%s: Exp. the window open to be %t", test.desc, test.expOpen)
+		}
+		if !next.Equal(test.expNext) {
+			t.Errorf("%s: Exp. the next window at %s but got %s", test.desc, test.expNext, next)
+		}
+	}
+}
+
+func TestCheckMaintenanceWindow(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	// a window which opened a minute ago and closes in a minute is always open
+	now := time.Now().UTC()
+	openWindow := api.MaintenanceWindowSpec{
+		Schedule: now.Add(-time.Minute).Format("4 15 * * *"),
+		Duration: metav1.Duration{Duration: 3 * time.Minute},
+	}
+	// a window which opens in twelve hours is closed
+	closedWindow := api.MaintenanceWindowSpec{
+		Schedule: now.Add(12 * time.Hour).Format("4 15 * * *"),
+		Duration: metav1.Duration{Duration: time.Minute},
+	}
+
+	tests := []struct {
+		desc        string
+		maintenance *api.ElasticsearchMaintenanceSpec
+		override    bool
+		exp         bool
+		expNext     bool
+	}{
+		{desc: "without windows", exp: true},
+		{desc: "open window", maintenance: &api.ElasticsearchMaintenanceSpec{Windows: []api.MaintenanceWindowSpec{openWindow}}, exp: true, expNext: true},
+		{desc: "closed window", maintenance: &api.ElasticsearchMaintenanceSpec{Windows: []api.MaintenanceWindowSpec{closedWindow}}, expNext: true},
+		{desc: "override", maintenance: &api.ElasticsearchMaintenanceSpec{Windows: []api.MaintenanceWindowSpec{closedWindow}}, override: true, exp: true, expNext: true},
+	}
+	for _, test := range tests {
+		cluster := newMigrationCluster()
+		cluster.Spec.Maintenance = test.maintenance
+		if test.override {
+			cluster.Annotations = map[string]string{api.MaintenanceOverrideAnnotation: "true"}
+		}
+		er := &ElasticsearchRequest{
+			client:  fake.NewClientBuilder().WithObjects(cluster.DeepCopy()).Build(),
+			cluster: cluster,
+			ll:      log.NewLogger("maintenance-testing"),
+		}
+
+		if got := er.checkMaintenanceWindow(); got != test.exp {
+			t.Errorf("%s: Exp. starting the scheduled upgrades to be allowed %t", test.desc, test.exp)
+		}
+		if got := cluster.Status.NextMaintenanceWindow != nil; got != test.expNext {
+			t.Errorf("%s: Exp. the next maintenance window in the status %t but got %v", test.desc, test.expNext, cluster.Status.NextMaintenanceWindow)
+		}
+	}
+}
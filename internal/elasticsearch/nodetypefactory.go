@@ -2,6 +2,9 @@ package elasticsearch
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
@@ -44,10 +47,8 @@ func (er *ElasticsearchRequest) GetNodeTypeInterface(uuid string, node api.Elast
 
 	// if we have a data node then we need to create one deployment per replica
 	if isDataNode(node) {
-		// for loop from 1 to replica as replicaIndex
-		//   it is 1 instead of 0 because of legacy code
-		for replicaIndex := int32(1); replicaIndex <= node.NodeCount; replicaIndex++ {
-			dataNodeName := addDataNodeSuffix(nodeName, replicaIndex)
+		dataNodeNames, _ := getDataNodeNames(nodeName, node.NodeCount, &er.cluster.Status)
+		for _, dataNodeName := range dataNodeNames {
 			node := newDeploymentNode(er.ll, dataNodeName, node, er.cluster, roleMap, er.client, er.esClient)
 			nodes = append(nodes, node)
 		}
@@ -60,6 +61,10 @@ func (er *ElasticsearchRequest) GetNodeTypeInterface(uuid string, node api.Elast
 }
 
 func getNodeSuffix(uuid string, roleMap map[api.ElasticsearchNodeRole]bool) string {
+	if roleMap[api.ElasticsearchRoleCoordinating] {
+		return fmt.Sprintf("%s-%s", "co", uuid)
+	}
+
 	suffix := ""
 	if roleMap[api.ElasticsearchRoleClient] {
 		suffix = fmt.Sprintf("%s%s", suffix, "c")
@@ -69,6 +74,10 @@ func getNodeSuffix(uuid string, roleMap map[api.ElasticsearchNodeRole]bool) stri
 		suffix = fmt.Sprintf("%s%s", suffix, "d")
 	}
 
+	if roleMap[api.ElasticsearchRoleIngest] {
+		suffix = fmt.Sprintf("%s%s", suffix, "i")
+	}
+
 	if roleMap[api.ElasticsearchRoleMaster] {
 		suffix = fmt.Sprintf("%s%s", suffix, "m")
 	}
@@ -80,6 +89,74 @@ func addDataNodeSuffix(nodeName string, replicaNumber int32) string {
 	return fmt.Sprintf("%s-%d", nodeName, replicaNumber)
 }
 
+// getDataNodeNames returns the deployment names of the replicas of a data node. The names
+// are suffixed with the replica index, which starts at 1 because of legacy code. Replicas
+// removed by lowering the node count are kept until their shards were moved, and so are the
+// replicas migrating to a new storage class along with their replacements.
+func getDataNodeNames(nodeName string, nodeCount int32, status *api.ElasticsearchStatus) (names, scaledDown []string) {
+	used := map[int32]bool{}
+	existing := map[int32]bool{}
+	active := map[int32]bool{}
+	migrating := []string{}
+	for _, nodeStatus := range status.Nodes {
+		index, ok := parseDataNodeIndex(nodeName, nodeStatus.DeploymentName)
+		if !ok {
+			continue
+		}
+		used[index] = true
+
+		migration := nodeStatus.StorageMigration
+		if migration == nil {
+			existing[index] = true
+			active[index] = true
+			continue
+		}
+		migrating = append(migrating, nodeStatus.DeploymentName)
+		if replacementIndex, ok := parseDataNodeIndex(nodeName, migration.Replacement); ok {
+			used[replacementIndex] = true
+			active[replacementIndex] = true
+		}
+	}
+
+	indices := []int32{}
+	for index := range active {
+		indices = append(indices, index)
+	}
+	// fill up with the lowest free indices
+	for index := int32(1); int32(len(indices)) < nodeCount; index++ {
+		if !used[index] {
+			indices = append(indices, index)
+		}
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	names = []string{}
+	scaledDown = []string{}
+	for i, index := range indices {
+		name := addDataNodeSuffix(nodeName, index)
+		switch {
+		case int32(i) < nodeCount:
+			names = append(names, name)
+		case existing[index]:
+			names = append(names, name)
+			scaledDown = append(scaledDown, name)
+		}
+	}
+	return append(names, migrating...), scaledDown
+}
+
+// parseDataNodeIndex returns the replica index of the deployment name of a data node
+func parseDataNodeIndex(nodeName, name string) (int32, bool) {
+	if !strings.HasPrefix(name, nodeName+"-") {
+		return 0, false
+	}
+	index, err := strconv.ParseInt(strings.TrimPrefix(name, nodeName+"-"), 10, 32)
+	if err != nil || index < 1 {
+		return 0, false
+	}
+	return int32(index), true
+}
+
 // newDeploymentNode constructs deploymentNode struct for data nodes
 func newDeploymentNode(log logr.Logger, nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, esClient esclient.Client) NodeTypeInterface {
 	deploymentNode := deploymentNode{
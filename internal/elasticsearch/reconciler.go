@@ -47,10 +47,12 @@ func SecretReconcile(log logr.Logger, requestCluster *elasticsearchv1.Elasticsea
 		}
 	}
 
-	// check if cluster is in the mid of cert redeploy
+	// check if cluster is in the mid of cert redeploy. The rolling restart of a CA rotation
+	// or of changed trusted CAs restarts the nodes with the secret changed in between as well.
 	certRestartNodes := elasticsearchRequest.getScheduledCertRedeployNodes()
 	stillRecovering := containsClusterCondition(elasticsearchv1.Recovering, corev1.ConditionTrue, &elasticsearchRequest.cluster.Status)
-	if len(certRestartNodes) > 0 || stillRecovering {
+	rollingRestart := (requestCluster.Status.CARotation != nil || requestCluster.Status.TrustedCAsRollout) && !stillRecovering
+	if (len(certRestartNodes) > 0 && !rollingRestart) || stillRecovering {
 		// Requeue if there are nodes being scheduled CertRedeploy or under recovering
 		// and reset the certRedeploy status
 		for _, node := range nodes[nodeMapKey(requestCluster.Name, requestCluster.Namespace)] {
@@ -115,8 +117,9 @@ func SecretReconcile(log logr.Logger, requestCluster *elasticsearchv1.Elasticsea
 	return true, nil
 }
 
-func Reconcile(log logr.Logger, requestCluster *elasticsearchv1.Elasticsearch, requestClient client.Client) error {
+func Reconcile(ctx context.Context, log logr.Logger, requestCluster *elasticsearchv1.Elasticsearch, requestClient client.Client) error {
 	esClient := esclient.NewClient(log, requestCluster.Name, requestCluster.Namespace, requestClient)
+	esClient.SetContext(ctx)
 
 	elasticsearchRequest := ElasticsearchRequest{
 		client:   requestClient,
@@ -132,7 +135,20 @@ func Reconcile(log logr.Logger, requestCluster *elasticsearchv1.Elasticsearch, r
 		manageBool, _ := strconv.ParseBool(value)
 		if manageBool {
 			cr := NewCertificateRequest(log, requestCluster.Name, requestCluster.Namespace, requestCluster.GetOwnerRef(), requestClient)
-			cr.GenerateElasticsearchCerts(requestCluster.Name)
+			cr.TrustedCAs = elasticsearchRequest.remoteClusterCAs()
+			cr.Certificates = requestCluster.Spec.Certificates
+			// certificates are rotated ahead of their expiry in the maintenance windows
+			cr.RotationAllowed, _ = elasticsearchRequest.getMaintenanceState()
+			// the signing CA is rotated in phases rolled out to the nodes one at a time
+			cr.ManageCARotation = true
+			cr.CARotation = requestCluster.Status.CARotation
+			cr.CARolledOut = elasticsearchRequest.secretRolledOut()
+			// a change of the trusted CAs only is rolled out one node at a time until all
+			// nodes loaded it
+			cr.TrustedCAsRollout = requestCluster.Status.TrustedCAsRollout && !cr.CARolledOut
+			cr.CertRedeployPending = len(elasticsearchRequest.getScheduledCertRedeployNodes()) > 0
+			provider := cr.Provider()
+			provider.GenerateElasticsearchCerts(requestCluster.Name)
 
 			// for any components specified like:
 			// logging.openshift.io/elasticsearch-cert.{secret_name}: {component_name}
@@ -140,14 +156,30 @@ func Reconcile(log logr.Logger, requestCluster *elasticsearchv1.Elasticsearch, r
 				if strings.HasPrefix(annotationKey, constants.EOComponentCertPrefix) {
 					secretName := strings.TrimPrefix(annotationKey, constants.EOComponentCertPrefix)
 
-					cr.GenerateComponentCerts(secretName, componentName)
+					provider.GenerateComponentCerts(secretName, componentName)
 				}
 			}
+
+			cr.setCertificateMetrics()
+			if err := elasticsearchRequest.persistCertificateStatus(cr.Status); err != nil {
+				elasticsearchRequest.ll.Error(err, "failed to update the certificate status")
+			}
+			if err := elasticsearchRequest.persistCARotationStatus(cr.CARotation); err != nil {
+				elasticsearchRequest.ll.Error(err, "failed to update the CA rotation status")
+			}
+			if err := elasticsearchRequest.persistTrustedCAsRollout(cr.TrustedCAsRollout); err != nil {
+				elasticsearchRequest.ll.Error(err, "failed to update the trusted CAs rollout status")
+			}
 		}
 	}
 
 	degradedCondition := false
 
+	// Follow the upgrade to the requested flavor before the configuration of the nodes is
+	// rendered for it
+	elasticsearchRequest.updateFlavorStatus()
+	esClient.SetFlavor(RunningFlavor(requestCluster))
+
 	// Ensure existence of securitycontextconstraints
 	if err := elasticsearchRequest.CreateOrUpdateSecurityContextConstraints(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile SecurityContextConstraints for Elasticsearch cluster")
@@ -0,0 +1,248 @@
+package elasticsearch
+
+import (
+	"context"
+	"net"
+	"reflect"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	defaultTransportPort = "9300"
+
+	remoteClusterConnectedReason     = "Connected"
+	remoteClusterDisconnectedReason  = "Disconnected"
+	remoteClusterNotConfiguredReason = "NotConfigured"
+)
+
+// updateRemoteClusters applies the remote clusters of the spec to the persistent cluster
+// settings, removes the ones dropped from the spec and reports their connection state
+func (er *ElasticsearchRequest) updateRemoteClusters() {
+	cluster := er.cluster
+	if len(cluster.Spec.RemoteClusters) == 0 && len(cluster.Status.RemoteClusters) == 0 {
+		return
+	}
+
+	info, err := er.esClient.GetRemoteInfo()
+	if err != nil {
+		er.ll.Error(err, "failed to get remote cluster info")
+		return
+	}
+
+	if remotes := remoteClusterSettingsChanges(cluster, info); len(remotes) > 0 {
+		if err := er.esClient.UpdateRemoteClusters(remotes); err != nil {
+			er.ll.Error(err, "failed to update remote clusters")
+			return
+		}
+		er.ll.Info("Updated remote clusters")
+
+		if info, err = er.esClient.GetRemoteInfo(); err != nil {
+			er.ll.Error(err, "failed to get remote cluster info")
+			return
+		}
+	}
+
+	status := newRemoteClusterStatus(cluster.Spec.RemoteClusters, cluster.Status.RemoteClusters, info)
+	if err := er.persistRemoteClusterStatus(status); err != nil {
+		er.ll.Error(err, "failed to update remote cluster status")
+	}
+}
+
+// remoteClusterCAs returns the PEM encoded CAs of the remote clusters. The CAs of
+// missing secrets are skipped until they exist.
+func (er *ElasticsearchRequest) remoteClusterCAs() []byte {
+	var bundle []byte
+	for _, remote := range er.cluster.Spec.RemoteClusters {
+		if remote.CASecret == nil || remote.CASecret.Name == "" {
+			continue
+		}
+
+		key := client.ObjectKey{Name: remote.CASecret.Name, Namespace: er.cluster.Namespace}
+		s, err := secret.Get(context.TODO(), er.client, key)
+		if err != nil {
+			er.ll.Error(err, "failed to get remote cluster CA secret", "remote", remote.Name, "secret", key.Name)
+			continue
+		}
+
+		ca := s.Data[api.RemoteClusterCAKey]
+		if len(ca) == 0 {
+			er.ll.Error(kverrors.New("missing remote cluster CA in secret", "key", api.RemoteClusterCAKey),
+				"failed to get remote cluster CA", "remote", remote.Name, "secret", key.Name)
+			continue
+		}
+		bundle = appendPEM(bundle, ca)
+	}
+	return bundle
+}
+
+// remoteClusterSettingsChanges returns the settings of the remote clusters differing from
+// the ones in use and the removal of the remote clusters dropped from the spec
+func remoteClusterSettingsChanges(cluster *api.Elasticsearch, info estypes.RemoteInfoResponse) map[string]estypes.RemoteClusterSettings {
+	changes := map[string]estypes.RemoteClusterSettings{}
+
+	desired := map[string]bool{}
+	for _, remote := range cluster.Spec.RemoteClusters {
+		desired[remote.Name] = true
+
+		skipUnavailable := remote.SkipUnavailable
+		settings := estypes.RemoteClusterSettings{
+			Seeds:           remoteClusterSeeds(remote.Seeds),
+			SkipUnavailable: &skipUnavailable,
+		}
+
+		current, ok := info[remote.Name]
+		if ok && reflect.DeepEqual(current.Seeds, settings.Seeds) && current.SkipUnavailable == skipUnavailable {
+			continue
+		}
+		changes[remote.Name] = settings
+	}
+
+	for _, remote := range cluster.Status.RemoteClusters {
+		if desired[remote.Name] {
+			continue
+		}
+		if _, ok := info[remote.Name]; ok {
+			changes[remote.Name] = estypes.RemoteClusterSettings{}
+		}
+	}
+	return changes
+}
+
+// remoteClusterSeeds adds the default transport port to the seeds without one
+func remoteClusterSeeds(seeds []string) []string {
+	addresses := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		if _, _, err := net.SplitHostPort(seed); err != nil {
+			seed = net.JoinHostPort(seed, defaultTransportPort)
+		}
+		addresses = append(addresses, seed)
+	}
+	return addresses
+}
+
+// newRemoteClusterStatus returns the connection state of the remote clusters of the spec
+// keeping the transition time of the unchanged conditions
+func newRemoteClusterStatus(remotes []api.ElasticsearchRemoteCluster, previous []api.RemoteClusterStatus, info estypes.RemoteInfoResponse) []api.RemoteClusterStatus {
+	var status []api.RemoteClusterStatus
+	for _, remote := range remotes {
+		remoteStatus := api.RemoteClusterStatus{
+			Name: remote.Name,
+		}
+		for _, p := range previous {
+			if p.Name == remote.Name {
+				remoteStatus.Conditions = p.Conditions
+			}
+		}
+
+		condition := api.ClusterCondition{
+			Type:    api.RemoteClusterConnected,
+			Status:  v1.ConditionFalse,
+			Reason:  remoteClusterNotConfiguredReason,
+			Message: "The remote cluster is not configured in the cluster settings",
+		}
+		if current, ok := info[remote.Name]; ok {
+			remoteStatus.Seeds = current.Seeds
+			remoteStatus.NumNodesConnected = current.NumNodesConnected
+
+			condition.Reason = remoteClusterDisconnectedReason
+			condition.Message = "Unable to connect to any seed of the remote cluster"
+			if current.Connected {
+				condition.Status = v1.ConditionTrue
+				condition.Reason = remoteClusterConnectedReason
+				condition.Message = ""
+			}
+		}
+		remoteStatus.Conditions = updateRemoteClusterCondition(remoteStatus.Conditions, condition)
+
+		status = append(status, remoteStatus)
+	}
+	return status
+}
+
+func updateRemoteClusterCondition(conditions api.ClusterConditions, condition api.ClusterCondition) api.ClusterConditions {
+	updated := api.ClusterConditions{}
+	condition.LastTransitionTime = metav1.Now()
+	for _, c := range conditions {
+		if c.Type != condition.Type {
+			updated = append(updated, c)
+			continue
+		}
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+	}
+	return append(updated, condition)
+}
+
+// persistRemoteClusterStatus writes the remote cluster status if it changed
+func (er *ElasticsearchRequest) persistRemoteClusterStatus(status []api.RemoteClusterStatus) error {
+	cluster := er.cluster
+	if equality.Semantic.DeepEqual(status, cluster.Status.RemoteClusters) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.RemoteClusters = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update remote cluster status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
+
+// persistTrustedCAsRollout writes whether the trusted CAs are rolled out to the nodes one
+// at a time if it changed
+func (er *ElasticsearchRequest) persistTrustedCAsRollout(rollout bool) error {
+	cluster := er.cluster
+	if cluster.Status.TrustedCAsRollout == rollout {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.TrustedCAsRollout = rollout
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update trusted CAs rollout status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
+
+// IsRemoteClusterCASecret returns true if the secret holds the CA of a remote cluster of the
+// cluster
+func IsRemoteClusterCASecret(cluster *api.Elasticsearch, secretName string) bool {
+	for _, remote := range cluster.Spec.RemoteClusters {
+		if remote.CASecret != nil && remote.CASecret.Name == secretName {
+			return true
+		}
+	}
+	return false
+}
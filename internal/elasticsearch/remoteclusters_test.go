@@ -0,0 +1,245 @@
+package elasticsearch
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestRemoteClusterSeeds(t *testing.T) {
+	seeds := remoteClusterSeeds([]string{
+		"elasticsearch-cluster.east.svc",
+		"elasticsearch-cluster.west.svc:9301",
+		"10.0.0.1",
+		"[fd00::1]:9300",
+	})
+	exp := []string{
+		"elasticsearch-cluster.east.svc:9300",
+		"elasticsearch-cluster.west.svc:9301",
+		"10.0.0.1:9300",
+		"[fd00::1]:9300",
+	}
+	if !reflect.DeepEqual(seeds, exp) {
+		t.Errorf("Expected seeds %v but got %v", exp, seeds)
+	}
+}
+
+func TestRemoteClusterSettingsChanges(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			RemoteClusters: []api.ElasticsearchRemoteCluster{
+				{Name: "east", Seeds: []string{"east.example.com"}},
+				{Name: "west", Seeds: []string{"west.example.com:9300"}, SkipUnavailable: true},
+			},
+		},
+		Status: api.ElasticsearchStatus{
+			RemoteClusters: []api.RemoteClusterStatus{
+				{Name: "east"},
+				{Name: "north"},
+				{Name: "south"},
+			},
+		},
+	}
+	info := estypes.RemoteInfoResponse{
+		"east":  {Seeds: []string{"east.example.com:9300"}, Connected: true},
+		"west":  {Seeds: []string{"west.example.com:9300"}, SkipUnavailable: false},
+		"north": {Seeds: []string{"north.example.com:9300"}},
+		"other": {Seeds: []string{"other.example.com:9300"}},
+	}
+
+	changes := remoteClusterSettingsChanges(cluster, info)
+
+	skipUnavailable := true
+	exp := map[string]estypes.RemoteClusterSettings{
+		"west":  {Seeds: []string{"west.example.com:9300"}, SkipUnavailable: &skipUnavailable},
+		"north": {},
+	}
+	if !reflect.DeepEqual(changes, exp) {
+		t.Errorf("Expected changes %v but got %v", exp, changes)
+	}
+}
+
+func TestUpdateRemoteClusters(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch",
+			Namespace: "openshift-logging",
+		},
+		Spec: api.ElasticsearchSpec{
+			RemoteClusters: []api.ElasticsearchRemoteCluster{
+				{Name: "east", Seeds: []string{"elasticsearch-cluster.east.svc"}},
+				{Name: "west", Seeds: []string{"west.example.com:9300"}},
+			},
+		},
+		Status: api.ElasticsearchStatus{
+			RemoteClusters: []api.RemoteClusterStatus{
+				{Name: "north"},
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(cluster.DeepCopy()).Build()
+
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_remote/info": {
+			{
+				StatusCode: 200,
+				Body:       `{"north": {"seeds": ["north.example.com:9300"], "connected": true, "num_nodes_connected": 1}}`,
+			},
+			{
+				StatusCode: 200,
+				Body: `{"east": {"seeds": ["elasticsearch-cluster.east.svc:9300"], "connected": true, "num_nodes_connected": 3},
+				        "west": {"seeds": ["west.example.com:9300"], "connected": false, "num_nodes_connected": 0}}`,
+			},
+		},
+		"_cluster/settings": {
+			{
+				StatusCode: 200,
+				Body:       `{"acknowledged": true}`,
+			},
+		},
+	})
+
+	er := ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		ll:       log.NewLogger("remote-clusters-testing"),
+	}
+	er.updateRemoteClusters()
+
+	req, found := chatter.GetRequest("_cluster/settings")
+	if !found {
+		t.Fatal("Expected the remote clusters to be updated")
+	}
+	for _, exp := range []string{
+		`"east":{"seeds":["elasticsearch-cluster.east.svc:9300"],"skip_unavailable":false}`,
+		`"west":{"seeds":["west.example.com:9300"],"skip_unavailable":false}`,
+		`"north":{"seeds":null,"skip_unavailable":null}`,
+	} {
+		if !strings.Contains(req.Body, exp) {
+			t.Errorf("Expected request body %s to contain %s", req.Body, exp)
+		}
+	}
+
+	updated := &api.Elasticsearch{}
+	if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), updated); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	status := updated.Status.RemoteClusters
+	if len(status) != 2 {
+		t.Fatalf("Expected the status of 2 remote clusters but got %v", status)
+	}
+	for _, s := range []struct {
+		name      string
+		nodes     int32
+		condition v1.ConditionStatus
+		reason    string
+	}{
+		{name: "east", nodes: 3, condition: v1.ConditionTrue, reason: remoteClusterConnectedReason},
+		{name: "west", nodes: 0, condition: v1.ConditionFalse, reason: remoteClusterDisconnectedReason},
+	} {
+		var remote *api.RemoteClusterStatus
+		for i := range status {
+			if status[i].Name == s.name {
+				remote = &status[i]
+			}
+		}
+		if remote == nil {
+			t.Errorf("Expected the status of remote cluster %s", s.name)
+			continue
+		}
+		if remote.NumNodesConnected != s.nodes {
+			t.Errorf("Expected %d connected nodes for %s but got %d", s.nodes, s.name, remote.NumNodesConnected)
+		}
+		if len(remote.Conditions) != 1 ||
+			remote.Conditions[0].Type != api.RemoteClusterConnected ||
+			remote.Conditions[0].Status != s.condition ||
+			remote.Conditions[0].Reason != s.reason {
+			t.Errorf("Expected condition %s/%s for %s but got %v", s.condition, s.reason, s.name, remote.Conditions)
+		}
+	}
+}
+
+func TestAppendPEM(t *testing.T) {
+	bundle := appendPEM([]byte("-----CA-----"), []byte("-----REMOTE-----\n"))
+	if exp := "-----CA-----\n-----REMOTE-----\n"; string(bundle) != exp {
+		t.Errorf("Expected bundle %q but got %q", exp, string(bundle))
+	}
+	if bundle := appendPEM([]byte("-----CA-----\n"), nil); string(bundle) != "-----CA-----\n" {
+		t.Errorf("Expected the bundle to be unchanged but got %q", string(bundle))
+	}
+}
+
+func TestTrustedCAsChange(t *testing.T) {
+	current := map[string][]byte{
+		esAdminCAName:       []byte("-----CA-----\n"),
+		"elasticsearch.crt": []byte("-----CERT-----\n"),
+	}
+	tests := []struct {
+		desc           string
+		current        map[string][]byte
+		data           map[string][]byte
+		changed        bool
+		trustedCAsOnly bool
+	}{
+		{
+			desc:    "new secret",
+			data:    current,
+			changed: true,
+		},
+		{
+			desc:    "unchanged",
+			current: current,
+			data:    current,
+		},
+		{
+			desc:    "trusted CAs changed",
+			current: current,
+			data: map[string][]byte{
+				esAdminCAName:       []byte("-----CA-----\n-----REMOTE-----\n"),
+				"elasticsearch.crt": []byte("-----CERT-----\n"),
+			},
+			changed:        true,
+			trustedCAsOnly: true,
+		},
+		{
+			desc:    "certificate changed",
+			current: current,
+			data: map[string][]byte{
+				esAdminCAName:       []byte("-----CA-----\n-----REMOTE-----\n"),
+				"elasticsearch.crt": []byte("-----NEW-----\n"),
+			},
+			changed: true,
+		},
+		{
+			desc:    "key removed",
+			current: current,
+			data: map[string][]byte{
+				esAdminCAName: []byte("-----CA-----\n"),
+			},
+			changed: true,
+		},
+	}
+	for _, test := range tests {
+		changed, trustedCAsOnly := trustedCAsChange(test.current, test.data)
+		if changed != test.changed || trustedCAsOnly != test.trustedCAsOnly {
+			t.Errorf("%s: expected changed %t and trusted CAs only %t but got %t and %t",
+				test.desc, test.changed, test.trustedCAsOnly, changed, trustedCAsOnly)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// scaleDownDataNodes removes the data nodes of a lowered node count, one node at a time.
+// The node is excluded from shard allocation and only deleted once all its shards were
+// moved to the other nodes. The progress is reported by the ScalingDown condition.
+func (er *ElasticsearchRequest) scaleDownDataNodes() {
+	status := er.cluster.Status.DeepCopy()
+
+	scaledDown := er.getScaledDownNodes()
+	if len(scaledDown) == 0 {
+		if updateScalingDownCondition(status, v1.ConditionFalse) {
+			if err := er.updateNodeStatus(*status); err != nil {
+				er.ll.Error(err, "failed to update the scaling down condition")
+			}
+		}
+		return
+	}
+
+	// nodes are drained one at a time, after the storage migration in progress
+	for _, nodeStatus := range status.Nodes {
+		if nodeStatus.StorageMigration != nil {
+			return
+		}
+	}
+
+	node := scaledDown[0]
+	if err := er.excludeFromAllocation(node.name()); err != nil {
+		er.ll.Error(err, "failed to exclude the node from shard allocation", "node", node.name())
+		return
+	}
+	shards, err := er.esClient.GetNodeShardCount(node.name())
+	if err != nil {
+		er.ll.Error(err, "failed to get the shards of the node", "node", node.name())
+		return
+	}
+
+	message := fmt.Sprintf("Moving the shards off node %s before removing it, %d left", node.name(), shards)
+	if shards == 0 {
+		if health, _ := er.esClient.GetClusterHealthStatus(); !utils.Contains(desiredClusterStates, health) {
+			er.ll.Info("Unable to delete/scale down any Elasticsearch nodes because of current cluster health", "currentHealth", health, "desiredHealth", desiredClusterStates)
+			return
+		}
+
+		// if we're removing a node make sure we set a lower min masters to keep cluster functional
+		if er.AnyNodeReady() {
+			er.updateMinMasters()
+		}
+
+		if err := er.removeEvacuatedNode(node, status); err != nil {
+			er.ll.Error(err, "failed to remove the scaled down node", "node", node.name())
+			return
+		}
+		if index, _ := getNodeStatus(node.name(), status); index != NotFoundIndex {
+			status.Nodes = append(status.Nodes[:index], status.Nodes[index+1:]...)
+		}
+		er.ll.Info("Removed the scaled down node", "node", node.name())
+		message = fmt.Sprintf("Removed node %s", node.name())
+	}
+
+	updateESNodeCondition(status, &api.ClusterCondition{
+		Type:    api.ScalingDown,
+		Status:  v1.ConditionTrue,
+		Reason:  "DrainingNodes",
+		Message: message,
+	})
+	if err := er.updateNodeStatus(*status); err != nil {
+		er.ll.Error(err, "failed to update the scaling down condition")
+	}
+}
+
+// getScaledDownNodes returns the data nodes removed by lowering their node count
+func (er *ElasticsearchRequest) getScaledDownNodes() []NodeTypeInterface {
+	scaledDown := []NodeTypeInterface{}
+	for _, specNode := range er.cluster.Spec.Nodes {
+		if !isDataNode(specNode) || specNode.GenUUID == nil {
+			continue
+		}
+
+		nodeName := fmt.Sprintf("%s-%s", er.cluster.Name, getNodeSuffix(*specNode.GenUUID, getNodeRoleMap(specNode)))
+		_, names := getDataNodeNames(nodeName, specNode.NodeCount, &er.cluster.Status)
+		for _, name := range names {
+			if node := er.getNode(name); node != nil {
+				scaledDown = append(scaledDown, node)
+			}
+		}
+	}
+	return scaledDown
+}
@@ -0,0 +1,198 @@
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/log"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func newScaleDownRequest(cluster *api.Elasticsearch, chatter *helpers.FakeElasticsearchChatter, objs ...client.Object) *ElasticsearchRequest {
+	k8sClient := fake.NewClientBuilder().WithObjects(append(objs, cluster.DeepCopy())...).Build()
+
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+	nodes[key] = []NodeTypeInterface{}
+	for _, nodeStatus := range cluster.Status.Nodes {
+		nodes[key] = append(nodes[key], newMigrationNode(nodeStatus.DeploymentName, k8sClient, chatter))
+	}
+
+	return &ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		ll:       log.NewLogger("scale-down-testing"),
+	}
+}
+
+func TestScaleDownDataNodesDrainsNode(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := newMigrationCluster(
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-1"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-2"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-3"},
+	)
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings": {
+			{StatusCode: 200, Body: `{"persistent": {}, "transient": {}}`},
+			{StatusCode: 200, Body: `{"acknowledged": true}`},
+		},
+		"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+			{StatusCode: 200, Body: `[{"index": "app-000001", "shard": "0", "prirep": "r", "state": "RELOCATING", "node": "elasticsearch-d-abc-3 -> 10.0.0.1 abc elasticsearch-d-abc-1"}]`},
+		},
+	})
+	er := newScaleDownRequest(cluster, chatter)
+	defer delete(nodes, nodeMapKey(cluster.Name, cluster.Namespace))
+
+	er.scaleDownDataNodes()
+
+	_, _ = chatter.GetRequest("_cluster/settings")
+	req, found := chatter.GetRequest("_cluster/settings")
+	if !found || req.Method != http.MethodPut {
+		t.Fatal("Exp. the scaled down node to be excluded from shard allocation")
+	}
+	if exp := `{"persistent":{"cluster.routing.allocation.exclude._name":"elasticsearch-d-abc-3"}}`; req.Body != exp {
+		t.Errorf("Exp. request body %s but got %s", exp, req.Body)
+	}
+
+	_, condition := getESNodeCondition(cluster.Status.Conditions, api.ScalingDown)
+	if condition == nil || condition.Status != v1.ConditionTrue {
+		t.Fatalf("Exp. the %s condition to be true but got %v", api.ScalingDown, cluster.Status.Conditions)
+	}
+	if exp := "Moving the shards off node elasticsearch-d-abc-3 before removing it, 1 left"; condition.Message != exp {
+		t.Errorf("Exp. the condition message %q but got %q", exp, condition.Message)
+	}
+	if index, _ := getNodeStatus("elasticsearch-d-abc-3", &cluster.Status); index == NotFoundIndex {
+		t.Errorf("Exp. the node to be kept until its shards were moved")
+	}
+}
+
+func TestScaleDownDataNodesRemovesDrainedNode(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := newMigrationCluster(
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-1"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-2"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-3"},
+	)
+	excluded := `{"persistent": {"cluster": {"routing": {"allocation": {"exclude": {"_name": "elasticsearch-d-abc-3"}}}}}}`
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings": {
+			{StatusCode: 200, Body: excluded},
+			{StatusCode: 200, Body: excluded},
+			{StatusCode: 200, Body: `{"acknowledged": true}`},
+		},
+		"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+			{StatusCode: 200, Body: `[{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-d-abc-1"}]`},
+		},
+		"_cluster/health": {
+			{StatusCode: 200, Body: `{"status": "green"}`},
+		},
+		"_cluster/state/nodes": {
+			{StatusCode: 200, Body: `{"nodes": {"a": {"name": "elasticsearch-d-abc-1"}, "b": {"name": "elasticsearch-d-abc-2"}}}`},
+		},
+	})
+	er := newScaleDownRequest(cluster, chatter,
+		&apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-d-abc-3", Namespace: cluster.Namespace}},
+		newExpansionClaim("elasticsearch-elasticsearch-d-abc-3", "10Gi"),
+	)
+	defer delete(nodes, nodeMapKey(cluster.Name, cluster.Namespace))
+
+	er.scaleDownDataNodes()
+
+	if index, _ := getNodeStatus("elasticsearch-d-abc-3", &cluster.Status); index != NotFoundIndex {
+		t.Errorf("Exp. the status of the drained node to be removed")
+	}
+	err := er.client.Get(context.TODO(), client.ObjectKey{Name: "elasticsearch-d-abc-3", Namespace: cluster.Namespace}, &apps.Deployment{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Exp. the deployment of the drained node to be deleted but got %v", err)
+	}
+
+	_, _ = chatter.GetRequest("_cluster/settings")
+	_, _ = chatter.GetRequest("_cluster/settings")
+	req, found := chatter.GetRequest("_cluster/settings")
+	if !found || req.Method != http.MethodPut {
+		t.Fatal("Exp. the exclude of the drained node to be cleared")
+	}
+	if exp := `{"persistent":{"cluster.routing.allocation.exclude._name":null}}`; req.Body != exp {
+		t.Errorf("Exp. request body %s but got %s", exp, req.Body)
+	}
+
+	// without nodes left to remove the scale down is completed
+	er.scaleDownDataNodes()
+	if _, condition := getESNodeCondition(cluster.Status.Conditions, api.ScalingDown); condition != nil {
+		t.Errorf("Exp. the %s condition to be cleared but got %v", api.ScalingDown, condition)
+	}
+}
+
+func TestScaleDownDataNodesKeepsRemovedNodeExcluded(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := newMigrationCluster(
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-1"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-2"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-3"},
+	)
+	excluded := `{"persistent": {"cluster": {"routing": {"allocation": {"exclude": {"_name": "elasticsearch-d-abc-3"}}}}}}`
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings": {
+			{StatusCode: 200, Body: excluded},
+			{StatusCode: 200, Body: excluded},
+			{StatusCode: 200, Body: `{"acknowledged": true}`},
+		},
+		"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+			{StatusCode: 200, Body: `[{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-d-abc-1"}]`},
+		},
+		"_cluster/health": {
+			{StatusCode: 200, Body: `{"status": "green"}`},
+		},
+		"_cluster/state/nodes": {
+			{StatusCode: 200, Body: `{"nodes": {"a": {"name": "elasticsearch-d-abc-1"}, "b": {"name": "elasticsearch-d-abc-2"}, "c": {"name": "elasticsearch-d-abc-3"}}}`},
+			{StatusCode: 200, Body: `{"nodes": {"a": {"name": "elasticsearch-d-abc-1"}, "b": {"name": "elasticsearch-d-abc-2"}}}`},
+		},
+	})
+	er := newScaleDownRequest(cluster, chatter,
+		&apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-d-abc-3", Namespace: cluster.Namespace}},
+	)
+	defer delete(nodes, nodeMapKey(cluster.Name, cluster.Namespace))
+
+	er.scaleDownDataNodes()
+
+	if index, _ := getNodeStatus("elasticsearch-d-abc-3", &cluster.Status); index != NotFoundIndex {
+		t.Errorf("Exp. the status of the drained node to be removed")
+	}
+	if exp := []string{"elasticsearch-d-abc-3"}; !reflect.DeepEqual(cluster.Status.RemovedNodes, exp) {
+		t.Fatalf("Exp. the removed nodes %v but got %v", exp, cluster.Status.RemovedNodes)
+	}
+	if requests := chatter.Requests["_cluster/settings"]; len(requests) != 1 || requests[0].Method != http.MethodGet {
+		t.Fatalf("Exp. the node still in the cluster to be kept excluded from shard allocation but got %v", requests)
+	}
+	_, _ = chatter.GetRequest("_cluster/settings")
+
+	// the node is included on the next reconcile once it left the cluster
+	er.includeRemovedNodes()
+
+	if len(cluster.Status.RemovedNodes) != 0 {
+		t.Errorf("Exp. no removed nodes but got %v", cluster.Status.RemovedNodes)
+	}
+	_, _ = chatter.GetRequest("_cluster/settings")
+	req, found := chatter.GetRequest("_cluster/settings")
+	if !found || req.Method != http.MethodPut {
+		t.Fatal("Exp. the exclude of the removed node to be cleared")
+	}
+	if exp := `{"persistent":{"cluster.routing.allocation.exclude._name":null}}`; req.Body != exp {
+		t.Errorf("Exp. request body %s but got %s", exp, req.Body)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/service"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -43,7 +44,7 @@ func (er *ElasticsearchRequest) CreateOrUpdateServices() error {
 		dpl.Name,
 		"restapi",
 		9200,
-		selectorForES("es-node-client", dpl.Name),
+		restAPISelector(dpl),
 		annotations,
 		false,
 		map[string]string{},
@@ -103,3 +104,12 @@ func (er *ElasticsearchRequest) createOrUpdateService(serviceName, namespace, cl
 
 	return nil
 }
+
+// restAPISelector routes the REST API to the coordinating-only nodes if any and to the
+// client nodes otherwise
+func restAPISelector(cluster *api.Elasticsearch) map[string]string {
+	if hasCoordinatingNodes(cluster) {
+		return selectorForES("es-node-coordinating", cluster.Name)
+	}
+	return selectorForES("es-node-client", cluster.Name)
+}
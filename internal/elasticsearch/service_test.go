@@ -379,3 +379,35 @@ func TestCreateOrUpdateServices(t *testing.T) {
 		})
 	}
 }
+
+func TestRESTAPISelector(t *testing.T) {
+	cluster := &loggingv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch"},
+		Spec: loggingv1.ElasticsearchSpec{
+			Nodes: []loggingv1.ElasticsearchNode{
+				{
+					Roles: []loggingv1.ElasticsearchNodeRole{
+						loggingv1.ElasticsearchRoleClient,
+						loggingv1.ElasticsearchRoleData,
+						loggingv1.ElasticsearchRoleMaster,
+					},
+					NodeCount: 3,
+				},
+			},
+		},
+	}
+
+	want := map[string]string{"cluster-name": "elasticsearch", "es-node-client": "true"}
+	if diff := cmp.Diff(restAPISelector(cluster), want); diff != "" {
+		t.Errorf("diff: %s", diff)
+	}
+
+	cluster.Spec.Nodes = append(cluster.Spec.Nodes, loggingv1.ElasticsearchNode{
+		Roles:     []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleCoordinating},
+		NodeCount: 2,
+	})
+	want = map[string]string{"cluster-name": "elasticsearch", "es-node-coordinating": "true"}
+	if diff := cmp.Diff(restAPISelector(cluster), want); diff != "" {
+		t.Errorf("diff: %s", diff)
+	}
+}
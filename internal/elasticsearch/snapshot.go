@@ -0,0 +1,213 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+const (
+	// SnapshotAccessKeyIDKey is the key of the access key id in the secret of a s3 snapshot repository
+	SnapshotAccessKeyIDKey = "access_key_id"
+	// SnapshotSecretAccessKeyKey is the key of the secret access key in the secret of a s3 snapshot repository
+	SnapshotSecretAccessKeyKey = "secret_access_key"
+
+	snapshotVolumeName            = "elasticsearch-snapshots"
+	snapshotCredentialsVolumeName = "elasticsearch-snapshot-credentials"
+	keystoreVolumeName            = "elasticsearch-keystore"
+	snapshotCredentialsMountPath  = "/etc/elasticsearch/snapshot-credentials"
+	keystoreMountPath             = "/etc/elasticsearch/keystore"
+	keystoreFile                  = "elasticsearch.keystore"
+	// esPathConf is the config directory of the elasticsearch image holding the keystore
+	esPathConf = "/etc/elasticsearch"
+
+	// snapshotCredentialsHashEnvVar carries the hash of the s3 credentials to restart the
+	// nodes with the new keystore when they are rotated
+	snapshotCredentialsHashEnvVar = "SNAPSHOT_CREDENTIALS_HASH"
+)
+
+// snapshotVolumes are the volumes of the pod template taken from the snapshot policy
+var snapshotVolumes = map[string]bool{
+	snapshotVolumeName:            true,
+	snapshotCredentialsVolumeName: true,
+	keystoreVolumeName:            true,
+}
+
+// addSnapshotVolume mounts the claim of a filesystem snapshot repository to the
+// elasticsearch container
+func addSnapshotVolume(template *v1.PodTemplateSpec, snapshot *api.SnapshotPolicySpec) {
+	if !isFSSnapshotRepository(snapshot) {
+		return
+	}
+
+	template.Spec.Volumes = append(template.Spec.Volumes, v1.Volume{
+		Name: snapshotVolumeName,
+		VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: snapshot.Repository.FS.ClaimName,
+			},
+		},
+	})
+
+	for i, container := range template.Spec.Containers {
+		if container.Name != "elasticsearch" {
+			continue
+		}
+		template.Spec.Containers[i].VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      snapshotVolumeName,
+			MountPath: constants.SnapshotRepositoryPath,
+		})
+	}
+}
+
+// addSnapshotKeystore adds the credentials of a s3 snapshot repository to the keystore of
+// the elasticsearch container. An init container creates the keystore from the secret of
+// the repository and the hash of the secret restarts the node when the credentials change.
+func addSnapshotKeystore(template *v1.PodTemplateSpec, namespace string, snapshot *api.SnapshotPolicySpec, c client.Client) {
+	if !isS3SnapshotRepository(snapshot) {
+		return
+	}
+	secretName := snapshot.Repository.S3.Secret.Name
+
+	template.Spec.Volumes = append(template.Spec.Volumes,
+		v1.Volume{
+			Name: snapshotCredentialsVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: secretName,
+					Items: []v1.KeyToPath{
+						{Key: SnapshotAccessKeyIDKey, Path: SnapshotAccessKeyIDKey},
+						{Key: SnapshotSecretAccessKeyKey, Path: SnapshotSecretAccessKeyKey},
+					},
+				},
+			},
+		},
+		v1.Volume{
+			Name: keystoreVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		},
+	)
+
+	credentialsHash := secret.GetDataSHA256(context.TODO(), c, client.ObjectKey{Name: secretName, Namespace: namespace})
+
+	for i, container := range template.Spec.Containers {
+		if container.Name != "elasticsearch" {
+			continue
+		}
+		template.Spec.Containers[i].Env = append(container.Env, v1.EnvVar{
+			Name:  snapshotCredentialsHashEnvVar,
+			Value: fmt.Sprintf("%x", credentialsHash),
+		})
+		template.Spec.Containers[i].VolumeMounts = append(template.Spec.Containers[i].VolumeMounts, v1.VolumeMount{
+			Name:      keystoreVolumeName,
+			MountPath: fmt.Sprintf("%s/%s", esPathConf, keystoreFile),
+			SubPath:   keystoreFile,
+			ReadOnly:  true,
+		})
+
+		keystore := "/usr/share/elasticsearch/bin/elasticsearch-keystore"
+		template.Spec.InitContainers = append(template.Spec.InitContainers, v1.Container{
+			Name:            "keystore",
+			Image:           container.Image,
+			ImagePullPolicy: container.ImagePullPolicy,
+			Command: []string{
+				"sh", "-c",
+				strings.Join([]string{
+					"set -e",
+					fmt.Sprintf("rm -f %s/%s", keystoreMountPath, keystoreFile),
+					fmt.Sprintf("%s create", keystore),
+					fmt.Sprintf("%s add-file s3.client.default.access_key %s/%s", keystore, snapshotCredentialsMountPath, SnapshotAccessKeyIDKey),
+					fmt.Sprintf("%s add-file s3.client.default.secret_key %s/%s", keystore, snapshotCredentialsMountPath, SnapshotSecretAccessKeyKey),
+				}, "; "),
+			},
+			Env: []v1.EnvVar{
+				{Name: "ES_PATH_CONF", Value: keystoreMountPath},
+			},
+			VolumeMounts: []v1.VolumeMount{
+				{
+					Name:      snapshotCredentialsVolumeName,
+					MountPath: snapshotCredentialsMountPath,
+					ReadOnly:  true,
+				},
+				{
+					Name:      keystoreVolumeName,
+					MountPath: keystoreMountPath,
+				},
+			},
+			Resources:       container.Resources,
+			SecurityContext: utils.ContainerSecurityContext(),
+		})
+	}
+}
+
+// syncSnapshotVolume replaces the snapshot volumes of the current volumes with the desired ones
+// if any. The other volumes are kept as is.
+func syncSnapshotVolume(current, desired []v1.Volume) []v1.Volume {
+	volumes := []v1.Volume{}
+	for _, volume := range current {
+		if !snapshotVolumes[volume.Name] {
+			volumes = append(volumes, volume)
+		}
+	}
+	for _, volume := range desired {
+		if snapshotVolumes[volume.Name] {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes
+}
+
+func isFSSnapshotRepository(snapshot *api.SnapshotPolicySpec) bool {
+	return snapshot != nil &&
+		snapshot.Repository.Type == api.SnapshotRepositoryTypeFS &&
+		snapshot.Repository.FS != nil &&
+		snapshot.Repository.FS.ClaimName != ""
+}
+
+func isS3SnapshotRepository(snapshot *api.SnapshotPolicySpec) bool {
+	return snapshot != nil &&
+		snapshot.Repository.Type == api.SnapshotRepositoryTypeS3 &&
+		snapshot.Repository.S3 != nil &&
+		snapshot.Repository.S3.Secret.Name != ""
+}
+
+// snapshotPathRepo returns the path.repo setting allowing the filesystem repository
+func snapshotPathRepo(snapshot *api.SnapshotPolicySpec) string {
+	if !isFSSnapshotRepository(snapshot) {
+		return ""
+	}
+	return constants.SnapshotRepositoryPath
+}
+
+// snapshotS3Client returns the endpoint and protocol of the default s3 client. The endpoint
+// is empty for AWS S3.
+func snapshotS3Client(snapshot *api.SnapshotPolicySpec) (endpoint, protocol string) {
+	if snapshot == nil || snapshot.Repository.Type != api.SnapshotRepositoryTypeS3 || snapshot.Repository.S3 == nil {
+		return "", ""
+	}
+
+	endpoint = snapshot.Repository.S3.Endpoint
+	if endpoint == "" {
+		return "", ""
+	}
+
+	protocol = "https"
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		if u.Scheme != "" {
+			protocol = u.Scheme
+		}
+		endpoint = u.Host
+	}
+	return strings.TrimSuffix(endpoint, "/"), protocol
+}
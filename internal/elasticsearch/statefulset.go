@@ -56,6 +56,11 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 		nodeName, cluster.Name, cluster.Namespace, node,
 		cluster.Spec.Spec, labels, roleMap, client, logConfig,
 	)
+	addFlavor(&template, cluster, true)
+	addSnapshotVolume(&template, cluster.Spec.Snapshot)
+	addSnapshotKeystore(&template, cluster.Namespace, cluster.Spec.Snapshot, client)
+	addESConfig(&template, cluster, node)
+	addZoneAwareness(&template, cluster.Name, node, roleMap)
 
 	sts := statefulset.New(nodeName, cluster.Namespace, labels, replicas).
 		WithSelector(metav1.LabelSelector{
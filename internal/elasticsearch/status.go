@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/pod"
@@ -342,18 +343,21 @@ func (er *ElasticsearchRequest) updateStorageConditions(status *api.Elasticsearc
 				return nil
 			}
 
+			// nodes allowing it are migrated to the new storage class
 			isDefaultName := specVol.StorageClassName == nil && current.Spec.StorageClassName != nil
-			if !isDefaultName && !reflect.DeepEqual(current.Spec.StorageClassName, specVol.StorageClassName) {
+			if !isDefaultName && !allowsStorageClassMigration(node) && !reflect.DeepEqual(current.Spec.StorageClassName, specVol.StorageClassName) {
 				nameStatus = v1.ConditionTrue
 			}
 
+			// growing volumes are expanded when their storage class allows it
 			currentSize := current.Spec.Resources.Requests.Storage()
-			if currentSize != nil && specVol.Size != nil {
-				if !currentSize.Equal(*specVol.Size) {
+			switch specVol.Size.Cmp(*currentSize) {
+			case -1:
+				sizeStatus = v1.ConditionTrue
+			case 1:
+				if allowed, err := er.allowsVolumeExpansion(current); err == nil && !allowed {
 					sizeStatus = v1.ConditionTrue
 				}
-			} else if currentSize != nil || specVol.Size != nil {
-				sizeStatus = v1.ConditionTrue
 			}
 
 			return nil
@@ -377,7 +381,7 @@ func (er *ElasticsearchRequest) updateStorageConditions(status *api.Elasticsearc
 		Status:             nameStatus,
 		LastTransitionTime: metav1.Now(),
 		Reason:             "StorageClassNameChangeIgnored",
-		Message:            "Changing the storage class name for a custom resource is not supported",
+		Message:            "Changing the storage class name is only supported by data nodes allowing storage class migration",
 	})
 
 	updateESNodeCondition(status, &api.ClusterCondition{
@@ -385,7 +389,7 @@ func (er *ElasticsearchRequest) updateStorageConditions(status *api.Elasticsearc
 		Status:             sizeStatus,
 		LastTransitionTime: metav1.Now(),
 		Reason:             "StorageSizeChangeIgnored",
-		Message:            "Resizing the storage for a custom resource is not supported",
+		Message:            "Shrinking the storage, or expanding it with a storage class which does not allow volume expansion, is not supported",
 	})
 
 	return nil
@@ -642,61 +646,15 @@ func (er *ElasticsearchRequest) updatePodNodeConditions(status *api.Elasticsearc
 }
 
 func (er *ElasticsearchRequest) refreshDiskWatermarkThresholds() {
-	// quantity, err := resource.ParseQuantity(string)
-	low, high, flood, err := er.esClient.GetDiskWatermarks()
+	watermarks, err := er.esClient.GetDiskWatermarks()
 	if err != nil {
-		er.L().Info("Unable to refresh disk watermarks from cluster, using defaults", "error", err)
-	}
-
-	switch low.(type) {
-	case float64:
-		value := low.(float64)
-		DiskWatermarkLowPct = &value
-		DiskWatermarkLowAbs = nil
-	case string:
-		value, err := resource.ParseQuantity(strings.ToUpper(low.(string)))
-		if err != nil {
-			er.L().Info("Unable to parse quantity", "value", low.(string), "error", err)
-		}
-		DiskWatermarkLowAbs = &value
-		DiskWatermarkLowPct = nil
-	default:
-		er.L().Error(err, "Unknown type for low", "type", fmt.Sprintf("%T", low))
-	}
-
-	switch high.(type) {
-	case float64:
-		value := high.(float64)
-		DiskWatermarkHighPct = &value
-		DiskWatermarkHighAbs = nil
-	case string:
-		value, err := resource.ParseQuantity(strings.ToUpper(high.(string)))
-		if err != nil {
-			er.L().Info("Unable to parse quantity", "value", high.(string), "error", err)
-		}
-		DiskWatermarkHighAbs = &value
-		DiskWatermarkHighPct = nil
-	default:
-		// error
-		er.L().Error(err, "Unknown type for high", "type", fmt.Sprintf("%T", high))
+		er.L().Info("Unable to refresh disk watermarks from cluster, using previous values", "error", err)
+		return
 	}
 
-	switch flood.(type) {
-	case float64:
-		value := flood.(float64)
-		DiskWatermarkFloodPct = &value
-		DiskWatermarkFloodAbs = nil
-	case string:
-		value, err := resource.ParseQuantity(strings.ToUpper(flood.(string)))
-		if err != nil {
-			er.L().Info("Unable to parse quantity", "value", flood.(string), "error", err)
-		}
-		DiskWatermarkFloodAbs = &value
-		DiskWatermarkFloodPct = nil
-	default:
-		// error
-		er.L().Error(err, "Unknown type for flood", "type", fmt.Sprintf("%T", flood))
-	}
+	DiskWatermarkLowPct, DiskWatermarkLowAbs = watermarks.Low.Percentage, watermarks.Low.Quantity
+	DiskWatermarkHighPct, DiskWatermarkHighAbs = watermarks.High.Percentage, watermarks.High.Quantity
+	DiskWatermarkFloodPct, DiskWatermarkFloodAbs = watermarks.FloodStage.Percentage, watermarks.FloodStage.Quantity
 }
 
 func exceedsLowWatermark(usage resource.Quantity, percent float64) bool {
@@ -971,6 +929,24 @@ func updateInvalidDataCountCondition(status *api.ElasticsearchStatus, value v1.C
 	})
 }
 
+func updateInvalidRolesCondition(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+	var message string
+	var reason string
+	if value == v1.ConditionTrue {
+		message = "Coordinating role combined with other roles. Please ensure coordinating nodes have no other roles"
+		reason = "Invalid Settings"
+	} else {
+		message = ""
+		reason = ""
+	}
+	return updateESNodeCondition(status, &api.ClusterCondition{
+		Type:    api.InvalidRoles,
+		Status:  value,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
 func updateInvalidUUIDChangeCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
 	var reason string
 	if value == v1.ConditionTrue {
@@ -1093,3 +1069,60 @@ func updateUpdatingESSettingsCondition(status *api.ElasticsearchStatus, value v1
 		Status: value,
 	})
 }
+
+// changingConditions are the conditions of a cluster in the middle of a change
+var changingConditions = []api.ClusterConditionType{
+	api.UpdatingSettings,
+	api.ScalingUp,
+	api.ScalingDown,
+	api.Restarting,
+	api.Recovering,
+	api.UpdatingESSettings,
+}
+
+// IsClusterChanging returns true if the cluster is restarting, upgrading, scaling or
+// recovering. Node upgrades deferred to the next maintenance window are not changing
+// the cluster.
+func IsClusterChanging(cluster *api.Elasticsearch) bool {
+	for _, conditionType := range changingConditions {
+		if _, condition := getESNodeCondition(cluster.Status.Conditions, conditionType); condition != nil && condition.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+
+	// the removed nodes are excluded from shard allocation until they left the cluster
+	if len(cluster.Status.RemovedNodes) > 0 {
+		return true
+	}
+
+	maintenanceAllowed := isMaintenanceAllowed(cluster, time.Now().UTC())
+	for _, node := range cluster.Status.Nodes {
+		upgradeStatus := node.UpgradeStatus
+		if upgradeStatus.UnderUpgrade == v1.ConditionTrue ||
+			upgradeStatus.ScheduledForCertRedeploy == v1.ConditionTrue {
+			return true
+		}
+		if maintenanceAllowed && (upgradeStatus.ScheduledForUpgrade == v1.ConditionTrue ||
+			upgradeStatus.ScheduledForRedeploy == v1.ConditionTrue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsClusterSteady returns true if the cluster is green, all of its pods are ready and
+// nothing is changing
+func IsClusterSteady(cluster *api.Elasticsearch) bool {
+	if cluster.Status.Cluster.Status != "green" || IsClusterChanging(cluster) {
+		return false
+	}
+
+	for _, podStates := range cluster.Status.Pods {
+		if len(podStates[api.PodStateTypeNotReady]) > 0 || len(podStates[api.PodStateTypeFailed]) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
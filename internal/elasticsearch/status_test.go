@@ -448,3 +448,124 @@ func TestUnschedulableNodesConditions(t *testing.T) {
 		t.Errorf("Expected cluster node statuses to be same. Diff is %s", diff)
 	}
 }
+
+func TestClusterActivity(t *testing.T) {
+	readyPods := map[loggingv1.ElasticsearchNodeRole]loggingv1.PodStateMap{
+		loggingv1.ElasticsearchRoleData: {
+			loggingv1.PodStateTypeReady:    {"elasticsearch-cdm-1"},
+			loggingv1.PodStateTypeNotReady: {},
+			loggingv1.PodStateTypeFailed:   {},
+		},
+	}
+
+	tests := []struct {
+		desc         string
+		spec         loggingv1.ElasticsearchSpec
+		status       loggingv1.ElasticsearchStatus
+		wantChanging bool
+		wantSteady   bool
+	}{
+		{
+			desc: "green cluster with ready pods",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Pods:    readyPods,
+			},
+			wantSteady: true,
+		},
+		{
+			desc: "yellow cluster",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "yellow"},
+				Pods:    readyPods,
+			},
+		},
+		{
+			desc: "green cluster with pod not ready",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Pods: map[loggingv1.ElasticsearchNodeRole]loggingv1.PodStateMap{
+					loggingv1.ElasticsearchRoleData: {
+						loggingv1.PodStateTypeNotReady: {"elasticsearch-cdm-1"},
+					},
+				},
+			},
+		},
+		{
+			desc: "restarting cluster",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Pods:    readyPods,
+				Conditions: []loggingv1.ClusterCondition{
+					{Type: loggingv1.Restarting, Status: corev1.ConditionTrue},
+				},
+			},
+			wantChanging: true,
+		},
+		{
+			desc: "node under upgrade",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Pods:    readyPods,
+				Nodes: []loggingv1.ElasticsearchNodeStatus{
+					{
+						DeploymentName: "elasticsearch-cdm-1",
+						UpgradeStatus: loggingv1.ElasticsearchNodeUpgradeStatus{
+							UnderUpgrade: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			wantChanging: true,
+		},
+		{
+			desc: "node upgrade deferred to the next maintenance window",
+			spec: loggingv1.ElasticsearchSpec{
+				Maintenance: &loggingv1.ElasticsearchMaintenanceSpec{
+					Windows: []loggingv1.MaintenanceWindowSpec{
+						{Schedule: "0 2 1 1 *", Duration: metav1.Duration{Duration: time.Minute}},
+					},
+				},
+			},
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Pods:    readyPods,
+				Nodes: []loggingv1.ElasticsearchNodeStatus{
+					{
+						DeploymentName: "elasticsearch-cdm-1",
+						UpgradeStatus: loggingv1.ElasticsearchNodeUpgradeStatus{
+							ScheduledForUpgrade: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			wantSteady: true,
+		},
+		{
+			desc: "node upgrade scheduled without maintenance windows",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Pods:    readyPods,
+				Nodes: []loggingv1.ElasticsearchNodeStatus{
+					{
+						DeploymentName: "elasticsearch-cdm-1",
+						UpgradeStatus: loggingv1.ElasticsearchNodeUpgradeStatus{
+							ScheduledForUpgrade: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			wantChanging: true,
+		},
+	}
+
+	for _, test := range tests {
+		cluster := &loggingv1.Elasticsearch{Spec: test.spec, Status: test.status}
+		if changing := IsClusterChanging(cluster); changing != test.wantChanging {
+			t.Errorf("%s: exp. changing to be %t but was %t", test.desc, test.wantChanging, changing)
+		}
+		if steady := IsClusterSteady(cluster); steady != test.wantSteady {
+			t.Errorf("%s: exp. steady to be %t but was %t", test.desc, test.wantSteady, steady)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package elasticsearch
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// migrateStorageClasses migrates the data nodes allowing it to a changed storage class name,
+// one node at a time. A replacement node is created on the new storage class before the
+// node is excluded from shard allocation. The node and its volume are deleted once all its
+// shards were moved to the other nodes.
+func (er *ElasticsearchRequest) migrateStorageClasses() {
+	status := er.cluster.Status.DeepCopy()
+	for index := range status.Nodes {
+		if status.Nodes[index].StorageMigration != nil {
+			er.progressStorageMigration(status, index)
+			return
+		}
+	}
+
+	// nodes are replaced one at a time, after the scaled down nodes were removed
+	if len(er.getScaledDownNodes()) > 0 {
+		return
+	}
+
+	volume, err := er.getVolumeToMigrate()
+	if err != nil {
+		er.ll.Error(err, "failed to get the volumes of the nodes")
+		return
+	}
+	if volume == nil {
+		return
+	}
+
+	if health, _ := er.esClient.GetClusterHealthStatus(); health != greenClusterState {
+		er.ll.Info("Waiting for the cluster to be green to migrate the storage class of a node", "node", volume.node.name(), "currentHealth", health)
+		return
+	}
+
+	index, nodeStatus := getNodeStatus(volume.node.name(), status)
+	if index == NotFoundIndex {
+		return
+	}
+	nodeStatus.StorageMigration = &api.ElasticsearchStorageMigrationStatus{
+		Phase:            api.StorageMigrationPending,
+		StorageClassName: *volume.spec.Storage.StorageClassName,
+		Replacement:      nextDataNodeName(volume.node.name(), status),
+	}
+	status.Nodes[index] = *nodeStatus
+
+	er.ll.Info("Migrating the node to a new storage class", "node", volume.node.name(),
+		"storageClass", nodeStatus.StorageMigration.StorageClassName,
+		"replacement", nodeStatus.StorageMigration.Replacement)
+	if err := er.updateNodeStatus(*status); err != nil {
+		er.ll.Error(err, "failed to update the storage migration status")
+	}
+}
+
+// progressStorageMigration moves the migration of the node at the index of the status to
+// its next phase
+func (er *ElasticsearchRequest) progressStorageMigration(status *api.ElasticsearchStatus, index int) {
+	nodeName := status.Nodes[index].DeploymentName
+	migration := status.Nodes[index].StorageMigration
+
+	node := er.getNode(nodeName)
+	if node == nil {
+		return
+	}
+
+	if er.getNode(migration.Replacement) == nil {
+		// the replacement was removed by scaling the node down
+		er.ll.Info("Cancelling the storage migration of the node without replacement", "node", nodeName)
+		if err := er.includeInAllocation(nodeName); err != nil {
+			er.ll.Error(err, "failed to include the node in shard allocation", "node", nodeName)
+			return
+		}
+		status.Nodes[index].StorageMigration = nil
+		if err := er.updateNodeStatus(*status); err != nil {
+			er.ll.Error(err, "failed to update the storage migration status")
+		}
+		return
+	}
+
+	switch migration.Phase {
+	case api.StorageMigrationPending:
+		joined, err := er.esClient.IsNodeInCluster(migration.Replacement)
+		if err != nil || !joined {
+			er.ll.Info("Waiting for the replacement of the node to join the cluster", "node", nodeName, "replacement", migration.Replacement)
+			return
+		}
+		if err := er.excludeFromAllocation(nodeName); err != nil {
+			er.ll.Error(err, "failed to exclude the node from shard allocation", "node", nodeName)
+			return
+		}
+		migration.Phase = api.StorageMigrationEvacuating
+
+	case api.StorageMigrationEvacuating:
+		// keep the node excluded in case the setting was reset
+		if err := er.excludeFromAllocation(nodeName); err != nil {
+			er.ll.Error(err, "failed to exclude the node from shard allocation", "node", nodeName)
+			return
+		}
+		shards, err := er.esClient.GetNodeShardCount(nodeName)
+		if err != nil {
+			er.ll.Error(err, "failed to get the shards of the node", "node", nodeName)
+			return
+		}
+		migration.Shards = int32(shards)
+		if shards > 0 {
+			er.ll.Info("Waiting for the shards to be moved off the node", "node", nodeName, "shards", shards)
+			break
+		}
+
+		if err := er.removeEvacuatedNode(node, status); err != nil {
+			er.ll.Error(err, "failed to remove the migrated node", "node", nodeName)
+			return
+		}
+		status.Nodes = append(status.Nodes[:index], status.Nodes[index+1:]...)
+		er.ll.Info("Completed the storage migration of the node", "node", nodeName, "replacement", migration.Replacement)
+	}
+
+	if err := er.updateNodeStatus(*status); err != nil {
+		er.ll.Error(err, "failed to update the storage migration status")
+	}
+}
+
+// getVolumeToMigrate returns the first volume whose storage class differs from the storage
+// class name of a node allowing storage class migration
+func (er *ElasticsearchRequest) getVolumeToMigrate() (*nodeVolume, error) {
+	volumes, err := er.getNodeVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, volume := range volumes {
+		if !allowsStorageClassMigration(volume.spec) || volume.claim.Status.Phase != v1.ClaimBound {
+			continue
+		}
+		if isStorageClassChanged(volume.claim, volume.spec.Storage) {
+			return &volumes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (er *ElasticsearchRequest) getNode(name string) NodeTypeInterface {
+	for _, node := range nodes[nodeMapKey(er.cluster.Name, er.cluster.Namespace)] {
+		if node.name() == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// allowsStorageClassMigration returns true if the node opted in to migrate to a changed
+// storage class name. Master nodes are not migrated to keep the quorum of the cluster.
+func allowsStorageClassMigration(node api.ElasticsearchNode) bool {
+	return node.Storage.AllowStorageClassMigration && isDataNode(node) && !isMasterNode(node)
+}
+
+// isStorageClassChanged returns true if the claim does not use the storage class name of
+// the storage spec. Without a storage class name the claim keeps its default class.
+func isStorageClassChanged(claim *v1.PersistentVolumeClaim, storage api.ElasticsearchStorageSpec) bool {
+	if storage.StorageClassName == nil {
+		return false
+	}
+	return claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName != *storage.StorageClassName
+}
+
+// nextDataNodeName returns the name of a new replica of the data node, whose index follows
+// the highest one used. The names of removed replicas are not reused while their volumes
+// may still be deleted.
+func nextDataNodeName(name string, status *api.ElasticsearchStatus) string {
+	nodeName := name[:strings.LastIndex(name, "-")]
+
+	next := int32(1)
+	for _, nodeStatus := range status.Nodes {
+		names := []string{nodeStatus.DeploymentName}
+		if nodeStatus.StorageMigration != nil {
+			names = append(names, nodeStatus.StorageMigration.Replacement)
+		}
+		for _, name := range names {
+			if index, ok := parseDataNodeIndex(nodeName, name); ok && index >= next {
+				next = index + 1
+			}
+		}
+	}
+	return addDataNodeSuffix(nodeName, next)
+}
@@ -0,0 +1,226 @@
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/log"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func newMigrationCluster(nodeStatus ...api.ElasticsearchNodeStatus) *api.Elasticsearch {
+	size := resource.MustParse("10Gi")
+	return &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		Spec: api.ElasticsearchSpec{
+			Nodes: []api.ElasticsearchNode{
+				{
+					Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+					NodeCount: 2,
+					GenUUID:   pointer.String("abc"),
+					Storage: api.ElasticsearchStorageSpec{
+						StorageClassName:           pointer.String("gp3"),
+						Size:                       &size,
+						AllowStorageClassMigration: true,
+					},
+				},
+			},
+		},
+		Status: api.ElasticsearchStatus{Nodes: nodeStatus},
+	}
+}
+
+func newMigrationNode(name string, k8sClient client.Client, chatter *helpers.FakeElasticsearchChatter) NodeTypeInterface {
+	return &deploymentNode{
+		self:     apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-logging"}},
+		client:   k8sClient,
+		esClient: helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", k8sClient, chatter),
+	}
+}
+
+func TestGetDataNodeNames(t *testing.T) {
+	migrating := api.ElasticsearchNodeStatus{
+		DeploymentName:   "elasticsearch-d-abc-1",
+		StorageMigration: &api.ElasticsearchStorageMigrationStatus{Replacement: "elasticsearch-d-abc-4"},
+	}
+	tests := []struct {
+		desc      string
+		nodeCount int32
+		status    []api.ElasticsearchNodeStatus
+		exp       []string
+		expScaled []string
+	}{
+		{
+			desc:      "new node",
+			nodeCount: 2,
+			exp:       []string{"elasticsearch-d-abc-1", "elasticsearch-d-abc-2"},
+		},
+		{
+			desc:      "scaled up node",
+			nodeCount: 3,
+			status:    []api.ElasticsearchNodeStatus{{DeploymentName: "elasticsearch-d-abc-2"}, {DeploymentName: "elasticsearch-d-abc-4"}},
+			exp:       []string{"elasticsearch-d-abc-1", "elasticsearch-d-abc-2", "elasticsearch-d-abc-4"},
+		},
+		{
+			desc:      "scaled down node",
+			nodeCount: 1,
+			status:    []api.ElasticsearchNodeStatus{{DeploymentName: "elasticsearch-d-abc-1"}, {DeploymentName: "elasticsearch-d-abc-2"}},
+			exp:       []string{"elasticsearch-d-abc-1", "elasticsearch-d-abc-2"},
+			expScaled: []string{"elasticsearch-d-abc-2"},
+		},
+		{
+			desc:      "migrating node",
+			nodeCount: 2,
+			status:    []api.ElasticsearchNodeStatus{migrating, {DeploymentName: "elasticsearch-d-abc-2"}, {DeploymentName: "elasticsearch-cdm-xyz-1"}},
+			exp:       []string{"elasticsearch-d-abc-2", "elasticsearch-d-abc-4", "elasticsearch-d-abc-1"},
+		},
+	}
+	for _, test := range tests {
+		got, scaledDown := getDataNodeNames("elasticsearch-d-abc", test.nodeCount, &api.ElasticsearchStatus{Nodes: test.status})
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("%s: Exp. the names %v but got %v", test.desc, test.exp, got)
+		}
+		if test.expScaled == nil {
+			test.expScaled = []string{}
+		}
+		if !reflect.DeepEqual(scaledDown, test.expScaled) {
+			t.Errorf("%s: Exp. the scaled down names %v but got %v", test.desc, test.expScaled, scaledDown)
+		}
+	}
+}
+
+func TestMigrateStorageClassesStartsWithGreenCluster(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := newMigrationCluster(
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-1"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-2"},
+	)
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		cluster.DeepCopy(),
+		newExpansionClaim("elasticsearch-elasticsearch-d-abc-1", "10Gi"),
+		newExpansionClaim("elasticsearch-elasticsearch-d-abc-2", "10Gi"),
+	).Build()
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/health": {
+			{StatusCode: 200, Body: `{"status": "green"}`},
+		},
+	})
+
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+	nodes[key] = []NodeTypeInterface{
+		newMigrationNode("elasticsearch-d-abc-1", k8sClient, chatter),
+		newMigrationNode("elasticsearch-d-abc-2", k8sClient, chatter),
+	}
+	defer delete(nodes, key)
+
+	er := &ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		ll:       log.NewLogger("storage-migration-testing"),
+	}
+	er.migrateStorageClasses()
+
+	exp := &api.ElasticsearchStorageMigrationStatus{
+		Phase:            api.StorageMigrationPending,
+		StorageClassName: "gp3",
+		Replacement:      "elasticsearch-d-abc-3",
+	}
+	if _, nodeStatus := getNodeStatus("elasticsearch-d-abc-1", &cluster.Status); !reflect.DeepEqual(nodeStatus.StorageMigration, exp) {
+		t.Errorf("Exp. the first node to be migrated with %+v but got %+v", exp, nodeStatus.StorageMigration)
+	}
+	if _, nodeStatus := getNodeStatus("elasticsearch-d-abc-2", &cluster.Status); nodeStatus.StorageMigration != nil {
+		t.Errorf("Exp. one node to be migrated at a time but got %+v", nodeStatus.StorageMigration)
+	}
+}
+
+func TestMigrateStorageClassesRemovesEvacuatedNode(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	cluster := newMigrationCluster(
+		api.ElasticsearchNodeStatus{
+			DeploymentName: "elasticsearch-d-abc-1",
+			StorageMigration: &api.ElasticsearchStorageMigrationStatus{
+				Phase:            api.StorageMigrationEvacuating,
+				StorageClassName: "gp3",
+				Replacement:      "elasticsearch-d-abc-3",
+			},
+		},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-2"},
+		api.ElasticsearchNodeStatus{DeploymentName: "elasticsearch-d-abc-3"},
+	)
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		cluster.DeepCopy(),
+		&apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-d-abc-1", Namespace: cluster.Namespace}},
+		newExpansionClaim("elasticsearch-elasticsearch-d-abc-1", "10Gi"),
+	).Build()
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings": {
+			{StatusCode: 200, Body: `{"persistent": {"cluster": {"routing": {"allocation": {"exclude": {"_name": "elasticsearch-d-abc-1"}}}}}}`},
+			{StatusCode: 200, Body: `{"persistent": {"cluster": {"routing": {"allocation": {"exclude": {"_name": "elasticsearch-d-abc-1"}}}}}}`},
+			{StatusCode: 200, Body: `{"acknowledged": true}`},
+		},
+		"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+			{StatusCode: 200, Body: `[{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-d-abc-3"}]`},
+		},
+		"_cluster/state/nodes": {
+			{StatusCode: 200, Body: `{"nodes": {"a": {"name": "elasticsearch-d-abc-2"}, "b": {"name": "elasticsearch-d-abc-3"}}}`},
+		},
+	})
+
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+	nodes[key] = []NodeTypeInterface{
+		newMigrationNode("elasticsearch-d-abc-1", k8sClient, chatter),
+		newMigrationNode("elasticsearch-d-abc-2", k8sClient, chatter),
+		newMigrationNode("elasticsearch-d-abc-3", k8sClient, chatter),
+	}
+	defer delete(nodes, key)
+
+	er := &ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		ll:       log.NewLogger("storage-migration-testing"),
+	}
+	er.migrateStorageClasses()
+
+	if index, _ := getNodeStatus("elasticsearch-d-abc-1", &cluster.Status); index != NotFoundIndex {
+		t.Errorf("Exp. the status of the migrated node to be removed")
+	}
+	if er.getNode("elasticsearch-d-abc-1") != nil {
+		t.Errorf("Exp. the migrated node to be removed from the nodes")
+	}
+
+	err := k8sClient.Get(context.TODO(), client.ObjectKey{Name: "elasticsearch-d-abc-1", Namespace: cluster.Namespace}, &apps.Deployment{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Exp. the deployment of the migrated node to be deleted but got %v", err)
+	}
+	err = k8sClient.Get(context.TODO(), client.ObjectKey{Name: "elasticsearch-elasticsearch-d-abc-1", Namespace: cluster.Namespace}, &v1.PersistentVolumeClaim{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Exp. the volume of the migrated node to be deleted but got %v", err)
+	}
+
+	_, _ = chatter.GetRequest("_cluster/settings")
+	_, _ = chatter.GetRequest("_cluster/settings")
+	req, found := chatter.GetRequest("_cluster/settings")
+	if !found || req.Method != http.MethodPut {
+		t.Fatal("Exp. the node to be included in shard allocation again")
+	}
+	if exp := `{"persistent":{"cluster.routing.allocation.exclude._name":null}}`; req.Body != exp {
+		t.Errorf("Exp. request body %s but got %s", exp, req.Body)
+	}
+}
@@ -0,0 +1,41 @@
+package elasticsearch
+
+import (
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+const (
+	tierAttribute        = "tier"
+	tierAttributeSetting = "node.attr." + tierAttribute
+)
+
+// getHotDataCount returns the number of data nodes of the hot tier
+func getHotDataCount(dpl *api.Elasticsearch) int32 {
+	hotCount := int32(0)
+	for _, node := range dpl.Spec.Nodes {
+		if isDataNode(node) && node.Tier == api.ElasticsearchTierHot {
+			hotCount += node.NodeCount
+		}
+	}
+	return hotCount
+}
+
+// getIndexingDataCount returns the number of data nodes new indices are allocated to. These
+// are the nodes of the hot tier if the cluster has one and all data nodes otherwise.
+func getIndexingDataCount(dpl *api.Elasticsearch) int32 {
+	if hotCount := getHotDataCount(dpl); hotCount > 0 {
+		return hotCount
+	}
+	return GetDataCount(dpl)
+}
+
+// IndexRoutingRequire returns the node attributes new indices are required to be allocated
+// to. New indices are pinned to the hot tier if the cluster has one.
+func IndexRoutingRequire(dpl *api.Elasticsearch) map[string]string {
+	if getHotDataCount(dpl) == 0 {
+		return nil
+	}
+	return map[string]string{
+		tierAttribute: string(api.ElasticsearchTierHot),
+	}
+}
@@ -104,6 +104,43 @@ func GetDataCount(dpl *api.Elasticsearch) int32 {
 	return dataCount
 }
 
+func hasIngestNodes(dpl *api.Elasticsearch) bool {
+	for _, node := range dpl.Spec.Nodes {
+		if isIngestNode(node) && node.NodeCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isIngestingNode returns true if the node runs ingest pipelines. Without dedicated ingest
+// nodes all nodes but the coordinating-only ones keep running them as by default.
+func isIngestingNode(cluster *api.Elasticsearch, node api.ElasticsearchNode) bool {
+	if isCoordinatingNode(node) {
+		return false
+	}
+	return isIngestNode(node) || !hasIngestNodes(cluster)
+}
+
+func hasCoordinatingNodes(dpl *api.Elasticsearch) bool {
+	for _, node := range dpl.Spec.Nodes {
+		if isCoordinatingNode(node) && node.NodeCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidRoles returns false if the coordinating role is combined with other roles
+func isValidRoles(dpl *api.Elasticsearch) bool {
+	for _, node := range dpl.Spec.Nodes {
+		if isCoordinatingNode(node) && len(node.Roles) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
 func isValidMasterCount(dpl *api.Elasticsearch) bool {
 	if len(dpl.Spec.Nodes) == 0 {
 		return true
@@ -123,7 +160,7 @@ func isValidDataCount(dpl *api.Elasticsearch) bool {
 }
 
 func isValidRedundancyPolicy(dpl *api.Elasticsearch) bool {
-	dataCount := int(GetDataCount(dpl))
+	dataCount := int(getIndexingDataCount(dpl))
 
 	switch dpl.Spec.RedundancyPolicy {
 	case api.ZeroRedundancy:
@@ -149,6 +186,13 @@ func (er *ElasticsearchRequest) isValidScaleDownRate() (bool, error) {
 
 	currentDataCount := int32(len(dataNodes[api.PodStateTypeReady]) + len(dataNodes[api.PodStateTypeFailed]) + len(dataNodes[api.PodStateTypeNotReady]))
 
+	// nodes migrating to a new storage class are only removed once they were replaced
+	for _, node := range er.cluster.Status.Nodes {
+		if node.StorageMigration != nil {
+			currentDataCount--
+		}
+	}
+
 	if currentDataCount <= 0 {
 		return true, nil
 	}
@@ -203,6 +247,17 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		}
 	}
 
+	if !isValidRoles(dpl) {
+		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidRolesCondition, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set roles status")
+		}
+		return kverrors.New("coordinating role combined with other roles. Please ensure coordinating nodes have no other roles")
+	} else {
+		if err := updateConditionWithRetry(dpl, v1.ConditionFalse, updateInvalidRolesCondition, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set roles status")
+		}
+	}
+
 	if !isValidRedundancyPolicy(dpl) {
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidReplicationCondition, er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set replication status")
@@ -246,16 +301,10 @@ func (er *ElasticsearchRequest) isValidConf() error {
 	return nil
 }
 
+// validateUUIDs returns an error if the uuid of a deployment or statefulset in the status
+// is no longer found in the spec. The admission webhook rejects such changes, this covers
+// the changes applied while it was not available.
 func validateUUIDs(dpl *api.Elasticsearch) error {
-	// TODO:
-	// check that someone didn't update a uuid
-	// check status.nodes[*].deploymentName for list of used uuids
-	// deploymentName should match pattern {cluster.Name}-{uuid}[-replica]
-	// if any in that list aren't found in spec.Nodes[*].GenUUID then someone did something bad...
-	// somehow rollback the cluster object change and update message?
-	// no way to rollback, but maybe maintain a last known "good state" and update SPEC to that?
-	// update status message to be very descriptive of this
-
 	prefix := fmt.Sprintf("%s-", dpl.Name)
 
 	var knownUUIDs []string
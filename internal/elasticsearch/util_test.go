@@ -924,3 +924,44 @@ func TestFullRedundancyScaleDownInvalid(t *testing.T) {
 		t.Errorf("Expected to be invalid scale down case")
 	}
 }
+
+func TestInvalidCoordinatingRoles(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			Nodes: []api.ElasticsearchNode{
+				{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating, api.ElasticsearchRoleData}, NodeCount: 1},
+			},
+		},
+	}
+	if isValidRoles(cluster) {
+		t.Error("Exp. the coordinating role combined with the data role to be invalid")
+	}
+
+	cluster.Spec.Nodes[0].Roles = []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}
+	if !isValidRoles(cluster) {
+		t.Error("Exp. a coordinating-only node to be valid")
+	}
+}
+
+func TestNodeSuffixOfRoles(t *testing.T) {
+	tests := []struct {
+		roles []api.ElasticsearchNodeRole
+		exp   string
+	}{
+		{roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster}, exp: "cdm-abc"},
+		{roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData, api.ElasticsearchRoleIngest}, exp: "di-abc"},
+		{roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleIngest}, exp: "i-abc"},
+		{roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}, exp: "co-abc"},
+	}
+	for _, test := range tests {
+		roleMap := getNodeRoleMap(api.ElasticsearchNode{Roles: test.roles})
+		if suffix := getNodeSuffix("abc", roleMap); suffix != test.exp {
+			t.Errorf("Exp. suffix %s for roles %v but got %s", test.exp, test.roles, suffix)
+		}
+	}
+
+	roleMap := getNodeRoleMap(api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}})
+	if !roleMap[api.ElasticsearchRoleClient] || roleMap[api.ElasticsearchRoleData] || roleMap[api.ElasticsearchRoleMaster] || roleMap[api.ElasticsearchRoleIngest] {
+		t.Errorf("Exp. a coordinating-only node to serve the REST API only but got %v", roleMap)
+	}
+}
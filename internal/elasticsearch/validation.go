@@ -0,0 +1,234 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"github.com/openshift/elasticsearch-operator/internal/utils/cron"
+)
+
+// SetDefaults sets the defaults of the fields of the spec left empty
+func SetDefaults(cluster *api.Elasticsearch) {
+	if cluster.Spec.ManagementState == "" {
+		cluster.Spec.ManagementState = api.ManagementStateManaged
+	}
+	if cluster.Spec.RedundancyPolicy == "" {
+		cluster.Spec.RedundancyPolicy = api.SingleRedundancy
+		if getIndexingDataCount(cluster) <= 1 {
+			cluster.Spec.RedundancyPolicy = api.ZeroRedundancy
+		}
+	}
+	if cluster.Spec.Flavor == "" {
+		cluster.Spec.Flavor = RunningFlavor(cluster)
+	}
+}
+
+// ValidateSpec returns the errors of a spec the cluster cannot be reconciled to. These are
+// the checks of isValidConf which do not depend on the state of the cluster.
+func ValidateSpec(cluster *api.Elasticsearch) field.ErrorList {
+	errs := field.ErrorList{}
+	nodesPath := field.NewPath("spec", "nodes")
+
+	if !isValidMasterCount(cluster) {
+		errs = append(errs, field.Invalid(nodesPath, getMasterCount(cluster),
+			fmt.Sprintf("the total nodes with master roles must be between 1 and %d", maxMasterCount)))
+	}
+	if !isValidDataCount(cluster) {
+		errs = append(errs, field.Invalid(nodesPath, GetDataCount(cluster),
+			"at least 1 node with data roles is required"))
+	}
+	for i, node := range cluster.Spec.Nodes {
+		if isCoordinatingNode(node) && len(node.Roles) > 1 {
+			errs = append(errs, field.Invalid(nodesPath.Index(i).Child("roles"), node.Roles,
+				"the coordinating role cannot be combined with other roles"))
+		}
+		if node.Storage.AllowStorageClassMigration && !allowsStorageClassMigration(node) {
+			errs = append(errs, field.Forbidden(nodesPath.Index(i).Child("storage", "allowStorageClassMigration"),
+				"storage class migration is only supported by data nodes without the master role"))
+		}
+	}
+	if !isValidRedundancyPolicy(cluster) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "redundancyPolicy"), cluster.Spec.RedundancyPolicy,
+			"the redundancy policy requires more nodes with data roles"))
+	}
+	if cluster.Spec.Maintenance != nil {
+		errs = append(errs, validateMaintenanceWindows(field.NewPath("spec", "maintenance", "windows"), cluster.Spec.Maintenance.Windows)...)
+	}
+	if cluster.Spec.Certificates != nil {
+		errs = append(errs, validateCertificates(field.NewPath("spec", "certificates"), cluster.Spec.Certificates)...)
+	}
+	if cluster.Spec.Authentication != nil {
+		errs = append(errs, validateAuthentication(field.NewPath("spec", "authentication"), cluster.Spec.Authentication)...)
+	}
+	return errs
+}
+
+// validateMaintenanceWindows returns the errors of the maintenance windows which never open
+func validateMaintenanceWindows(path *field.Path, windows []api.MaintenanceWindowSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, window := range windows {
+		schedule, err := cron.Parse(window.Schedule)
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("schedule"), window.Schedule,
+				"the schedule requires the cron fields minute, hour, day of month, month and day of week"))
+		} else if schedule.Next(time.Now()).IsZero() {
+			errs = append(errs, field.Invalid(path.Index(i).Child("schedule"), window.Schedule,
+				"the schedule is never active"))
+		}
+		if window.Duration.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Index(i).Child("duration"), window.Duration.String(),
+				"the duration must be positive"))
+		}
+	}
+	return errs
+}
+
+// ValidateSpecUpdate returns the errors of the changes to the spec which are not supported
+func ValidateSpecUpdate(old, cluster *api.Elasticsearch) field.ErrorList {
+	errs := field.ErrorList{}
+	nodesPath := field.NewPath("spec", "nodes")
+
+	if err := validateUUIDs(cluster); err != nil {
+		errs = append(errs, field.Forbidden(nodesPath, "the genUUID of an existing node cannot be changed or removed"))
+	}
+
+	// the replicas of the indices are lost if more data nodes are removed at once
+	removed := GetDataCount(old) - GetDataCount(cluster)
+	if replicas := int32(CalculateReplicaCount(old)); removed > 0 && removed > replicas {
+		errs = append(errs, field.Forbidden(nodesPath,
+			fmt.Sprintf("removing %d data nodes at once loses data, the redundancy policy allows removing %d", removed, replicas)))
+	}
+
+	running, requested := RunningFlavor(old), Flavor(cluster)
+	if !isSupportedFlavorUpgrade(running, requested) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "flavor"),
+			fmt.Sprintf("a cluster running %s cannot be changed to %s", running, requested)))
+	}
+
+	oldNodes := map[string]api.ElasticsearchNode{}
+	for _, node := range old.Spec.Nodes {
+		if node.GenUUID != nil {
+			oldNodes[*node.GenUUID] = node
+		}
+	}
+	for i, node := range cluster.Spec.Nodes {
+		if node.GenUUID == nil {
+			continue
+		}
+		if oldNode, ok := oldNodes[*node.GenUUID]; ok {
+			errs = append(errs, validateStorageUpdate(nodesPath.Index(i).Child("storage"), oldNode.Storage, node)...)
+		}
+	}
+	return errs
+}
+
+// validateStorageUpdate returns the errors of the changes to the storage of an existing node.
+// Clearing the storage class name keeps the current storage class, changing it migrates the
+// nodes allowing it and growing the storage expands the volumes of the node.
+func validateStorageUpdate(path *field.Path, old api.ElasticsearchStorageSpec, node api.ElasticsearchNode) field.ErrorList {
+	errs := field.ErrorList{}
+	storage := node.Storage
+
+	if isEphemeralStorage(old) != isEphemeralStorage(storage) {
+		return append(errs, field.Forbidden(path, "changing the storage structure of a node is not supported"))
+	}
+	if isEphemeralStorage(storage) {
+		return errs
+	}
+
+	if storage.StorageClassName != nil && !allowsStorageClassMigration(node) && !reflect.DeepEqual(old.StorageClassName, storage.StorageClassName) {
+		errs = append(errs, field.Forbidden(path.Child("storageClassName"), "changing the storage class name of a node requires allowStorageClassMigration on a data node without the master role"))
+	}
+	if storage.Size.Cmp(*old.Size) < 0 {
+		errs = append(errs, field.Forbidden(path.Child("size"), "shrinking the storage of a node is not supported"))
+	}
+	return errs
+}
+
+// isEphemeralStorage returns true if the node stores its data in an emptyDir
+func isEphemeralStorage(storage api.ElasticsearchStorageSpec) bool {
+	return storage.Size == nil
+}
+
+// validateCertificates returns the errors of the certificate settings which cannot be issued
+// or would be rotated right away
+func validateCertificates(path *field.Path, spec *api.ElasticsearchCertificatesSpec) field.ErrorList {
+	errs := field.ErrorList{}
+
+	algorithm, _ := certificateKeyType(spec)
+	sizes := validRSAKeySizes
+	if algorithm == api.CertificateKeyECDSA {
+		sizes = validECDSAKeySizes
+	}
+	if spec.KeySize != 0 && !utils.Contains(sizes, strconv.Itoa(int(spec.KeySize))) {
+		errs = append(errs, field.NotSupported(path.Child("keySize"), spec.KeySize, sizes))
+	}
+
+	caLifetime, lifetime, lead := certificateCALifetime(spec), certificateLifetime(spec), certificateRotationLead(spec)
+	if spec.RotationLead != nil && lead <= 0 {
+		errs = append(errs, field.Invalid(path.Child("rotationLead"), lead.String(), "the rotation lead time must be positive"))
+	}
+	if lifetime > caLifetime {
+		errs = append(errs, field.Invalid(path.Child("lifetime"), lifetime.String(), "the certificates cannot outlive the CA"))
+	}
+	if lead >= lifetime {
+		errs = append(errs, field.Invalid(path.Child("rotationLead"), lead.String(), "the rotation lead time must be shorter than the certificate lifetime"))
+	}
+	if caRotationLead := lead + caRotationRolloutPeriod; caRotationLead >= caLifetime {
+		errs = append(errs, field.Invalid(path.Child("caLifetime"), caLifetime.String(),
+			fmt.Sprintf("the CA lifetime must be longer than the %s the rotation of the CA starts before its expiry", caRotationLead)))
+	}
+	return errs
+}
+
+// validateAuthentication returns the errors of the realms which cannot be configured in the
+// security plugin and of a Kibana login without its realm
+func validateAuthentication(path *field.Path, spec *api.ElasticsearchAuthenticationSpec) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if spec.LDAP != nil {
+		ldapPath := path.Child("ldap")
+		for i, host := range spec.LDAP.Hosts {
+			if _, _, err := net.SplitHostPort(host); err != nil {
+				errs = append(errs, field.Invalid(ldapPath.Child("hosts").Index(i), host, "the host must be given as host:port"))
+			}
+		}
+		if spec.LDAP.BindDN != "" && (spec.LDAP.BindSecret == nil || spec.LDAP.BindSecret.Name == "") {
+			errs = append(errs, field.Required(ldapPath.Child("bindSecret"), "the password of the bind DN is required"))
+		}
+	}
+	if spec.OIDC != nil {
+		if u, err := url.Parse(spec.OIDC.ConnectURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("oidc", "connectURL"), spec.OIDC.ConnectURL, "the connect URL must be an https URL"))
+		}
+	}
+	if spec.InternalUsers != nil && spec.InternalUsers.Secret.Name == "" {
+		errs = append(errs, field.Required(path.Child("internalUsers", "secret", "name"), "the secret of the internal users is required"))
+	}
+
+	loginPath := path.Child("kibanaLogin")
+	switch spec.KibanaLogin {
+	case api.KibanaLoginBasic:
+		if spec.LDAP == nil && spec.InternalUsers == nil {
+			errs = append(errs, field.Invalid(loginPath, spec.KibanaLogin, "the basic login requires the ldap or internalUsers realm"))
+		}
+	case api.KibanaLoginOpenID:
+		switch {
+		case spec.OIDC == nil:
+			errs = append(errs, field.Invalid(loginPath, spec.KibanaLogin, "the OpenID login requires the oidc realm"))
+		case spec.OIDC.ClientID == "":
+			errs = append(errs, field.Required(path.Child("oidc", "clientID"), "the client ID of Kibana is required for the OpenID login"))
+		case spec.OIDC.ClientSecret == nil || spec.OIDC.ClientSecret.Name == "":
+			errs = append(errs, field.Required(path.Child("oidc", "clientSecret"), "the client secret of Kibana is required for the OpenID login"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,279 @@
+package elasticsearch
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+func newValidationCluster() *api.Elasticsearch {
+	size := resource.MustParse("10Gi")
+	return &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch"},
+		Spec: api.ElasticsearchSpec{
+			RedundancyPolicy: api.SingleRedundancy,
+			Nodes: []api.ElasticsearchNode{
+				{
+					Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+					NodeCount: 3,
+					GenUUID:   pointer.String("abc"),
+					Storage: api.ElasticsearchStorageSpec{
+						StorageClassName: pointer.String("gp2"),
+						Size:             &size,
+					},
+				},
+			},
+		},
+		Status: api.ElasticsearchStatus{
+			Nodes: []api.ElasticsearchNodeStatus{
+				{DeploymentName: "elasticsearch-cdm-abc-1"},
+				{DeploymentName: "elasticsearch-cdm-abc-2"},
+				{DeploymentName: "elasticsearch-cdm-abc-3"},
+			},
+		},
+	}
+}
+
+func TestDefault(t *testing.T) {
+	cluster := newValidationCluster()
+	cluster.Spec.RedundancyPolicy = ""
+	SetDefaults(cluster)
+	if cluster.Spec.ManagementState != api.ManagementStateManaged || cluster.Spec.RedundancyPolicy != api.SingleRedundancy || cluster.Spec.Flavor != api.ElasticsearchFlavor6 {
+		t.Errorf("Exp. a managed Elasticsearch 6 cluster with single redundancy but got %+v", cluster.Spec)
+	}
+
+	cluster.Spec.RedundancyPolicy = ""
+	cluster.Spec.Nodes[0].NodeCount = 1
+	SetDefaults(cluster)
+	if cluster.Spec.RedundancyPolicy != api.ZeroRedundancy {
+		t.Errorf("Exp. zero redundancy for a single data node but got %s", cluster.Spec.RedundancyPolicy)
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	if errs := ValidateSpec(newValidationCluster()); len(errs) != 0 {
+		t.Errorf("Exp. a valid spec but got %v", errs)
+	}
+
+	tests := []struct {
+		desc   string
+		change func(cluster *api.Elasticsearch)
+		field  string
+	}{
+		{
+			desc:   "too many masters",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Nodes[0].NodeCount = 4 },
+			field:  "spec.nodes",
+		},
+		{
+			desc: "no data nodes",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Nodes[0].Roles = []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}
+				cluster.Spec.RedundancyPolicy = api.ZeroRedundancy
+			},
+			field: "spec.nodes",
+		},
+		{
+			desc: "coordinating role combined with others",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Nodes = append(cluster.Spec.Nodes, api.ElasticsearchNode{
+					Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating, api.ElasticsearchRoleData},
+					NodeCount: 1,
+				})
+			},
+			field: "spec.nodes[1].roles",
+		},
+		{
+			desc:   "storage class migration of master nodes",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Nodes[0].Storage.AllowStorageClassMigration = true },
+			field:  "spec.nodes[0].storage.allowStorageClassMigration",
+		},
+		{
+			desc: "invalid maintenance schedule",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Maintenance = &api.ElasticsearchMaintenanceSpec{
+					Windows: []api.MaintenanceWindowSpec{{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: time.Hour}}},
+				}
+			},
+			field: "spec.maintenance.windows[0].schedule",
+		},
+		{
+			desc: "maintenance window without duration",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Maintenance = &api.ElasticsearchMaintenanceSpec{
+					Windows: []api.MaintenanceWindowSpec{{Schedule: "0 2 * * 6"}},
+				}
+			},
+			field: "spec.maintenance.windows[0].duration",
+		},
+		{
+			desc: "key size of another algorithm",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Certificates = &api.ElasticsearchCertificatesSpec{KeyAlgorithm: api.CertificateKeyECDSA, KeySize: 4096}
+			},
+			field: "spec.certificates.keySize",
+		},
+		{
+			desc: "certificates outliving the CA",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Certificates = &api.ElasticsearchCertificatesSpec{CALifetime: &metav1.Duration{Duration: 720 * time.Hour}}
+			},
+			field: "spec.certificates.lifetime",
+		},
+		{
+			desc: "CA lifetime shorter than its rotation lead time",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Certificates = &api.ElasticsearchCertificatesSpec{
+					CALifetime: &metav1.Duration{Duration: 48 * time.Hour},
+					Lifetime:   &metav1.Duration{Duration: 24 * time.Hour},
+				}
+			},
+			field: "spec.certificates.caLifetime",
+		},
+		{
+			desc: "rotation lead time longer than the certificate lifetime",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Certificates = &api.ElasticsearchCertificatesSpec{
+					Lifetime:     &metav1.Duration{Duration: 720 * time.Hour},
+					RotationLead: &metav1.Duration{Duration: 720 * time.Hour},
+				}
+			},
+			field: "spec.certificates.rotationLead",
+		},
+		{
+			desc: "LDAP host without port",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Authentication = &api.ElasticsearchAuthenticationSpec{
+					LDAP: &api.LDAPRealmSpec{Hosts: []string{"ldap.example.com"}, UserBase: "ou=people,dc=example,dc=com"},
+				}
+			},
+			field: "spec.authentication.ldap.hosts[0]",
+		},
+		{
+			desc: "OIDC connect URL without TLS",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Authentication = &api.ElasticsearchAuthenticationSpec{
+					OIDC: &api.OIDCRealmSpec{ConnectURL: "http://idp.example.com/.well-known/openid-configuration"},
+				}
+			},
+			field: "spec.authentication.oidc.connectURL",
+		},
+		{
+			desc: "OpenID login without the client of Kibana",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Authentication = &api.ElasticsearchAuthenticationSpec{
+					OIDC:        &api.OIDCRealmSpec{ConnectURL: "https://idp.example.com/.well-known/openid-configuration"},
+					KibanaLogin: api.KibanaLoginOpenID,
+				}
+			},
+			field: "spec.authentication.oidc.clientID",
+		},
+		{
+			desc: "basic login without a realm checking passwords",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Authentication = &api.ElasticsearchAuthenticationSpec{KibanaLogin: api.KibanaLoginBasic}
+			},
+			field: "spec.authentication.kibanaLogin",
+		},
+		{
+			desc:   "redundancy without enough data nodes",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Nodes[0].NodeCount = 1 },
+			field:  "spec.redundancyPolicy",
+		},
+	}
+	for _, test := range tests {
+		cluster := newValidationCluster()
+		test.change(cluster)
+		errs := ValidateSpec(cluster)
+		if len(errs) != 1 || errs[0].Field != test.field {
+			t.Errorf("%s: Exp. an error for %s but got %v", test.desc, test.field, errs)
+		}
+	}
+}
+
+func TestValidateSpecUpdate(t *testing.T) {
+	old := newValidationCluster()
+	if errs := ValidateSpecUpdate(old, newValidationCluster()); len(errs) != 0 {
+		t.Errorf("Exp. an unchanged spec to be valid but got %v", errs)
+	}
+
+	tests := []struct {
+		desc   string
+		change func(cluster *api.Elasticsearch)
+		field  string
+	}{
+		{
+			desc:   "changed uuid",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Nodes[0].GenUUID = pointer.String("xyz") },
+			field:  "spec.nodes",
+		},
+		{
+			desc:   "removing more data nodes than replicas",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Nodes[0].NodeCount = 1 },
+			field:  "spec.nodes",
+		},
+		{
+			desc:   "flavor downgrade",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Flavor = api.ElasticsearchFlavor6 },
+			field:  "spec.flavor",
+		},
+		{
+			desc: "storage class change",
+			change: func(cluster *api.Elasticsearch) {
+				cluster.Spec.Nodes[0].Storage.StorageClassName = pointer.String("gp3")
+			},
+			field: "spec.nodes[0].storage.storageClassName",
+		},
+		{
+			desc: "storage shrink",
+			change: func(cluster *api.Elasticsearch) {
+				size := resource.MustParse("5Gi")
+				cluster.Spec.Nodes[0].Storage.Size = &size
+			},
+			field: "spec.nodes[0].storage.size",
+		},
+		{
+			desc:   "ephemeral storage",
+			change: func(cluster *api.Elasticsearch) { cluster.Spec.Nodes[0].Storage = api.ElasticsearchStorageSpec{} },
+			field:  "spec.nodes[0].storage",
+		},
+	}
+	for _, test := range tests {
+		old := newValidationCluster()
+		old.Status.Flavor = api.ElasticsearchFlavor7
+		cluster := newValidationCluster()
+		cluster.Status.Flavor = api.ElasticsearchFlavor7
+		cluster.Spec.Flavor = api.ElasticsearchFlavor7
+		test.change(cluster)
+
+		errs := ValidateSpecUpdate(old, cluster)
+		if len(errs) != 1 || errs[0].Field != test.field {
+			t.Errorf("%s: Exp. an error for %s but got %v", test.desc, test.field, errs)
+		}
+	}
+
+	cluster := newValidationCluster()
+	cluster.Spec.Nodes[0].Storage.StorageClassName = nil
+	if errs := ValidateSpecUpdate(old, cluster); len(errs) != 0 {
+		t.Errorf("Exp. clearing the storage class name to keep the current class but got %v", errs)
+	}
+
+	size := resource.MustParse("20Gi")
+	cluster.Spec.Nodes[0].Storage.Size = &size
+	if errs := ValidateSpecUpdate(old, cluster); len(errs) != 0 {
+		t.Errorf("Exp. growing the storage to be valid but got %v", errs)
+	}
+
+	cluster = newValidationCluster()
+	cluster.Spec.Nodes[0].Roles = []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}
+	cluster.Spec.Nodes[0].Storage.StorageClassName = pointer.String("gp3")
+	cluster.Spec.Nodes[0].Storage.AllowStorageClassMigration = true
+	if errs := ValidateSpecUpdate(old, cluster); len(errs) != 0 {
+		t.Errorf("Exp. changing the storage class of data nodes allowing migration to be valid but got %v", errs)
+	}
+}
@@ -0,0 +1,224 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/persistentvolume"
+)
+
+// fileSystemResizeTimeout is how long the file system of an expanded volume is given to be
+// resized while the node is running. Volumes which cannot be resized online require the
+// node to be restarted.
+var fileSystemResizeTimeout = 5 * time.Minute
+
+// nodeVolume is the persistent volume claim of a node and the size requested by its spec
+type nodeVolume struct {
+	node  NodeTypeInterface
+	spec  api.ElasticsearchNode
+	claim *v1.PersistentVolumeClaim
+	size  resource.Quantity
+}
+
+// expandVolumes expands the persistent volumes of the nodes to the size of their storage
+// spec, one node at a time. The claim of the next node is only resized once the volume and
+// the file system of the previous one were. Shrinking a volume is not supported.
+func (er *ElasticsearchRequest) expandVolumes() {
+	volumes, err := er.getNodeVolumes()
+	if err != nil {
+		er.ll.Error(err, "failed to get the volumes of the nodes")
+		return
+	}
+
+	var resizing *nodeVolume
+	pending := []nodeVolume{}
+	expansions := map[string]*api.ElasticsearchVolumeExpansionStatus{}
+	for i, volume := range volumes {
+		switch {
+		case volume.claim.Status.Phase != v1.ClaimBound:
+			continue
+		case isVolumeResizing(volume.claim):
+			resizing = &volumes[i]
+			expansions[volume.node.name()] = newVolumeExpansionStatus(volume, er.getVolumeExpansionStatus(volume.node.name()))
+		case volume.claim.Spec.Resources.Requests.Storage().Cmp(volume.size) < 0:
+			pending = append(pending, volume)
+		default:
+			expansions[volume.node.name()] = nil
+		}
+	}
+
+	for i := range pending {
+		volume := pending[i]
+		expansion := &api.ElasticsearchVolumeExpansionStatus{
+			Phase:    api.VolumeExpansionPending,
+			Size:     volume.size,
+			Capacity: volume.claim.Status.Capacity.Storage(),
+		}
+		expansions[volume.node.name()] = expansion
+
+		allowed, err := er.allowsVolumeExpansion(volume.claim)
+		if err != nil {
+			er.ll.Error(err, "failed to get the storage class of the volume", "claim", volume.claim.Name)
+			continue
+		}
+		if !allowed {
+			expansion.Phase = api.VolumeExpansionUnsupported
+			expansion.Message = "The storage class of the volume does not allow volume expansion"
+			continue
+		}
+		if resizing != nil {
+			continue
+		}
+
+		er.ll.Info("Expanding the volume of the node", "node", volume.node.name(), "size", volume.size.String())
+		if err := er.resizeClaim(volume.claim, volume.size); err != nil {
+			er.ll.Error(err, "failed to expand the volume of the node", "node", volume.node.name())
+			continue
+		}
+		expansion.Phase = api.VolumeExpansionResizing
+		resizing = &pending[i]
+	}
+
+	// the node is restarted once per expansion, the file system is left to its provisioner
+	// when it is still not resized after the restart
+	if resizing != nil && requiresRestartForFileSystemResize(resizing.claim) {
+		expansion := expansions[resizing.node.name()]
+		if expansion.RestartTime == nil {
+			er.ll.Info("Restarting the node to resize the file system of its volume", "node", resizing.node.name())
+			if err := er.PerformNodeRestart(resizing.node); err != nil {
+				er.ll.Error(err, "unable to restart node", "node", resizing.node.name())
+			} else {
+				now := metav1.Now()
+				expansion.RestartTime = &now
+			}
+		}
+	}
+
+	if err := er.updateVolumeExpansionStatus(expansions); err != nil {
+		er.ll.Error(err, "failed to update the volume expansion status")
+	}
+}
+
+// getNodeVolumes returns the persistent volume claims of the nodes using persistent storage
+func (er *ElasticsearchRequest) getNodeVolumes() ([]nodeVolume, error) {
+	volumes := []nodeVolume{}
+	for _, node := range nodes[nodeMapKey(er.cluster.Name, er.cluster.Namespace)] {
+		for _, specNode := range er.cluster.Spec.Nodes {
+			if specNode.GenUUID == nil || !strings.Contains(node.name(), *specNode.GenUUID) || specNode.Storage.Size == nil {
+				continue
+			}
+
+			claim := &v1.PersistentVolumeClaim{}
+			key := types.NamespacedName{Name: fmt.Sprintf("%s-%s", er.cluster.Name, node.name()), Namespace: er.cluster.Namespace}
+			if err := er.client.Get(context.TODO(), key, claim); err != nil {
+				if apierrors.IsNotFound(err) {
+					break
+				}
+				return nil, kverrors.Wrap(err, "failed to get PVC", "claim", key.Name)
+			}
+			volumes = append(volumes, nodeVolume{node: node, spec: specNode, claim: claim, size: *specNode.Storage.Size})
+			break
+		}
+	}
+	return volumes, nil
+}
+
+// allowsVolumeExpansion returns true if the storage class of the claim allows to expand it
+func (er *ElasticsearchRequest) allowsVolumeExpansion(claim *v1.PersistentVolumeClaim) (bool, error) {
+	if claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName == "" {
+		return false, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: *claim.Spec.StorageClassName}, storageClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, kverrors.Wrap(err, "failed to get storage class", "storageClass", *claim.Spec.StorageClassName)
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+func (er *ElasticsearchRequest) resizeClaim(claim *v1.PersistentVolumeClaim, size resource.Quantity) error {
+	desired := claim.DeepCopy()
+	desired.Spec.Resources.Requests[v1.ResourceStorage] = size
+	return persistentvolume.CreateOrUpdatePVC(context.TODO(), er.client, desired, persistentvolume.StorageRequestEqual, persistentvolume.MutateStorageRequest)
+}
+
+// updateVolumeExpansionStatus sets the volume expansion status of the nodes. A nil status
+// removes the one of a node.
+func (er *ElasticsearchRequest) updateVolumeExpansionStatus(expansions map[string]*api.ElasticsearchVolumeExpansionStatus) error {
+	status := er.cluster.Status.DeepCopy()
+	for name, expansion := range expansions {
+		index, nodeStatus := getNodeStatus(name, status)
+		if index == NotFoundIndex {
+			continue
+		}
+		nodeStatus.VolumeExpansion = expansion
+		status.Nodes[index] = *nodeStatus
+	}
+	return er.updateNodeStatus(*status)
+}
+
+// getVolumeExpansionStatus returns the volume expansion status of the node, or nil
+func (er *ElasticsearchRequest) getVolumeExpansionStatus(name string) *api.ElasticsearchVolumeExpansionStatus {
+	if index, nodeStatus := getNodeStatus(name, &er.cluster.Status); index != NotFoundIndex {
+		return nodeStatus.VolumeExpansion
+	}
+	return nil
+}
+
+// newVolumeExpansionStatus returns the status of the volume being resized. The restart of
+// the node is kept from the previous status of the same expansion.
+func newVolumeExpansionStatus(volume nodeVolume, previous *api.ElasticsearchVolumeExpansionStatus) *api.ElasticsearchVolumeExpansionStatus {
+	expansion := &api.ElasticsearchVolumeExpansionStatus{
+		Phase:    api.VolumeExpansionResizing,
+		Size:     *volume.claim.Spec.Resources.Requests.Storage(),
+		Capacity: volume.claim.Status.Capacity.Storage(),
+	}
+	if previous != nil && previous.Size.Cmp(expansion.Size) == 0 {
+		expansion.RestartTime = previous.RestartTime
+	}
+	if condition := getClaimCondition(volume.claim, v1.PersistentVolumeClaimFileSystemResizePending); condition != nil {
+		expansion.Phase = api.VolumeExpansionFileSystemResizePending
+		expansion.Message = condition.Message
+	}
+	return expansion
+}
+
+// isVolumeResizing returns true until both the volume and its file system provide the
+// requested size
+func isVolumeResizing(claim *v1.PersistentVolumeClaim) bool {
+	if claim.Status.Capacity.Storage().Cmp(*claim.Spec.Resources.Requests.Storage()) < 0 {
+		return true
+	}
+	return getClaimCondition(claim, v1.PersistentVolumeClaimResizing) != nil ||
+		getClaimCondition(claim, v1.PersistentVolumeClaimFileSystemResizePending) != nil
+}
+
+// requiresRestartForFileSystemResize returns true if the file system of the volume was not
+// resized while the node is running
+func requiresRestartForFileSystemResize(claim *v1.PersistentVolumeClaim) bool {
+	condition := getClaimCondition(claim, v1.PersistentVolumeClaimFileSystemResizePending)
+	return condition != nil && time.Since(condition.LastTransitionTime.Time) > fileSystemResizeTimeout
+}
+
+func getClaimCondition(claim *v1.PersistentVolumeClaim, conditionType v1.PersistentVolumeClaimConditionType) *v1.PersistentVolumeClaimCondition {
+	for i, condition := range claim.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+			return &claim.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package elasticsearch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/log"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+func newExpansionClaim(name, size string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-logging"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: pointer.String("gp2"),
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+		Status: v1.PersistentVolumeClaimStatus{
+			Phase:    v1.ClaimBound,
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+}
+
+func TestExpandVolumes(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		desc           string
+		allowExpansion bool
+		expSizes       []string
+		expPhases      []api.ElasticsearchVolumeExpansionPhase
+	}{
+		{
+			desc:           "one node at a time",
+			allowExpansion: true,
+			expSizes:       []string{"20Gi", "10Gi"},
+			expPhases:      []api.ElasticsearchVolumeExpansionPhase{api.VolumeExpansionResizing, api.VolumeExpansionPending},
+		},
+		{
+			desc:      "storage class without volume expansion",
+			expSizes:  []string{"10Gi", "10Gi"},
+			expPhases: []api.ElasticsearchVolumeExpansionPhase{api.VolumeExpansionUnsupported, api.VolumeExpansionUnsupported},
+		},
+	}
+	for _, test := range tests {
+		size := resource.MustParse("20Gi")
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+						NodeCount: 2,
+						GenUUID:   pointer.String("abc"),
+						Storage:   api.ElasticsearchStorageSpec{StorageClassName: pointer.String("gp2"), Size: &size},
+					},
+				},
+			},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{DeploymentName: "elasticsearch-cd-abc-1"},
+					{DeploymentName: "elasticsearch-cd-abc-2"},
+				},
+			},
+		}
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "gp2"},
+			AllowVolumeExpansion: pointer.Bool(test.allowExpansion),
+		}
+		k8sClient := fake.NewClientBuilder().WithObjects(
+			cluster.DeepCopy(),
+			storageClass,
+			newExpansionClaim("elasticsearch-elasticsearch-cd-abc-1", "10Gi"),
+			newExpansionClaim("elasticsearch-elasticsearch-cd-abc-2", "10Gi"),
+		).Build()
+
+		key := nodeMapKey(cluster.Name, cluster.Namespace)
+		nodes[key] = []NodeTypeInterface{
+			&deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc-1"}}},
+			&deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc-2"}}},
+		}
+
+		er := &ElasticsearchRequest{client: k8sClient, cluster: cluster, ll: log.NewLogger("volume-expansion-testing")}
+		er.expandVolumes()
+		delete(nodes, key)
+
+		for i, name := range []string{"elasticsearch-cd-abc-1", "elasticsearch-cd-abc-2"} {
+			claim := &v1.PersistentVolumeClaim{}
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-" + name, Namespace: cluster.Namespace}, claim); err != nil {
+				t.Fatalf("%s: failed to get claim: %v", test.desc, err)
+			}
+			if got := claim.Spec.Resources.Requests.Storage(); got.Cmp(resource.MustParse(test.expSizes[i])) != 0 {
+				t.Errorf("%s: Exp. the claim of %s to request %s but got %s", test.desc, name, test.expSizes[i], got.String())
+			}
+
+			_, nodeStatus := getNodeStatus(name, &cluster.Status)
+			if nodeStatus.VolumeExpansion == nil || nodeStatus.VolumeExpansion.Phase != test.expPhases[i] {
+				t.Errorf("%s: Exp. the volume expansion of %s to be %s but got %+v", test.desc, name, test.expPhases[i], nodeStatus.VolumeExpansion)
+			}
+		}
+	}
+}
+
+func TestRequiresRestartForFileSystemResize(t *testing.T) {
+	claim := newExpansionClaim("elasticsearch-elasticsearch-cd-abc-1", "20Gi")
+	if requiresRestartForFileSystemResize(claim) || isVolumeResizing(claim) {
+		t.Errorf("Exp. an expanded volume to require no restart")
+	}
+
+	claim.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+		{
+			Type:               v1.PersistentVolumeClaimFileSystemResizePending,
+			Status:             v1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now()),
+		},
+	}
+	if requiresRestartForFileSystemResize(claim) {
+		t.Errorf("Exp. the file system to be given time to be resized online")
+	}
+	if !isVolumeResizing(claim) {
+		t.Errorf("Exp. the volume to be resizing until its file system was resized")
+	}
+
+	claim.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * fileSystemResizeTimeout))
+	if !requiresRestartForFileSystemResize(claim) {
+		t.Errorf("Exp. the node to be restarted once the file system was not resized online")
+	}
+}
+
+func TestExpandVolumesRestartsNodeOncePerExpansion(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	size := resource.MustParse("20Gi")
+	restarted := metav1.NewTime(time.Now().Add(-fileSystemResizeTimeout).Truncate(time.Second))
+	cluster := &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		Spec: api.ElasticsearchSpec{
+			Nodes: []api.ElasticsearchNode{
+				{
+					Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+					NodeCount: 1,
+					GenUUID:   pointer.String("abc"),
+					Storage:   api.ElasticsearchStorageSpec{StorageClassName: pointer.String("gp2"), Size: &size},
+				},
+			},
+		},
+		Status: api.ElasticsearchStatus{
+			Nodes: []api.ElasticsearchNodeStatus{
+				{
+					DeploymentName: "elasticsearch-cd-abc-1",
+					VolumeExpansion: &api.ElasticsearchVolumeExpansionStatus{
+						Phase:       api.VolumeExpansionFileSystemResizePending,
+						Size:        size,
+						RestartTime: &restarted,
+					},
+				},
+			},
+		},
+	}
+	claim := newExpansionClaim("elasticsearch-elasticsearch-cd-abc-1", "20Gi")
+	claim.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+		{
+			Type:               v1.PersistentVolumeClaimFileSystemResizePending,
+			Status:             v1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * fileSystemResizeTimeout)),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(cluster.DeepCopy(), claim).Build()
+
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+	nodes[key] = []NodeTypeInterface{
+		&deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc-1"}}},
+	}
+	defer delete(nodes, key)
+
+	// the node was restarted for this expansion, restarting it again would require a client
+	er := &ElasticsearchRequest{client: k8sClient, cluster: cluster, ll: log.NewLogger("volume-expansion-testing")}
+	er.expandVolumes()
+
+	_, nodeStatus := getNodeStatus("elasticsearch-cd-abc-1", &cluster.Status)
+	expansion := nodeStatus.VolumeExpansion
+	if expansion == nil || expansion.Phase != api.VolumeExpansionFileSystemResizePending {
+		t.Fatalf("Exp. the volume expansion to be %s but got %+v", api.VolumeExpansionFileSystemResizePending, expansion)
+	}
+	if expansion.RestartTime == nil || !expansion.RestartTime.Equal(&restarted) {
+		t.Errorf("Exp. the restart time %v to be kept but got %v", restarted, expansion.RestartTime)
+	}
+}
@@ -0,0 +1,290 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+const (
+	zoneAttribute          = "zone"
+	zoneAttributeSetting   = "node.attr." + zoneAttribute
+	zoneEnvVar             = "ZONE"
+	zoneVolumeName         = "elasticsearch-zone"
+	zoneMountPath          = "/etc/elasticsearch/zone"
+	defaultZoneTopologyKey = "topology.kubernetes.io/zone"
+
+	// ZoneTopologyKeyAnnotation holds the label of the Kubernetes node with the zone of a pod
+	ZoneTopologyKeyAnnotation = "logging.openshift.io/zone-topology-key"
+	// ZoneAnnotation holds the zone of a pod once it is scheduled. The downward API cannot
+	// read the labels of the Kubernetes node, so the zone is copied to the pod.
+	ZoneAnnotation = "logging.openshift.io/zone"
+
+	zoneMissingReason      = "MissingZoneAwareness"
+	zoneInsufficientReason = "InsufficientZones"
+)
+
+// zoneTopologyKey returns the label of the Kubernetes nodes holding the zone of a zone aware node
+func zoneTopologyKey(node api.ElasticsearchNode) string {
+	if node.ZoneAwareness == nil {
+		return ""
+	}
+	if node.ZoneAwareness.TopologyKey == "" {
+		return defaultZoneTopologyKey
+	}
+	return node.ZoneAwareness.TopologyKey
+}
+
+// addZoneAwareness spreads the pods of a zone aware node across the zones and passes the
+// zone of the pod to the elasticsearch container. An init container waits until the zone
+// is copied from the Kubernetes node to the pod.
+func addZoneAwareness(podTemplate *v1.PodTemplateSpec, clusterName string, node api.ElasticsearchNode, roleMap map[api.ElasticsearchNodeRole]bool) {
+	topologyKey := zoneTopologyKey(node)
+	if topologyKey == "" {
+		return
+	}
+
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = map[string]string{}
+	}
+	podTemplate.Annotations[ZoneTopologyKeyAnnotation] = topologyKey
+
+	podTemplate.Spec.TopologySpreadConstraints = append(podTemplate.Spec.TopologySpreadConstraints, v1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       topologyKey,
+		WhenUnsatisfiable: v1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"cluster-name":   clusterName,
+				"component":      "elasticsearch",
+				"es-node-client": strconv.FormatBool(roleMap[api.ElasticsearchRoleClient]),
+				"es-node-data":   strconv.FormatBool(roleMap[api.ElasticsearchRoleData]),
+				"es-node-master": strconv.FormatBool(roleMap[api.ElasticsearchRoleMaster]),
+			},
+		},
+	})
+
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, v1.Volume{
+		Name: zoneVolumeName,
+		VolumeSource: v1.VolumeSource{
+			DownwardAPI: &v1.DownwardAPIVolumeSource{
+				Items: []v1.DownwardAPIVolumeFile{
+					{
+						Path: zoneAttribute,
+						FieldRef: &v1.ObjectFieldSelector{
+							FieldPath: fmt.Sprintf("metadata.annotations['%s']", ZoneAnnotation),
+						},
+					},
+				},
+			},
+		},
+	})
+
+	for i, container := range podTemplate.Spec.Containers {
+		if container.Name != "elasticsearch" {
+			continue
+		}
+		podTemplate.Spec.Containers[i].Env = append(container.Env, v1.EnvVar{
+			Name: zoneEnvVar,
+			ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", ZoneAnnotation),
+				},
+			},
+		})
+
+		zoneFile := fmt.Sprintf("%s/%s", zoneMountPath, zoneAttribute)
+		podTemplate.Spec.InitContainers = append(podTemplate.Spec.InitContainers, v1.Container{
+			Name:            "zone",
+			Image:           container.Image,
+			ImagePullPolicy: container.ImagePullPolicy,
+			Command: []string{
+				"sh", "-c",
+				fmt.Sprintf("until [ -s %s ]; do echo waiting for the zone of the pod; sleep 2; done", zoneFile),
+			},
+			VolumeMounts: []v1.VolumeMount{
+				{
+					Name:      zoneVolumeName,
+					MountPath: zoneMountPath,
+					ReadOnly:  true,
+				},
+			},
+			Resources:       container.Resources,
+			SecurityContext: utils.ContainerSecurityContext(),
+		})
+	}
+}
+
+// syncZoneVolume replaces the zone volume of the current volumes with the desired one
+// if any. The other volumes are kept as is.
+func syncZoneVolume(current, desired []v1.Volume) []v1.Volume {
+	volumes := []v1.Volume{}
+	for _, volume := range current {
+		if volume.Name != zoneVolumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	for _, volume := range desired {
+		if volume.Name == zoneVolumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes
+}
+
+// ReconcileZone copies the zone label of the Kubernetes node of an elasticsearch pod to
+// the zone annotation of the pod
+func ReconcileZone(log logr.Logger, pod *v1.Pod, c client.Client) error {
+	topologyKey := pod.Annotations[ZoneTopologyKeyAnnotation]
+	if topologyKey == "" || pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	node := &v1.Node{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to get node of pod",
+			"pod", pod.Name,
+			"node", pod.Spec.NodeName,
+		)
+	}
+
+	zone := node.Labels[topologyKey]
+	if zone == "" {
+		log.Info("Node of pod has no zone label", "pod", pod.Name, "node", node.Name, "label", topologyKey)
+		return nil
+	}
+	if pod.Annotations[ZoneAnnotation] == zone {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	pod.Annotations[ZoneAnnotation] = zone
+	if err := c.Patch(context.TODO(), pod, patch); err != nil {
+		return kverrors.Wrap(err, "failed to set zone of pod",
+			"pod", pod.Name,
+			"zone", zone,
+		)
+	}
+	return nil
+}
+
+// updateZoneAwareness allocates the copies of a shard to different zones when all data
+// nodes are zone aware and reports whether the zones can hold the copies required by the
+// redundancy policy
+func (er *ElasticsearchRequest) updateZoneAwareness() {
+	cluster := er.cluster
+
+	var topologyKeys []string
+	dataNodes, zoneAwareNodes := 0, 0
+	for _, node := range cluster.Spec.Nodes {
+		if !isDataNode(node) {
+			continue
+		}
+		dataNodes++
+		if key := zoneTopologyKey(node); key != "" {
+			zoneAwareNodes++
+			topologyKeys = append(topologyKeys, key)
+		}
+	}
+
+	attributes := ""
+	if dataNodes > 0 && zoneAwareNodes == dataNodes {
+		attributes = zoneAttribute
+	}
+	if err := er.setAllocationAwarenessAttributes(attributes); err != nil {
+		er.ll.Error(err, "failed to set allocation awareness attributes")
+	}
+
+	value := v1.ConditionFalse
+	reason := ""
+	message := ""
+	switch {
+	case zoneAwareNodes == 0:
+	case zoneAwareNodes < dataNodes:
+		value = v1.ConditionTrue
+		reason = zoneMissingReason
+		message = "Shards are not allocated by zone because not all data nodes are zone aware"
+	default:
+		zones, err := er.listZones(topologyKeys)
+		if err != nil {
+			er.ll.Error(err, "failed to list zones")
+			return
+		}
+		copies := CalculateReplicaCount(cluster) + 1
+		if len(zones) < copies {
+			value = v1.ConditionTrue
+			reason = zoneInsufficientReason
+			message = fmt.Sprintf("The %d zones (%s) cannot hold the %d copies of a shard required by the %s redundancy policy",
+				len(zones), strings.Join(zones, ", "), copies, cluster.Spec.RedundancyPolicy)
+		}
+	}
+
+	err := updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.ZoneAwarenessUnsatisfied,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		er.client,
+	)
+	if err != nil {
+		er.ll.Error(err, "failed to update zone awareness condition")
+	}
+}
+
+func (er *ElasticsearchRequest) setAllocationAwarenessAttributes(attributes string) error {
+	current, err := er.esClient.GetAllocationAwarenessAttributes()
+	if err != nil {
+		return err
+	}
+	if current == attributes {
+		return nil
+	}
+
+	if _, err := er.esClient.SetAllocationAwarenessAttributes(attributes); err != nil {
+		return err
+	}
+	er.ll.Info("Updated allocation awareness attributes", "attributes", attributes)
+	return nil
+}
+
+// listZones returns the sorted zones of the Kubernetes nodes labeled with the topology keys
+func (er *ElasticsearchRequest) listZones(topologyKeys []string) ([]string, error) {
+	found := map[string]bool{}
+	for _, key := range topologyKeys {
+		nodes := &v1.NodeList{}
+		if err := er.client.List(context.TODO(), nodes, client.HasLabels{key}); err != nil {
+			return nil, kverrors.Wrap(err, "failed to list nodes", "label", key)
+		}
+		for _, node := range nodes.Items {
+			found[node.Labels[key]] = true
+		}
+	}
+
+	zones := make([]string, 0, len(found))
+	for zone := range found {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
@@ -0,0 +1,228 @@
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestAddZoneAwareness(t *testing.T) {
+	node := api.ElasticsearchNode{
+		Roles:         []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+		ZoneAwareness: &api.ZoneAwarenessSpec{},
+	}
+
+	podTemplate := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addZoneAwareness(&podTemplate, "elasticsearch", node, getNodeRoleMap(node))
+
+	if key := podTemplate.Annotations[ZoneTopologyKeyAnnotation]; key != defaultZoneTopologyKey {
+		t.Errorf("Exp. the topology key annotation to be %s but was %q", defaultZoneTopologyKey, key)
+	}
+
+	constraints := podTemplate.Spec.TopologySpreadConstraints
+	if len(constraints) != 1 || constraints[0].TopologyKey != defaultZoneTopologyKey {
+		t.Fatalf("Exp. a topology spread constraint on %s but got %v", defaultZoneTopologyKey, constraints)
+	}
+	expLabels := map[string]string{
+		"cluster-name":   "elasticsearch",
+		"component":      "elasticsearch",
+		"es-node-client": "false",
+		"es-node-data":   "true",
+		"es-node-master": "false",
+	}
+	if !reflect.DeepEqual(constraints[0].LabelSelector.MatchLabels, expLabels) {
+		t.Errorf("Exp. the pods to be spread by %v but got %v", expLabels, constraints[0].LabelSelector.MatchLabels)
+	}
+
+	var zoneEnv *v1.EnvVar
+	for i, env := range podTemplate.Spec.Containers[0].Env {
+		if env.Name == zoneEnvVar {
+			zoneEnv = &podTemplate.Spec.Containers[0].Env[i]
+		}
+	}
+	if zoneEnv == nil || zoneEnv.ValueFrom == nil || zoneEnv.ValueFrom.FieldRef == nil {
+		t.Fatalf("Exp. the elasticsearch container to have the %s env var from the downward API", zoneEnvVar)
+	}
+	if exp := "metadata.annotations['logging.openshift.io/zone']"; zoneEnv.ValueFrom.FieldRef.FieldPath != exp {
+		t.Errorf("Exp. the %s env var to refer to %s but was %s", zoneEnvVar, exp, zoneEnv.ValueFrom.FieldRef.FieldPath)
+	}
+
+	if len(podTemplate.Spec.InitContainers) != 1 {
+		t.Fatalf("Exp. an init container waiting for the zone but got %v", podTemplate.Spec.InitContainers)
+	}
+	found := false
+	for _, volume := range podTemplate.Spec.Volumes {
+		if volume.Name == zoneVolumeName && volume.DownwardAPI != nil {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Exp. the zone volume to exist")
+	}
+
+	custom := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	node.ZoneAwareness.TopologyKey = "example.com/rack"
+	addZoneAwareness(&custom, "elasticsearch", node, getNodeRoleMap(node))
+	if key := custom.Spec.TopologySpreadConstraints[0].TopologyKey; key != "example.com/rack" {
+		t.Errorf("Exp. the pods to be spread by example.com/rack but was %s", key)
+	}
+
+	plain := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	unchanged := preparePodTemplateSpecProvidingNodeSelectors(nil)
+	addZoneAwareness(&unchanged, "elasticsearch", api.ElasticsearchNode{}, nil)
+	if !reflect.DeepEqual(plain, unchanged) {
+		t.Errorf("Exp. the pod template to be unchanged without zone awareness")
+	}
+
+	updated := createUpdatablePodTemplateSpec(plain, podTemplate)
+	found = false
+	for _, volume := range updated.Spec.Volumes {
+		if volume.Name == zoneVolumeName {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Exp. the zone volume to be taken from the update")
+	}
+}
+
+func TestZoneAwareESConfig(t *testing.T) {
+	cluster := &api.Elasticsearch{
+		Spec: api.ElasticsearchSpec{
+			Nodes: []api.ElasticsearchNode{
+				{GenUUID: pointer.String("abc"), ZoneAwareness: &api.ZoneAwarenessSpec{}},
+				{GenUUID: pointer.String("def"), ESConfig: map[string]string{"node.attr.zone": "a"}},
+			},
+		},
+	}
+
+	exp := map[string]map[string]string{
+		"elasticsearch.yml": {},
+		"elasticsearch-abc.yml": {
+			"node.attr.zone": "${ZONE}",
+		},
+	}
+	if files := esConfigFiles(cluster); !reflect.DeepEqual(files, exp) {
+		t.Errorf("Exp. config files %v but got %v", exp, files)
+	}
+}
+
+func TestReconcileZone(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch-cdm-1",
+			Namespace: "openshift-logging",
+			Annotations: map[string]string{
+				ZoneTopologyKeyAnnotation: defaultZoneTopologyKey,
+			},
+		},
+		Spec: v1.PodSpec{NodeName: "worker-1"},
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker-1",
+			Labels: map[string]string{defaultZoneTopologyKey: "us-east-1a"},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(pod.DeepCopy(), node).Build()
+
+	if err := ReconcileZone(log.NewLogger("zone-awareness-testing"), pod, k8sClient); err != nil {
+		t.Fatalf("Failed to reconcile zone: %v", err)
+	}
+
+	updated := &v1.Pod{}
+	if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(pod), updated); err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	if zone := updated.Annotations[ZoneAnnotation]; zone != "us-east-1a" {
+		t.Errorf("Exp. the zone of the pod to be us-east-1a but was %q", zone)
+	}
+}
+
+func TestUpdateZoneAwareness(t *testing.T) {
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	dataRoles := []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}
+	cluster := &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch",
+			Namespace: "openshift-logging",
+		},
+		Spec: api.ElasticsearchSpec{
+			RedundancyPolicy: api.MultipleRedundancy,
+			Nodes: []api.ElasticsearchNode{
+				{Roles: dataRoles, NodeCount: 5, ZoneAwareness: &api.ZoneAwarenessSpec{}},
+			},
+		},
+	}
+	objs := []client.Object{cluster.DeepCopy()}
+	for _, worker := range []struct{ name, zone string }{
+		{name: "worker-1", zone: "us-east-1a"},
+		{name: "worker-2", zone: "us-east-1b"},
+		{name: "worker-3", zone: "us-east-1b"},
+	} {
+		objs = append(objs, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   worker.name,
+				Labels: map[string]string{defaultZoneTopologyKey: worker.zone},
+			},
+		})
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cluster/settings": {
+			{
+				StatusCode: 200,
+				Body:       `{"persistent": {}, "transient": {}}`,
+			},
+			{
+				StatusCode: 200,
+				Body:       `{"acknowledged": true}`,
+			},
+		},
+	})
+
+	er := ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		ll:       log.NewLogger("zone-awareness-testing"),
+	}
+	er.updateZoneAwareness()
+
+	_, _ = chatter.GetRequest("_cluster/settings")
+	req, found := chatter.GetRequest("_cluster/settings")
+	if !found || req.Method != http.MethodPut {
+		t.Fatal("Exp. the allocation awareness attributes to be set")
+	}
+	if exp := `{"persistent":{"cluster.routing.allocation.awareness.attributes":"zone"}}`; req.Body != exp {
+		t.Errorf("Exp. request body %s but got %s", exp, req.Body)
+	}
+
+	updated := &api.Elasticsearch{}
+	if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), updated); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+	// MultipleRedundancy with 5 data nodes requires 3 copies of a shard in 2 zones
+	_, condition := getESNodeCondition(updated.Status.Conditions, api.ZoneAwarenessUnsatisfied)
+	if condition == nil || condition.Status != v1.ConditionTrue || condition.Reason != zoneInsufficientReason {
+		t.Fatalf("Exp. the %s condition with reason %s but got %v", api.ZoneAwarenessUnsatisfied, zoneInsufficientReason, updated.Status.Conditions)
+	}
+	if !strings.Contains(condition.Message, "us-east-1a, us-east-1b") {
+		t.Errorf("Exp. the condition message to list the zones but was %q", condition.Message)
+	}
+}
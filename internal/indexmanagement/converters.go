@@ -2,28 +2,106 @@ package indexmanagement
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
 	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 )
 
+// primaryShardCount returns the number of primary shards of the indices created from the
+// templates. The rollover conditions are calculated for the same number of shards.
+func primaryShardCount(cluster *apis.Elasticsearch) int32 {
+	return int32(elasticsearch.CalculatePrimaryCount(cluster))
+}
+
 func calculateConditions(policy apis.IndexManagementPolicySpec, primaryShards int32) rolloverConditions {
 	// 40GB = 40960 1K messages
-	maxDoc := constants.TheoreticalShardMaxSizeInMB * 1000 * primaryShards
-	maxSize := defaultShardSize * primaryShards
+	maxDoc := constants.TheoreticalShardMaxSizeInMB * 1000 * int64(primaryShards)
+	maxSize := fmt.Sprintf("%dgb", defaultShardSize*primaryShards)
 	maxAge := ""
 	if policy.Phases.Hot != nil && policy.Phases.Hot.Actions.Rollover != nil {
-		maxAge = string(policy.Phases.Hot.Actions.Rollover.MaxAge)
+		rollover := policy.Phases.Hot.Actions.Rollover
+		maxAge = string(rollover.MaxAge)
+		if rollover.MaxDocs > 0 {
+			maxDoc = rollover.MaxDocs
+		}
+		if size := calculateMaxSize(rollover, primaryShards); size != "" {
+			maxSize = size
+		}
 	}
 	return rolloverConditions{
-		MaxSize: fmt.Sprintf("%dgb", maxSize),
+		MaxSize: maxSize,
 		MaxDocs: maxDoc,
 		MaxAge:  maxAge,
 	}
 }
 
+// calculateMaxSize returns the smaller of the spec'd index size and the spec'd primary shard
+// size multiplied by the number of primary shards, because the rollover 'max_size' condition
+// is evaluated against the size of all primary shards of an index
+func calculateMaxSize(rollover *apis.IndexManagementActionSpec, primaryShards int32) string {
+	candidates := []string{}
+	if rollover.MaxSize != "" {
+		candidates = append(candidates, string(rollover.MaxSize))
+	}
+	if match := reByteSize.FindStringSubmatch(string(rollover.MaxPrimaryShardSize)); match != nil {
+		size, err := strconv.ParseUint(match[1], 10, 64)
+		if err == nil && primaryShards > 0 && size <= math.MaxUint64/uint64(primaryShards) {
+			candidates = append(candidates, fmt.Sprintf("%d%s", size*uint64(primaryShards), match[2]))
+		}
+	}
+
+	maxSize := ""
+	maxBytes := uint64(0)
+	for _, candidate := range candidates {
+		bytes, err := calculateBytesForByteSize(apis.ByteSize(candidate))
+		if err != nil {
+			continue
+		}
+		if maxSize == "" || bytes < maxBytes {
+			maxSize = candidate
+			maxBytes = bytes
+		}
+	}
+	return maxSize
+}
+
+func calculateBytesForByteSize(size apis.ByteSize) (uint64, error) {
+	match := reByteSize.FindStringSubmatch(string(size))
+	if match == nil {
+		return 0, kverrors.New("unable to convert size to bytes for invalid size unit",
+			"size", size)
+	}
+	number, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return 0, kverrors.Wrap(err, "unable to parse uint", "value", match[1])
+	}
+	var shift uint
+	switch match[2] {
+	case "b":
+		shift = 0
+	case "kb":
+		shift = 10
+	case "mb":
+		shift = 20
+	case "gb":
+		shift = 30
+	case "tb":
+		shift = 40
+	case "pb":
+		shift = 50
+	default:
+		return 0, kverrors.New("conversion to bytes for size unit is unsupported", "unit", match[2])
+	}
+	if number > math.MaxUint64>>shift {
+		return 0, kverrors.New("size exceeds the maximum number of bytes", "size", size)
+	}
+	return number << shift, nil
+}
+
 func calculateMillisForTimeUnit(timeunit apis.TimeUnit) (uint64, error) {
 	match := reTimeUnit.FindStringSubmatch(string(timeunit))
 	if match == nil || len(match) < 2 {
@@ -62,3 +140,9 @@ func crontabScheduleFor(timeunit apis.TimeUnit) (string, error) {
 
 	return "", kverrors.New("crontab schedule for time unit is unsupported", "timeunit", match[2])
 }
+
+// MillisForTimeUnit returns the milliseconds of the time unit, e.g. for the retention of
+// snapshots which is given in the same units as the index management policies
+func MillisForTimeUnit(timeunit apis.TimeUnit) (uint64, error) {
+	return calculateMillisForTimeUnit(timeunit)
+}
@@ -56,7 +56,7 @@ var _ = Describe("Index Management", func() {
 				Expect(conditions.MaxSize).To(Equal("120gb"))
 			})
 			It("should restrict the index to (TheoreticalShardMaxSizeInMB * 1000) docs per primary shard", func() {
-				Expect(conditions.MaxDocs).To(Equal(constants.TheoreticalShardMaxSizeInMB * 1000 * primaryShards))
+				Expect(conditions.MaxDocs).To(Equal(constants.TheoreticalShardMaxSizeInMB * 1000 * int64(primaryShards)))
 			})
 			It("should restrict the age to that defined by policy management", func() {
 				Expect(conditions.MaxAge).To(Equal(string(policy.Phases.Hot.Actions.Rollover.MaxAge)))
@@ -72,6 +72,64 @@ var _ = Describe("Index Management", func() {
 				Expect(conditions.MaxAge).To(Equal(""))
 			})
 		})
+		Context("with spec'd size and document conditions", func() {
+			var (
+				rollover      *apis.IndexManagementActionSpec
+				policy        apis.IndexManagementPolicySpec
+				primaryShards = int32(3)
+			)
+			BeforeEach(func() {
+				rollover = &apis.IndexManagementActionSpec{
+					MaxAge: "3d",
+				}
+				policy = apis.IndexManagementPolicySpec{
+					Phases: apis.IndexManagementPhasesSpec{
+						Hot: &apis.IndexManagementHotPhaseSpec{
+							Actions: apis.IndexManagementActionsSpec{
+								Rollover: rollover,
+							},
+						},
+					},
+				}
+			})
+			It("should use the spec'd document count", func() {
+				rollover.MaxDocs = 1000
+				Expect(calculateConditions(policy, primaryShards).MaxDocs).To(Equal(int64(1000)))
+			})
+			It("should use the spec'd index size", func() {
+				rollover.MaxSize = "300gb"
+				Expect(calculateConditions(policy, primaryShards).MaxSize).To(Equal("300gb"))
+			})
+			It("should calculate the index size from the spec'd primary shard size", func() {
+				rollover.MaxPrimaryShardSize = "50gb"
+				Expect(calculateConditions(policy, primaryShards).MaxSize).To(Equal("150gb"))
+			})
+			It("should use the smaller of the spec'd index and primary shard sizes", func() {
+				rollover.MaxSize = "1tb"
+				rollover.MaxPrimaryShardSize = "500gb"
+				Expect(calculateConditions(policy, primaryShards).MaxSize).To(Equal("1tb"))
+
+				rollover.MaxSize = "200gb"
+				Expect(calculateConditions(policy, primaryShards).MaxSize).To(Equal("200gb"))
+			})
+		})
+	})
+
+	Describe("#calculateBytesForByteSize", func() {
+		It("should error for an invalid value", func() {
+			_, err := calculateBytesForByteSize(apis.ByteSize("12gib"))
+			Expect(err).ToNot(BeNil())
+		})
+		It("should convert the supported units", func() {
+			Expect(calculateBytesForByteSize(apis.ByteSize("12b"))).To(BeEquivalentTo(uint64(12)))
+			Expect(calculateBytesForByteSize(apis.ByteSize("12kb"))).To(BeEquivalentTo(uint64(12288)))
+			Expect(calculateBytesForByteSize(apis.ByteSize("12mb"))).To(BeEquivalentTo(uint64(12582912)))
+			Expect(calculateBytesForByteSize(apis.ByteSize("12gb"))).To(BeEquivalentTo(uint64(12884901888)))
+		})
+		It("should error for a size exceeding the maximum number of bytes", func() {
+			_, err := calculateBytesForByteSize(apis.ByteSize("20000pb"))
+			Expect(err).ToNot(BeNil())
+		})
 	})
 
 	Describe("#calculateMillisForTimeUnit", func() {
@@ -81,6 +81,38 @@ func (cxt *statusTestContext) withPolicyState(state esapi.IndexManagementPolicyS
 	return cxt
 }
 
+func (cxt *statusTestContext) withPhase(name esapi.IndexManagementPhaseName, state esapi.IndexManagementPolicyState) *statusTestContext {
+	var phase *esapi.IndexManagementPhaseStatus
+	for i, status := range cxt.policyStatus.Phases {
+		if status.Name == name {
+			phase = &cxt.policyStatus.Phases[i]
+			break
+		}
+	}
+	Expect(phase).ToNot(BeNil(), "The phase %q wasn't found in the policy status", name)
+	Expect(phase.State).To(Equal(state))
+	return cxt
+}
+
+func (cxt *statusTestContext) withPhaseMessage(name esapi.IndexManagementPhaseName, message string) *statusTestContext {
+	var phase *esapi.IndexManagementPhaseStatus
+	for i, status := range cxt.policyStatus.Phases {
+		if status.Name == name {
+			phase = &cxt.policyStatus.Phases[i]
+			break
+		}
+	}
+	Expect(phase).ToNot(BeNil(), "The phase %q wasn't found in the policy status", name)
+	Expect(phase.Message).To(Equal(message))
+	return cxt
+}
+
+func (cxt *statusTestContext) withRollover(conditions esapi.IndexManagementRolloverConditionsStatus) *statusTestContext {
+	Expect(cxt.policyStatus.Rollover).ToNot(BeNil(), "The policy status has no rollover conditions")
+	Expect(*cxt.policyStatus.Rollover).To(Equal(conditions))
+	return cxt
+}
+
 func (cxt *statusTestContext) withMappingState(state esapi.IndexManagementMappingState) *statusTestContext {
 	Expect(cxt.mappingStatus.State).To(Equal(state), fmt.Sprintf("status: %v", cxt.mappingStatus))
 	return cxt
@@ -15,12 +15,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("Index Management", func() {
 	defer GinkgoRecover()
+
+	_ = elasticsearch.SchemeBuilder.AddToScheme(scheme.Scheme)
+
 	var (
 		logger  = log.NewLogger("index-management-testing")
 		chatter *helpers.FakeElasticsearchChatter
@@ -77,8 +81,7 @@ var _ = Describe("Index Management", func() {
 				}
 
 				req = &IndexManagementRequest{
-					ll:     logger,
-					client: fake.NewFakeClient(),
+					ll: logger,
 					cluster: &elasticsearch.Elasticsearch{
 						ObjectMeta: metav1.ObjectMeta{
 							Name:      "elasticsearch",
@@ -122,6 +125,7 @@ var _ = Describe("Index Management", func() {
 						},
 					},
 				}
+				req.client = fake.NewFakeClient(req.cluster)
 			})
 
 			It("should suspend all cronjobs when non available", func() {
@@ -138,7 +142,7 @@ var _ = Describe("Index Management", func() {
 			})
 
 			It("should unsuspend all cronjobs when at least on elasticsearch pod running", func() {
-				req.client = fake.NewFakeClient(esPods...)
+				req.client = fake.NewFakeClient(append(esPods, req.cluster)...)
 				Expect(req.createOrUpdateIndexManagement()).To(BeNil())
 
 				cj := &batchv1.CronJob{}
@@ -150,6 +154,17 @@ var _ = Describe("Index Management", func() {
 				Expect(req.client.Get(context.TODO(), key, cj)).To(BeNil())
 				Expect(*cj.Spec.Suspend).To(BeFalse())
 			})
+
+			It("should persist the index management status", func() {
+				Expect(req.createOrUpdateIndexManagement()).To(BeNil())
+
+				es := &elasticsearch.Elasticsearch{}
+				key := client.ObjectKey{Name: "elasticsearch", Namespace: "openshift-logging"}
+				Expect(req.client.Get(context.TODO(), key, es)).To(BeNil())
+				Expect(es.Status.IndexManagementStatus).ToNot(BeNil())
+				Expect(es.Status.IndexManagementStatus.Mappings).To(HaveLen(1))
+				Expect(es.Status.IndexManagementStatus.Mappings[0].Name).To(Equal("infra"))
+			})
 		})
 	})
 
@@ -241,6 +256,37 @@ var _ = Describe("Index Management", func() {
 					"template": "node.infra*"
 				}`)
 		})
+		It("should pin new indices to the hot tier of the cluster", func() {
+			tiered := *request
+			tiered.cluster = request.cluster.DeepCopy()
+			tiered.cluster.Spec.Nodes[0].Tier = elasticsearch.ElasticsearchTierHot
+			tiered.cluster.Spec.Nodes[1].Tier = elasticsearch.ElasticsearchTierHot
+			tiered.cluster.Spec.Nodes[2].Tier = elasticsearch.ElasticsearchTierWarm
+
+			Expect(tiered.createOrUpdateIndexTemplate(mapping)).To(BeNil())
+			req, _ := chatter.GetRequest("_template/ocp-gen-node.infra")
+			helpers.ExpectJSON(req.Body).ToEqual(
+				`{
+					"aliases": {
+						"infra": {},
+						"node.infra" : {}
+					},
+					"settings": {
+						"index": {
+							"number_of_replicas": "1",
+							"number_of_shards": "2",
+							"routing": {
+								"allocation": {
+									"require": {
+										"tier": "hot"
+									}
+								}
+							}
+						}
+					},
+					"template": "node.infra*"
+				}`)
+		})
 	})
 	Describe("#initializeIndexIfNeeded", func() {
 		Context("when an index matching the pattern for rolling indices does not exist", func() {
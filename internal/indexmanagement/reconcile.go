@@ -8,14 +8,12 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
 	"github.com/go-logr/logr"
 	batch "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -23,38 +21,35 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/constants"
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/runner"
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/configmap"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/cronjob"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/pod"
 	"github.com/openshift/elasticsearch-operator/internal/manifests/rbac"
 	"github.com/openshift/elasticsearch-operator/internal/metrics"
 	esapi "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
-	"github.com/openshift/elasticsearch-operator/internal/utils"
-	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
 )
 
 const (
+	// indexManagementConfigmap held the scripts run by the cronjobs before the runner was
+	// part of the operator binary. It is only kept to remove it from existing clusters.
 	indexManagementConfigmap = "indexmanagement-scripts"
 	defaultShardSize         = int32(40)
-	workingDir               = "/tmp/scripts"
 
-	jobHistoryLimitFailed  int32 = 1
-	jobHistoryLimitSuccess int32 = 1
+	containerName = "indexmanagement"
+
+	// jobHistoryLimitFailed keeps enough failed jobs to detect repeated failures
+	jobHistoryLimitFailed int32 = failedJobsThreshold
 )
 
 var (
-	defaultCPURequest    = resource.MustParse("100m")
-	defaultMemoryRequest = resource.MustParse("32Mi")
-
 	millisPerSecond = uint64(1000)
 	millisPerMinute = uint64(60 * millisPerSecond)
 	millisPerHour   = uint64(millisPerMinute * 60)
 	millisPerDay    = uint64(millisPerHour * 24)
 	millisPerWeek   = uint64(millisPerDay * 7)
 
-	// fullExecMode octal 0777
-	fullExecMode int32 = 0o777
-
 	imLabels = map[string]string{
 		"provider":      "openshift",
 		"component":     "indexManagement",
@@ -64,7 +59,7 @@ var (
 
 type rolloverConditions struct {
 	MaxAge  string `json:"max_age,omitempty"`
-	MaxDocs int32  `json:"max_docs,omitempty"`
+	MaxDocs int64  `json:"max_docs,omitempty"`
 	MaxSize string `json:"max_size,omitempty"`
 }
 
@@ -75,9 +70,11 @@ type IndexManagementRequest struct {
 	ll       logr.Logger
 }
 
-func Reconcile(log logr.Logger, req *apis.Elasticsearch, reqClient client.Client) error {
+func Reconcile(ctx context.Context, log logr.Logger, req *apis.Elasticsearch, reqClient client.Client) error {
 	ll := log.WithValues("cluster", req.Name, "namespace", req.Namespace, "handler", "indexmanagement")
 	esClient := esclient.NewClient(ll, req.Name, req.Namespace, reqClient)
+	esClient.SetContext(ctx)
+	esClient.SetFlavor(elasticsearch.RunningFlavor(req))
 
 	imr := IndexManagementRequest{
 		client:   reqClient,
@@ -93,6 +90,7 @@ func (imr *IndexManagementRequest) createOrUpdateIndexManagement() error {
 	if imr.cluster.Spec.IndexManagement == nil {
 		return nil
 	}
+	previous := imr.cluster.Status.IndexManagementStatus
 	spec := verifyAndNormalize(imr.cluster)
 	policies := spec.PolicyMap()
 
@@ -130,7 +128,7 @@ func (imr *IndexManagementRequest) createOrUpdateIndexManagement() error {
 		}
 	}
 
-	if err := createOrUpdateCurationConfigmap(imr.ll, imr.client, imr.cluster); err != nil {
+	if err := removeCurationConfigmap(imr.client, imr.cluster); err != nil {
 		return err
 	}
 
@@ -139,7 +137,7 @@ func (imr *IndexManagementRequest) createOrUpdateIndexManagement() error {
 	}
 
 	suspend := len(esPods) == 0
-	primaryShards := elasticsearch.GetDataCount(imr.cluster)
+	primaryShards := primaryShardCount(imr.cluster)
 	for _, mapping := range spec.Mappings {
 		policy := policies[mapping.PolicyRef]
 		ll := imr.ll.WithValues("mapping", mapping.Name, "policy", policy.Name)
@@ -147,9 +145,21 @@ func (imr *IndexManagementRequest) createOrUpdateIndexManagement() error {
 			ll.Error(err, "could not reconcile indexmanagement cronjob")
 			return err
 		}
+		if err := imr.reconcileIndexManagementPhaseCronjob(apis.IndexManagementPhaseWarm, policy.Phases.Warm, policy, mapping, suspend); err != nil {
+			ll.Error(err, "could not reconcile indexmanagement warm phase cronjob")
+			return err
+		}
+		if err := imr.reconcileIndexManagementPhaseCronjob(apis.IndexManagementPhaseCold, policy.Phases.Cold, policy, mapping, suspend); err != nil {
+			ll.Error(err, "could not reconcile indexmanagement cold phase cronjob")
+			return err
+		}
 	}
 
-	return nil
+	if err := imr.updateJobStatus(previous, spec); err != nil {
+		imr.ll.Error(err, "could not read the results of the indexmanagement jobs")
+		return err
+	}
+	return imr.persistStatus(previous)
 }
 
 func (imr *IndexManagementRequest) cullIndexManagement(mappings []apis.IndexManagementPolicyMappingSpec, policies apis.PolicyMap) {
@@ -185,7 +195,7 @@ func (imr *IndexManagementRequest) initializeIndexIfNeeded(mapping apis.IndexMan
 	}
 	if len(indices) < 1 {
 		indexName := fmt.Sprintf("%s-000001", mapping.Name)
-		primaryShards := int32(elasticsearch.CalculatePrimaryCount(imr.cluster))
+		primaryShards := primaryShardCount(imr.cluster)
 		replicas := int32(elasticsearch.CalculateReplicaCount(imr.cluster))
 		index := esapi.NewIndex(indexName, primaryShards, replicas)
 		index.AddAlias(mapping.Name, false)
@@ -209,10 +219,16 @@ func formatWriteAlias(mapping apis.IndexManagementPolicyMappingSpec) string {
 func (imr *IndexManagementRequest) createOrUpdateIndexTemplate(mapping apis.IndexManagementPolicyMappingSpec) error {
 	name := formatTemplateName(mapping.Name)
 	pattern := fmt.Sprintf("%s*", mapping.Name)
-	primaryShards := int32(elasticsearch.CalculatePrimaryCount(imr.cluster))
+	primaryShards := primaryShardCount(imr.cluster)
 	replicas := int32(elasticsearch.CalculateReplicaCount(imr.cluster))
 	aliases := append(mapping.Aliases, mapping.Name)
 	template := esapi.NewIndexTemplate(pattern, aliases, primaryShards, replicas)
+	require := elasticsearch.IndexRoutingRequire(imr.cluster)
+	if len(require) > 0 {
+		template.Settings.Index.Routing = &esapi.IndexRoutingSettings{
+			Allocation: esapi.IndexRoutingAllocationSettings{Require: require},
+		}
+	}
 
 	// check to compare the current index templates vs what we just generated
 	templates, err := imr.esClient.GetIndexTemplates()
@@ -220,8 +236,13 @@ func (imr *IndexManagementRequest) createOrUpdateIndexTemplate(mapping apis.Inde
 		return err
 	}
 
-	for templateName := range templates {
-		if templateName == name {
+	for templateName, current := range templates {
+		if templateName != name {
+			continue
+		}
+		// recreate the template only to pin new indices to a tier or to release them
+		currentRequire := current.Settings.Index.Routing.Allocation.Require
+		if (len(currentRequire) == 0 && len(require) == 0) || reflect.DeepEqual(currentRequire, require) {
 			return nil
 		}
 	}
@@ -234,6 +255,13 @@ func (imr *IndexManagementRequest) removeCronJobsForMappings(mappings []apis.Ind
 	for _, mapping := range mappings {
 		expected.Insert(fmt.Sprintf("%s-im-%s", imr.cluster.Name, mapping.Name))
 		expected.Insert(fmt.Sprintf("%s-im-prune-%s", imr.cluster.Name, mapping.Name))
+		policy := policies[mapping.PolicyRef]
+		if policy.Phases.Warm != nil {
+			expected.Insert(formatPhaseCronJobName(imr.cluster.Name, apis.IndexManagementPhaseWarm, mapping))
+		}
+		if policy.Phases.Cold != nil {
+			expected.Insert(formatPhaseCronJobName(imr.cluster.Name, apis.IndexManagementPhaseCold, mapping))
+		}
 	}
 
 	cronList, err := cronjob.List(context.TODO(), imr.client, imr.cluster.Namespace, imLabels)
@@ -260,14 +288,11 @@ func (imr *IndexManagementRequest) removeCronJobsForMappings(mappings []apis.Ind
 	return nil
 }
 
-func createOrUpdateCurationConfigmap(log logr.Logger, apiclient client.Client, cluster *apis.Elasticsearch) error {
-	data := scriptMap
-	desired := configmap.New(indexManagementConfigmap, cluster.Namespace, imLabels, data)
-	cluster.AddOwnerRefTo(desired)
-
-	_, err := configmap.CreateOrUpdate(context.TODO(), apiclient, desired, configmap.DataEqual, configmap.MutateDataOnly)
-	if err != nil {
-		return kverrors.Wrap(err, "failed to create or update index management configmap",
+func removeCurationConfigmap(apiclient client.Client, cluster *apis.Elasticsearch) error {
+	key := client.ObjectKey{Name: indexManagementConfigmap, Namespace: cluster.Namespace}
+	err := configmap.Delete(context.TODO(), apiclient, key)
+	if err != nil && !apierrors.IsNotFound(kverrors.Root(err)) {
+		return kverrors.Wrap(err, "failed to remove index management configmap",
 			"cluster", cluster.Name,
 			"namespace", cluster.Namespace,
 		)
@@ -408,12 +433,12 @@ func (imr *IndexManagementRequest) reconcileIndexManagementCronjob(policy apis.I
 			return kverrors.Wrap(err, "failed to reconcile prune cronjob", "policymapping", mapping.Name, "namespaceSpec", policy.Phases.Delete.Namespaces)
 		}
 		name := fmt.Sprintf("%s-im-prune-%s", imr.cluster.Name, mapping.Name)
-		script := "./prune-namespaces"
+		script := runner.CmdPruneNamespaces
 		desired := newCronJob(imr.cluster.Name, imr.cluster.Namespace, name, schedule, script, imr.cluster.Spec.Spec.NodeSelector, imr.cluster.Spec.Spec.Tolerations, envvars, suspend)
 
 		imr.cluster.AddOwnerRefTo(desired)
 
-		err = cronjob.CreateOrUpdate(context.TODO(), imr.client, desired, areCronJobsSame, cronjob.Mutate)
+		err = cronjob.CreateOrUpdate(context.TODO(), imr.client, desired, jobs.AreCronJobsSame, cronjob.Mutate)
 		if err != nil {
 			return kverrors.Wrap(err, "failed to create or update cronjob",
 				"cluster", desired.Name,
@@ -433,7 +458,7 @@ func (imr *IndexManagementRequest) reconcileIndexManagementCronjob(policy apis.I
 
 	imr.cluster.AddOwnerRefTo(desired)
 
-	err = cronjob.CreateOrUpdate(context.TODO(), imr.client, desired, areCronJobsSame, cronjob.Mutate)
+	err = cronjob.CreateOrUpdate(context.TODO(), imr.client, desired, jobs.AreCronJobsSame, cronjob.Mutate)
 	if err != nil {
 		return kverrors.Wrap(err, "failed to create or update cronjob",
 			"cluster", desired.Name,
@@ -444,145 +469,88 @@ func (imr *IndexManagementRequest) reconcileIndexManagementCronjob(policy apis.I
 	return nil
 }
 
-func formatCmd(policy apis.IndexManagementPolicySpec) string {
-	cmd := ""
-	if policy.Phases.Delete != nil {
-		cmd = "./delete"
-	}
-	if policy.Phases.Hot != nil {
-		cmd = "./rollover"
-	}
-	if policy.Phases.Delete != nil && policy.Phases.Hot != nil {
-		cmd = "./delete-then-rollover"
+// reconcileIndexManagementPhaseCronjob creates or updates the cronjob moving the indices of
+// a mapping into the given phase (e.g. warm, cold) once they are older than its minAge
+func (imr *IndexManagementRequest) reconcileIndexManagementPhaseCronjob(phaseName apis.IndexManagementPhaseName, phase *apis.IndexManagementTierPhaseSpec, policy apis.IndexManagementPolicySpec, mapping apis.IndexManagementPolicyMappingSpec, suspend bool) error {
+	if phase == nil {
+		imr.ll.V(1).Info("Skipping indexmanagement phase cronjob for policymapping; phase not defined", "policymapping", mapping.Name, "phase", phaseName)
+		return nil
 	}
-	return cmd
-}
 
-func areCronJobsSame(lhs, rhs *batch.CronJob) bool {
-	if len(lhs.Spec.JobTemplate.Spec.Template.Spec.Containers) != len(rhs.Spec.JobTemplate.Spec.Template.Spec.Containers) {
-		return false
-	}
-	if !comparators.AreStringMapsSame(lhs.Spec.JobTemplate.Spec.Template.Spec.NodeSelector, rhs.Spec.JobTemplate.Spec.Template.Spec.NodeSelector) {
-		return false
+	minAgeMillis, err := calculateMillisForTimeUnit(phase.MinAge)
+	if err != nil {
+		return err
 	}
 
-	if !comparators.AreTolerationsSame(lhs.Spec.JobTemplate.Spec.Template.Spec.Tolerations, rhs.Spec.JobTemplate.Spec.Template.Spec.Tolerations) {
-		return false
-	}
-	if lhs.Spec.Schedule != rhs.Spec.Schedule {
-		lhs.Spec.Schedule = rhs.Spec.Schedule
-		return false
+	envvars := []corev1.EnvVar{
+		{Name: "POLICY_MAPPING", Value: mapping.Name},
+		{Name: "PHASE", Value: string(phaseName)},
+		{Name: "MIN_AGE", Value: strconv.FormatUint(minAgeMillis, 10)},
 	}
-	if lhs.Spec.Suspend != nil && rhs.Spec.Suspend != nil && *lhs.Spec.Suspend != *rhs.Spec.Suspend {
-		return false
+	envvars = append(envvars, phaseActionEnvVars(phase.Actions)...)
+
+	schedule, err := crontabScheduleFor(policy.PollInterval)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to reconcile phase cronjob", "policymapping", mapping.Name, "phase", phaseName)
 	}
-	for i, container := range lhs.Spec.JobTemplate.Spec.Template.Spec.Containers {
-		other := rhs.Spec.JobTemplate.Spec.Template.Spec.Containers[i]
-		if !areContainersSame(container, other) {
-			return false
-		}
+
+	name := formatPhaseCronJobName(imr.cluster.Name, phaseName, mapping)
+	script := runner.CmdTransition
+	desired := newCronJob(imr.cluster.Name, imr.cluster.Namespace, name, schedule, script, imr.cluster.Spec.Spec.NodeSelector, imr.cluster.Spec.Spec.Tolerations, envvars, suspend)
+
+	imr.cluster.AddOwnerRefTo(desired)
+
+	err = cronjob.CreateOrUpdate(context.TODO(), imr.client, desired, jobs.AreCronJobsSame, cronjob.Mutate)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to create or update cronjob",
+			"cluster", desired.Name,
+			"namespace", desired.Namespace,
+		)
 	}
-	return true
+
+	return nil
 }
 
-func areContainersSame(container, other corev1.Container) bool {
-	if container.Name != other.Name {
-		return false
+func phaseActionEnvVars(actions apis.IndexManagementTierActionsSpec) []corev1.EnvVar {
+	envvars := []corev1.EnvVar{}
+	if actions.Replicas != nil {
+		envvars = append(envvars, corev1.EnvVar{Name: "NUMBER_OF_REPLICAS", Value: strconv.FormatInt(int64(actions.Replicas.NumberOfReplicas), 10)})
 	}
-	if container.Image != other.Image {
-		return false
+	if actions.Allocate != nil && len(actions.Allocate.Require) > 0 {
+		require, _ := json.Marshal(actions.Allocate.Require)
+		envvars = append(envvars, corev1.EnvVar{Name: "ALLOCATE_REQUIRE", Value: string(require)})
 	}
-
-	if !reflect.DeepEqual(container.Command, other.Command) {
-		return false
+	if actions.Shrink != nil {
+		envvars = append(envvars, corev1.EnvVar{Name: "SHRINK_NUMBER_OF_SHARDS", Value: strconv.FormatInt(int64(actions.Shrink.NumberOfShards), 10)})
 	}
-	if !reflect.DeepEqual(container.Args, other.Args) {
-		return false
+	if actions.ForceMerge != nil {
+		envvars = append(envvars, corev1.EnvVar{Name: "FORCEMERGE_MAX_NUM_SEGMENTS", Value: strconv.FormatInt(int64(actions.ForceMerge.MaxNumSegments), 10)})
 	}
-
-	if !comparators.AreResourceRequementsSame(container.Resources, other.Resources) {
-		return false
+	if actions.ReadOnly {
+		envvars = append(envvars, corev1.EnvVar{Name: "READ_ONLY", Value: "true"})
 	}
+	return envvars
+}
 
-	if !comparators.EnvValueEqual(container.Env, other.Env) {
-		return false
-	}
-	return true
+func formatPhaseCronJobName(clusterName string, phase apis.IndexManagementPhaseName, mapping apis.IndexManagementPolicyMappingSpec) string {
+	return fmt.Sprintf("%s-im-%s-%s", clusterName, phase, mapping.Name)
 }
 
-func newContainer(clusterName, name, image, scriptPath string, envvars []corev1.EnvVar) corev1.Container {
-	envvars = append(envvars, corev1.EnvVar{Name: "ES_SERVICE", Value: fmt.Sprintf("https://%s:9200", clusterName)})
-	container := corev1.Container{
-		Name:            name,
-		Image:           image,
-		ImagePullPolicy: corev1.PullIfNotPresent,
-		Resources: corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceMemory: defaultMemoryRequest,
-				corev1.ResourceCPU:    defaultCPURequest,
-			},
-		},
-		WorkingDir: workingDir,
-		Env:        envvars,
-		Command:    []string{"bash"},
-		Args: []string{
-			"-c",
-			scriptPath,
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			{Name: "certs", ReadOnly: true, MountPath: "/etc/indexmanagement/keys"},
-			{Name: "scripts", ReadOnly: false, MountPath: workingDir},
-		},
-		SecurityContext: utils.ContainerSecurityContext(),
-	}
-
-	return container
+func formatCmd(policy apis.IndexManagementPolicySpec) string {
+	cmd := ""
+	if policy.Phases.Delete != nil {
+		cmd = runner.CmdDelete
+	}
+	if policy.Phases.Hot != nil {
+		cmd = runner.CmdRollover
+	}
+	if policy.Phases.Delete != nil && policy.Phases.Hot != nil {
+		cmd = runner.CmdDeleteThenRollover
+	}
+	return cmd
 }
 
-func newCronJob(clusterName, namespace, name, schedule, script string, nodeSelector map[string]string, tolerations []corev1.Toleration, envvars []corev1.EnvVar, suspend bool) *batch.CronJob {
-	containerName := "indexmanagement"
-	containers := []corev1.Container{
-		newContainer(clusterName, containerName, constants.PackagedCuratorImage(), script, envvars),
-	}
-	volumes := []corev1.Volume{
-		{
-			Name: "certs",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: clusterName,
-				},
-			},
-		},
-		{
-			Name: "scripts",
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: indexManagementConfigmap,
-					},
-					DefaultMode: &fullExecMode,
-				},
-			},
-		},
-	}
-
-	podSpec := pod.NewSpec(clusterName, containers, volumes).
-		WithNodeSelectors(nodeSelector).
-		WithTolerations(tolerations...).
-		WithRestartPolicy(corev1.RestartPolicyNever).
-		WithRestartPolicy(corev1.RestartPolicyNever).
-		WithTerminationGracePeriodSeconds(300 * time.Second).
-		WithSecurityContext(utils.PodSecurityContext()).
-		Build()
-
-	return cronjob.New(name, namespace, imLabels).
-		WithSuspend(suspend).
-		WithConcurrencyPolicy(batch.ForbidConcurrent).
-		WithSuccessfulJobsHistoryLimit(jobHistoryLimitSuccess).
-		WithFailedJobsHistoryLimit(jobHistoryLimitFailed).
-		WithSchedule(schedule).
-		WithBackoffLimit(0).
-		WithParallelism(1).
-		WithPodSpec(containerName, podSpec).
-		Build()
+func newCronJob(clusterName, namespace, name, schedule, cmd string, nodeSelector map[string]string, tolerations []corev1.Toleration, envvars []corev1.EnvVar, suspend bool) *batch.CronJob {
+	container := jobs.NewContainer(clusterName, containerName, runner.Command, []string{cmd}, runner.CertsDir, envvars)
+	return jobs.NewCronJob(clusterName, namespace, name, schedule, imLabels, nodeSelector, tolerations, container, suspend, jobHistoryLimitFailed)
 }
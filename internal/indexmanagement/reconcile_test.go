@@ -1,6 +1,7 @@
 package indexmanagement
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
@@ -15,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
 )
 
 var _ = Describe("Index Management", func() {
@@ -73,6 +75,20 @@ var _ = Describe("Index Management", func() {
 			})
 		})
 	})
+	Describe("#newCronJob", func() {
+		It("should run the index management command of the operator image", func() {
+			cronjob = newCronJob(cluster.Name, cluster.Namespace, "mycluster-im-foo", "*/5 * * * *", "rollover", map[string]string{}, []core.Toleration{}, []core.EnvVar{}, false)
+
+			container := cronjob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal(constants.PackagedOperatorImage()))
+			Expect(container.Command).To(Equal([]string{"elasticsearch-operator", "indexmanagement"}))
+			Expect(container.Args).To(Equal([]string{"rollover"}))
+			Expect(container.Env).To(ContainElement(core.EnvVar{Name: "ES_SERVICE", Value: "https://mycluster:9200"}))
+			Expect(container.VolumeMounts).To(Equal([]core.VolumeMount{
+				{Name: "certs", ReadOnly: true, MountPath: "/etc/indexmanagement/keys"},
+			}))
+		})
+	})
 	Describe("#formatCmd", func() {
 		Context("with no policies", func() {
 			It("should return an empty command", func() {
@@ -82,20 +98,81 @@ var _ = Describe("Index Management", func() {
 		Context("with delete phase", func() {
 			It("should format the command for delete", func() {
 				policy.Phases.Delete = &apis.IndexManagementDeletePhaseSpec{}
-				Expect(formatCmd(policy)).To(Equal("./delete"))
+				Expect(formatCmd(policy)).To(Equal("delete"))
 			})
 		})
 		Context("with rollover phase", func() {
 			It("should format the command for rollover", func() {
 				policy.Phases.Hot = &apis.IndexManagementHotPhaseSpec{}
-				Expect(formatCmd(policy)).To(Equal("./rollover"))
+				Expect(formatCmd(policy)).To(Equal("rollover"))
 			})
 		})
 		Context("with delete and rollover phases", func() {
 			It("should format the command for all phases", func() {
 				policy.Phases.Delete = &apis.IndexManagementDeletePhaseSpec{}
 				policy.Phases.Hot = &apis.IndexManagementHotPhaseSpec{}
-				Expect(formatCmd(policy)).To(Equal("./delete-then-rollover"))
+				Expect(formatCmd(policy)).To(Equal("delete-then-rollover"))
+			})
+		})
+	})
+	Describe("#phaseActionEnvVars", func() {
+		It("should only set the env vars for the defined actions", func() {
+			actions := apis.IndexManagementTierActionsSpec{
+				ForceMerge: &apis.IndexManagementForceMergeActionSpec{MaxNumSegments: 1},
+				Replicas:   &apis.IndexManagementReplicasActionSpec{NumberOfReplicas: 0},
+				Allocate: &apis.IndexManagementAllocateActionSpec{
+					Require: map[string]string{"box_type": "warm"},
+				},
+			}
+			Expect(phaseActionEnvVars(actions)).To(Equal([]core.EnvVar{
+				{Name: "NUMBER_OF_REPLICAS", Value: "0"},
+				{Name: "ALLOCATE_REQUIRE", Value: `{"box_type":"warm"}`},
+				{Name: "FORCEMERGE_MAX_NUM_SEGMENTS", Value: "1"},
+			}))
+		})
+	})
+	Describe("#ReconcileIndexManagementPhaseCronjob", func() {
+		var phase *apis.IndexManagementTierPhaseSpec
+		BeforeEach(func() {
+			phase = &apis.IndexManagementTierPhaseSpec{
+				MinAge: "2d",
+				Actions: apis.IndexManagementTierActionsSpec{
+					ReadOnly: true,
+				},
+			}
+		})
+		Context("when the phase is not defined", func() {
+			It("should not create the cronjob", func() {
+				imr := &IndexManagementRequest{ll: logger, client: apiclient, cluster: cluster}
+				Expect(imr.reconcileIndexManagementPhaseCronjob(apis.IndexManagementPhaseWarm, nil, policy, mapping, false)).To(Succeed())
+
+				key := client.ObjectKey{Name: "mycluster-im-warm-foo", Namespace: cluster.Namespace}
+				Expect(apiclient.Get(context.TODO(), key, &batch.CronJob{})).To(Not(Succeed()))
+			})
+		})
+		Context("when the phase is defined", func() {
+			It("should create the cronjob for the phase", func() {
+				imr := &IndexManagementRequest{ll: logger, client: apiclient, cluster: cluster}
+				Expect(imr.reconcileIndexManagementPhaseCronjob(apis.IndexManagementPhaseCold, phase, policy, mapping, false)).To(Succeed())
+
+				key := client.ObjectKey{Name: "mycluster-im-cold-foo", Namespace: cluster.Namespace}
+				actual := &batch.CronJob{}
+				Expect(apiclient.Get(context.TODO(), key, actual)).To(Succeed())
+				Expect(actual.Spec.Schedule).To(Equal("*/5 * * * *"))
+
+				container := actual.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+				Expect(container.Command).To(Equal([]string{"elasticsearch-operator", "indexmanagement"}))
+				Expect(container.Args).To(Equal([]string{"transition"}))
+				Expect(container.Env).To(ContainElement(core.EnvVar{Name: "PHASE", Value: "cold"}))
+				Expect(container.Env).To(ContainElement(core.EnvVar{Name: "MIN_AGE", Value: "172800000"}))
+				Expect(container.Env).To(ContainElement(core.EnvVar{Name: "READ_ONLY", Value: "true"}))
+			})
+		})
+		Context("with an invalid minAge", func() {
+			It("should return the error", func() {
+				phase.MinAge = "notavalue"
+				imr := &IndexManagementRequest{ll: logger, client: apiclient, cluster: cluster}
+				Expect(imr.reconcileIndexManagementPhaseCronjob(apis.IndexManagementPhaseWarm, phase, policy, mapping, false)).To(Not(Succeed()))
 			})
 		})
 	})
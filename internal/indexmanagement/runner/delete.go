@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// Delete removes the indices of every alias of the policy mapping which are older than
+// the minimum age. If a disk threshold is given, the oldest indices exceeding the share
+// of the total disk space are removed first.
+func (r *Runner) Delete() error {
+	aliases, err := r.writeAliases()
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := r.deleteForAlias(alias); err != nil {
+			return kverrors.Wrap(err, "failed to delete indices", "alias", alias)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) deleteForAlias(alias string) error {
+	ll := r.log.WithValues("alias", alias)
+	writeAlias := alias + writeAliasSuffix
+
+	writeIndex, err := r.writeIndex(writeAlias)
+	if err != nil {
+		return err
+	}
+	r.aliasResult(alias).WriteIndex = writeIndex
+
+	if r.config.DiskThreshold != 0 {
+		if err := r.deleteByPercentage(alias, writeIndex); err != nil {
+			return err
+		}
+	}
+
+	indices, err := r.esClient.ListIndicesWithCreationDate(writeAlias)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return kverrors.New("received no indices for alias, the server may not be ready", "alias", writeAlias)
+	}
+
+	minAgeFromEpoch := r.now().UnixMilli() - int64(r.config.MinAge)
+	var deletable []string
+	for _, index := range indices {
+		if index.Index == writeIndex {
+			continue
+		}
+		created, err := strconv.ParseInt(index.CreationDate, 10, 64)
+		if err != nil {
+			ll.Error(err, "failed to parse index creation date", "index", index.Index)
+			continue
+		}
+		if created < minAgeFromEpoch {
+			deletable = append(deletable, index.Index)
+		}
+	}
+
+	if len(deletable) == 0 {
+		ll.Info("No indices to delete")
+		return nil
+	}
+	ll.Info("Deleting indices", "indices", deletable)
+	return r.deleteIndices(alias, deletable)
+}
+
+// deleteByPercentage removes the indices of the write alias which exceed the disk threshold.
+// All indices are traversed from newest to oldest adding up their size. Once the sum exceeds
+// the allowed share of the total disk space, the indices belonging to the alias are removed.
+func (r *Runner) deleteByPercentage(alias, writeIndex string) error {
+	ll := r.log.WithValues("alias", alias)
+	writeAlias := alias + writeAliasSuffix
+
+	total, err := r.esClient.GetDiskTotal()
+	if err != nil {
+		return err
+	}
+	maxAllowedSize := uint64(float64(r.config.DiskThreshold) / 100.0 * float64(total))
+
+	members, err := r.esClient.GetAliases(writeAlias)
+	if err != nil {
+		return err
+	}
+
+	indices, err := r.esClient.ListIndicesWithCreationDate("*")
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		lhs, _ := strconv.ParseInt(indices[i].CreationDate, 10, 64)
+		rhs, _ := strconv.ParseInt(indices[j].CreationDate, 10, 64)
+		return lhs > rhs
+	})
+
+	var (
+		size      uint64
+		deletable []string
+	)
+	for _, index := range indices {
+		// closed indices and indices with unassigned primaries report no store size
+		indexSize, err := strconv.ParseUint(index.StoreSize, 10, 64)
+		if err != nil {
+			ll.Info("Counting index without store size as empty", "index", index.Index, "storeSize", index.StoreSize)
+			indexSize = 0
+		}
+		if size+indexSize < maxAllowedSize {
+			size += indexSize
+			continue
+		}
+		if _, ok := members[index.Index]; !ok {
+			continue
+		}
+		if index.Index == writeIndex {
+			ll.Info("Cannot delete write index", "index", index.Index)
+			continue
+		}
+		deletable = append(deletable, index.Index)
+	}
+
+	if len(deletable) == 0 {
+		return nil
+	}
+	ll.Info("Deleting indices exceeding the disk threshold", "indices", deletable, "threshold", r.config.DiskThreshold)
+	return r.deleteIndices(alias, deletable)
+}
+
+// deleteIndices deletes the indices in batches for cases where there are a large number of indices to remove
+func (r *Runner) deleteIndices(alias string, indices []string) error {
+	res := r.aliasResult(alias)
+	for start := 0; start < len(indices); start += deleteBatchSize {
+		end := start + deleteBatchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+		if err := r.esClient.DeleteIndices(indices[start:end]...); err != nil {
+			return err
+		}
+		res.DeletedIndices += int32(end - start)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// PruneNamespaces deletes the documents of the configured namespaces older than their
+// minimum age from all indices of the policy mapping
+func (r *Runner) PruneNamespaces() error {
+	if len(r.config.NamespaceSpecs) == 0 {
+		return kverrors.New("no namespaces to prune")
+	}
+
+	namespaces := make([]string, 0, len(r.config.NamespaceSpecs))
+	for namespace := range r.config.NamespaceSpecs {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	// Prune namespaces runs on all current index patterns
+	pattern := fmt.Sprintf("%s*", r.config.PolicyMapping)
+	for _, namespace := range namespaces {
+		minAge := r.config.NamespaceSpecs[namespace]
+		if minAge == "" {
+			minAge = defaultPruneAge
+		}
+		r.log.Info("Pruning namespace", "namespace", namespace, "minAge", minAge)
+		if err := r.esClient.DeleteByQuery(pattern, pruneNamespaceQuery(namespace, minAge)); err != nil {
+			return kverrors.Wrap(err, "failed to prune namespace", "namespace", namespace)
+		}
+	}
+	return nil
+}
+
+// pruneNamespaceQuery matches all documents of namespaces starting with the given prefix
+// which are older than minAge (e.g. 7d)
+func pruneNamespaceQuery(namespacePrefix, minAge string) map[string]interface{} {
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []interface{}{
+					map[string]interface{}{
+						"prefix": map[string]interface{}{
+							"kubernetes.namespace_name": namespacePrefix,
+						},
+					},
+				},
+				"filter": []interface{}{
+					map[string]interface{}{
+						"range": map[string]interface{}{
+							"@timestamp": map[string]interface{}{
+								"lt": fmt.Sprintf("now-%s", minAge),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
+)
+
+// Result is the outcome of a run written as termination message of the cronjob container
+type Result struct {
+	Command string        `json:"command"`
+	Aliases []AliasResult `json:"aliases,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// AliasResult is the outcome of a run for a single alias of the policy mapping
+type AliasResult struct {
+	Alias                 string `json:"alias"`
+	WriteIndex            string `json:"writeIndex,omitempty"`
+	IndexCount            int32  `json:"indexCount,omitempty"`
+	OldestIndexAgeSeconds int64  `json:"oldestIndexAgeSeconds,omitempty"`
+	RolledOver            bool   `json:"rolledOver,omitempty"`
+	DeletedIndices        int32  `json:"deletedIndices,omitempty"`
+}
+
+// ParseResult reads the result from a termination message. It returns nil if the
+// message is not a result, e.g. when the container was killed.
+func ParseResult(message string) *Result {
+	result := &Result{}
+	if !jobs.ParseResult(message, result) {
+		return nil
+	}
+	return result
+}
+
+// Result returns the outcome of the last run
+func (r *Runner) Result() *Result {
+	result := &Result{
+		Command: r.command,
+	}
+	names := make([]string, 0, len(r.aliases))
+	for name := range r.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Aliases = append(result.Aliases, *r.aliases[name])
+	}
+	if r.err != nil {
+		result.Error = jobs.ErrorMessage(r.err)
+	}
+	return result
+}
+
+func (r *Runner) aliasResult(alias string) *AliasResult {
+	if res, ok := r.aliases[alias]; ok {
+		return res
+	}
+	res := &AliasResult{Alias: alias}
+	r.aliases[alias] = res
+	return res
+}
+
+// collectAliasStats records the write index, the number of indices and the age of the oldest index
+// of every alias visited by the run
+func (r *Runner) collectAliasStats() {
+	for alias, res := range r.aliases {
+		writeAlias := alias + writeAliasSuffix
+		indices, err := r.esClient.ListIndicesWithCreationDate(writeAlias)
+		if err != nil {
+			r.log.Error(err, "failed to collect index stats", "alias", alias)
+			continue
+		}
+
+		res.IndexCount = int32(len(indices))
+		oldest := int64(0)
+		for _, index := range indices {
+			created, err := strconv.ParseInt(index.CreationDate, 10, 64)
+			if err != nil {
+				continue
+			}
+			if oldest == 0 || created < oldest {
+				oldest = created
+			}
+		}
+		if oldest > 0 {
+			res.OldestIndexAgeSeconds = (r.now().UnixMilli() - oldest) / 1000
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"k8s.io/utils/pointer"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const writeAliasSuffix = "-write"
+
+// Rollover rolls over the write alias of every alias of the policy mapping
+// (e.g. app-write for app-*) if the rollover conditions are met
+func (r *Runner) Rollover() error {
+	aliases, err := r.writeAliases()
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := r.rolloverAlias(alias); err != nil {
+			return kverrors.Wrap(err, "failed to rollover", "alias", alias)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) rolloverAlias(alias string) error {
+	ll := r.log.WithValues("alias", alias)
+	writeAlias := alias + writeAliasSuffix
+	res := r.aliasResult(alias)
+
+	writeIndex, err := r.writeIndex(writeAlias)
+	if err != nil {
+		return err
+	}
+	res.WriteIndex = writeIndex
+	ll.Info("Current write index", "index", writeIndex)
+
+	var nextIndex string
+	rolled, err := r.esClient.Rollover(writeAlias, r.config.Conditions)
+	if err != nil {
+		// already in bad state, e.g. the next index was created but the alias was not moved
+		ll.Error(err, "rollover request failed, calculating next write index based on current write index")
+		nextIndex, err = nextGeneration(writeIndex)
+	} else {
+		nextIndex, err = checkRollover(rolled, writeIndex)
+	}
+	if err != nil {
+		return err
+	}
+	ll.Info("Next write index", "index", nextIndex)
+
+	// ensure next index was created and cluster permits operations on it,
+	// e.g. not in read-only state because of low disk space.
+	index, err := r.esClient.GetIndex(nextIndex)
+	if err != nil {
+		return err
+	}
+	if index == nil {
+		return kverrors.New("next write index does not exist", "index", nextIndex)
+	}
+
+	current, err := r.writeIndex(writeAlias)
+	if err != nil {
+		return err
+	}
+	if current != nextIndex {
+		ll.Info("Updating write alias", "from", current, "to", nextIndex)
+		err = r.esClient.UpdateAlias(estypes.AliasActions{
+			Actions: []estypes.AliasAction{
+				{Add: &estypes.AddAliasAction{Index: current, Alias: writeAlias, IsWriteIndex: pointer.Bool(false)}},
+				{Add: &estypes.AddAliasAction{Index: nextIndex, Alias: writeAlias, IsWriteIndex: pointer.Bool(true)}},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	res.WriteIndex = nextIndex
+	res.RolledOver = nextIndex != writeIndex
+	return nil
+}
+
+// checkRollover returns the write index after the rollover
+func checkRollover(res *estypes.RolloverResponse, currentIndex string) (string, error) {
+	if !res.Acknowledged && !res.RolledOver {
+		for condition, met := range res.Conditions {
+			if met {
+				return "", kverrors.New("index was not rolled over despite meeting conditions to do so",
+					"condition", condition,
+					"conditions", res.Conditions)
+			}
+		}
+		return res.OldIndex, nil
+	}
+
+	if res.OldIndex != currentIndex {
+		return "", kverrors.New("old index does not match expected index",
+			"old_index", res.OldIndex,
+			"expected", currentIndex)
+	}
+	return res.NewIndex, nil
+}
+
+// nextGeneration returns the name of the index following the given one (e.g. app-000002 for app-000001)
+func nextGeneration(index string) (string, error) {
+	pos := strings.LastIndex(index, "-")
+	if pos == -1 {
+		return "", kverrors.New("index name has no generation suffix", "index", index)
+	}
+	generation, err := strconv.Atoi(index[pos+1:])
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to parse index generation", "index", index)
+	}
+	return fmt.Sprintf("%s-%06d", index[:pos], generation+1), nil
+}
+
+// writeAliases returns the aliases of the policy mapping having a write alias, without the
+// write suffix (e.g. app for app-write)
+func (r *Runner) writeAliases() ([]string, error) {
+	pattern := fmt.Sprintf("%s*%s", r.config.PolicyMapping, writeAliasSuffix)
+	res, err := r.esClient.GetAliases(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	for _, index := range res {
+		for alias := range index.Aliases {
+			if strings.HasSuffix(alias, writeAliasSuffix) {
+				found[strings.TrimSuffix(alias, writeAliasSuffix)] = true
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil, kverrors.New("no write aliases found, the server may not be ready", "pattern", pattern)
+	}
+
+	aliases := make([]string, 0, len(found))
+	for alias := range found {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases, nil
+}
+
+// writeIndex returns the write index of the write alias. If the alias has more than
+// one write index, all but the latest one are unmarked.
+func (r *Runner) writeIndex(writeAlias string) (string, error) {
+	res, err := r.esClient.GetAliases(writeAlias)
+	if err != nil {
+		return "", err
+	}
+
+	var writeIndices []string
+	for index, aliases := range res {
+		if aliases.Aliases[writeAlias].IsWriteIndex {
+			writeIndices = append(writeIndices, index)
+		}
+	}
+	if len(writeIndices) == 0 {
+		return "", kverrors.New("no write index found", "alias", writeAlias)
+	}
+
+	// the first index is the latest one
+	sort.Sort(sort.Reverse(sort.StringSlice(writeIndices)))
+	for _, index := range writeIndices[1:] {
+		r.log.Info("Removing extra write index", "alias", writeAlias, "index", index)
+		err := r.esClient.UpdateAlias(estypes.AliasActions{
+			Actions: []estypes.AliasAction{
+				{Add: &estypes.AddAliasAction{Index: index, Alias: writeAlias, IsWriteIndex: pointer.Bool(false)}},
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return writeIndices[0], nil
+}
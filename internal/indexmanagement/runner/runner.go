@@ -0,0 +1,262 @@
+package runner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	// Command is the operator subcommand running the index management cronjobs
+	Command = "indexmanagement"
+	// CertsDir is the mount path of the cluster secret in the index management cronjobs
+	CertsDir = "/etc/indexmanagement/keys"
+
+	CmdRollover           = "rollover"
+	CmdDelete             = "delete"
+	CmdDeleteThenRollover = "delete-then-rollover"
+	CmdPruneNamespaces    = "prune-namespaces"
+	CmdTransition         = "transition"
+
+	defaultPruneAge = "7d"
+	deleteBatchSize = 25
+)
+
+// ExitCode is the exit code of the index management command telling which step failed
+type ExitCode int
+
+const (
+	ExitOK                      ExitCode = 0
+	ExitUsage                   ExitCode = 2
+	ExitInvalidConfig           ExitCode = 3
+	ExitRolloverFailed          ExitCode = 10
+	ExitDeleteFailed            ExitCode = 11
+	ExitDeleteAndRolloverFailed ExitCode = 12
+	ExitPruneNamespacesFailed   ExitCode = 13
+	ExitTransitionFailed        ExitCode = 14
+)
+
+// Config is the configuration of a run given to the cronjob containers as env vars
+type Config struct {
+	PolicyMapping string
+	ESService     string
+	// MinAge in milliseconds
+	MinAge         uint64
+	DiskThreshold  int64
+	NamespaceSpecs map[string]string
+	Conditions     estypes.RolloverConditions
+	Phase          string
+	Actions        PhaseActions
+}
+
+// PhaseActions are the actions applied to the indices moving into a warm or cold phase
+type PhaseActions struct {
+	NumberOfReplicas         *int32
+	AllocateRequire          map[string]string
+	ShrinkNumberOfShards     *int32
+	ForceMergeMaxNumSegments *int32
+	ReadOnly                 bool
+}
+
+// Runner executes the index management commands against the cluster of a policy mapping
+type Runner struct {
+	log      logr.Logger
+	esClient esclient.Client
+	config   *Config
+	now      func() time.Time
+
+	command string
+	aliases map[string]*AliasResult
+	err     error
+}
+
+// Main runs the index management command given in args and returns the exit code
+func Main(args []string) int {
+	logger := log.NewLogger("indexmanagement")
+
+	if len(args) != 1 {
+		logger.Error(nil, "Usage: elasticsearch-operator indexmanagement <command>",
+			"commands", []string{CmdRollover, CmdDelete, CmdDeleteThenRollover, CmdPruneNamespaces, CmdTransition})
+		return int(ExitUsage)
+	}
+
+	config, err := ConfigFromEnv(os.Getenv)
+	if err != nil {
+		logger.Error(err, "invalid index management configuration")
+		return int(ExitInvalidConfig)
+	}
+
+	esClient := esclient.NewClient(logger, "", "", nil)
+	esClient.SetSendRequestFn(esclient.NewMountedCredentialsSendRequestFn(config.ESService, filepath.Join(CertsDir, "admin-ca")))
+
+	r := New(logger, esClient, config)
+	code := r.Run(args[0])
+	if err := jobs.WriteResult(jobs.TerminationMessagePath, r.Result()); err != nil {
+		logger.Error(err, "failed to report the index management result")
+	}
+	return int(code)
+}
+
+// New returns a runner for the given configuration
+func New(log logr.Logger, esClient esclient.Client, config *Config) *Runner {
+	return &Runner{
+		log:      log.WithValues("policymapping", config.PolicyMapping),
+		esClient: esClient,
+		config:   config,
+		now:      time.Now,
+		aliases:  map[string]*AliasResult{},
+	}
+}
+
+// Run executes the command and maps its failure to an exit code
+func (r *Runner) Run(cmd string) ExitCode {
+	ll := r.log.WithValues("command", cmd)
+	ll.Info("Index management process starting")
+
+	r.command = cmd
+	r.aliases = map[string]*AliasResult{}
+	r.err = nil
+
+	code := ExitOK
+	switch cmd {
+	case CmdRollover:
+		if err := r.Rollover(); err != nil {
+			ll.Error(err, "rollover failed")
+			r.err = err
+			code = ExitRolloverFailed
+		}
+	case CmdDelete:
+		if err := r.Delete(); err != nil {
+			ll.Error(err, "delete failed")
+			r.err = err
+			code = ExitDeleteFailed
+		}
+	case CmdDeleteThenRollover:
+		deleteErr := r.Delete()
+		if deleteErr != nil {
+			ll.Error(deleteErr, "delete failed")
+			r.err = deleteErr
+			code = ExitDeleteFailed
+		}
+		if err := r.Rollover(); err != nil {
+			ll.Error(err, "rollover failed")
+			r.err = err
+			code = ExitRolloverFailed
+			if deleteErr != nil {
+				r.err = kverrors.New("delete and rollover failed",
+					"delete_error", deleteErr.Error(),
+					"rollover_error", err.Error())
+				code = ExitDeleteAndRolloverFailed
+			}
+		}
+	case CmdPruneNamespaces:
+		if err := r.PruneNamespaces(); err != nil {
+			ll.Error(err, "prune namespaces failed")
+			r.err = err
+			code = ExitPruneNamespacesFailed
+		}
+	case CmdTransition:
+		if err := r.Transition(); err != nil {
+			ll.Error(err, "transition failed", "phase", r.config.Phase)
+			r.err = err
+			code = ExitTransitionFailed
+		}
+	default:
+		ll.Error(nil, "unknown index management command")
+		r.err = kverrors.New("unknown index management command", "command", cmd)
+		return ExitUsage
+	}
+
+	r.collectAliasStats()
+
+	if code == ExitOK {
+		ll.Info("Index management process done")
+	}
+	return code
+}
+
+// ConfigFromEnv reads the configuration from the env vars set on the cronjob container
+func ConfigFromEnv(getenv func(string) string) (*Config, error) {
+	config := &Config{
+		PolicyMapping: getenv("POLICY_MAPPING"),
+		ESService:     getenv("ES_SERVICE"),
+		Phase:         getenv("PHASE"),
+	}
+	if config.PolicyMapping == "" {
+		return nil, kverrors.New("missing required env var", "name", "POLICY_MAPPING")
+	}
+	if config.ESService == "" {
+		return nil, kverrors.New("missing required env var", "name", "ES_SERVICE")
+	}
+
+	var err error
+	if v := getenv("MIN_AGE"); v != "" {
+		if config.MinAge, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "MIN_AGE")
+		}
+	}
+	if v := getenv("DISK_THRESHOLD"); v != "" {
+		if config.DiskThreshold, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "DISK_THRESHOLD")
+		}
+	}
+	if v := getenv("NAMESPACE_SPECS"); v != "" {
+		if err := json.Unmarshal([]byte(v), &config.NamespaceSpecs); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "NAMESPACE_SPECS")
+		}
+	}
+	if v := getenv("PAYLOAD"); v != "" {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to decode env var", "name", "PAYLOAD")
+		}
+		request := estypes.RolloverRequest{}
+		if err := json.Unmarshal(decoded, &request); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "PAYLOAD")
+		}
+		config.Conditions = request.Conditions
+	}
+
+	actions := &config.Actions
+	if actions.NumberOfReplicas, err = parseOptionalInt32(getenv, "NUMBER_OF_REPLICAS"); err != nil {
+		return nil, err
+	}
+	if actions.ShrinkNumberOfShards, err = parseOptionalInt32(getenv, "SHRINK_NUMBER_OF_SHARDS"); err != nil {
+		return nil, err
+	}
+	if actions.ForceMergeMaxNumSegments, err = parseOptionalInt32(getenv, "FORCEMERGE_MAX_NUM_SEGMENTS"); err != nil {
+		return nil, err
+	}
+	if v := getenv("ALLOCATE_REQUIRE"); v != "" {
+		if err := json.Unmarshal([]byte(v), &actions.AllocateRequire); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "ALLOCATE_REQUIRE")
+		}
+	}
+	actions.ReadOnly = getenv("READ_ONLY") == "true"
+
+	return config, nil
+}
+
+func parseOptionalInt32(getenv func(string) string, name string) (*int32, error) {
+	v := getenv(name)
+	if v == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse env var", "name", name)
+	}
+	value := int32(i)
+	return &value, nil
+}
@@ -0,0 +1,366 @@
+package runner
+
+import (
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ViaQ/logerr/v2/log"
+	"k8s.io/utils/pointer"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+const (
+	writeAliasesURI = "_alias/app*-write"
+	writeIndexURI   = "_alias/app-write"
+	catIndicesURI   = "_cat/indices/app-write?format=json&h=index,pri,creation.date,store.size&bytes=b&s=creation.date"
+)
+
+var _ = Describe("Index management runner", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		config  *Config
+		now     = time.Date(2022, time.June, 10, 0, 0, 0, 0, time.UTC)
+
+		aliasesResponse = helpers.FakeElasticsearchResponse{
+			StatusCode: 200,
+			Body:       `{"app-000001":{"aliases":{"app-write":{}}},"app-000002":{"aliases":{"app-write":{"is_write_index":true}}}}`,
+		}
+		indicesResponse = helpers.FakeElasticsearchResponse{
+			StatusCode: 200,
+			Body: `[{"index":"app-000002","pri":"1","creation.date":"` + formatMillis(now.Add(-48*time.Hour).UnixMilli()) + `","store.size":"100"},
+			        {"index":"app-000003","pri":"1","creation.date":"` + formatMillis(now.UnixMilli()) + `","store.size":"0"}]`,
+		}
+	)
+
+	newRunner := func(responses map[string]helpers.FakeElasticsearchResponses) *Runner {
+		chatter = helpers.NewFakeElasticsearchChatter(responses)
+		esClient := helpers.NewFakeElasticsearchClient("", "", nil, chatter)
+		r := New(log.NewLogger("indexmanagement-runner-testing"), esClient, config)
+		r.now = func() time.Time { return now }
+		return r
+	}
+
+	BeforeEach(func() {
+		config = &Config{
+			PolicyMapping: "app",
+			ESService:     "https://elasticsearch:9200",
+		}
+	})
+
+	Describe("#ConfigFromEnv", func() {
+		It("should read the configuration from the cronjob env vars", func() {
+			env := map[string]string{
+				"POLICY_MAPPING":              "app",
+				"ES_SERVICE":                  "https://elasticsearch:9200",
+				"MIN_AGE":                     "604800000",
+				"DISK_THRESHOLD":              "75",
+				"NAMESPACE_SPECS":             `{"openshift-":"1d"}`,
+				"PAYLOAD":                     "eyJjb25kaXRpb25zIjp7Im1heF9hZ2UiOiI4aCIsIm1heF9kb2NzIjo0MDk2MDAwMCwibWF4X3NpemUiOiI0MGdiIn19",
+				"NUMBER_OF_REPLICAS":          "0",
+				"ALLOCATE_REQUIRE":            `{"box_type":"warm"}`,
+				"FORCEMERGE_MAX_NUM_SEGMENTS": "1",
+				"READ_ONLY":                   "true",
+			}
+			actual, err := ConfigFromEnv(func(name string) string { return env[name] })
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(&Config{
+				PolicyMapping:  "app",
+				ESService:      "https://elasticsearch:9200",
+				MinAge:         604800000,
+				DiskThreshold:  75,
+				NamespaceSpecs: map[string]string{"openshift-": "1d"},
+				Conditions: estypes.RolloverConditions{
+					MaxAge:  "8h",
+					MaxDocs: 40960000,
+					MaxSize: "40gb",
+				},
+				Actions: PhaseActions{
+					NumberOfReplicas:         pointer.Int32(0),
+					AllocateRequire:          map[string]string{"box_type": "warm"},
+					ForceMergeMaxNumSegments: pointer.Int32(1),
+					ReadOnly:                 true,
+				},
+			}))
+		})
+		It("should fail without a policy mapping", func() {
+			_, err := ConfigFromEnv(func(name string) string { return "" })
+			Expect(err).To(Not(BeNil()))
+		})
+		It("should fail for a malformed value", func() {
+			env := map[string]string{
+				"POLICY_MAPPING": "app",
+				"ES_SERVICE":     "https://elasticsearch:9200",
+				"MIN_AGE":        "7d",
+			}
+			_, err := ConfigFromEnv(func(name string) string { return env[name] })
+			Expect(err).To(Not(BeNil()))
+		})
+	})
+
+	Describe("#Run", func() {
+		It("should exit with a usage error for an unknown command", func() {
+			r := newRunner(map[string]helpers.FakeElasticsearchResponses{})
+			Expect(r.Run("curate")).To(Equal(ExitUsage))
+		})
+
+		Context("for rollover", func() {
+			It("should rollover the write alias", func() {
+				rolledOver := helpers.FakeElasticsearchResponse{
+					StatusCode: 200,
+					Body:       `{"app-000002":{"aliases":{"app-write":{}}},"app-000003":{"aliases":{"app-write":{"is_write_index":true}}}}`,
+				}
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {aliasesResponse},
+					writeIndexURI:   {aliasesResponse, rolledOver},
+					"app-write/_rollover": {
+						{
+							StatusCode: 200,
+							Body:       `{"acknowledged":true,"shards_acknowledged":true,"old_index":"app-000002","new_index":"app-000003","rolled_over":true,"dry_run":false,"conditions":{"[max_age: 8h]":true}}`,
+						},
+					},
+					"app-000003": {
+						{StatusCode: 200, Body: `{"app-000003":{}}`},
+					},
+					catIndicesURI: {indicesResponse},
+				})
+				Expect(r.Run(CmdRollover)).To(Equal(ExitOK))
+				_, updated := chatter.GetRequest("_aliases")
+				Expect(updated).To(BeFalse(), "Exp. the write alias to be moved by the rollover")
+				Expect(r.Result()).To(Equal(&Result{
+					Command: CmdRollover,
+					Aliases: []AliasResult{
+						{
+							Alias:                 "app",
+							WriteIndex:            "app-000003",
+							IndexCount:            2,
+							OldestIndexAgeSeconds: 172800,
+							RolledOver:            true,
+						},
+					},
+				}))
+			})
+			It("should fix the write alias when the next index exists", func() {
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {aliasesResponse},
+					writeIndexURI:   {aliasesResponse, aliasesResponse},
+					"app-write/_rollover": {
+						{StatusCode: 400, Body: `{"error":{"type":"resource_already_exists_exception"}}`},
+					},
+					"app-000003": {
+						{StatusCode: 200, Body: `{"app-000003":{}}`},
+					},
+					"_aliases": {
+						{StatusCode: 200, Body: `{"acknowledged":true}`},
+					},
+					catIndicesURI: {indicesResponse},
+				})
+				Expect(r.Run(CmdRollover)).To(Equal(ExitOK))
+				req, found := chatter.GetRequest("_aliases")
+				Expect(found).To(BeTrue())
+				Expect(req.Body).To(MatchJSON(`{"actions":[
+					{"add":{"index":"app-000002","alias":"app-write","is_write_index":false}},
+					{"add":{"index":"app-000003","alias":"app-write","is_write_index":true}}
+				]}`))
+			})
+			It("should fail when the conditions are met but the index was not rolled over", func() {
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {aliasesResponse},
+					writeIndexURI:   {aliasesResponse},
+					"app-write/_rollover": {
+						{
+							StatusCode: 200,
+							Body:       `{"acknowledged":false,"old_index":"app-000002","new_index":"app-000003","rolled_over":false,"conditions":{"[max_age: 8h]":true}}`,
+						},
+					},
+					catIndicesURI: {indicesResponse},
+				})
+				Expect(r.Run(CmdRollover)).To(Equal(ExitRolloverFailed))
+				result := r.Result()
+				Expect(result.Error).To(ContainSubstring("index was not rolled over despite meeting conditions to do so"))
+				Expect(result.Aliases).To(HaveLen(1))
+				Expect(result.Aliases[0].RolledOver).To(BeFalse())
+			})
+		})
+
+		Context("for delete", func() {
+			It("should delete the indices older than the minimum age except the write index", func() {
+				config.MinAge = uint64(7 * 24 * time.Hour / time.Millisecond)
+				old := now.Add(-8 * 24 * time.Hour).UnixMilli()
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {aliasesResponse},
+					writeIndexURI:   {aliasesResponse},
+					catIndicesURI: {
+						{
+							StatusCode: 200,
+							Body: `[{"index":"app-000001","pri":"1","creation.date":"` + formatMillis(old) + `","store.size":"100"},
+							        {"index":"app-000002","pri":"1","creation.date":"` + formatMillis(old) + `","store.size":"100"}]`,
+						},
+						{
+							StatusCode: 200,
+							Body:       `[{"index":"app-000002","pri":"1","creation.date":"` + formatMillis(old) + `","store.size":"100"}]`,
+						},
+					},
+					"app-000001": {
+						{StatusCode: 200, Body: `{"acknowledged":true}`},
+					},
+				})
+				Expect(r.Run(CmdDelete)).To(Equal(ExitOK))
+				req, found := chatter.GetRequest("app-000001")
+				Expect(found).To(BeTrue())
+				Expect(req.Method).To(Equal("DELETE"))
+				Expect(r.Result().Aliases).To(Equal([]AliasResult{
+					{
+						Alias:                 "app",
+						WriteIndex:            "app-000002",
+						IndexCount:            1,
+						OldestIndexAgeSeconds: 8 * 24 * 3600,
+						DeletedIndices:        1,
+					},
+				}))
+			})
+			It("should delete the oldest indices exceeding the disk threshold", func() {
+				config.MinAge = uint64(7 * 24 * time.Hour / time.Millisecond)
+				config.DiskThreshold = 50
+				recent := now.Add(-1 * time.Hour).UnixMilli()
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {aliasesResponse},
+					writeIndexURI:   {aliasesResponse, aliasesResponse},
+					"_cat/allocation?format=json&h=node,disk.total&bytes=b": {
+						{StatusCode: 200, Body: `[{"node":"elasticsearch-cdm-1","disk.total":"400"}]`},
+					},
+					"_cat/indices/*?format=json&h=index,pri,creation.date,store.size&bytes=b&s=creation.date": {
+						{
+							StatusCode: 200,
+							Body: `[{"index":"app-000001","pri":"1","creation.date":"` + formatMillis(recent-2) + `","store.size":"100"},
+							        {"index":"infra-000001","pri":"1","creation.date":"` + formatMillis(recent-1) + `","store.size":"100"},
+							        {"index":"app-000002","pri":"1","creation.date":"` + formatMillis(recent) + `","store.size":"50"}]`,
+						},
+					},
+					"app-000001": {
+						{StatusCode: 200, Body: `{"acknowledged":true}`},
+					},
+					catIndicesURI: {
+						{
+							StatusCode: 200,
+							Body:       `[{"index":"app-000002","pri":"1","creation.date":"` + formatMillis(recent) + `","store.size":"50"}]`,
+						},
+						{
+							StatusCode: 200,
+							Body:       `[{"index":"app-000002","pri":"1","creation.date":"` + formatMillis(recent) + `","store.size":"50"}]`,
+						},
+					},
+				})
+				Expect(r.Run(CmdDelete)).To(Equal(ExitOK))
+				_, found := chatter.GetRequest("app-000001")
+				Expect(found).To(BeTrue())
+				_, found = chatter.GetRequest("infra-000001")
+				Expect(found).To(BeFalse(), "Exp. only indices of the alias to be deleted")
+			})
+			It("should count the indices without store size as empty", func() {
+				config.MinAge = uint64(7 * 24 * time.Hour / time.Millisecond)
+				config.DiskThreshold = 50
+				recent := now.Add(-1 * time.Hour).UnixMilli()
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {aliasesResponse},
+					writeIndexURI:   {aliasesResponse, aliasesResponse},
+					"_cat/allocation?format=json&h=node,disk.total&bytes=b": {
+						{StatusCode: 200, Body: `[{"node":"elasticsearch-cdm-1","disk.total":"400"}]`},
+					},
+					"_cat/indices/*?format=json&h=index,pri,creation.date,store.size&bytes=b&s=creation.date": {
+						{
+							StatusCode: 200,
+							Body: `[{"index":"infra-000000","pri":"1","creation.date":"` + formatMillis(recent-3) + `","store.size":null},
+							        {"index":"app-000001","pri":"1","creation.date":"` + formatMillis(recent-2) + `","store.size":"100"},
+							        {"index":"infra-000001","pri":"1","creation.date":"` + formatMillis(recent-1) + `","store.size":""},
+							        {"index":"app-000002","pri":"1","creation.date":"` + formatMillis(recent) + `","store.size":"150"}]`,
+						},
+					},
+					"app-000001": {
+						{StatusCode: 200, Body: `{"acknowledged":true}`},
+					},
+					catIndicesURI: {
+						{
+							StatusCode: 200,
+							Body:       `[{"index":"app-000002","pri":"1","creation.date":"` + formatMillis(recent) + `","store.size":"150"}]`,
+						},
+						{
+							StatusCode: 200,
+							Body:       `[{"index":"app-000002","pri":"1","creation.date":"` + formatMillis(recent) + `","store.size":"150"}]`,
+						},
+					},
+				})
+				Expect(r.Run(CmdDelete)).To(Equal(ExitOK))
+				_, found := chatter.GetRequest("app-000001")
+				Expect(found).To(BeTrue(), "Exp. the indices exceeding the disk threshold to be deleted")
+			})
+		})
+
+		Context("for delete-then-rollover", func() {
+			It("should report both failures", func() {
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					writeAliasesURI: {
+						{StatusCode: 500, Body: `{"error":{"type":"node_not_connected_exception"}}`},
+						{StatusCode: 500, Body: `{"error":{"type":"node_not_connected_exception"}}`},
+					},
+				})
+				Expect(r.Run(CmdDeleteThenRollover)).To(Equal(ExitDeleteAndRolloverFailed))
+			})
+		})
+
+		Context("for prune-namespaces", func() {
+			It("should fail without namespaces", func() {
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{})
+				Expect(r.Run(CmdPruneNamespaces)).To(Equal(ExitPruneNamespacesFailed))
+			})
+			It("should delete the documents of the namespaces by query", func() {
+				config.NamespaceSpecs = map[string]string{"openshift-": ""}
+				r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+					"app*/_delete_by_query?conflicts=proceed": {
+						{StatusCode: 200, Body: `{"deleted":10}`},
+					},
+				})
+				Expect(r.Run(CmdPruneNamespaces)).To(Equal(ExitOK))
+				req, found := chatter.GetRequest("app*/_delete_by_query?conflicts=proceed")
+				Expect(found).To(BeTrue())
+				Expect(req.Body).To(MatchJSON(`{"query":{"bool":{
+					"must":[{"prefix":{"kubernetes.namespace_name":"openshift-"}}],
+					"filter":[{"range":{"@timestamp":{"lt":"now-7d"}}}]
+				}}}`))
+			})
+		})
+	})
+
+	Describe("#ParseResult", func() {
+		It("should read the result from the termination message", func() {
+			Expect(ParseResult(`{"command":"delete","aliases":[{"alias":"app","deletedIndices":2}]}`)).To(Equal(&Result{
+				Command: CmdDelete,
+				Aliases: []AliasResult{{Alias: "app", DeletedIndices: 2}},
+			}))
+		})
+		It("should ignore messages which are no result", func() {
+			Expect(ParseResult("")).To(BeNil())
+			Expect(ParseResult("OOMKilled")).To(BeNil())
+		})
+	})
+
+	Describe("#nextGeneration", func() {
+		It("should increment the generation of the index", func() {
+			Expect(nextGeneration("app-000009")).To(Equal("app-000010"))
+		})
+		It("should fail for indices without generation", func() {
+			_, err := nextGeneration("app")
+			Expect(err).To(Not(BeNil()))
+		})
+	})
+})
+
+func formatMillis(millis int64) string {
+	return strconv.FormatInt(millis, 10)
+}
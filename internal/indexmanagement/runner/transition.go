@@ -0,0 +1,171 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const healthTimeout = "10m"
+
+// Transition applies the actions of the phase to every index of the policy mapping older
+// than the minimum age. Indices which went through the phase are marked with the alias
+// <alias>-<phase> (e.g. app-warm).
+func (r *Runner) Transition() error {
+	if r.config.Phase == "" {
+		return kverrors.New("missing phase to transition to")
+	}
+	aliases, err := r.writeAliases()
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := r.transitionAlias(alias); err != nil {
+			return kverrors.Wrap(err, "failed to transition indices", "alias", alias, "phase", r.config.Phase)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) transitionAlias(alias string) error {
+	ll := r.log.WithValues("alias", alias, "phase", r.config.Phase)
+	writeAlias := alias + writeAliasSuffix
+	r.aliasResult(alias)
+	marker := fmt.Sprintf("%s-%s", alias, r.config.Phase)
+	minAgeFromEpoch := r.now().UnixMilli() - int64(r.config.MinAge)
+
+	aliases, err := r.esClient.GetIndexAliases(writeAlias)
+	if err != nil {
+		return err
+	}
+	indices, err := r.esClient.ListIndicesWithCreationDate(writeAlias)
+	if err != nil {
+		return err
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i].Index < indices[j].Index
+	})
+
+	for _, index := range indices {
+		indexAliases := aliases[index.Index].Aliases
+		if indexAliases[writeAlias].IsWriteIndex {
+			continue
+		}
+		if _, ok := indexAliases[marker]; ok {
+			continue
+		}
+		created, err := strconv.ParseInt(index.CreationDate, 10, 64)
+		if err != nil {
+			return kverrors.Wrap(err, "failed to parse index creation date", "index", index.Index)
+		}
+		if created >= minAgeFromEpoch {
+			continue
+		}
+
+		target, err := r.transitionIndex(index, indexAliases)
+		if err != nil {
+			return err
+		}
+		err = r.esClient.UpdateAlias(estypes.AliasActions{
+			Actions: []estypes.AliasAction{
+				{Add: &estypes.AddAliasAction{Index: target, Alias: marker}},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		ll.Info("Index moved to the phase", "index", target)
+	}
+	return nil
+}
+
+// transitionIndex applies the phase actions and returns the name of the resulting index
+func (r *Runner) transitionIndex(index estypes.CatIndicesResponse, aliases map[string]estypes.IndexAlias) (string, error) {
+	actions := r.config.Actions
+	name := index.Index
+
+	settings := map[string]interface{}{}
+	if actions.NumberOfReplicas != nil {
+		settings["index.number_of_replicas"] = *actions.NumberOfReplicas
+	}
+	for attribute, value := range actions.AllocateRequire {
+		settings[fmt.Sprintf("index.routing.allocation.require.%s", attribute)] = value
+	}
+	if len(settings) > 0 {
+		if err := r.esClient.PutIndexSettings(name, settings); err != nil {
+			return "", err
+		}
+	}
+
+	if actions.ShrinkNumberOfShards != nil {
+		primaries, err := strconv.ParseInt(index.Primaries, 10, 32)
+		if err != nil {
+			return "", kverrors.Wrap(err, "failed to parse index primary shard count", "index", name)
+		}
+		if *actions.ShrinkNumberOfShards < int32(primaries) {
+			if name, err = r.shrinkIndex(name, index.CreationDate, aliases, *actions.ShrinkNumberOfShards); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if actions.ForceMergeMaxNumSegments != nil {
+		if err := r.esClient.ForceMerge(name, *actions.ForceMergeMaxNumSegments); err != nil {
+			return "", err
+		}
+	}
+
+	if actions.ReadOnly {
+		if err := r.esClient.PutIndexSettings(name, map[string]interface{}{"index.blocks.write": true}); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// shrinkIndex shrinks the index into <index>-shrunk. Shrinking requires a copy of every shard
+// on a single node and the index to be write blocked. The shrunken index takes over all
+// aliases of its source which is deleted afterwards. It keeps the creation date of its source
+// for the age of the later phases.
+func (r *Runner) shrinkIndex(index, creationDate string, aliases map[string]estypes.IndexAlias, numberOfShards int32) (string, error) {
+	shards, err := r.esClient.GetIndexShards(index)
+	if err != nil {
+		return "", err
+	}
+	if len(shards) == 0 || shards[0].Node == "" {
+		return "", kverrors.New("no allocated shards found to shrink index", "index", index)
+	}
+
+	err = r.esClient.PutIndexSettings(index, map[string]interface{}{
+		"index.routing.allocation.require._name": shards[0].Node,
+		"index.blocks.write":                     true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := r.esClient.WaitForIndexHealth(index, "yellow", healthTimeout); err != nil {
+		return "", err
+	}
+
+	target := fmt.Sprintf("%s-shrunk", index)
+	settings := map[string]interface{}{
+		"index.number_of_shards":                 numberOfShards,
+		"index.creation_date":                    creationDate,
+		"index.routing.allocation.require._name": nil,
+		"index.blocks.write":                     nil,
+	}
+	if err := r.esClient.ShrinkIndex(index, target, settings, aliases); err != nil {
+		return "", err
+	}
+	if err := r.esClient.WaitForIndexHealth(target, "yellow", healthTimeout); err != nil {
+		return "", err
+	}
+	if err := r.esClient.DeleteIndices(index); err != nil {
+		return "", err
+	}
+	return target, nil
+}
@@ -0,0 +1,265 @@
+package indexmanagement
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/runner"
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/pod"
+)
+
+const (
+	// failedJobsThreshold is the number of consecutive failed runs of a cronjob after which
+	// the mapping is marked as failing. It must not exceed jobHistoryLimitFailed.
+	failedJobsThreshold = 3
+)
+
+// jobRun is the outcome of a finished index management job
+type jobRun struct {
+	jobs.Run
+	cronJob string
+	result  *runner.Result
+}
+
+func (run jobRun) errorMessage() string {
+	if run.result != nil && run.result.Error != "" {
+		return run.result.Error
+	}
+	if run.Reason != "" {
+		return fmt.Sprintf("job of cronjob %s failed with exit code %d: %s", run.cronJob, run.ExitCode, run.Reason)
+	}
+	return fmt.Sprintf("job of cronjob %s failed with exit code %d", run.cronJob, run.ExitCode)
+}
+
+// updateJobStatus adds the results of the last index management jobs to the status of
+// every accepted mapping
+func (imr *IndexManagementRequest) updateJobStatus(previous *apis.IndexManagementStatus, spec *apis.IndexManagementSpec) error {
+	status := imr.cluster.Status.IndexManagementStatus
+	if status == nil || len(spec.Mappings) == 0 {
+		return nil
+	}
+
+	pods, err := pod.List(context.TODO(), imr.client, imr.cluster.Namespace, imLabels)
+	if err != nil {
+		return err
+	}
+	runs := finishedJobRuns(pods)
+
+	for _, mapping := range spec.Mappings {
+		index := mappingStatusIndex(status, mapping.Name)
+		if index == -1 {
+			continue
+		}
+		mappingStatus := &status.Mappings[index]
+
+		var mappingRuns []jobRun
+		names := imr.cronJobNames(mapping)
+		for _, run := range runs {
+			for _, name := range names {
+				if isJobOf(run.cronJob, name) {
+					run.cronJob = name
+					mappingRuns = append(mappingRuns, run)
+					break
+				}
+			}
+		}
+
+		var prevAliases []apis.IndexManagementAliasStatus
+		if previous != nil {
+			if prevIndex := mappingStatusIndex(previous, mapping.Name); prevIndex != -1 {
+				prevAliases = previous.Mappings[prevIndex].Aliases
+			}
+		}
+		applyJobRuns(mappingStatus, prevAliases, mappingRuns)
+	}
+	return nil
+}
+
+// persistStatus writes the index management status if it changed. Statuses which only differ
+// in their timestamps keep the previous ones to not trigger a new reconciliation.
+func (imr *IndexManagementRequest) persistStatus(previous *apis.IndexManagementStatus) error {
+	cluster := imr.cluster
+	status := cluster.Status.IndexManagementStatus
+	if status == nil {
+		return nil
+	}
+	if previous != nil && isStatusSame(previous, status) {
+		cluster.Status.IndexManagementStatus = previous
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := imr.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.IndexManagementStatus = status
+		return imr.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update index management status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
+
+func (imr *IndexManagementRequest) cronJobNames(mapping apis.IndexManagementPolicyMappingSpec) []string {
+	return []string{
+		fmt.Sprintf("%s-im-%s", imr.cluster.Name, mapping.Name),
+		fmt.Sprintf("%s-im-prune-%s", imr.cluster.Name, mapping.Name),
+		formatPhaseCronJobName(imr.cluster.Name, apis.IndexManagementPhaseWarm, mapping),
+		formatPhaseCronJobName(imr.cluster.Name, apis.IndexManagementPhaseCold, mapping),
+	}
+}
+
+// finishedJobRuns returns the runs of the terminated job pods, newest first. The cronJob of
+// each run holds the job name until it is matched to a cronjob.
+func finishedJobRuns(pods []corev1.Pod) []jobRun {
+	var runs []jobRun
+	for _, run := range jobs.FinishedRuns(pods, containerName) {
+		runs = append(runs, jobRun{
+			Run:     run,
+			cronJob: run.JobName,
+			result:  runner.ParseResult(run.Message),
+		})
+	}
+	return runs
+}
+
+// isJobOf returns true if the job was scheduled by the cronjob, i.e. is named
+// <cronjob>-<scheduled time>
+func isJobOf(jobName, cronJob string) bool {
+	suffix := strings.TrimPrefix(jobName, cronJob+"-")
+	if suffix == jobName || suffix == "" {
+		return false
+	}
+	for _, c := range suffix {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// applyJobRuns sets the alias stats, last error and failing condition of the mapping from its
+// job runs sorted newest first. Rollover and delete times are kept from the previous aliases
+// as the job history only holds the last runs.
+func applyJobRuns(status *apis.IndexManagementMappingStatus, prevAliases []apis.IndexManagementAliasStatus, runs []jobRun) {
+	aliases := map[string]*apis.IndexManagementAliasStatus{}
+	for _, prev := range prevAliases {
+		alias := prev
+		aliases[alias.Name] = &alias
+	}
+
+	if len(runs) > 0 && runs[0].Failed() {
+		status.LastError = runs[0].errorMessage()
+	}
+
+	consecutiveFailures := map[string]int{}
+	succeeded := map[string]bool{}
+	for _, run := range runs {
+		if succeeded[run.cronJob] {
+			continue
+		}
+		if run.Failed() {
+			consecutiveFailures[run.cronJob]++
+		} else {
+			succeeded[run.cronJob] = true
+		}
+	}
+	cronJobs := make([]string, 0, len(consecutiveFailures))
+	for cronJob := range consecutiveFailures {
+		cronJobs = append(cronJobs, cronJob)
+	}
+	sort.Strings(cronJobs)
+	for _, cronJob := range cronJobs {
+		if failures := consecutiveFailures[cronJob]; failures >= failedJobsThreshold {
+			status.SetFailingCondition(fmt.Sprintf("the last %d jobs of cronjob %s failed", failures, cronJob))
+			break
+		}
+	}
+
+	// apply the oldest run first to end up with the stats of the newest run
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.result == nil {
+			continue
+		}
+		for _, res := range run.result.Aliases {
+			alias, ok := aliases[res.Alias]
+			if !ok {
+				alias = &apis.IndexManagementAliasStatus{Name: res.Alias}
+				aliases[res.Alias] = alias
+			}
+			if res.WriteIndex != "" {
+				alias.WriteIndex = res.WriteIndex
+			}
+			if res.IndexCount > 0 {
+				alias.IndexCount = res.IndexCount
+				alias.OldestIndexAge = &metav1.Duration{Duration: time.Duration(res.OldestIndexAgeSeconds) * time.Second}
+			}
+			if res.RolledOver {
+				alias.LastRolloverTime = laterTime(alias.LastRolloverTime, run.Finished)
+			}
+			if res.DeletedIndices > 0 {
+				alias.LastDeleteTime = laterTime(alias.LastDeleteTime, run.Finished)
+			}
+		}
+	}
+
+	status.Aliases = nil
+	for _, alias := range aliases {
+		status.Aliases = append(status.Aliases, *alias)
+	}
+	sort.Slice(status.Aliases, func(i, j int) bool {
+		return status.Aliases[i].Name < status.Aliases[j].Name
+	})
+}
+
+func laterTime(current *metav1.Time, other metav1.Time) *metav1.Time {
+	if current != nil && !current.Before(&other) {
+		return current
+	}
+	return &other
+}
+
+func mappingStatusIndex(status *apis.IndexManagementStatus, name string) int {
+	for i, mapping := range status.Mappings {
+		if mapping.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// isStatusSame compares the statuses ignoring when they were last updated
+func isStatusSame(lhs, rhs *apis.IndexManagementStatus) bool {
+	return reflect.DeepEqual(withoutLastUpdated(lhs), withoutLastUpdated(rhs))
+}
+
+func withoutLastUpdated(status *apis.IndexManagementStatus) *apis.IndexManagementStatus {
+	status = status.DeepCopy()
+	status.LastUpdated = metav1.Time{}
+	for i := range status.Policies {
+		status.Policies[i].LastUpdated = metav1.Time{}
+	}
+	for i := range status.Mappings {
+		status.Mappings[i].LastUpdated = metav1.Time{}
+	}
+	return status
+}
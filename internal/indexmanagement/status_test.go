@@ -0,0 +1,211 @@
+package indexmanagement
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ViaQ/logerr/v2/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("Index management status", func() {
+	defer GinkgoRecover()
+
+	_ = apis.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		logger  = log.NewLogger("index-management-status-testing")
+		now     = metav1.NewTime(time.Date(2022, time.June, 10, 12, 0, 0, 0, time.UTC))
+		cluster *apis.Elasticsearch
+		spec    *apis.IndexManagementSpec
+	)
+
+	newJobPod := func(jobName string, finished time.Duration, exitCode int32, message string) *corev1.Pod {
+		labels := map[string]string{"job-name": jobName}
+		for k, v := range imLabels {
+			labels[k] = v
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName + "-abcde",
+				Namespace: "openshift-logging",
+				Labels:    labels,
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "indexmanagement",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode:   exitCode,
+								Message:    message,
+								FinishedAt: metav1.NewTime(now.Add(finished)),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	newRequest := func(objs ...client.Object) *IndexManagementRequest {
+		cluster.Status.IndexManagementStatus = &apis.IndexManagementStatus{
+			Mappings: []apis.IndexManagementMappingStatus{*apis.NewIndexManagementMappingStatus("app")},
+		}
+		return &IndexManagementRequest{
+			ll:      logger,
+			client:  fake.NewClientBuilder().WithObjects(objs...).Build(),
+			cluster: cluster,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apis.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+		}
+		spec = &apis.IndexManagementSpec{
+			Mappings: []apis.IndexManagementPolicyMappingSpec{
+				{Name: "app", PolicyRef: "app-policy", Aliases: []string{"app"}},
+			},
+		}
+	})
+
+	Describe("#updateJobStatus", func() {
+		It("should report the alias stats of the last jobs", func() {
+			imr := newRequest(
+				newJobPod("elasticsearch-im-app-27580000", -10*time.Minute, 0,
+					`{"command":"delete-then-rollover","aliases":[{"alias":"app","writeIndex":"app-000002","indexCount":2,"oldestIndexAgeSeconds":3600,"rolledOver":true,"deletedIndices":1}]}`),
+				newJobPod("elasticsearch-im-app-27580015", -5*time.Minute, 0,
+					`{"command":"delete-then-rollover","aliases":[{"alias":"app","writeIndex":"app-000002","indexCount":2,"oldestIndexAgeSeconds":3900}]}`),
+				newJobPod("other-im-app-27580015", 0, 0,
+					`{"command":"delete-then-rollover","aliases":[{"alias":"app","writeIndex":"app-000042","indexCount":42}]}`),
+			)
+			Expect(imr.updateJobStatus(nil, spec)).To(Succeed())
+
+			status := imr.cluster.Status.IndexManagementStatus.Mappings[0]
+			Expect(status.LastError).To(BeEmpty())
+			Expect(status.Conditions).To(BeEmpty())
+			Expect(status.Aliases).To(HaveLen(1))
+			alias := status.Aliases[0]
+			Expect(alias.Name).To(Equal("app"))
+			Expect(alias.WriteIndex).To(Equal("app-000002"))
+			Expect(alias.IndexCount).To(Equal(int32(2)))
+			Expect(alias.OldestIndexAge).To(Equal(&metav1.Duration{Duration: 3900 * time.Second}))
+			Expect(alias.LastRolloverTime.Time).To(BeTemporally("==", now.Add(-10*time.Minute)))
+			Expect(alias.LastDeleteTime.Time).To(BeTemporally("==", now.Add(-10*time.Minute)))
+		})
+
+		It("should keep the last rollover time after the job was removed", func() {
+			rolledOver := metav1.NewTime(now.Add(-2 * time.Hour))
+			previous := &apis.IndexManagementStatus{
+				Mappings: []apis.IndexManagementMappingStatus{
+					{
+						Name: "app",
+						Aliases: []apis.IndexManagementAliasStatus{
+							{Name: "app", WriteIndex: "app-000002", LastRolloverTime: &rolledOver},
+						},
+					},
+				},
+			}
+			imr := newRequest(
+				newJobPod("elasticsearch-im-app-27580015", -5*time.Minute, 0,
+					`{"command":"rollover","aliases":[{"alias":"app","writeIndex":"app-000002","indexCount":2,"oldestIndexAgeSeconds":3900}]}`),
+			)
+			Expect(imr.updateJobStatus(previous, spec)).To(Succeed())
+
+			alias := imr.cluster.Status.IndexManagementStatus.Mappings[0].Aliases[0]
+			Expect(alias.LastRolloverTime).To(Equal(&rolledOver))
+			Expect(alias.LastDeleteTime).To(BeNil())
+		})
+
+		It("should report the error of the last failed job", func() {
+			imr := newRequest(
+				newJobPod("elasticsearch-im-app-27580000", -10*time.Minute, 0, `{"command":"rollover"}`),
+				newJobPod("elasticsearch-im-prune-app-27580015", -5*time.Minute, 13,
+					`{"command":"prune-namespaces","error":"failed to prune namespaces"}`),
+			)
+			Expect(imr.updateJobStatus(nil, spec)).To(Succeed())
+
+			status := imr.cluster.Status.IndexManagementStatus.Mappings[0]
+			Expect(status.LastError).To(Equal("failed to prune namespaces"))
+			Expect(status.Conditions).To(BeEmpty())
+		})
+
+		It("should mark the mapping as failing when the jobs fail repeatedly", func() {
+			imr := newRequest(
+				newJobPod("elasticsearch-im-app-27580000", -20*time.Minute, 0, `{"command":"rollover"}`),
+				newJobPod("elasticsearch-im-app-27580005", -15*time.Minute, 10, ""),
+				newJobPod("elasticsearch-im-app-27580010", -10*time.Minute, 10, ""),
+				newJobPod("elasticsearch-im-app-27580015", -5*time.Minute, 10, ""),
+			)
+			Expect(imr.updateJobStatus(nil, spec)).To(Succeed())
+
+			status := imr.cluster.Status.IndexManagementStatus.Mappings[0]
+			Expect(status.LastError).To(Equal("job of cronjob elasticsearch-im-app failed with exit code 10"))
+			Expect(status.Conditions).To(Equal([]apis.IndexManagementMappingCondition{
+				{
+					Type:    apis.IndexManagementMappingConditionTypeFailing,
+					Reason:  apis.IndexManagementMappingReasonJobsFailing,
+					Status:  corev1.ConditionTrue,
+					Message: "the last 3 jobs of cronjob elasticsearch-im-app failed",
+				},
+			}))
+		})
+
+		It("should not mark the mapping as failing after a successful job", func() {
+			imr := newRequest(
+				newJobPod("elasticsearch-im-app-27580005", -15*time.Minute, 10, ""),
+				newJobPod("elasticsearch-im-app-27580010", -10*time.Minute, 10, ""),
+				newJobPod("elasticsearch-im-app-27580015", -5*time.Minute, 0, `{"command":"rollover"}`),
+			)
+			Expect(imr.updateJobStatus(nil, spec)).To(Succeed())
+
+			status := imr.cluster.Status.IndexManagementStatus.Mappings[0]
+			Expect(status.LastError).To(BeEmpty())
+			Expect(status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Describe("#persistStatus", func() {
+		It("should not update the cluster when only the timestamps changed", func() {
+			imr := newRequest()
+			previous := imr.cluster.Status.IndexManagementStatus.DeepCopy()
+			previous.Mappings[0].LastUpdated = metav1.NewTime(now.Add(-time.Hour))
+
+			Expect(imr.persistStatus(previous)).To(Succeed())
+			Expect(imr.cluster.Status.IndexManagementStatus).To(BeIdenticalTo(previous))
+		})
+
+		It("should update the cluster status when the status changed", func() {
+			imr := newRequest(cluster.DeepCopy())
+			imr.cluster.Status.IndexManagementStatus.Mappings[0].LastError = "failed to rollover"
+
+			Expect(imr.persistStatus(&apis.IndexManagementStatus{})).To(Succeed())
+
+			es := &apis.Elasticsearch{}
+			key := client.ObjectKey{Name: "elasticsearch", Namespace: "openshift-logging"}
+			Expect(imr.client.Get(context.TODO(), key, es)).To(Succeed())
+			Expect(es.Status.IndexManagementStatus.Mappings[0].LastError).To(Equal("failed to rollover"))
+		})
+	})
+
+	Describe("#isJobOf", func() {
+		It("should match the jobs scheduled by the cronjob", func() {
+			Expect(isJobOf("elasticsearch-im-app-27580015", "elasticsearch-im-app")).To(BeTrue())
+			Expect(isJobOf("elasticsearch-im-app-warm-27580015", "elasticsearch-im-app")).To(BeFalse())
+			Expect(isJobOf("elasticsearch-im-app", "elasticsearch-im-app")).To(BeFalse())
+		})
+	})
+})
@@ -5,15 +5,23 @@ import (
 	"regexp"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
 	esapi "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 )
 
-var reTimeUnit = regexp.MustCompile("^(?P<number>\\d+)(?P<unit>[wdhHms])$")
+var (
+	reTimeUnit = regexp.MustCompile("^(?P<number>\\d+)(?P<unit>[wdhHms])$")
+	reByteSize = regexp.MustCompile("^(?P<number>\\d+)(?P<unit>b|kb|mb|gb|tb|pb)$")
+)
 
 const (
 	pollIntervalFailMessage  = "The pollInterval is missing or requires a valid time unit (e.g. 3d)"
 	phaseTimeUnitFailMessage = "The %s phase '%s' is missing or requires a valid time unit (e.g. 3d)"
 	policyRefFailMessage     = "A policy mapping must reference a defined IndexManagement policy"
+	phaseOrderFailMessage    = "The %s phase 'minAge' must be greater than the %s phase 'minAge'"
+	phaseSizeFailMessage     = "The %s phase '%s' requires a valid size unit (e.g. 50gb)"
+	phaseMaxDocsFailMessage  = "The %s phase '%s' must be a positive number"
 )
 
 // verifyAndNormalize validates the spec'd indexManagement and returns a spec which removes policies
@@ -40,6 +48,38 @@ func verifyAndNormalize(cluster *esapi.Elasticsearch) *esapi.IndexManagementSpec
 	return result
 }
 
+// ValidateSpec returns the errors of the policies and mappings verifyAndNormalize would drop
+func ValidateSpec(cluster *esapi.Elasticsearch) field.ErrorList {
+	errs := field.ErrorList{}
+	if cluster.Spec.IndexManagement == nil {
+		return errs
+	}
+	verified := cluster.DeepCopy()
+	verifyAndNormalize(verified)
+
+	path := field.NewPath("spec", "indexManagement")
+	for i, policy := range verified.Status.IndexManagementStatus.Policies {
+		for _, condition := range policy.Conditions {
+			errs = append(errs, field.Invalid(path.Child("policies").Index(i),
+				cluster.Spec.IndexManagement.Policies[i].Name, conditionDetail(string(condition.Type), string(condition.Reason), condition.Message)))
+		}
+	}
+	for i, mapping := range verified.Status.IndexManagementStatus.Mappings {
+		for _, condition := range mapping.Conditions {
+			errs = append(errs, field.Invalid(path.Child("mappings").Index(i),
+				cluster.Spec.IndexManagement.Mappings[i].Name, conditionDetail(string(condition.Type), string(condition.Reason), condition.Message)))
+		}
+	}
+	return errs
+}
+
+func conditionDetail(conditionType, reason, message string) string {
+	if message == "" {
+		return fmt.Sprintf("%s %s", conditionType, reason)
+	}
+	return message
+}
+
 func validatePolicies(cluster *esapi.Elasticsearch, result *esapi.IndexManagementSpec) {
 	if cluster.Spec.IndexManagement == nil {
 		return
@@ -47,6 +87,7 @@ func validatePolicies(cluster *esapi.Elasticsearch, result *esapi.IndexManagemen
 	policyNames := map[string]interface{}{}
 	for n, policy := range cluster.Spec.IndexManagement.Policies {
 		status := esapi.NewIndexManagementPolicyStatus(policy.Name)
+		phases := phaseConditions{}
 		if strings.TrimSpace(policy.Name) == "" {
 			status.Name = fmt.Sprintf("policy[%d]", n)
 			status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeName, esapi.IndexManagementPolicyReasonMissing, "")
@@ -65,29 +106,139 @@ func validatePolicies(cluster *esapi.Elasticsearch, result *esapi.IndexManagemen
 		if policy.Phases.Hot != nil {
 			if policy.Phases.Hot.Actions.Rollover == nil || !isValidTimeUnit(policy.Phases.Hot.Actions.Rollover.MaxAge) {
 				message := fmt.Sprintf(phaseTimeUnitFailMessage, "hot", "maxAge")
-				status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
+				phases.add(status, esapi.IndexManagementPhaseHot, esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
+			}
+			if rollover := policy.Phases.Hot.Actions.Rollover; rollover != nil {
+				validateRolloverConditions(rollover, status, phases)
+			}
+		}
+		if policy.Phases.Warm != nil {
+			if !isValidTimeUnit(policy.Phases.Warm.MinAge) {
+				message := fmt.Sprintf(phaseTimeUnitFailMessage, "warm", "minAge")
+				phases.add(status, esapi.IndexManagementPhaseWarm, esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
+			}
+		}
+		if policy.Phases.Cold != nil {
+			if !isValidTimeUnit(policy.Phases.Cold.MinAge) {
+				message := fmt.Sprintf(phaseTimeUnitFailMessage, "cold", "minAge")
+				phases.add(status, esapi.IndexManagementPhaseCold, esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
 			}
 		}
 		if policy.Phases.Delete != nil {
 			if !isValidTimeUnit(policy.Phases.Delete.MinAge) {
 				message := fmt.Sprintf(phaseTimeUnitFailMessage, "delete", "minAge")
-				status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
+				phases.add(status, esapi.IndexManagementPhaseDelete, esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
 			}
 		}
+		if len(status.Conditions) == 0 {
+			validatePhaseOrder(policy, status, phases)
+		}
 		if len(status.Conditions) > 0 {
 			status.State = esapi.IndexManagementPolicyStateDropped
 			status.Reason = esapi.IndexManagementPolicyReasonConditionsNotMet
 		} else {
 			result.Policies = append(result.Policies, policy)
+			if policy.Phases.Hot != nil {
+				conditions := calculateConditions(policy, primaryShardCount(cluster))
+				status.Rollover = &esapi.IndexManagementRolloverConditionsStatus{
+					MaxAge:  esapi.TimeUnit(conditions.MaxAge),
+					MaxSize: esapi.ByteSize(conditions.MaxSize),
+					MaxDocs: conditions.MaxDocs,
+				}
+			}
 		}
+		addPhaseStatuses(policy, status, phases)
 		cluster.Status.IndexManagementStatus.Policies = append(cluster.Status.IndexManagementStatus.Policies, *status)
 	}
 }
 
+func validateRolloverConditions(rollover *esapi.IndexManagementActionSpec, status *esapi.IndexManagementPolicyStatus, phases phaseConditions) {
+	if rollover.MaxSize != "" && !isValidByteSize(rollover.MaxSize) {
+		message := fmt.Sprintf(phaseSizeFailMessage, "hot", "maxSize")
+		phases.add(status, esapi.IndexManagementPhaseHot, esapi.IndexManagementPolicyConditionTypeRollover, esapi.IndexManagementPolicyReasonMalformed, message)
+	}
+	if rollover.MaxPrimaryShardSize != "" && !isValidByteSize(rollover.MaxPrimaryShardSize) {
+		message := fmt.Sprintf(phaseSizeFailMessage, "hot", "maxPrimaryShardSize")
+		phases.add(status, esapi.IndexManagementPhaseHot, esapi.IndexManagementPolicyConditionTypeRollover, esapi.IndexManagementPolicyReasonMalformed, message)
+	}
+	if rollover.MaxDocs < 0 {
+		message := fmt.Sprintf(phaseMaxDocsFailMessage, "hot", "maxDocs")
+		phases.add(status, esapi.IndexManagementPhaseHot, esapi.IndexManagementPolicyConditionTypeRollover, esapi.IndexManagementPolicyReasonMalformed, message)
+	}
+}
+
+// validatePhaseOrder ensures the minAge of the warm, cold and delete phases
+// increase in that order, since an index moves through them one after the other
+func validatePhaseOrder(policy esapi.IndexManagementPolicySpec, status *esapi.IndexManagementPolicyStatus, phases phaseConditions) {
+	type phaseAge struct {
+		name   esapi.IndexManagementPhaseName
+		millis uint64
+	}
+	ages := []phaseAge{}
+	if policy.Phases.Warm != nil {
+		millis, _ := calculateMillisForTimeUnit(policy.Phases.Warm.MinAge)
+		ages = append(ages, phaseAge{esapi.IndexManagementPhaseWarm, millis})
+	}
+	if policy.Phases.Cold != nil {
+		millis, _ := calculateMillisForTimeUnit(policy.Phases.Cold.MinAge)
+		ages = append(ages, phaseAge{esapi.IndexManagementPhaseCold, millis})
+	}
+	if policy.Phases.Delete != nil {
+		millis, _ := calculateMillisForTimeUnit(policy.Phases.Delete.MinAge)
+		ages = append(ages, phaseAge{esapi.IndexManagementPhaseDelete, millis})
+	}
+	for i := 1; i < len(ages); i++ {
+		if ages[i].millis <= ages[i-1].millis {
+			message := fmt.Sprintf(phaseOrderFailMessage, ages[i].name, ages[i-1].name)
+			phases.add(status, ages[i].name, esapi.IndexManagementPolicyConditionTypePhaseOrder, esapi.IndexManagementPolicyReasonMalformed, message)
+		}
+	}
+}
+
+// phaseConditions holds the message of the first condition raised for each phase of a policy
+type phaseConditions map[esapi.IndexManagementPhaseName]string
+
+// add records the condition on the policy status and attributes it to the phase
+func (p phaseConditions) add(status *esapi.IndexManagementPolicyStatus, phase esapi.IndexManagementPhaseName, conditionType esapi.IndexManagementPolicyConditionType, reason esapi.IndexManagementPolicyConditionReason, message string) {
+	status.AddPolicyCondition(conditionType, reason, message)
+	if _, found := p[phase]; !found {
+		p[phase] = message
+	}
+}
+
+// addPhaseStatuses records an entry for each phase defined by the policy. A phase is only
+// dropped by its own conditions, the conditions of the policy itself drop the policy.
+func addPhaseStatuses(policy esapi.IndexManagementPolicySpec, status *esapi.IndexManagementPolicyStatus, phases phaseConditions) {
+	defined := []struct {
+		name    esapi.IndexManagementPhaseName
+		defined bool
+	}{
+		{esapi.IndexManagementPhaseHot, policy.Phases.Hot != nil},
+		{esapi.IndexManagementPhaseWarm, policy.Phases.Warm != nil},
+		{esapi.IndexManagementPhaseCold, policy.Phases.Cold != nil},
+		{esapi.IndexManagementPhaseDelete, policy.Phases.Delete != nil},
+	}
+	for _, phase := range defined {
+		if !phase.defined {
+			continue
+		}
+		state := esapi.IndexManagementPolicyStateAccepted
+		message, found := phases[phase.name]
+		if found {
+			state = esapi.IndexManagementPolicyStateDropped
+		}
+		status.AddPhase(phase.name, state, message)
+	}
+}
+
 func isValidTimeUnit(time esapi.TimeUnit) bool {
 	return reTimeUnit.MatchString(string(time))
 }
 
+func isValidByteSize(size esapi.ByteSize) bool {
+	return reByteSize.MatchString(string(size))
+}
+
 func validateMappings(cluster *esapi.Elasticsearch, result *esapi.IndexManagementSpec) {
 	if cluster.Spec.IndexManagement == nil {
 		return
@@ -4,6 +4,7 @@ import (
 	. "github.com/onsi/ginkgo"
 
 	esapi "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
 )
 
 var _ = Describe("Index Management", func() {
@@ -67,6 +68,36 @@ var _ = Describe("Index Management", func() {
 					withPolicyCondition(esapi.IndexManagementPolicyConditionTypePollInterval, esapi.IndexManagementPolicyReasonMalformed).
 					withPolicyConditionMessage("The pollInterval is missing or requires a valid time unit (e.g. 3d)")
 			})
+			Context("warm phase", func() {
+				It("should spec a value", func() {
+					validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+						Name:         "warm",
+						PollInterval: "15m",
+						Phases: esapi.IndexManagementPhasesSpec{
+							Warm: &esapi.IndexManagementTierPhaseSpec{},
+						},
+					})
+					expectStatus(cluster).hasPolicy("warm").
+						withPolicyState(esapi.IndexManagementPolicyStateDropped).
+						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed).
+						withPolicyConditionMessage("The warm phase 'minAge' is missing or requires a valid time unit (e.g. 3d)")
+				})
+			})
+			Context("cold phase", func() {
+				It("should spec a value", func() {
+					validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+						Name:         "cold",
+						PollInterval: "15m",
+						Phases: esapi.IndexManagementPhasesSpec{
+							Cold: &esapi.IndexManagementTierPhaseSpec{},
+						},
+					})
+					expectStatus(cluster).hasPolicy("cold").
+						withPolicyState(esapi.IndexManagementPolicyStateDropped).
+						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed).
+						withPolicyConditionMessage("The cold phase 'minAge' is missing or requires a valid time unit (e.g. 3d)")
+				})
+			})
 			It("should spec an acceptible time unit", func() {
 				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
 					Name:         "foo",
@@ -123,6 +154,146 @@ var _ = Describe("Index Management", func() {
 					withPolicyConditionMessage("The hot phase 'maxAge' is missing or requires a valid time unit (e.g. 3d)")
 			})
 		})
+		Context("Rollover conditions", func() {
+			var rollover *esapi.IndexManagementActionSpec
+			BeforeEach(func() {
+				rollover = &esapi.IndexManagementActionSpec{
+					MaxAge: "3d",
+				}
+			})
+			validateRollover := func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Hot: &esapi.IndexManagementHotPhaseSpec{
+							Actions: esapi.IndexManagementActionsSpec{
+								Rollover: rollover,
+							},
+						},
+					},
+				})
+			}
+			It("should spec a valid maxSize", func() {
+				rollover.MaxSize = "50GB"
+				validateRollover()
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeRollover, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The hot phase 'maxSize' requires a valid size unit (e.g. 50gb)")
+			})
+			It("should spec a valid maxPrimaryShardSize", func() {
+				rollover.MaxPrimaryShardSize = "50"
+				validateRollover()
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeRollover, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The hot phase 'maxPrimaryShardSize' requires a valid size unit (e.g. 50gb)")
+			})
+			It("should spec a positive maxDocs", func() {
+				rollover.MaxDocs = -1
+				validateRollover()
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeRollover, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The hot phase 'maxDocs' must be a positive number")
+			})
+			It("should report the effective conditions", func() {
+				cluster.Spec.Nodes = []esapi.ElasticsearchNode{
+					{Roles: []esapi.ElasticsearchNodeRole{esapi.ElasticsearchRoleData}, NodeCount: 2},
+				}
+				rollover.MaxDocs = 1000
+				rollover.MaxPrimaryShardSize = "50gb"
+				validateRollover()
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateAccepted).
+					withRollover(esapi.IndexManagementRolloverConditionsStatus{
+						MaxAge:  "3d",
+						MaxSize: "100gb",
+						MaxDocs: 1000,
+					})
+			})
+			It("should calculate the conditions for the primary shards on the hot tier", func() {
+				cluster.Spec.Nodes = []esapi.ElasticsearchNode{
+					{Roles: []esapi.ElasticsearchNodeRole{esapi.ElasticsearchRoleData}, NodeCount: 2, Tier: esapi.ElasticsearchTierHot},
+					{Roles: []esapi.ElasticsearchNodeRole{esapi.ElasticsearchRoleData}, NodeCount: 3, Tier: esapi.ElasticsearchTierWarm},
+				}
+				rollover.MaxPrimaryShardSize = "50gb"
+				validateRollover()
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateAccepted).
+					withRollover(esapi.IndexManagementRolloverConditionsStatus{
+						MaxAge:  "3d",
+						MaxSize: "100gb",
+						MaxDocs: constants.TheoreticalShardMaxSizeInMB * 1000 * 2,
+					})
+			})
+		})
+		Context("Phase order", func() {
+			It("should require the cold phase to start after the warm phase", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Warm: &esapi.IndexManagementTierPhaseSpec{MinAge: "7d"},
+						Cold: &esapi.IndexManagementTierPhaseSpec{MinAge: "2d"},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypePhaseOrder, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The cold phase 'minAge' must be greater than the warm phase 'minAge'").
+					withPhase(esapi.IndexManagementPhaseWarm, esapi.IndexManagementPolicyStateAccepted).
+					withPhaseMessage(esapi.IndexManagementPhaseWarm, "").
+					withPhase(esapi.IndexManagementPhaseCold, esapi.IndexManagementPolicyStateDropped).
+					withPhaseMessage(esapi.IndexManagementPhaseCold, "The cold phase 'minAge' must be greater than the warm phase 'minAge'")
+			})
+			It("should require the delete phase to start after the cold phase", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Cold:   &esapi.IndexManagementTierPhaseSpec{MinAge: "7d"},
+						Delete: &esapi.IndexManagementDeletePhaseSpec{MinAge: "7d"},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypePhaseOrder, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The delete phase 'minAge' must be greater than the cold phase 'minAge'")
+			})
+		})
+		It("should record a status for each phase", func() {
+			validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+				Name:         "foo",
+				PollInterval: "15m",
+				Phases: esapi.IndexManagementPhasesSpec{
+					Warm:   &esapi.IndexManagementTierPhaseSpec{MinAge: "2d"},
+					Cold:   &esapi.IndexManagementTierPhaseSpec{MinAge: "5d"},
+					Delete: &esapi.IndexManagementDeletePhaseSpec{MinAge: "7d"},
+				},
+			})
+			expectStatus(cluster).hasPolicy("foo").
+				withPolicyState(esapi.IndexManagementPolicyStateAccepted).
+				withPhase(esapi.IndexManagementPhaseWarm, esapi.IndexManagementPolicyStateAccepted).
+				withPhase(esapi.IndexManagementPhaseCold, esapi.IndexManagementPolicyStateAccepted).
+				withPhase(esapi.IndexManagementPhaseDelete, esapi.IndexManagementPolicyStateAccepted)
+		})
+		It("should keep the phases of a policy dropped by its own conditions", func() {
+			validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+				Name:         "foo",
+				PollInterval: "15",
+				Phases: esapi.IndexManagementPhasesSpec{
+					Warm:   &esapi.IndexManagementTierPhaseSpec{MinAge: "2d"},
+					Delete: &esapi.IndexManagementDeletePhaseSpec{MinAge: "7d"},
+				},
+			})
+			expectStatus(cluster).hasPolicy("foo").
+				withPolicyState(esapi.IndexManagementPolicyStateDropped).
+				withPolicyCondition(esapi.IndexManagementPolicyConditionTypePollInterval, esapi.IndexManagementPolicyReasonMalformed).
+				withPhase(esapi.IndexManagementPhaseWarm, esapi.IndexManagementPolicyStateAccepted).
+				withPhase(esapi.IndexManagementPhaseDelete, esapi.IndexManagementPolicyStateAccepted)
+		})
 		It("should accept a valid policy", func() {
 			validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
 				Name:         "foo",
@@ -212,4 +212,22 @@ var _ = Describe("Index Management", func() {
 			})
 		})
 	})
+
+	Describe("#ValidateSpec", func() {
+		It("should accept a valid spec", func() {
+			Expect(ValidateSpec(cluster)).To(BeEmpty())
+		})
+		It("should reject malformed policies and mappings without changing the status", func() {
+			cluster.Spec.IndexManagement.Policies[0].PollInterval = "10"
+			cluster.Spec.IndexManagement.Mappings[0].PolicyRef = "undefined"
+
+			errs := ValidateSpec(cluster)
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("spec.indexManagement.policies[0]"))
+			Expect(errs[0].Detail).To(Equal(pollIntervalFailMessage))
+			Expect(errs[1].Field).To(Equal("spec.indexManagement.mappings[0]"))
+			Expect(errs[1].Detail).To(Equal(policyRefFailMessage))
+			Expect(cluster.Status.IndexManagementStatus).To(BeNil())
+		})
+	})
 })
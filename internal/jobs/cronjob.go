@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/cronjob"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/pod"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
+)
+
+const (
+	certsVolumeName = "certs"
+
+	jobHistoryLimitSuccess int32 = 1
+)
+
+var (
+	defaultCPURequest    = resource.MustParse("100m")
+	defaultMemoryRequest = resource.MustParse("32Mi")
+)
+
+// NewContainer returns the container running the operator subcommand with the args against
+// the cluster. The cluster secret is mounted at certsDir.
+func NewContainer(clusterName, name, command string, args []string, certsDir string, envvars []corev1.EnvVar) corev1.Container {
+	envvars = append(envvars, corev1.EnvVar{Name: "ES_SERVICE", Value: fmt.Sprintf("https://%s:9200", clusterName)})
+	return corev1.Container{
+		Name:            name,
+		Image:           constants.PackagedOperatorImage(),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: defaultMemoryRequest,
+				corev1.ResourceCPU:    defaultCPURequest,
+			},
+		},
+		Env:                    envvars,
+		Command:                []string{"elasticsearch-operator", command},
+		Args:                   args,
+		TerminationMessagePath: TerminationMessagePath,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: certsVolumeName, ReadOnly: true, MountPath: certsDir},
+		},
+		SecurityContext: utils.ContainerSecurityContext(),
+	}
+}
+
+// NewCronJob returns the cronjob running the container once at a time on the given schedule.
+// The failed jobs are kept up to failedJobsHistoryLimit to report their errors.
+func NewCronJob(clusterName, namespace, name, schedule string, labels, nodeSelector map[string]string, tolerations []corev1.Toleration, container corev1.Container, suspend bool, failedJobsHistoryLimit int32) *batch.CronJob {
+	volumes := []corev1.Volume{
+		{
+			Name: certsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: clusterName,
+				},
+			},
+		},
+	}
+
+	podSpec := pod.NewSpec(clusterName, []corev1.Container{container}, volumes).
+		WithNodeSelectors(nodeSelector).
+		WithTolerations(tolerations...).
+		WithRestartPolicy(corev1.RestartPolicyNever).
+		WithTerminationGracePeriodSeconds(300 * time.Second).
+		WithSecurityContext(utils.PodSecurityContext()).
+		Build()
+
+	return cronjob.New(name, namespace, labels).
+		WithSuspend(suspend).
+		WithConcurrencyPolicy(batch.ForbidConcurrent).
+		WithSuccessfulJobsHistoryLimit(jobHistoryLimitSuccess).
+		WithFailedJobsHistoryLimit(failedJobsHistoryLimit).
+		WithSchedule(schedule).
+		WithBackoffLimit(0).
+		WithParallelism(1).
+		WithPodSpec(container.Name, podSpec).
+		Build()
+}
+
+// AreCronJobsSame returns true if the cronjobs run the same containers on the same schedule
+// and nodes
+func AreCronJobsSame(lhs, rhs *batch.CronJob) bool {
+	if len(lhs.Spec.JobTemplate.Spec.Template.Spec.Containers) != len(rhs.Spec.JobTemplate.Spec.Template.Spec.Containers) {
+		return false
+	}
+	if !comparators.AreStringMapsSame(lhs.Spec.JobTemplate.Spec.Template.Spec.NodeSelector, rhs.Spec.JobTemplate.Spec.Template.Spec.NodeSelector) {
+		return false
+	}
+
+	if !comparators.AreTolerationsSame(lhs.Spec.JobTemplate.Spec.Template.Spec.Tolerations, rhs.Spec.JobTemplate.Spec.Template.Spec.Tolerations) {
+		return false
+	}
+	if lhs.Spec.Schedule != rhs.Spec.Schedule {
+		lhs.Spec.Schedule = rhs.Spec.Schedule
+		return false
+	}
+	if lhs.Spec.Suspend != nil && rhs.Spec.Suspend != nil && *lhs.Spec.Suspend != *rhs.Spec.Suspend {
+		return false
+	}
+	if lhs.Spec.FailedJobsHistoryLimit != nil && rhs.Spec.FailedJobsHistoryLimit != nil && *lhs.Spec.FailedJobsHistoryLimit != *rhs.Spec.FailedJobsHistoryLimit {
+		return false
+	}
+	for i, container := range lhs.Spec.JobTemplate.Spec.Template.Spec.Containers {
+		other := rhs.Spec.JobTemplate.Spec.Template.Spec.Containers[i]
+		if !areContainersSame(container, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func areContainersSame(container, other corev1.Container) bool {
+	if container.Name != other.Name {
+		return false
+	}
+	if container.Image != other.Image {
+		return false
+	}
+
+	if !reflect.DeepEqual(container.Command, other.Command) {
+		return false
+	}
+	if !reflect.DeepEqual(container.Args, other.Args) {
+		return false
+	}
+
+	if !comparators.AreResourceRequementsSame(container.Resources, other.Resources) {
+		return false
+	}
+
+	if !comparators.EnvValueEqual(container.Env, other.Env) {
+		return false
+	}
+	return true
+}
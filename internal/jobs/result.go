@@ -0,0 +1,50 @@
+// Package jobs holds the plumbing shared by the cronjobs running subcommands of the
+// operator binary against a cluster, i.e. index management and snapshots. A run reports
+// its result as termination message which the operator reads back into the status.
+package jobs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+const (
+	// TerminationMessagePath is where the result of a run is written to be reported
+	// by the operator in the status of the cluster
+	TerminationMessagePath = "/dev/termination-log"
+
+	// maxErrorLength keeps the result within the termination message size limit of 4096 bytes
+	maxErrorLength = 1024
+)
+
+// WriteResult writes the result of a run as termination message
+func WriteResult(path string, result interface{}) error {
+	message, err := json.Marshal(result)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to serialize the result")
+	}
+	if err := ioutil.WriteFile(path, message, 0o644); err != nil {
+		return kverrors.Wrap(err, "failed to write the result", "path", path)
+	}
+	return nil
+}
+
+// ParseResult reads the result from a termination message. It returns false if the
+// message is not a result, e.g. when the container was killed.
+func ParseResult(message string, result interface{}) bool {
+	if message == "" {
+		return false
+	}
+	return json.Unmarshal([]byte(message), result) == nil
+}
+
+// ErrorMessage returns the message of the error cut to fit into the termination message
+func ErrorMessage(err error) string {
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	return message
+}
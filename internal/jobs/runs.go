@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const jobNameLabel = "job-name"
+
+// Run is the outcome of a terminated container of a job pod
+type Run struct {
+	JobName  string
+	Finished metav1.Time
+	ExitCode int32
+	Reason   string
+	// Message is the termination message holding the result of the run
+	Message string
+}
+
+// Failed returns true if the container did not exit successfully
+func (run Run) Failed() bool {
+	return run.ExitCode != 0
+}
+
+// FinishedRuns returns the runs of the terminated containers with the given name of the
+// job pods, newest first
+func FinishedRuns(pods []corev1.Pod, containerName string) []Run {
+	var runs []Run
+	for _, p := range pods {
+		jobName := p.Labels[jobNameLabel]
+		if jobName == "" {
+			continue
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			terminated := cs.State.Terminated
+			if cs.Name != containerName || terminated == nil {
+				continue
+			}
+			runs = append(runs, Run{
+				JobName:  jobName,
+				Finished: terminated.FinishedAt,
+				ExitCode: terminated.ExitCode,
+				Reason:   terminated.Reason,
+				Message:  terminated.Message,
+			})
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[j].Finished.Before(&runs[i].Finished)
+	})
+	return runs
+}
@@ -0,0 +1,196 @@
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	kibana "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	elasticsearchNotFoundReason = "NotFound"
+	elasticsearchNotReadyReason = "NotReady"
+	crossNamespaceReason        = "CrossNamespaceReference"
+)
+
+// ElasticsearchKey returns the namespaced name of the Elasticsearch cluster referenced
+// by the Kibana. It is false if the Kibana has no reference.
+func ElasticsearchKey(kibanaCR *kibana.Kibana) (types.NamespacedName, bool) {
+	ref := kibanaCR.Spec.ElasticsearchRef
+	if ref == nil {
+		return types.NamespacedName{}, false
+	}
+
+	key := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+	if key.Namespace == "" {
+		key.Namespace = kibanaCR.Namespace
+	}
+	return key, true
+}
+
+// GetElasticsearchCR returns the Elasticsearch cluster referenced by the Kibana or, without
+// a reference, the one found in the namespace of the Kibana
+func GetElasticsearchCR(c client.Client, kibanaCR *kibana.Kibana) (*kibana.Elasticsearch, error) {
+	key, ok := ElasticsearchKey(kibanaCR)
+	if !ok {
+		return elasticsearch.GetElasticsearchCR(c, kibanaCR.Namespace)
+	}
+
+	es := &kibana.Elasticsearch{}
+	if err := c.Get(context.TODO(), key, es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, kverrors.Wrap(err, "unable to get elasticsearch instance",
+			"name", key.Name,
+			"namespace", key.Namespace,
+		)
+	}
+	return es, nil
+}
+
+// isElasticsearchReady returns true if the cluster health is green or yellow
+func isElasticsearchReady(es *kibana.Elasticsearch) bool {
+	switch es.Status.Cluster.Status {
+	case "green", "yellow":
+		return true
+	}
+	return false
+}
+
+// certManagementRequested returns true if the Elasticsearch cluster has the annotation
+// logging.openshift.io/elasticsearch-cert-management: true
+func certManagementRequested(es *kibana.Elasticsearch) bool {
+	value, ok := es.Annotations[constants.EOCertManagementLabel]
+	if !ok {
+		return false
+	}
+	manageBool, _ := strconv.ParseBool(value)
+	return manageBool
+}
+
+// ManagesCertificates returns true if the certificates of the Kibana are generated by the
+// operator. The certificates are owned by the Elasticsearch CR, so they can only be managed
+// for a Kibana in the same namespace.
+func ManagesCertificates(kibanaCR *kibana.Kibana, es *kibana.Elasticsearch) bool {
+	return certManagementRequested(es) && es.Namespace == kibanaCR.Namespace
+}
+
+// elasticsearchConditions returns the conditions of the Elasticsearch cluster used by the
+// Kibana. A nil cluster is a missing one.
+func elasticsearchConditions(kibanaCR *kibana.Kibana, es *kibana.Elasticsearch) kibana.ClusterConditions {
+	name := "in namespace " + kibanaCR.Namespace
+	if key, ok := ElasticsearchKey(kibanaCR); ok {
+		name = key.String()
+	}
+
+	if es == nil {
+		return kibana.ClusterConditions{
+			{
+				Type:    kibana.ElasticsearchUnavailable,
+				Status:  v1.ConditionTrue,
+				Reason:  elasticsearchNotFoundReason,
+				Message: fmt.Sprintf("Elasticsearch cluster %s not found", name),
+			},
+		}
+	}
+
+	var conditions kibana.ClusterConditions
+	if !isElasticsearchReady(es) {
+		conditions = append(conditions, kibana.ClusterCondition{
+			Type:    kibana.ElasticsearchUnavailable,
+			Status:  v1.ConditionTrue,
+			Reason:  elasticsearchNotReadyReason,
+			Message: fmt.Sprintf("Elasticsearch cluster %s/%s is not ready: %s", es.Namespace, es.Name, es.Status.Cluster.Status),
+		})
+	}
+	if certManagementRequested(es) && !ManagesCertificates(kibanaCR, es) {
+		conditions = append(conditions, kibana.ClusterCondition{
+			Type:   kibana.CertManagementDisabled,
+			Status: v1.ConditionTrue,
+			Reason: crossNamespaceReason,
+			Message: fmt.Sprintf("Certificates are not managed for Elasticsearch cluster %s/%s in another namespace, "+
+				"the Kibana certificates must be provided in namespace %s", es.Namespace, es.Name, kibanaCR.Namespace),
+		})
+	}
+	return conditions
+}
+
+// UpdateElasticsearchStatus reports the state of the Elasticsearch cluster used by the
+// Kibana in its status. A nil cluster is a missing one.
+func UpdateElasticsearchStatus(c client.Client, kibanaCR *kibana.Kibana, es *kibana.Elasticsearch) error {
+	desired := elasticsearchConditions(kibanaCR, es)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &kibana.Kibana{}
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(kibanaCR), current); err != nil {
+			return err
+		}
+
+		if !setElasticsearchConditions(current, desired) {
+			return nil
+		}
+		if err := c.Status().Update(context.TODO(), current); err != nil {
+			return err
+		}
+		kibanaCR.Status = current.Status
+		kibanaCR.ResourceVersion = current.ResourceVersion
+		return nil
+	})
+}
+
+// setElasticsearchConditions sets the conditions on each status of the Kibana and returns
+// true if they changed. The transition time of an unchanged condition is kept.
+func setElasticsearchConditions(kibanaCR *kibana.Kibana, conditions kibana.ClusterConditions) bool {
+	if len(kibanaCR.Status) == 0 {
+		if len(conditions) == 0 {
+			return false
+		}
+		kibanaCR.Status = []kibana.KibanaStatus{{}}
+	}
+
+	changed := false
+	for i := range kibanaCR.Status {
+		status := &kibanaCR.Status[i]
+		desired := make(kibana.ClusterConditions, 0, len(conditions))
+		for _, condition := range conditions {
+			condition.LastTransitionTime = metav1.Now()
+			for _, existing := range status.ElasticsearchConditions {
+				if existing.Type == condition.Type && existing.Status == condition.Status {
+					condition.LastTransitionTime = existing.LastTransitionTime
+				}
+			}
+			desired = append(desired, condition)
+		}
+
+		if len(desired) == 0 && len(status.ElasticsearchConditions) == 0 {
+			continue
+		}
+		if reflect.DeepEqual(desired, status.ElasticsearchConditions) {
+			continue
+		}
+		status.ElasticsearchConditions = desired
+		changed = true
+	}
+	return changed
+}
+
+// elasticsearchHost returns the service host of the Elasticsearch cluster used by the Kibana
+func elasticsearchHost(kibanaCR *kibana.Kibana, clusterName string) string {
+	namespace := kibanaCR.Namespace
+	if key, ok := ElasticsearchKey(kibanaCR); ok {
+		namespace = key.Namespace
+	}
+	return fmt.Sprintf("%s.%s.svc", clusterName, namespace)
+}
@@ -0,0 +1,136 @@
+package kibana
+
+import (
+	"context"
+	"testing"
+
+	kibana "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetReferencedElasticsearchCR(t *testing.T) {
+	_ = kibana.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	local := &kibana.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "test-namespace"},
+	}
+	remote := &kibana.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: "other-namespace"},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(local, remote).Build()
+
+	kibanaCR := &kibana.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Name: "kibana", Namespace: "test-namespace"},
+	}
+	es, err := GetElasticsearchCR(k8sClient, kibanaCR)
+	if err != nil || es.Name != "elasticsearch" {
+		t.Errorf("Exp. the elasticsearch CR of the namespace without reference but got %v, %v", es, err)
+	}
+
+	kibanaCR.Spec.ElasticsearchRef = &kibana.ElasticsearchReference{Name: "logs", Namespace: "other-namespace"}
+	es, err = GetElasticsearchCR(k8sClient, kibanaCR)
+	if err != nil || es.Name != "logs" || es.Namespace != "other-namespace" {
+		t.Errorf("Exp. the referenced elasticsearch CR but got %v, %v", es, err)
+	}
+	if host := elasticsearchHost(kibanaCR, es.Name); host != "logs.other-namespace.svc" {
+		t.Errorf("Exp. the elasticsearch host to be logs.other-namespace.svc but was %s", host)
+	}
+
+	kibanaCR.Spec.ElasticsearchRef = &kibana.ElasticsearchReference{Name: "logs"}
+	if _, err = GetElasticsearchCR(k8sClient, kibanaCR); err == nil {
+		t.Errorf("Exp. the referenced elasticsearch CR in the namespace of the kibana to be missing")
+	}
+}
+
+func TestUpdateElasticsearchStatus(t *testing.T) {
+	_ = kibana.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	kibanaCR := &kibana.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Name: "kibana", Namespace: "test-namespace"},
+		Spec: kibana.KibanaSpec{
+			ElasticsearchRef: &kibana.ElasticsearchReference{Name: "logs"},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(kibanaCR.DeepCopy()).Build()
+
+	getCondition := func() *kibana.ClusterCondition {
+		current := &kibana.Kibana{}
+		if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(kibanaCR), current); err != nil {
+			t.Fatalf("Failed to get kibana: %v", err)
+		}
+		if len(current.Status) == 0 || len(current.Status[0].ElasticsearchConditions) == 0 {
+			return nil
+		}
+		return &current.Status[0].ElasticsearchConditions[0]
+	}
+
+	if err := UpdateElasticsearchStatus(k8sClient, kibanaCR, nil); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	condition := getCondition()
+	if condition == nil || condition.Type != kibana.ElasticsearchUnavailable || condition.Status != v1.ConditionTrue || condition.Reason != elasticsearchNotFoundReason {
+		t.Fatalf("Exp. the %s condition with reason %s but got %v", kibana.ElasticsearchUnavailable, elasticsearchNotFoundReason, condition)
+	}
+
+	es := &kibana.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: "test-namespace"},
+		Status: kibana.ElasticsearchStatus{
+			Cluster: kibana.ClusterHealth{Status: "red"},
+		},
+	}
+	if err := UpdateElasticsearchStatus(k8sClient, kibanaCR, es); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	condition = getCondition()
+	if condition == nil || condition.Reason != elasticsearchNotReadyReason {
+		t.Fatalf("Exp. the %s condition with reason %s but got %v", kibana.ElasticsearchUnavailable, elasticsearchNotReadyReason, condition)
+	}
+
+	es.Status.Cluster.Status = "green"
+	if err := UpdateElasticsearchStatus(k8sClient, kibanaCR, es); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	if condition = getCondition(); condition != nil {
+		t.Errorf("Exp. no condition for a ready elasticsearch cluster but got %v", condition)
+	}
+}
+
+func TestCrossNamespaceCertManagementCondition(t *testing.T) {
+	kibanaCR := &kibana.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Name: "kibana", Namespace: "test-namespace"},
+		Spec: kibana.KibanaSpec{
+			ElasticsearchRef: &kibana.ElasticsearchReference{Name: "logs", Namespace: "other-namespace"},
+		},
+	}
+	es := &kibana.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "logs",
+			Namespace:   "other-namespace",
+			Annotations: map[string]string{constants.EOCertManagementLabel: "true"},
+		},
+		Status: kibana.ElasticsearchStatus{
+			Cluster: kibana.ClusterHealth{Status: "green"},
+		},
+	}
+
+	if ManagesCertificates(kibanaCR, es) {
+		t.Errorf("Exp. the certificates not to be managed for a cross-namespace reference")
+	}
+	conditions := elasticsearchConditions(kibanaCR, es)
+	if len(conditions) != 1 || conditions[0].Type != kibana.CertManagementDisabled || conditions[0].Reason != crossNamespaceReason {
+		t.Errorf("Exp. the %s condition with reason %s but got %v", kibana.CertManagementDisabled, crossNamespaceReason, conditions)
+	}
+
+	kibanaCR.Namespace = "other-namespace"
+	if !ManagesCertificates(kibanaCR, es) {
+		t.Errorf("Exp. the certificates to be managed in the namespace of the elasticsearch cluster")
+	}
+	if conditions := elasticsearchConditions(kibanaCR, es); len(conditions) != 0 {
+		t.Errorf("Exp. no condition for a managed kibana but got %v", conditions)
+	}
+}
@@ -204,7 +204,7 @@ var _ = Describe("Reconciling", func() {
 			})
 
 			It("should create one new console link for the Kibana route", func() {
-				Expect(Reconcile(logger, cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(logger, cluster, client, esClient, proxy, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: KibanaConsoleLinkName}
 				got := &consolev1.ConsoleLink{}
@@ -261,7 +261,7 @@ var _ = Describe("Reconciling", func() {
 
 			It("should use the default CA bundle in kibana proxy", func() {
 				// Reconcile w/o custom CA bundle
-				Expect(Reconcile(logger, cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(logger, cluster, client, esClient, proxy, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: constants.KibanaTrustedCAName, Namespace: cluster.GetNamespace()}
 				kibanaCaBundle := &corev1.ConfigMap{}
@@ -282,7 +282,7 @@ var _ = Describe("Reconciling", func() {
 
 			It("should use the injected custom CA bundle in kibana proxy", func() {
 				// Reconcile w/o custom CA bundle
-				Expect(Reconcile(logger, cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(logger, cluster, client, esClient, proxy, nil, nil)).Should(Succeed())
 
 				// Inject custom CA bundle into kibana config map
 				injectedCABundle := kibanaCABundle.DeepCopy()
@@ -291,7 +291,7 @@ var _ = Describe("Reconciling", func() {
 
 				// Reconcile with injected custom CA bundle
 				esClient = newFakeEsClient(client, fakeResponses)
-				Expect(Reconcile(logger, cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(logger, cluster, client, esClient, proxy, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: cluster.GetName(), Namespace: cluster.GetNamespace()}
 				dpl := &appsv1.Deployment{}
@@ -305,7 +305,7 @@ var _ = Describe("Reconciling", func() {
 			})
 
 			It("should create a deployment with the source kibana proxy image", func() {
-				Expect(Reconcile(logger, cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(logger, cluster, client, esClient, proxy, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: "kibana", Namespace: cluster.GetNamespace()}
 				depl := &appsv1.Deployment{}
@@ -325,7 +325,7 @@ var _ = Describe("Reconciling", func() {
 				)
 				esClient = newFakeEsClient(client, fakeResponses)
 
-				Expect(Reconcile(logger, cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(logger, cluster, client, esClient, proxy, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: "kibana", Namespace: cluster.GetNamespace()}
 				depl := &appsv1.Deployment{}
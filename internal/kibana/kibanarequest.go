@@ -17,6 +17,8 @@ type KibanaRequest struct {
 	client   client.Client
 	cluster  *kibana.Kibana
 	esClient esclient.Client
+	// the realms of the cluster users log in to Kibana with
+	authentication *kibana.ElasticsearchAuthenticationSpec
 }
 
 // TODO: determine if this is even necessary
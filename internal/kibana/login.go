@@ -0,0 +1,66 @@
+package kibana
+
+import (
+	kibana "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	securityAuthTypeSetting     = "opendistro_security.auth.type"
+	openIDConnectURLSetting     = "opendistro_security.openid.connect_url"
+	openIDClientIDSetting       = "opendistro_security.openid.client_id"
+	openIDClientSecretSetting   = "opendistro_security.openid.client_secret"
+	openShiftProxySkipAuthRegex = "^/api/status$"
+	// the security plugin of Kibana logs the users in itself
+	kibanaLoginProxySkipAuthRegex = "^/"
+)
+
+// kibanaLogin returns the way users log in to Kibana with the realms of the cluster
+func kibanaLogin(spec *kibana.ElasticsearchAuthenticationSpec) kibana.KibanaLoginType {
+	if spec == nil || spec.KibanaLogin == "" {
+		return kibana.KibanaLoginOpenShift
+	}
+	return spec.KibanaLogin
+}
+
+// loginEnvVars returns the settings of the security plugin of Kibana logging the users in
+// with the realms of the cluster. The image passes variables named after Kibana settings
+// on to kibana.yml.
+func loginEnvVars(spec *kibana.ElasticsearchAuthenticationSpec) []v1.EnvVar {
+	switch kibanaLogin(spec) {
+	case kibana.KibanaLoginBasic:
+		return []v1.EnvVar{
+			{Name: securityAuthTypeSetting, Value: "basicauth"},
+		}
+	case kibana.KibanaLoginOpenID:
+		if spec.OIDC == nil {
+			return nil
+		}
+		env := []v1.EnvVar{
+			{Name: securityAuthTypeSetting, Value: "openid"},
+			{Name: openIDConnectURLSetting, Value: spec.OIDC.ConnectURL},
+			{Name: openIDClientIDSetting, Value: spec.OIDC.ClientID},
+		}
+		if spec.OIDC.ClientSecret != nil {
+			env = append(env, v1.EnvVar{
+				Name: openIDClientSecretSetting,
+				ValueFrom: &v1.EnvVarSource{
+					SecretKeyRef: &v1.SecretKeySelector{
+						LocalObjectReference: *spec.OIDC.ClientSecret,
+						Key:                  kibana.OIDCClientSecretKey,
+					},
+				},
+			})
+		}
+		return env
+	}
+	return nil
+}
+
+// proxySkipAuthRegex returns the paths the proxy passes on to Kibana without the OpenShift login
+func proxySkipAuthRegex(spec *kibana.ElasticsearchAuthenticationSpec) string {
+	if kibanaLogin(spec) == kibana.KibanaLoginOpenShift {
+		return openShiftProxySkipAuthRegex
+	}
+	return kibanaLoginProxySkipAuthRegex
+}
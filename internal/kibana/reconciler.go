@@ -44,21 +44,21 @@ var kibanaServiceAccountAnnotations = map[string]string{
 	"serviceaccounts.openshift.io/oauth-redirectreference.first": kibanaOAuthRedirectReference,
 }
 
-func Reconcile(log logr.Logger, requestCluster *kibana.Kibana, requestClient client.Client, esClient esclient.Client, proxyConfig *configv1.Proxy, eoManagedCerts bool, ownerRef metav1.OwnerReference) error {
+func Reconcile(log logr.Logger, requestCluster *kibana.Kibana, requestClient client.Client, esClient esclient.Client, proxyConfig *configv1.Proxy, certProvider elasticsearch.CertificateProvider, authentication *kibana.ElasticsearchAuthenticationSpec) error {
 	clusterKibanaRequest := KibanaRequest{
-		log:      log,
-		client:   requestClient,
-		cluster:  requestCluster,
-		esClient: esClient,
+		log:            log,
+		client:         requestClient,
+		cluster:        requestCluster,
+		esClient:       esClient,
+		authentication: authentication,
 	}
 
 	if clusterKibanaRequest.cluster == nil {
 		return nil
 	}
 
-	if eoManagedCerts {
-		cr := elasticsearch.NewCertificateRequest(log, ownerRef.Name, requestCluster.Namespace, ownerRef, requestClient)
-		cr.GenerateKibanaCerts(requestCluster.Name)
+	if certProvider != nil {
+		certProvider.GenerateKibanaCerts(requestCluster.Name)
 	}
 
 	// ensure that we have the certs pulled in from the secret first... required for route generation
@@ -161,6 +161,10 @@ func compareKibanaStatus(lhs, rhs []kibana.KibanaStatus) bool {
 					return false
 				}
 			}
+
+			if !reflect.DeepEqual(lhs[index].ElasticsearchConditions, rhs[index].ElasticsearchConditions) {
+				return false
+			}
 		}
 	}
 
@@ -230,7 +234,7 @@ func (clusterRequest *KibanaRequest) createOrUpdateKibanaDeployment(proxyConfig
 
 	kibanaPodSpec := newKibanaPodSpec(
 		clusterRequest,
-		fmt.Sprintf("%s.%s.svc", clusterName, clusterRequest.cluster.Namespace),
+		elasticsearchHost(clusterRequest.cluster, clusterName),
 		proxyConfig,
 		kibanaTrustBundle,
 		oauthProxyImage,
@@ -543,6 +547,7 @@ func newKibanaPodSpec(cluster *KibanaRequest, elasticsearchName string, proxyCon
 			},
 		},
 	}
+	kibanaContainer.Env = append(kibanaContainer.Env, loginEnvVars(cluster.authentication)...)
 
 	kibanaContainer.VolumeMounts = []v1.VolumeMount{
 		{Name: "kibana", ReadOnly: true, MountPath: "/etc/kibana/keys"},
@@ -586,7 +591,7 @@ func newKibanaPodSpec(cluster *KibanaRequest, elasticsearchName string, proxyCon
 		"-cookie-secret-file=/secret/session-secret",
 		"-cookie-expire=24h",
 		"-skip-provider-button",
-		"-skip-auth-regex=^/api/status$",
+		fmt.Sprintf("-skip-auth-regex=%s", proxySkipAuthRegex(cluster.authentication)),
 		"-upstream=http://localhost:5601",
 		"-scope=user:info user:check-access user:list-projects",
 		"--tls-cert=/secret/server-cert",
@@ -43,6 +43,46 @@ func TestNewKibanaPodSpecSetsProxyToUseServiceAccountAsOAuthClient(t *testing.T)
 	}
 }
 
+func TestNewKibanaPodSpecWithOpenIDLogin(t *testing.T) {
+	cluster := &KibanaRequest{
+		cluster: &kibana.Kibana{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+			},
+		},
+		authentication: &kibana.ElasticsearchAuthenticationSpec{
+			OIDC: &kibana.OIDCRealmSpec{
+				ConnectURL:   "https://idp.example.com/.well-known/openid-configuration",
+				ClientID:     "kibana",
+				ClientSecret: &v1.LocalObjectReference{Name: "kibana-oidc"},
+			},
+			KibanaLogin: kibana.KibanaLoginOpenID,
+		},
+	}
+	spec := newKibanaPodSpec(cluster, "kibana", nil, nil, "")
+
+	env := map[string]v1.EnvVar{}
+	for _, e := range spec.Containers[0].Env {
+		env[e.Name] = e
+	}
+	if env[securityAuthTypeSetting].Value != "openid" || env[openIDClientIDSetting].Value != "kibana" {
+		t.Errorf("Exp. the OpenID login settings in the kibana container but got %v", spec.Containers[0].Env)
+	}
+	secretRef := env[openIDClientSecretSetting].ValueFrom
+	if secretRef == nil || secretRef.SecretKeyRef == nil || secretRef.SecretKeyRef.Name != "kibana-oidc" || secretRef.SecretKeyRef.Key != kibana.OIDCClientSecretKey {
+		t.Errorf("Exp. the client secret to be read from the secret but got %v", env[openIDClientSecretSetting])
+	}
+	if !utils.Contains(spec.Containers[1].Args, "-skip-auth-regex=^/") {
+		t.Errorf("Exp. the proxy to pass all requests on to the Kibana login but got %v", spec.Containers[1].Args)
+	}
+
+	cluster.authentication = nil
+	spec = newKibanaPodSpec(cluster, "kibana", nil, nil, "")
+	if !utils.Contains(spec.Containers[1].Args, "-skip-auth-regex=^/api/status$") {
+		t.Errorf("Exp. the OpenShift login of the proxy but got %v", spec.Containers[1].Args)
+	}
+}
+
 func TestNewKibanaPodSpecWhenFieldsAreUndefined(t *testing.T) {
 	cluster := &KibanaRequest{
 		cluster: &kibana.Kibana{
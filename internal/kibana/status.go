@@ -20,10 +20,17 @@ func (clusterRequest *KibanaRequest) getKibanaStatus() ([]kibana.KibanaStatus, e
 		return status, err
 	}
 
+	// the conditions of the Elasticsearch cluster are set by UpdateElasticsearchStatus
+	var esConditions kibana.ClusterConditions
+	if len(clusterRequest.cluster.Status) > 0 {
+		esConditions = clusterRequest.cluster.Status[0].ElasticsearchConditions
+	}
+
 	for _, dpl := range kibanaDeploymentList {
 		kibanaStatus := kibana.KibanaStatus{
-			Deployment: dpl.Name,
-			Replicas:   *dpl.Spec.Replicas,
+			Deployment:              dpl.Name,
+			Replicas:                *dpl.Spec.Replicas,
+			ElasticsearchConditions: esConditions,
 		}
 
 		replicaSetList, _ := deployment.ListReplicaSets(context.TODO(), clusterRequest.client, dpl.Name, dpl.Namespace, selector)
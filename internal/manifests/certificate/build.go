@@ -0,0 +1,93 @@
+package certificate
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionKind is the kind of the cert-manager certificates
+var GroupVersionKind = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// Builder represents the struct to build cert-manager certificates. The certificates are
+// unstructured to not depend on the cert-manager API.
+type Builder struct {
+	c *unstructured.Unstructured
+}
+
+// New returns a new Builder for a cert-manager certificate issued to the secret
+func New(name, namespace, secretName, commonName string) *Builder {
+	c := newUnstructured(name, namespace)
+	_ = unstructured.SetNestedField(c.Object, secretName, "spec", "secretName")
+	_ = unstructured.SetNestedField(c.Object, commonName, "spec", "commonName")
+	return &Builder{c: c}
+}
+
+func newUnstructured(name, namespace string) *unstructured.Unstructured {
+	c := &unstructured.Unstructured{}
+	c.SetGroupVersionKind(GroupVersionKind)
+	c.SetName(name)
+	c.SetNamespace(namespace)
+	return c
+}
+
+// Build returns the final certificate object
+func (b *Builder) Build() *unstructured.Unstructured { return b.c }
+
+// WithSubject sets the organizations and organizational units of the subject
+func (b *Builder) WithSubject(organizations, organizationalUnits []string) *Builder {
+	_ = unstructured.SetNestedStringSlice(b.c.Object, organizations, "spec", "subject", "organizations")
+	_ = unstructured.SetNestedStringSlice(b.c.Object, organizationalUnits, "spec", "subject", "organizationalUnits")
+	return b
+}
+
+// WithDNSNames sets the DNS names of the subject alternative names
+func (b *Builder) WithDNSNames(names []string) *Builder {
+	if len(names) > 0 {
+		_ = unstructured.SetNestedStringSlice(b.c.Object, names, "spec", "dnsNames")
+	}
+	return b
+}
+
+// WithIPAddresses sets the IP addresses of the subject alternative names
+func (b *Builder) WithIPAddresses(ips []string) *Builder {
+	if len(ips) > 0 {
+		_ = unstructured.SetNestedStringSlice(b.c.Object, ips, "spec", "ipAddresses")
+	}
+	return b
+}
+
+// WithUsages sets the key usages of the certificate
+func (b *Builder) WithUsages(usages ...string) *Builder {
+	_ = unstructured.SetNestedStringSlice(b.c.Object, usages, "spec", "usages")
+	return b
+}
+
+// WithIssuerRef sets the issuer signing the certificate
+func (b *Builder) WithIssuerRef(name, kind, group string) *Builder {
+	_ = unstructured.SetNestedStringMap(b.c.Object, map[string]string{
+		"name":  name,
+		"kind":  kind,
+		"group": group,
+	}, "spec", "issuerRef")
+	return b
+}
+
+// WithDuration sets the lifetime of the certificate and how long before its expiry it is renewed
+func (b *Builder) WithDuration(duration, renewBefore time.Duration) *Builder {
+	_ = unstructured.SetNestedField(b.c.Object, duration.String(), "spec", "duration")
+	_ = unstructured.SetNestedField(b.c.Object, renewBefore.String(), "spec", "renewBefore")
+	return b
+}
+
+// WithPrivateKey sets the algorithm and size of the private key, which is regenerated on
+// every renewal
+func (b *Builder) WithPrivateKey(algorithm string, size int) *Builder {
+	_ = unstructured.SetNestedMap(b.c.Object, map[string]interface{}{
+		"algorithm":      algorithm,
+		"size":           int64(size),
+		"rotationPolicy": "Always",
+	}, "spec", "privateKey")
+	return b
+}
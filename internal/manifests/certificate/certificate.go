@@ -0,0 +1,101 @@
+package certificate
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EqualityFunc is the type for functions that compare two certificates.
+// Return true if two certificates are equal.
+type EqualityFunc func(current, desired *unstructured.Unstructured) bool
+
+// MutateFunc is the type for functions that mutate the current certificate
+// by applying the values from the desired certificate.
+type MutateFunc func(current, desired *unstructured.Unstructured)
+
+// Get returns the cert-manager certificate for the given object key or an error.
+func Get(ctx context.Context, c client.Client, key client.ObjectKey) (*unstructured.Unstructured, error) {
+	cert := newUnstructured(key.Name, key.Namespace)
+
+	if err := c.Get(ctx, key, cert); err != nil {
+		return cert, kverrors.Wrap(err, "failed to get certificate",
+			"name", cert.GetName(),
+			"namespace", cert.GetNamespace(),
+		)
+	}
+
+	return cert, nil
+}
+
+// CreateOrUpdate attempts first to get the given certificate. If the
+// certificate does not exist, the certificate will be created. Otherwise,
+// if the certificate exists and the provided comparison func detects any changes
+// an update is attempted. Updates are retried with backoff (See retry.DefaultRetry).
+// Returns on failure an non-nil error.
+func CreateOrUpdate(ctx context.Context, c client.Client, cert *unstructured.Unstructured, equal EqualityFunc, mutate MutateFunc) error {
+	current := newUnstructured(cert.GetName(), cert.GetNamespace())
+	key := client.ObjectKey{Name: cert.GetName(), Namespace: cert.GetNamespace()}
+	err := c.Get(ctx, key, current)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			err = c.Create(ctx, cert)
+
+			if err == nil {
+				return nil
+			}
+
+			return kverrors.Wrap(err, "failed to create certificate",
+				"name", cert.GetName(),
+				"namespace", cert.GetNamespace(),
+			)
+		}
+
+		return kverrors.Wrap(err, "failed to get certificate",
+			"name", cert.GetName(),
+			"namespace", cert.GetNamespace(),
+		)
+	}
+
+	if !equal(current, cert) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := c.Get(ctx, key, current); err != nil {
+				return kverrors.Wrap(err, "failed to get certificate",
+					"name", cert.GetName(),
+					"namespace", cert.GetNamespace(),
+				)
+			}
+
+			mutate(current, cert)
+			if err := c.Update(ctx, current); err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return kverrors.Wrap(err, "failed to update certificate",
+				"name", cert.GetName(),
+				"namespace", cert.GetNamespace(),
+			)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// SpecEqual returns true only if the certificates have equal specs.
+func SpecEqual(current, desired *unstructured.Unstructured) bool {
+	return equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"])
+}
+
+// MutateSpecOnly is a default mutate func implementation that copies
+// only the spec from desired to current certificate.
+func MutateSpecOnly(current, desired *unstructured.Unstructured) {
+	current.Object["spec"] = desired.Object["spec"]
+}
@@ -86,6 +86,20 @@ func MutateLabelsOnly(current, desired *corev1.PersistentVolumeClaim) {
 	current.Labels = desired.Labels
 }
 
+// StorageRequestEqual return only true if the pvcs request the same storage size.
+func StorageRequestEqual(current, desired *corev1.PersistentVolumeClaim) bool {
+	return current.Spec.Resources.Requests.Storage().Equal(*desired.Spec.Resources.Requests.Storage())
+}
+
+// MutateStorageRequest is a mutate function implementation
+// that copies only the requested storage size from desired to current persistentvolumeclaim.
+func MutateStorageRequest(current, desired *corev1.PersistentVolumeClaim) {
+	if current.Spec.Resources.Requests == nil {
+		current.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	current.Spec.Resources.Requests[corev1.ResourceStorage] = *desired.Spec.Resources.Requests.Storage()
+}
+
 // List returns a list of pods that match the given selector.
 func ListPVC(ctx context.Context, c client.Client, namespace string, selector map[string]string) ([]corev1.PersistentVolumeClaim, error) {
 	list := &corev1.PersistentVolumeClaimList{}
@@ -101,3 +115,18 @@ func ListPVC(ctx context.Context, c client.Client, namespace string, selector ma
 
 	return list.Items, nil
 }
+
+// DeletePVC attempts to delete the persistentvolumeclaim with the given key.
+// Returns on failure an non-nil error.
+func DeletePVC(ctx context.Context, c client.Client, key client.ObjectKey) error {
+	pvc := NewPVC(key.Name, key.Namespace, nil)
+
+	if err := c.Delete(ctx, pvc, &client.DeleteOptions{}); err != nil {
+		return kverrors.Wrap(err, "failed to delete persistentvolumeclaim",
+			"name", key.Name,
+			"namespace", key.Namespace,
+		)
+	}
+
+	return nil
+}
@@ -49,6 +49,12 @@ func (b *Builder) WithAffinity(a *corev1.Affinity) *Builder {
 	return b
 }
 
+// WithTopologySpreadConstraints appends topology spread constraints to the podspec
+func (b *Builder) WithTopologySpreadConstraints(c ...corev1.TopologySpreadConstraint) *Builder {
+	b.spec.TopologySpreadConstraints = append(b.spec.TopologySpreadConstraints, c...)
+	return b
+}
+
 // WithRestartPolicy sets the restart policy for the podspec
 func (b *Builder) WithRestartPolicy(rp corev1.RestartPolicy) *Builder {
 	b.spec.RestartPolicy = rp
@@ -2,6 +2,8 @@ package metrics
 
 import (
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -74,8 +76,44 @@ var (
 			Help: "Number of nodes with misconfigured memory resources",
 		},
 	)
+
+	certificateExpiryMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_es_certificate_expiry_timestamp_seconds",
+			Help: "Expiry of the certificates managed by the operator in seconds since the epoch",
+		}, []string{"namespace", "cluster", "secret", "component", "cn"},
+	)
+
+	certificateRotationMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_es_certificate_rotation_timestamp_seconds",
+			Help: "Start of the rotation lead time of the certificates managed by the operator in seconds since the epoch",
+		}, []string{"namespace", "cluster", "secret", "component", "cn"},
+	)
+
+	certificateFailureMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "eo_es_certificate_generation_failures_total",
+			Help: "Number of times the certificates of a secret failed to be generated",
+		}, []string{"namespace", "cluster", "secret", "component"},
+	)
+
+	// certificateSeries and certificateFailureSeries are the labels of the certificate series
+	// set per cluster, to delete the series of the certificates no longer managed
+	certificateSeries        = map[string]map[string]prometheus.Labels{}
+	certificateFailureSeries = map[string]map[string]prometheus.Labels{}
+	certificateSeriesMux     sync.Mutex
 )
 
+// CertificateExpiry is the expiry of a certificate managed by the operator
+type CertificateExpiry struct {
+	Secret       string
+	Component    string
+	CommonName   string
+	NotAfter     time.Time
+	RotationTime time.Time
+}
+
 // This function registers the custom metrics to the kubernetes controller-runtime default metrics.
 func RegisterCustomMetrics() {
 	metricCollectors := []prometheus.Collector{
@@ -86,6 +124,9 @@ func RegisterCustomMetrics() {
 		documentAgeMetric,
 		deleteNamespaceMetric,
 		memoryConfigurationMetric,
+		certificateExpiryMetric,
+		certificateRotationMetric,
+		certificateFailureMetric,
 	}
 
 	for _, metric := range metricCollectors {
@@ -125,6 +166,75 @@ func IncrementRestartCounterScheduled() {
 	}).Inc()
 }
 
+// Sets the metric values with the expiry and rotation time of the certificates of a cluster.
+// The series of the certificates no longer managed, e.g. of rotated CAs or deleted secrets,
+// are deleted.
+func SetCertificateExpiries(namespace, cluster string, certs []CertificateExpiry) {
+	certificateSeriesMux.Lock()
+	defer certificateSeriesMux.Unlock()
+
+	series := map[string]prometheus.Labels{}
+	for _, cert := range certs {
+		labels := prometheus.Labels{
+			"namespace": namespace,
+			"cluster":   cluster,
+			"secret":    cert.Secret,
+			"component": cert.Component,
+			"cn":        cert.CommonName,
+		}
+		certificateExpiryMetric.With(labels).Set(float64(cert.NotAfter.Unix()))
+		certificateRotationMetric.With(labels).Set(float64(cert.RotationTime.Unix()))
+		series[cert.Secret+"/"+cert.CommonName] = labels
+	}
+
+	key := namespace + "/" + cluster
+	for id, labels := range certificateSeries[key] {
+		if _, ok := series[id]; !ok {
+			certificateExpiryMetric.Delete(labels)
+			certificateRotationMetric.Delete(labels)
+		}
+	}
+	certificateSeries[key] = series
+}
+
+// Deletes the certificate metrics of a deleted cluster.
+func DeleteCertificateMetrics(namespace, cluster string) {
+	certificateSeriesMux.Lock()
+	defer certificateSeriesMux.Unlock()
+
+	key := namespace + "/" + cluster
+	for _, labels := range certificateSeries[key] {
+		certificateExpiryMetric.Delete(labels)
+		certificateRotationMetric.Delete(labels)
+	}
+	delete(certificateSeries, key)
+
+	for _, labels := range certificateFailureSeries[key] {
+		certificateFailureMetric.Delete(labels)
+	}
+	delete(certificateFailureSeries, key)
+}
+
+// Increment the metric value by "1" when the certificates of a secret failed to be generated.
+func IncrementCertificateFailureCounter(namespace, cluster, secret, component string) {
+	certificateSeriesMux.Lock()
+	defer certificateSeriesMux.Unlock()
+
+	labels := prometheus.Labels{
+		"namespace": namespace,
+		"cluster":   cluster,
+		"secret":    secret,
+		"component": component,
+	}
+	certificateFailureMetric.With(labels).Inc()
+
+	key := namespace + "/" + cluster
+	if certificateFailureSeries[key] == nil {
+		certificateFailureSeries[key] = map[string]prometheus.Labels{}
+	}
+	certificateFailureSeries[key][secret] = labels
+}
+
 // Sets the metric value with the number of seconds that a document
 // is retained for in a given index for a rollover or delete operation.
 func SetIndexRetentionDocumentAge(isDeleteOp bool, mapping string, seconds uint64) {
@@ -0,0 +1,298 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/go-logr/logr"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/cronjob"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/pod"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/secret"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	containerName = "snapshot"
+
+	// jobHistoryLimitFailed keeps the last failed job to report its error
+	jobHistoryLimitFailed int32 = 1
+)
+
+// SnapshotRequest reconciles the snapshot repository and the scheduled snapshots of a cluster
+type SnapshotRequest struct {
+	client   client.Client
+	cluster  *apis.Elasticsearch
+	esClient esclient.Client
+	ll       logr.Logger
+}
+
+// Reconcile registers the snapshot repository of the cluster and schedules its snapshots
+func Reconcile(ctx context.Context, log logr.Logger, req *apis.Elasticsearch, reqClient client.Client) error {
+	ll := log.WithValues("cluster", req.Name, "namespace", req.Namespace, "handler", "snapshot")
+	esClient := esclient.NewClient(ll, req.Name, req.Namespace, reqClient)
+	esClient.SetContext(ctx)
+	esClient.SetFlavor(elasticsearch.RunningFlavor(req))
+
+	sr := SnapshotRequest{
+		client:   reqClient,
+		esClient: esClient,
+		cluster:  req,
+		ll:       ll,
+	}
+
+	return sr.createOrUpdateSnapshots()
+}
+
+func (sr *SnapshotRequest) createOrUpdateSnapshots() error {
+	previous := sr.cluster.Status.Snapshot
+	spec := sr.cluster.Spec.Snapshot
+	if spec == nil {
+		if err := sr.removeCronJob(); err != nil {
+			return err
+		}
+		sr.cluster.Status.Snapshot = nil
+		return sr.persistStatus(previous)
+	}
+
+	status := &apis.SnapshotStatus{}
+	if previous != nil {
+		status = previous.DeepCopy()
+	}
+	sr.cluster.Status.Snapshot = status
+
+	if err := validate(spec); err != nil {
+		sr.ll.Error(err, "invalid snapshot policy")
+		status.Message = err.Error()
+		return sr.persistStatus(previous)
+	}
+
+	esPods, err := pod.List(context.TODO(), sr.client, sr.cluster.Namespace, map[string]string{
+		"cluster-name": sr.cluster.Name,
+		"component":    "elasticsearch",
+	})
+	if err != nil {
+		return err
+	}
+
+	running := false
+	for _, pod := range esPods {
+		if pod.Status.Phase == corev1.PodRunning {
+			running = true
+			break
+		}
+	}
+
+	if running {
+		if err := sr.createOrUpdateRepository(spec.Repository); err != nil {
+			sr.ll.Error(err, "failed to register snapshot repository", "repository", spec.Repository.Name)
+			status.Message = err.Error()
+			return sr.persistStatus(previous)
+		}
+		status.Repository = spec.Repository.Name
+	}
+
+	suspend := len(esPods) == 0
+	if err := sr.reconcileCronJob(spec, suspend); err != nil {
+		sr.ll.Error(err, "could not reconcile snapshot cronjob")
+		return err
+	}
+
+	if err := sr.updateJobStatus(status); err != nil {
+		sr.ll.Error(err, "could not read the results of the snapshot jobs")
+		return err
+	}
+	return sr.persistStatus(previous)
+}
+
+func validate(spec *apis.SnapshotPolicySpec) error {
+	repository := spec.Repository
+	if repository.Name == "" {
+		return kverrors.New("snapshot repository name is required")
+	}
+	if spec.Schedule == "" {
+		return kverrors.New("snapshot schedule is required")
+	}
+
+	switch repository.Type {
+	case apis.SnapshotRepositoryTypeFS:
+		if repository.FS == nil || repository.FS.ClaimName == "" {
+			return kverrors.New("claim name is required for snapshot repositories of type fs",
+				"repository", repository.Name)
+		}
+	case apis.SnapshotRepositoryTypeS3:
+		if repository.S3 == nil || repository.S3.Bucket == "" {
+			return kverrors.New("bucket is required for snapshot repositories of type s3",
+				"repository", repository.Name)
+		}
+		if repository.S3.Secret.Name == "" {
+			return kverrors.New("secret is required for snapshot repositories of type s3",
+				"repository", repository.Name)
+		}
+	default:
+		return kverrors.New("unsupported snapshot repository type",
+			"repository", repository.Name,
+			"type", repository.Type)
+	}
+
+	if spec.Retention != nil && spec.Retention.MaxAge != "" {
+		if _, err := indexmanagement.MillisForTimeUnit(spec.Retention.MaxAge); err != nil {
+			return kverrors.Wrap(err, "invalid snapshot retention max age")
+		}
+	}
+	return nil
+}
+
+// createOrUpdateRepository registers the repository unless it is registered with the same settings
+func (sr *SnapshotRequest) createOrUpdateRepository(spec apis.SnapshotRepositorySpec) error {
+	desired, err := sr.newRepository(spec)
+	if err != nil {
+		return err
+	}
+
+	current, err := sr.esClient.GetSnapshotRepository(spec.Name)
+	if err != nil {
+		return err
+	}
+	if current != nil && isRepositorySame(current, desired) {
+		return nil
+	}
+
+	sr.ll.Info("Registering snapshot repository", "repository", spec.Name, "type", spec.Type)
+	return sr.esClient.CreateSnapshotRepository(spec.Name, desired)
+}
+
+func (sr *SnapshotRequest) newRepository(spec apis.SnapshotRepositorySpec) (*estypes.SnapshotRepository, error) {
+	settings := map[string]interface{}{
+		"compress": "true",
+	}
+
+	switch spec.Type {
+	case apis.SnapshotRepositoryTypeFS:
+		settings["location"] = constants.SnapshotRepositoryPath
+	case apis.SnapshotRepositoryTypeS3:
+		s3 := spec.S3
+		key := client.ObjectKey{Name: s3.Secret.Name, Namespace: sr.cluster.Namespace}
+		s, err := secret.Get(context.TODO(), sr.client, key)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to get snapshot repository secret",
+				"repository", spec.Name)
+		}
+		// the credentials are added to the keystore of the nodes
+		for _, k := range []string{elasticsearch.SnapshotAccessKeyIDKey, elasticsearch.SnapshotSecretAccessKeyKey} {
+			if len(s.Data[k]) == 0 {
+				return nil, kverrors.New("snapshot repository secret is missing a required key",
+					"repository", spec.Name,
+					"secret", s3.Secret.Name,
+					"key", k)
+			}
+		}
+
+		settings["bucket"] = s3.Bucket
+		settings["client"] = "default"
+		if s3.BasePath != "" {
+			settings["base_path"] = s3.BasePath
+		}
+		if s3.PathStyleAccess {
+			settings["path_style_access"] = "true"
+		}
+	}
+
+	return &estypes.SnapshotRepository{
+		Type:     string(spec.Type),
+		Settings: settings,
+	}, nil
+}
+
+// isRepositorySame compares the settings as strings as returned by the cluster
+func isRepositorySame(current, desired *estypes.SnapshotRepository) bool {
+	if current.Type != desired.Type {
+		return false
+	}
+	for key, value := range desired.Settings {
+		currentValue, ok := current.Settings[key]
+		if !ok {
+			return false
+		}
+		if fmt.Sprint(currentValue) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (sr *SnapshotRequest) reconcileCronJob(spec *apis.SnapshotPolicySpec, suspend bool) error {
+	envvars := []corev1.EnvVar{
+		{Name: "REPOSITORY", Value: spec.Repository.Name},
+	}
+	if len(spec.Indices) > 0 {
+		envvars = append(envvars, corev1.EnvVar{Name: "INDICES", Value: strings.Join(spec.Indices, ",")})
+	}
+	if spec.Retention != nil {
+		if spec.Retention.MaxCount > 0 {
+			envvars = append(envvars, corev1.EnvVar{Name: "MAX_COUNT", Value: strconv.FormatInt(int64(spec.Retention.MaxCount), 10)})
+		}
+		if spec.Retention.MaxAge != "" {
+			maxAgeMillis, err := indexmanagement.MillisForTimeUnit(spec.Retention.MaxAge)
+			if err != nil {
+				return err
+			}
+			envvars = append(envvars, corev1.EnvVar{Name: "MAX_AGE", Value: strconv.FormatUint(maxAgeMillis, 10)})
+		}
+	}
+
+	desired := newCronJob(sr.cluster, spec.Schedule, envvars, suspend)
+	sr.cluster.AddOwnerRefTo(desired)
+
+	err := cronjob.CreateOrUpdate(context.TODO(), sr.client, desired, jobs.AreCronJobsSame, cronjob.Mutate)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to create or update cronjob",
+			"cluster", desired.Name,
+			"namespace", desired.Namespace,
+		)
+	}
+	return nil
+}
+
+func (sr *SnapshotRequest) removeCronJob() error {
+	key := client.ObjectKey{Name: cronJobName(sr.cluster.Name), Namespace: sr.cluster.Namespace}
+	if err := cronjob.Delete(context.TODO(), sr.client, key); err != nil {
+		if apierrors.IsNotFound(kverrors.Root(err)) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func cronJobName(clusterName string) string {
+	return fmt.Sprintf("%s-snapshot", clusterName)
+}
+
+func newLabels(clusterName string) map[string]string {
+	return map[string]string{
+		"provider":      "openshift",
+		"component":     "snapshot",
+		"logging-infra": "snapshot",
+		"cluster-name":  clusterName,
+	}
+}
+
+func newCronJob(cluster *apis.Elasticsearch, schedule string, envvars []corev1.EnvVar, suspend bool) *batch.CronJob {
+	container := jobs.NewContainer(cluster.Name, containerName, Command, nil, CertsDir, envvars)
+	return jobs.NewCronJob(cluster.Name, cluster.Namespace, cronJobName(cluster.Name), schedule, newLabels(cluster.Name),
+		cluster.Spec.Spec.NodeSelector, cluster.Spec.Spec.Tolerations, container, suspend, jobHistoryLimitFailed)
+}
@@ -0,0 +1,284 @@
+package snapshot
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ViaQ/logerr/v2/log"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("Snapshot reconcile", func() {
+	defer GinkgoRecover()
+
+	_ = apis.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		cluster *apis.Elasticsearch
+		now     = time.Date(2022, time.June, 10, 12, 0, 0, 0, time.UTC)
+
+		clusterKey = client.ObjectKey{Name: "elasticsearch", Namespace: "openshift-logging"}
+		cronJobKey = client.ObjectKey{Name: "elasticsearch-snapshot", Namespace: "openshift-logging"}
+	)
+
+	esPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch-cdm-1-abcde",
+			Namespace: "openshift-logging",
+			Labels: map[string]string{
+				"cluster-name": "elasticsearch",
+				"component":    "elasticsearch",
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	newJobPod := func(jobName string, finished time.Duration, exitCode int32, message string) *corev1.Pod {
+		labels := newLabels("elasticsearch")
+		labels["job-name"] = jobName
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName + "-abcde",
+				Namespace: "openshift-logging",
+				Labels:    labels,
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: containerName,
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode:   exitCode,
+								Message:    message,
+								FinishedAt: metav1.NewTime(now.Add(finished)),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	newRequest := func(responses map[string]helpers.FakeElasticsearchResponses, objs ...client.Object) *SnapshotRequest {
+		k8sClient := fake.NewClientBuilder().WithObjects(append(objs, cluster.DeepCopy())...).Build()
+		chatter = helpers.NewFakeElasticsearchChatter(responses)
+		return &SnapshotRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+			ll:       log.NewLogger("snapshot-testing"),
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apis.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+			Spec: apis.ElasticsearchSpec{
+				Snapshot: &apis.SnapshotPolicySpec{
+					Repository: apis.SnapshotRepositorySpec{
+						Name: "backups",
+						Type: apis.SnapshotRepositoryTypeFS,
+						FS:   &apis.SnapshotFSRepositorySpec{ClaimName: "snapshots"},
+					},
+					Schedule: "0 1 * * *",
+					Indices:  []string{"app-*", "infra-*"},
+					Retention: &apis.SnapshotRetentionSpec{
+						MaxCount: 7,
+						MaxAge:   "14d",
+					},
+				},
+			},
+		}
+	})
+
+	Describe("#createOrUpdateSnapshots", func() {
+		It("should register the repository and schedule the snapshots", func() {
+			sr := newRequest(map[string]helpers.FakeElasticsearchResponses{
+				"_snapshot/backups": {
+					{StatusCode: http.StatusNotFound, Body: `{"error":{"type":"repository_missing_exception"}}`},
+					{StatusCode: http.StatusOK, Body: `{"acknowledged":true}`},
+				},
+			}, esPod)
+			Expect(sr.createOrUpdateSnapshots()).To(Succeed())
+
+			_, _ = chatter.GetRequest("_snapshot/backups")
+			request, _ := chatter.GetRequest("_snapshot/backups")
+			Expect(request.Method).To(Equal(http.MethodPut))
+			Expect(request.Body).To(MatchJSON(`{"type":"fs","settings":{"compress":"true","location":"/elasticsearch/snapshots"}}`))
+
+			cj := &batch.CronJob{}
+			Expect(sr.client.Get(context.TODO(), cronJobKey, cj)).To(Succeed())
+			Expect(cj.Spec.Schedule).To(Equal("0 1 * * *"))
+			Expect(*cj.Spec.Suspend).To(BeFalse())
+			container := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+			Expect(container.Command).To(Equal([]string{"elasticsearch-operator", Command}))
+			Expect(container.Env).To(ConsistOf(
+				corev1.EnvVar{Name: "REPOSITORY", Value: "backups"},
+				corev1.EnvVar{Name: "ES_SERVICE", Value: "https://elasticsearch:9200"},
+				corev1.EnvVar{Name: "INDICES", Value: "app-*,infra-*"},
+				corev1.EnvVar{Name: "MAX_COUNT", Value: "7"},
+				corev1.EnvVar{Name: "MAX_AGE", Value: "1209600000"},
+			))
+
+			es := &apis.Elasticsearch{}
+			Expect(sr.client.Get(context.TODO(), clusterKey, es)).To(Succeed())
+			Expect(es.Status.Snapshot).To(Equal(&apis.SnapshotStatus{Repository: "backups"}))
+		})
+
+		It("should not register the repository again when it did not change", func() {
+			sr := newRequest(map[string]helpers.FakeElasticsearchResponses{
+				"_snapshot/backups": {
+					{StatusCode: http.StatusOK, Body: `{"backups":{"type":"fs","settings":{"compress":"true","location":"/elasticsearch/snapshots"}}}`},
+				},
+			}, esPod)
+			Expect(sr.createOrUpdateSnapshots()).To(Succeed())
+			Expect(chatter.Requests["_snapshot/backups"]).To(HaveLen(1))
+		})
+
+		It("should suspend the snapshots without elasticsearch pods", func() {
+			sr := newRequest(map[string]helpers.FakeElasticsearchResponses{})
+			Expect(sr.createOrUpdateSnapshots()).To(Succeed())
+
+			cj := &batch.CronJob{}
+			Expect(sr.client.Get(context.TODO(), cronJobKey, cj)).To(Succeed())
+			Expect(*cj.Spec.Suspend).To(BeTrue())
+		})
+
+		It("should report an invalid snapshot policy", func() {
+			cluster.Spec.Snapshot.Repository.FS = nil
+			sr := newRequest(map[string]helpers.FakeElasticsearchResponses{}, esPod)
+			Expect(sr.createOrUpdateSnapshots()).To(Succeed())
+
+			es := &apis.Elasticsearch{}
+			Expect(sr.client.Get(context.TODO(), clusterKey, es)).To(Succeed())
+			Expect(es.Status.Snapshot.Message).To(ContainSubstring("claim name is required"))
+			Expect(apierrors.IsNotFound(sr.client.Get(context.TODO(), cronJobKey, &batch.CronJob{}))).To(BeTrue())
+		})
+
+		It("should remove the cronjob when the snapshot policy was removed", func() {
+			cluster.Status.Snapshot = &apis.SnapshotStatus{Repository: "backups"}
+			cj := newCronJob(cluster, "0 1 * * *", nil, false)
+			cluster.Spec.Snapshot = nil
+			sr := newRequest(map[string]helpers.FakeElasticsearchResponses{}, cj)
+			Expect(sr.createOrUpdateSnapshots()).To(Succeed())
+
+			Expect(apierrors.IsNotFound(sr.client.Get(context.TODO(), cronJobKey, &batch.CronJob{}))).To(BeTrue())
+			es := &apis.Elasticsearch{}
+			Expect(sr.client.Get(context.TODO(), clusterKey, es)).To(Succeed())
+			Expect(es.Status.Snapshot).To(BeNil())
+		})
+	})
+
+	Describe("#updateJobStatus", func() {
+		It("should report the last successful snapshot", func() {
+			sr := newRequest(nil,
+				newJobPod("elasticsearch-snapshot-27580000", -25*time.Hour, 0,
+					`{"snapshot":"scheduled-2022.06.09-11.00.00","state":"SUCCESS","endTimeInMillis":1654772460000}`),
+				newJobPod("elasticsearch-snapshot-27581440", -time.Hour, 0,
+					`{"snapshot":"scheduled-2022.06.10-11.00.00","state":"SUCCESS","endTimeInMillis":1654858860000}`),
+			)
+			status := &apis.SnapshotStatus{}
+			Expect(sr.updateJobStatus(status)).To(Succeed())
+
+			Expect(status.LastSuccessfulSnapshot).To(Equal("scheduled-2022.06.10-11.00.00"))
+			Expect(status.LastSuccessfulSnapshotTime.Time).To(BeTemporally("==", time.UnixMilli(1654858860000)))
+			Expect(status.Message).To(BeEmpty())
+		})
+
+		It("should keep the last successful snapshot and report the error of a failed job", func() {
+			completed := metav1.NewTime(now.Add(-48 * time.Hour))
+			sr := newRequest(nil,
+				newJobPod("elasticsearch-snapshot-27581440", -time.Hour, 10,
+					`{"snapshot":"scheduled-2022.06.10-11.00.00","state":"PARTIAL","error":"snapshot did not succeed"}`),
+			)
+			status := &apis.SnapshotStatus{
+				LastSuccessfulSnapshot:     "scheduled-2022.06.08-11.00.00",
+				LastSuccessfulSnapshotTime: &completed,
+			}
+			Expect(sr.updateJobStatus(status)).To(Succeed())
+
+			Expect(status.LastSuccessfulSnapshot).To(Equal("scheduled-2022.06.08-11.00.00"))
+			Expect(status.LastSuccessfulSnapshotTime).To(Equal(&completed))
+			Expect(status.Message).To(Equal("snapshot did not succeed"))
+		})
+	})
+
+	Describe("#newRepository", func() {
+		It("should leave the s3 credentials to the keystore of the nodes", func() {
+			cluster.Spec.Snapshot.Repository = apis.SnapshotRepositorySpec{
+				Name: "backups",
+				Type: apis.SnapshotRepositoryTypeS3,
+				S3: &apis.SnapshotS3RepositorySpec{
+					Bucket: "logs",
+					Secret: corev1.LocalObjectReference{Name: "s3-credentials"},
+				},
+			}
+			sr := newRequest(nil, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "openshift-logging"},
+				Data: map[string][]byte{
+					"access_key_id":     []byte("key"),
+					"secret_access_key": []byte("secret"),
+				},
+			})
+
+			repository, err := sr.newRepository(cluster.Spec.Snapshot.Repository)
+			Expect(err).To(BeNil())
+			Expect(repository.Settings).To(Equal(map[string]interface{}{
+				"bucket": "logs", "client": "default", "compress": "true",
+			}))
+		})
+
+		It("should fail for a secret missing the credentials", func() {
+			cluster.Spec.Snapshot.Repository = apis.SnapshotRepositorySpec{
+				Name: "backups",
+				Type: apis.SnapshotRepositoryTypeS3,
+				S3: &apis.SnapshotS3RepositorySpec{
+					Bucket: "logs",
+					Secret: corev1.LocalObjectReference{Name: "s3-credentials"},
+				},
+			}
+			sr := newRequest(nil, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "openshift-logging"},
+				Data:       map[string][]byte{"access_key_id": []byte("key")},
+			})
+
+			_, err := sr.newRepository(cluster.Spec.Snapshot.Repository)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("#isRepositorySame", func() {
+		It("should compare all the settings of the repository", func() {
+			current := &estypes.SnapshotRepository{
+				Type:     "s3",
+				Settings: map[string]interface{}{"bucket": "logs", "client": "default", "compress": "true"},
+			}
+			desired := &estypes.SnapshotRepository{
+				Type:     "s3",
+				Settings: map[string]interface{}{"bucket": "logs", "client": "default", "compress": "true"},
+			}
+			Expect(isRepositorySame(current, desired)).To(BeTrue())
+
+			desired.Settings["base_path"] = "es"
+			Expect(isRepositorySame(current, desired)).To(BeFalse())
+		})
+	})
+})
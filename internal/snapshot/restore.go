@@ -0,0 +1,178 @@
+package snapshot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+// RestoreRequest restores the indices of an ElasticsearchRestore from a snapshot
+type RestoreRequest struct {
+	client   client.Client
+	restore  *apis.ElasticsearchRestore
+	cluster  *apis.Elasticsearch
+	esClient esclient.Client
+	ll       logr.Logger
+}
+
+// ReconcileRestore progresses the restore through its phases. It returns true once the
+// restore succeeded or failed and does not need to be reconciled again.
+func ReconcileRestore(ctx context.Context, log logr.Logger, restore *apis.ElasticsearchRestore, reqClient client.Client) (bool, error) {
+	ll := log.WithValues("restore", restore.Name, "namespace", restore.Namespace, "cluster", restore.Spec.ElasticsearchName)
+	if isRestoreDone(restore) {
+		return true, nil
+	}
+
+	cluster := &apis.Elasticsearch{}
+	key := client.ObjectKey{Name: restore.Spec.ElasticsearchName, Namespace: restore.Namespace}
+	if err := reqClient.Get(context.TODO(), key, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, setRestoreStatus(reqClient, restore, func(status *apis.ElasticsearchRestoreStatus) {
+				status.Phase = apis.ElasticsearchRestorePhasePending
+				status.Message = "elasticsearch cluster not found"
+			})
+		}
+		return false, kverrors.Wrap(err, "failed to get elasticsearch cluster", "cluster", key.Name)
+	}
+
+	esClient := esclient.NewClient(ll, cluster.Name, cluster.Namespace, reqClient)
+	esClient.SetContext(ctx)
+
+	rr := RestoreRequest{
+		client:   reqClient,
+		restore:  restore,
+		cluster:  cluster,
+		esClient: esClient,
+		ll:       ll,
+	}
+	return rr.reconcile()
+}
+
+func (rr *RestoreRequest) reconcile() (bool, error) {
+	if rr.cluster.Spec.Snapshot == nil {
+		return true, rr.fail("elasticsearch cluster has no snapshot policy")
+	}
+	repository := rr.cluster.Spec.Snapshot.Repository.Name
+
+	switch rr.restore.Status.Phase {
+	case "", apis.ElasticsearchRestorePhasePending:
+		return false, rr.start(repository)
+	case apis.ElasticsearchRestorePhaseInProgress:
+		return rr.progress(repository)
+	}
+	return true, nil
+}
+
+// start requests the restore of the snapshot
+func (rr *RestoreRequest) start(repository string) error {
+	snapshot := rr.restore.Spec.Snapshot
+	if snapshot == "" && rr.cluster.Status.Snapshot != nil {
+		snapshot = rr.cluster.Status.Snapshot.LastSuccessfulSnapshot
+	}
+	if snapshot == "" {
+		return rr.fail("no successful snapshot to restore")
+	}
+
+	request := &estypes.RestoreSnapshotRequest{
+		Indices:            strings.Join(rr.restore.Spec.Indices, ","),
+		IgnoreUnavailable:  true,
+		IncludeGlobalState: false,
+		IncludeAliases:     rr.restore.Spec.IncludeAliases,
+		RenamePattern:      rr.restore.Spec.RenamePattern,
+		RenameReplacement:  rr.restore.Spec.RenameReplacement,
+	}
+	if err := rr.esClient.RestoreSnapshot(repository, snapshot, request); err != nil {
+		rr.ll.Error(err, "failed to restore snapshot", "snapshot", snapshot)
+		return rr.fail(err.Error())
+	}
+
+	rr.ll.Info("Restoring snapshot", "snapshot", snapshot)
+	now := metav1.Now()
+	return setRestoreStatus(rr.client, rr.restore, func(status *apis.ElasticsearchRestoreStatus) {
+		status.Phase = apis.ElasticsearchRestorePhaseInProgress
+		status.Snapshot = snapshot
+		status.StartTime = &now
+		status.Message = ""
+	})
+}
+
+// progress completes the restore once no shards are recovered from the snapshot anymore
+// and the restored indices are allocated
+func (rr *RestoreRequest) progress(repository string) (bool, error) {
+	recoveries, err := rr.esClient.GetActiveRestores(repository, rr.restore.Status.Snapshot)
+	if err != nil {
+		return false, err
+	}
+	if len(recoveries) > 0 {
+		return false, nil
+	}
+
+	health, err := rr.esClient.GetClusterHealthStatus()
+	if err != nil {
+		return false, err
+	}
+	if health == "red" {
+		return false, setRestoreStatus(rr.client, rr.restore, func(status *apis.ElasticsearchRestoreStatus) {
+			status.Message = "waiting for the restored indices to be allocated"
+		})
+	}
+
+	rr.ll.Info("Restored snapshot", "snapshot", rr.restore.Status.Snapshot)
+	now := metav1.Now()
+	return true, setRestoreStatus(rr.client, rr.restore, func(status *apis.ElasticsearchRestoreStatus) {
+		status.Phase = apis.ElasticsearchRestorePhaseSucceeded
+		status.CompletionTime = &now
+		status.Message = ""
+	})
+}
+
+func (rr *RestoreRequest) fail(message string) error {
+	now := metav1.Now()
+	return setRestoreStatus(rr.client, rr.restore, func(status *apis.ElasticsearchRestoreStatus) {
+		status.Phase = apis.ElasticsearchRestorePhaseFailed
+		status.CompletionTime = &now
+		status.Message = message
+	})
+}
+
+func isRestoreDone(restore *apis.ElasticsearchRestore) bool {
+	return restore.Status.Phase == apis.ElasticsearchRestorePhaseSucceeded ||
+		restore.Status.Phase == apis.ElasticsearchRestorePhaseFailed
+}
+
+func setRestoreStatus(c client.Client, restore *apis.ElasticsearchRestore, mutate func(status *apis.ElasticsearchRestoreStatus)) error {
+	status := restore.Status.DeepCopy()
+	mutate(status)
+	if equality.Semantic.DeepEqual(*status, restore.Status) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: restore.Name, Namespace: restore.Namespace}, restore); err != nil {
+			return err
+		}
+
+		restore.Status = *status
+		return c.Status().Update(context.TODO(), restore)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update restore status",
+			"restore", restore.Name,
+			"retries", nretries)
+	}
+	return nil
+}
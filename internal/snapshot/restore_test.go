@@ -0,0 +1,154 @@
+package snapshot
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ViaQ/logerr/v2/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+const (
+	restoreURI    = "_snapshot/backups/scheduled-2022.06.10-00.00.00/_restore"
+	recoveriesURI = "_cat/recovery?format=json&active_only=true&h=index,type,stage,repository,snapshot"
+)
+
+var _ = Describe("Restore reconcile", func() {
+	defer GinkgoRecover()
+
+	_ = apis.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		cluster *apis.Elasticsearch
+		restore *apis.ElasticsearchRestore
+
+		restoreKey = client.ObjectKey{Name: "restore", Namespace: "openshift-logging"}
+	)
+
+	newRequest := func(responses map[string]helpers.FakeElasticsearchResponses) *RestoreRequest {
+		k8sClient := fake.NewClientBuilder().WithObjects(cluster, restore.DeepCopy()).Build()
+		chatter = helpers.NewFakeElasticsearchChatter(responses)
+		return &RestoreRequest{
+			client:   k8sClient,
+			restore:  restore,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+			ll:       log.NewLogger("restore-testing"),
+		}
+	}
+
+	getRestore := func(rr *RestoreRequest) *apis.ElasticsearchRestore {
+		actual := &apis.ElasticsearchRestore{}
+		Expect(rr.client.Get(context.TODO(), restoreKey, actual)).To(Succeed())
+		return actual
+	}
+
+	BeforeEach(func() {
+		cluster = &apis.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+			Spec: apis.ElasticsearchSpec{
+				Snapshot: &apis.SnapshotPolicySpec{
+					Repository: apis.SnapshotRepositorySpec{
+						Name: "backups",
+						Type: apis.SnapshotRepositoryTypeFS,
+						FS:   &apis.SnapshotFSRepositorySpec{ClaimName: "snapshots"},
+					},
+					Schedule: "0 1 * * *",
+				},
+			},
+			Status: apis.ElasticsearchStatus{
+				Snapshot: &apis.SnapshotStatus{
+					Repository:             "backups",
+					LastSuccessfulSnapshot: "scheduled-2022.06.10-00.00.00",
+				},
+			},
+		}
+		restore = &apis.ElasticsearchRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "restore",
+				Namespace: "openshift-logging",
+			},
+			Spec: apis.ElasticsearchRestoreSpec{
+				ElasticsearchName: "elasticsearch",
+				Indices:           []string{"app-000001"},
+				RenamePattern:     "(.+)",
+				RenameReplacement: "restored-$1",
+			},
+		}
+	})
+
+	It("should restore the last successful snapshot", func() {
+		rr := newRequest(map[string]helpers.FakeElasticsearchResponses{
+			restoreURI: {{StatusCode: http.StatusOK, Body: `{"accepted":true}`}},
+		})
+		Expect(rr.reconcile()).To(BeFalse())
+
+		request, _ := chatter.GetRequest(restoreURI)
+		Expect(request.Method).To(Equal(http.MethodPost))
+		Expect(request.Body).To(MatchJSON(`{
+			"indices":"app-000001",
+			"ignore_unavailable":true,
+			"include_global_state":false,
+			"include_aliases":false,
+			"rename_pattern":"(.+)",
+			"rename_replacement":"restored-$1"
+		}`))
+
+		status := getRestore(rr).Status
+		Expect(status.Phase).To(Equal(apis.ElasticsearchRestorePhaseInProgress))
+		Expect(status.Snapshot).To(Equal("scheduled-2022.06.10-00.00.00"))
+		Expect(status.StartTime).ToNot(BeNil())
+	})
+
+	It("should fail when the cluster rejects the restore", func() {
+		rr := newRequest(map[string]helpers.FakeElasticsearchResponses{
+			restoreURI: {{StatusCode: http.StatusInternalServerError, Body: `{"error":{"type":"snapshot_restore_exception"}}`}},
+		})
+		Expect(rr.reconcile()).To(BeFalse())
+
+		status := getRestore(rr).Status
+		Expect(status.Phase).To(Equal(apis.ElasticsearchRestorePhaseFailed))
+		Expect(status.Message).To(ContainSubstring("failed to restore snapshot"))
+	})
+
+	It("should fail without a successful snapshot", func() {
+		cluster.Status.Snapshot = nil
+		rr := newRequest(nil)
+		Expect(rr.reconcile()).To(BeFalse())
+		Expect(getRestore(rr).Status.Phase).To(Equal(apis.ElasticsearchRestorePhaseFailed))
+	})
+
+	It("should wait for the shards to be recovered from the snapshot", func() {
+		restore.Status = apis.ElasticsearchRestoreStatus{
+			Phase:    apis.ElasticsearchRestorePhaseInProgress,
+			Snapshot: "scheduled-2022.06.10-00.00.00",
+		}
+		rr := newRequest(map[string]helpers.FakeElasticsearchResponses{
+			recoveriesURI: {
+				{StatusCode: http.StatusOK, Body: `[{"index":"restored-app-000001","type":"snapshot","stage":"index","repository":"backups","snapshot":"scheduled-2022.06.10-00.00.00"}]`},
+				{StatusCode: http.StatusOK, Body: `[{"index":"app-000002","type":"peer","stage":"done","repository":"n/a","snapshot":"n/a"}]`},
+			},
+			"_cluster/health": {{StatusCode: http.StatusOK, Body: `{"status":"yellow"}`}},
+		})
+		Expect(rr.reconcile()).To(BeFalse())
+		Expect(getRestore(rr).Status.Phase).To(Equal(apis.ElasticsearchRestorePhaseInProgress))
+
+		Expect(rr.reconcile()).To(BeTrue())
+		status := getRestore(rr).Status
+		Expect(status.Phase).To(Equal(apis.ElasticsearchRestorePhaseSucceeded))
+		Expect(status.CompletionTime).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,279 @@
+package snapshot
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch/esclient"
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	// Command is the operator subcommand running the snapshot cronjob
+	Command = "snapshot"
+	// CertsDir is the mount path of the cluster secret in the snapshot cronjob
+	CertsDir = "/etc/snapshot/keys"
+	// SnapshotPrefix is the name prefix of the snapshots taken by the schedule. Only
+	// these snapshots are pruned.
+	SnapshotPrefix = "scheduled-"
+
+	snapshotNameLayout = "2006.01.02-15.04.05"
+	pollInterval       = 10 * time.Second
+	snapshotTimeout    = 6 * time.Hour
+
+	stateInProgress = "IN_PROGRESS"
+	stateSuccess    = "SUCCESS"
+)
+
+// ExitCode is the exit code of the snapshot command telling which step failed
+type ExitCode int
+
+const (
+	ExitOK             ExitCode = 0
+	ExitInvalidConfig  ExitCode = 3
+	ExitSnapshotFailed ExitCode = 10
+	ExitPruneFailed    ExitCode = 11
+)
+
+// Config is the configuration of a run given to the cronjob container as env vars
+type Config struct {
+	Repository string
+	ESService  string
+	Indices    []string
+	MaxCount   int32
+	// MaxAge in milliseconds
+	MaxAge uint64
+}
+
+// Result is the outcome of a run written as termination message of the cronjob container
+type Result struct {
+	Snapshot        string   `json:"snapshot,omitempty"`
+	State           string   `json:"state,omitempty"`
+	EndTimeInMillis int64    `json:"endTimeInMillis,omitempty"`
+	Deleted         []string `json:"deleted,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// Runner takes a snapshot of the cluster and prunes the snapshots exceeding the retention
+type Runner struct {
+	log      logr.Logger
+	esClient esclient.Client
+	config   *Config
+	now      func() time.Time
+	poll     func(condition wait.ConditionFunc) error
+
+	result *Result
+}
+
+// Main runs the snapshot command and returns the exit code
+func Main(args []string) int {
+	logger := log.NewLogger("snapshot")
+
+	config, err := ConfigFromEnv(os.Getenv)
+	if err != nil {
+		logger.Error(err, "invalid snapshot configuration")
+		return int(ExitInvalidConfig)
+	}
+
+	esClient := esclient.NewClient(logger, "", "", nil)
+	esClient.SetSendRequestFn(esclient.NewMountedCertificatesSendRequestFn(config.ESService, CertsDir))
+
+	r := New(logger, esClient, config)
+	code := r.Run()
+	if err := jobs.WriteResult(jobs.TerminationMessagePath, r.Result()); err != nil {
+		logger.Error(err, "failed to report the snapshot result")
+	}
+	return int(code)
+}
+
+// New returns a runner for the given configuration
+func New(log logr.Logger, esClient esclient.Client, config *Config) *Runner {
+	return &Runner{
+		log:      log.WithValues("repository", config.Repository),
+		esClient: esClient,
+		config:   config,
+		now:      time.Now,
+		poll: func(condition wait.ConditionFunc) error {
+			return wait.PollImmediate(pollInterval, snapshotTimeout, condition)
+		},
+		result: &Result{},
+	}
+}
+
+// Run takes the snapshot, prunes the expired ones and maps the failure to an exit code
+func (r *Runner) Run() ExitCode {
+	name := SnapshotPrefix + r.now().UTC().Format(snapshotNameLayout)
+	ll := r.log.WithValues("snapshot", name)
+	ll.Info("Snapshot process starting")
+
+	r.result = &Result{Snapshot: name}
+	if err := r.Snapshot(name); err != nil {
+		ll.Error(err, "snapshot failed")
+		r.setError(err)
+		return ExitSnapshotFailed
+	}
+	if err := r.Prune(); err != nil {
+		ll.Error(err, "prune failed")
+		r.setError(err)
+		return ExitPruneFailed
+	}
+
+	ll.Info("Snapshot process done")
+	return ExitOK
+}
+
+// Result returns the outcome of the last run
+func (r *Runner) Result() *Result {
+	return r.result
+}
+
+// Snapshot creates the snapshot and waits for its completion
+func (r *Runner) Snapshot(name string) error {
+	request := &estypes.CreateSnapshotRequest{
+		Indices:            strings.Join(r.config.Indices, ","),
+		IgnoreUnavailable:  true,
+		IncludeGlobalState: false,
+	}
+	if err := r.esClient.CreateSnapshot(r.config.Repository, name, request); err != nil {
+		return err
+	}
+
+	var snapshot *estypes.SnapshotInfo
+	err := r.poll(func() (bool, error) {
+		var err error
+		snapshot, err = r.esClient.GetSnapshot(r.config.Repository, name)
+		if err != nil {
+			return false, err
+		}
+		return snapshot != nil && snapshot.State != stateInProgress, nil
+	})
+	if err != nil {
+		return kverrors.Wrap(err, "failed waiting for the snapshot to complete",
+			"repository", r.config.Repository,
+			"snapshot", name)
+	}
+
+	r.result.State = snapshot.State
+	r.result.EndTimeInMillis = snapshot.EndTimeInMillis
+	if snapshot.State != stateSuccess {
+		return kverrors.New("snapshot did not succeed",
+			"repository", r.config.Repository,
+			"snapshot", name,
+			"state", snapshot.State)
+	}
+	return nil
+}
+
+// Prune deletes the scheduled snapshots exceeding the maximum count or age. The last
+// successful snapshot and the snapshots in progress are always kept.
+func (r *Runner) Prune() error {
+	if r.config.MaxCount <= 0 && r.config.MaxAge == 0 {
+		return nil
+	}
+
+	snapshots, err := r.esClient.ListSnapshots(r.config.Repository)
+	if err != nil {
+		return err
+	}
+
+	scheduled := []estypes.SnapshotInfo{}
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Snapshot, SnapshotPrefix) {
+			scheduled = append(scheduled, snapshot)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].StartTimeInMillis > scheduled[j].StartTimeInMillis
+	})
+
+	nowMillis := r.now().UnixMilli()
+	keptSuccess := false
+	kept := int32(0)
+	var failed []string
+	for _, snapshot := range scheduled {
+		if snapshot.State == stateInProgress {
+			continue
+		}
+		if !keptSuccess && snapshot.State == stateSuccess {
+			keptSuccess = true
+			kept++
+			continue
+		}
+
+		expired := r.config.MaxAge > 0 && uint64(nowMillis-snapshot.StartTimeInMillis) > r.config.MaxAge
+		exceeded := r.config.MaxCount > 0 && kept >= r.config.MaxCount
+		if !expired && !exceeded {
+			kept++
+			continue
+		}
+
+		if err := r.esClient.DeleteSnapshot(r.config.Repository, snapshot.Snapshot); err != nil {
+			r.log.Error(err, "failed to delete snapshot", "snapshot", snapshot.Snapshot)
+			failed = append(failed, snapshot.Snapshot)
+			continue
+		}
+		r.result.Deleted = append(r.result.Deleted, snapshot.Snapshot)
+	}
+
+	if len(failed) > 0 {
+		return kverrors.New("failed to delete snapshots",
+			"repository", r.config.Repository,
+			"snapshots", failed)
+	}
+	return nil
+}
+
+func (r *Runner) setError(err error) {
+	r.result.Error = jobs.ErrorMessage(err)
+}
+
+// ConfigFromEnv reads the configuration from the env vars set on the cronjob container
+func ConfigFromEnv(getenv func(string) string) (*Config, error) {
+	config := &Config{
+		Repository: getenv("REPOSITORY"),
+		ESService:  getenv("ES_SERVICE"),
+	}
+	if config.Repository == "" {
+		return nil, kverrors.New("missing required env var", "name", "REPOSITORY")
+	}
+	if config.ESService == "" {
+		return nil, kverrors.New("missing required env var", "name", "ES_SERVICE")
+	}
+
+	if v := getenv("INDICES"); v != "" {
+		config.Indices = strings.Split(v, ",")
+	}
+	if v := getenv("MAX_COUNT"); v != "" {
+		count, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "MAX_COUNT")
+		}
+		config.MaxCount = int32(count)
+	}
+	if v := getenv("MAX_AGE"); v != "" {
+		var err error
+		if config.MaxAge, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse env var", "name", "MAX_AGE")
+		}
+	}
+	return config, nil
+}
+
+// ParseResult reads the result from a termination message. It returns nil if the
+// message is not a result, e.g. when the container was killed.
+func ParseResult(message string) *Result {
+	result := &Result{}
+	if !jobs.ParseResult(message, result) {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,190 @@
+package snapshot
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ViaQ/logerr/v2/log"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+const (
+	snapshotURI  = "_snapshot/backups/scheduled-2022.06.10-00.00.00"
+	snapshotsURI = "_snapshot/backups/_all"
+)
+
+var _ = Describe("Snapshot runner", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		config  *Config
+		now     = time.Date(2022, time.June, 10, 0, 0, 0, 0, time.UTC)
+	)
+
+	newRunner := func(responses map[string]helpers.FakeElasticsearchResponses) *Runner {
+		chatter = helpers.NewFakeElasticsearchChatter(responses)
+		esClient := helpers.NewFakeElasticsearchClient("", "", nil, chatter)
+		r := New(log.NewLogger("snapshot-runner-testing"), esClient, config)
+		r.now = func() time.Time { return now }
+		r.poll = func(condition wait.ConditionFunc) error {
+			for {
+				done, err := condition()
+				if err != nil || done {
+					return err
+				}
+			}
+		}
+		return r
+	}
+
+	snapshotResponse := func(state string) helpers.FakeElasticsearchResponse {
+		return helpers.FakeElasticsearchResponse{
+			StatusCode: http.StatusOK,
+			Body:       `{"snapshots":[{"snapshot":"scheduled-2022.06.10-00.00.00","state":"` + state + `","end_time_in_millis":1654819260000}]}`,
+		}
+	}
+
+	BeforeEach(func() {
+		config = &Config{
+			Repository: "backups",
+			ESService:  "https://elasticsearch:9200",
+		}
+	})
+
+	Describe("#ConfigFromEnv", func() {
+		It("should read the configuration from the cronjob env vars", func() {
+			env := map[string]string{
+				"REPOSITORY": "backups",
+				"ES_SERVICE": "https://elasticsearch:9200",
+				"INDICES":    "app-*,infra-*",
+				"MAX_COUNT":  "7",
+				"MAX_AGE":    "604800000",
+			}
+			actual, err := ConfigFromEnv(func(name string) string { return env[name] })
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(&Config{
+				Repository: "backups",
+				ESService:  "https://elasticsearch:9200",
+				Indices:    []string{"app-*", "infra-*"},
+				MaxCount:   7,
+				MaxAge:     604800000,
+			}))
+		})
+
+		It("should fail without a repository", func() {
+			_, err := ConfigFromEnv(func(name string) string { return "" })
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("#Run", func() {
+		It("should wait for the snapshot to complete", func() {
+			r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+				snapshotURI: {
+					{StatusCode: http.StatusOK, Body: `{"accepted":true}`},
+					snapshotResponse("IN_PROGRESS"),
+					snapshotResponse("SUCCESS"),
+				},
+			})
+			Expect(r.Run()).To(Equal(ExitOK))
+
+			request, _ := chatter.GetRequest(snapshotURI)
+			Expect(request.Method).To(Equal(http.MethodPut))
+			Expect(request.Body).To(MatchJSON(`{"ignore_unavailable":true,"include_global_state":false}`))
+			Expect(r.Result()).To(Equal(&Result{
+				Snapshot:        "scheduled-2022.06.10-00.00.00",
+				State:           "SUCCESS",
+				EndTimeInMillis: 1654819260000,
+			}))
+		})
+
+		It("should fail when the snapshot did not succeed", func() {
+			r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+				snapshotURI: {
+					{StatusCode: http.StatusOK, Body: `{"accepted":true}`},
+					snapshotResponse("PARTIAL"),
+				},
+			})
+			Expect(r.Run()).To(Equal(ExitSnapshotFailed))
+
+			result := r.Result()
+			Expect(result.State).To(Equal("PARTIAL"))
+			Expect(result.Error).To(ContainSubstring("snapshot did not succeed"))
+		})
+
+		It("should prune the snapshots exceeding the retention", func() {
+			config.MaxCount = 2
+			config.MaxAge = uint64((48 * time.Hour).Milliseconds())
+			startTime := func(age time.Duration) string {
+				return formatMillis(now.Add(-age).UnixMilli())
+			}
+			r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+				snapshotURI: {
+					{StatusCode: http.StatusOK, Body: `{"accepted":true}`},
+					snapshotResponse("SUCCESS"),
+				},
+				snapshotsURI: {
+					{
+						StatusCode: http.StatusOK,
+						Body: `{"snapshots":[
+							{"snapshot":"manual","state":"SUCCESS","start_time_in_millis":` + startTime(96*time.Hour) + `},
+							{"snapshot":"scheduled-2022.06.07-00.00.00","state":"SUCCESS","start_time_in_millis":` + startTime(72*time.Hour) + `},
+							{"snapshot":"scheduled-2022.06.08-00.00.00","state":"FAILED","start_time_in_millis":` + startTime(48*time.Hour) + `},
+							{"snapshot":"scheduled-2022.06.09-00.00.00","state":"SUCCESS","start_time_in_millis":` + startTime(24*time.Hour) + `},
+							{"snapshot":"scheduled-2022.06.10-00.00.00","state":"SUCCESS","start_time_in_millis":` + startTime(0) + `}
+						]}`,
+					},
+				},
+				"_snapshot/backups/scheduled-2022.06.07-00.00.00": {{StatusCode: http.StatusOK, Body: `{"acknowledged":true}`}},
+				"_snapshot/backups/scheduled-2022.06.08-00.00.00": {{StatusCode: http.StatusOK, Body: `{"acknowledged":true}`}},
+			})
+			Expect(r.Run()).To(Equal(ExitOK))
+			Expect(r.Result().Deleted).To(Equal([]string{
+				"scheduled-2022.06.08-00.00.00",
+				"scheduled-2022.06.07-00.00.00",
+			}))
+			_, found := chatter.GetRequest("_snapshot/backups/manual")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should keep the last successful snapshot regardless of its age", func() {
+			config.MaxAge = uint64(time.Hour.Milliseconds())
+			r := newRunner(map[string]helpers.FakeElasticsearchResponses{
+				snapshotsURI: {
+					{
+						StatusCode: http.StatusOK,
+						Body: `{"snapshots":[
+							{"snapshot":"scheduled-2022.06.08-00.00.00","state":"SUCCESS","start_time_in_millis":` + formatMillis(now.Add(-48*time.Hour).UnixMilli()) + `},
+							{"snapshot":"scheduled-2022.06.10-00.00.00","state":"FAILED","start_time_in_millis":` + formatMillis(now.Add(-2*time.Hour).UnixMilli()) + `}
+						]}`,
+					},
+				},
+				"_snapshot/backups/scheduled-2022.06.10-00.00.00": {{StatusCode: http.StatusOK, Body: `{"acknowledged":true}`}},
+			})
+			Expect(r.Prune()).To(Succeed())
+			Expect(r.Result().Deleted).To(Equal([]string{"scheduled-2022.06.10-00.00.00"}))
+		})
+	})
+
+	Describe("#ParseResult", func() {
+		It("should ignore messages which are not a result", func() {
+			Expect(ParseResult("")).To(BeNil())
+			Expect(ParseResult("OOMKilled")).To(BeNil())
+			Expect(ParseResult(`{"snapshot":"scheduled-2022.06.10-00.00.00","state":"SUCCESS"}`)).To(Equal(&Result{
+				Snapshot: "scheduled-2022.06.10-00.00.00",
+				State:    "SUCCESS",
+			}))
+		})
+	})
+})
+
+func formatMillis(millis int64) string {
+	return strconv.FormatInt(millis, 10)
+}
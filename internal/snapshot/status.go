@@ -0,0 +1,110 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/jobs"
+	"github.com/openshift/elasticsearch-operator/internal/manifests/pod"
+)
+
+// jobRun is the outcome of a finished snapshot job
+type jobRun struct {
+	jobs.Run
+	result *Result
+}
+
+func (run jobRun) errorMessage() string {
+	if run.result != nil && run.result.Error != "" {
+		return run.result.Error
+	}
+	if run.Reason != "" {
+		return fmt.Sprintf("snapshot job failed with exit code %d: %s", run.ExitCode, run.Reason)
+	}
+	return fmt.Sprintf("snapshot job failed with exit code %d", run.ExitCode)
+}
+
+// updateJobStatus sets the last successful snapshot and the error of the last job from the
+// results of the finished snapshot jobs. The last successful snapshot is kept from the
+// status as the job history only holds the last runs.
+func (sr *SnapshotRequest) updateJobStatus(status *apis.SnapshotStatus) error {
+	pods, err := pod.List(context.TODO(), sr.client, sr.cluster.Namespace, newLabels(sr.cluster.Name))
+	if err != nil {
+		return err
+	}
+	runs := finishedJobRuns(pods)
+	if len(runs) == 0 {
+		return nil
+	}
+
+	// apply the oldest run first to end up with the newest successful snapshot
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.Failed() || run.result == nil || run.result.Snapshot == "" {
+			continue
+		}
+		completed := run.Finished
+		if run.result.EndTimeInMillis > 0 {
+			// the status only keeps seconds
+			completed = metav1.NewTime(time.UnixMilli(run.result.EndTimeInMillis).Truncate(time.Second))
+		}
+		if status.LastSuccessfulSnapshotTime != nil && completed.Before(status.LastSuccessfulSnapshotTime) {
+			continue
+		}
+		status.LastSuccessfulSnapshot = run.result.Snapshot
+		status.LastSuccessfulSnapshotTime = &completed
+	}
+
+	status.Message = ""
+	if runs[0].Failed() {
+		status.Message = runs[0].errorMessage()
+	}
+	return nil
+}
+
+// persistStatus writes the snapshot status if it changed
+func (sr *SnapshotRequest) persistStatus(previous *apis.SnapshotStatus) error {
+	cluster := sr.cluster
+	status := cluster.Status.Snapshot
+	if equality.Semantic.DeepEqual(previous, status) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := sr.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.Snapshot = status
+		return sr.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update snapshot status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
+
+// finishedJobRuns returns the runs of the terminated job pods, newest first
+func finishedJobRuns(pods []corev1.Pod) []jobRun {
+	var runs []jobRun
+	for _, run := range jobs.FinishedRuns(pods, containerName) {
+		runs = append(runs, jobRun{
+			Run:    run,
+			result: ParseResult(run.Message),
+		})
+	}
+	return runs
+}
@@ -1,5 +1,14 @@
 package elasticsearch
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
 func NewIndexTemplate(pattern string, aliases []string, shards, replicas int32) *IndexTemplate {
 	template := IndexTemplate{
 		Template: pattern,
@@ -62,6 +71,56 @@ type GetIndexTemplate struct {
 	Mappings      map[string]IndexMappingSettings `json:"mappings,omitempty"`
 }
 
+// ComposableIndexTemplate is the body of an index template of the composable index
+// template API used by Elasticsearch 7 and OpenSearch
+type ComposableIndexTemplate struct {
+	IndexPatterns []string           `json:"index_patterns"`
+	Priority      int32              `json:"priority,omitempty"`
+	Template      ComposableTemplate `json:"template"`
+}
+
+type ComposableTemplate struct {
+	Settings *IndexSettings        `json:"settings,omitempty"`
+	Aliases  map[string]IndexAlias `json:"aliases,omitempty"`
+}
+
+// NewComposableIndexTemplate returns the composable form of a legacy index template
+func NewComposableIndexTemplate(template *IndexTemplate, priority int32) *ComposableIndexTemplate {
+	settings := template.Settings
+	return &ComposableIndexTemplate{
+		IndexPatterns: []string{template.Template},
+		Priority:      priority,
+		Template: ComposableTemplate{
+			Settings: &settings,
+			Aliases:  template.Aliases,
+		},
+	}
+}
+
+// GetComposableIndexTemplate is a composable index template as returned by the cluster.
+// The mappings are kept as is to be put back unchanged.
+type GetComposableIndexTemplate struct {
+	IndexPatterns []string              `json:"index_patterns"`
+	Priority      int32                 `json:"priority,omitempty"`
+	Template      GetComposableTemplate `json:"template"`
+	ComposedOf    []string              `json:"composed_of,omitempty"`
+}
+
+type GetComposableTemplate struct {
+	Settings GetIndexTemplateSettings `json:"settings,omitempty"`
+	Aliases  map[string]IndexAlias    `json:"aliases,omitempty"`
+	Mappings json.RawMessage          `json:"mappings,omitempty"`
+}
+
+type ComposableIndexTemplatesResponse struct {
+	IndexTemplates []NamedComposableIndexTemplate `json:"index_templates"`
+}
+
+type NamedComposableIndexTemplate struct {
+	Name          string                     `json:"name"`
+	IndexTemplate GetComposableIndexTemplate `json:"index_template"`
+}
+
 type GetIndexTemplateSettings struct {
 	Index IndexTemplateSettings `json:"index,omitempty"`
 }
@@ -72,6 +131,7 @@ type IndexTemplateSettings struct {
 	RefreshInterval  string                 `json:"refresh_interval,omitempty"`
 	NumberOfShards   string                 `json:"number_of_shards,omitempty"`
 	NumberOfReplicas string                 `json:"number_of_replicas,omitempty"`
+	Routing          IndexRoutingSettings   `json:"routing,omitempty"`
 }
 
 type UnassignedIndexSetting struct {
@@ -104,6 +164,16 @@ type IndexingSettings struct {
 	Blocks           *IndexBlocksSettings  `json:"blocks,omitempty"`
 	Mapper           *IndexMapperSettings  `json:"mapper,omitempty"`
 	Mapping          *IndexMappingSettings `json:"mapping,omitempty"`
+	Routing          *IndexRoutingSettings `json:"routing,omitempty"`
+}
+
+type IndexRoutingSettings struct {
+	Allocation IndexRoutingAllocationSettings `json:"allocation,omitempty"`
+}
+
+// IndexRoutingAllocationSettings holds the node attributes an index is allocated to
+type IndexRoutingAllocationSettings struct {
+	Require map[string]string `json:"require,omitempty"`
 }
 
 type IndexBlocksSettings struct {
@@ -144,8 +214,9 @@ type AliasAction struct {
 }
 
 type AddAliasAction struct {
-	Index string `json:"index"`
-	Alias string `json:"alias"`
+	Index        string `json:"index"`
+	Alias        string `json:"alias"`
+	IsWriteIndex *bool  `json:"is_write_index,omitempty"`
 }
 
 type RemoveAliasAction struct {
@@ -165,6 +236,51 @@ type CatIndicesResponse struct {
 	DocsDeleted      string `json:"docs.deleted,omitempty"`
 	StoreSize        string `json:"store.size,omitempty"`
 	PrimaryStoreSize string `json:"pri.store.size,omitempty"`
+	CreationDate     string `json:"creation.date,omitempty"`
+}
+
+type CatAllocationResponses []CatAllocationResponse
+
+type CatAllocationResponse struct {
+	Node      string `json:"node,omitempty"`
+	DiskTotal string `json:"disk.total,omitempty"`
+}
+
+type CatShardsResponses []CatShardsResponse
+
+type CatShardsResponse struct {
+	Index  string `json:"index,omitempty"`
+	Shard  string `json:"shard,omitempty"`
+	PriRep string `json:"prirep,omitempty"`
+	State  string `json:"state,omitempty"`
+	Node   string `json:"node,omitempty"`
+}
+
+// AliasesResponse is the response of the get alias API keyed by index name
+type AliasesResponse map[string]IndexAliases
+
+type IndexAliases struct {
+	Aliases map[string]IndexAlias `json:"aliases"`
+}
+
+type RolloverRequest struct {
+	Conditions RolloverConditions `json:"conditions"`
+}
+
+type RolloverConditions struct {
+	MaxAge  string `json:"max_age,omitempty"`
+	MaxDocs int64  `json:"max_docs,omitempty"`
+	MaxSize string `json:"max_size,omitempty"`
+}
+
+type RolloverResponse struct {
+	Acknowledged       bool            `json:"acknowledged"`
+	ShardsAcknowledged bool            `json:"shards_acknowledged"`
+	OldIndex           string          `json:"old_index"`
+	NewIndex           string          `json:"new_index"`
+	RolledOver         bool            `json:"rolled_over"`
+	DryRun             bool            `json:"dry_run"`
+	Conditions         map[string]bool `json:"conditions"`
 }
 
 type MasterNodeAndNodeStateResponse struct {
@@ -184,6 +300,187 @@ type NodeStateResponse struct {
 	Attributes       map[string]string `json:"attributes,omitempty"`
 }
 
+// ClusterHealthResponse is the response of the cluster health API
+type ClusterHealthResponse struct {
+	ClusterName          string `json:"cluster_name,omitempty"`
+	Status               string `json:"status,omitempty"`
+	TimedOut             bool   `json:"timed_out"`
+	NumberOfNodes        int32  `json:"number_of_nodes"`
+	NumberOfDataNodes    int32  `json:"number_of_data_nodes"`
+	ActivePrimaryShards  int32  `json:"active_primary_shards"`
+	ActiveShards         int32  `json:"active_shards"`
+	RelocatingShards     int32  `json:"relocating_shards"`
+	InitializingShards   int32  `json:"initializing_shards"`
+	UnassignedShards     int32  `json:"unassigned_shards"`
+	NumberOfPendingTasks int32  `json:"number_of_pending_tasks"`
+}
+
+// ClusterSettingsResponse is the response of the get cluster settings API. The defaults
+// are only returned with include_defaults=true.
+type ClusterSettingsResponse struct {
+	Persistent ClusterSettings `json:"persistent,omitempty"`
+	Transient  ClusterSettings `json:"transient,omitempty"`
+	Defaults   ClusterSettings `json:"defaults,omitempty"`
+}
+
+// Setting returns the value of the setting with the transient settings taking precedence
+// over the persistent ones and the persistent ones over the defaults
+func (r *ClusterSettingsResponse) Setting(key string) (string, bool) {
+	for _, settings := range []ClusterSettings{r.Transient, r.Persistent, r.Defaults} {
+		if value, ok := settings.Get(key); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// ClusterSettings are cluster settings keyed by their flat name (e.g. cluster.routing.allocation.enable)
+type ClusterSettings map[string]interface{}
+
+// UnmarshalJSON flattens the nested settings into their flat names
+func (s *ClusterSettings) UnmarshalJSON(data []byte) error {
+	nested := map[string]interface{}{}
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return err
+	}
+	*s = ClusterSettings{}
+	s.flatten("", nested)
+	return nil
+}
+
+func (s ClusterSettings) flatten(prefix string, nested map[string]interface{}) {
+	for key, value := range nested {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			s.flatten(key, object)
+			continue
+		}
+		s[key] = value
+	}
+}
+
+// Get returns the value of the setting as a string. Lists are joined by commas.
+func (s ClusterSettings) Get(key string) (string, bool) {
+	value, ok := s[key]
+	if !ok || value == nil {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return strings.Join(values, ","), true
+	}
+	return fmt.Sprint(value), true
+}
+
+// DiskWatermark is a disk watermark of the cluster either as a percentage of the disk used
+// or as an absolute amount of free disk space
+type DiskWatermark struct {
+	Percentage *float64
+	Quantity   *resource.Quantity
+}
+
+// DiskWatermarks are the low, high and flood stage disk watermarks of the cluster
+type DiskWatermarks struct {
+	Low        DiskWatermark
+	High       DiskWatermark
+	FloodStage DiskWatermark
+}
+
+// byteSizeUnits maps the byte size units of elasticsearch to the binary quantity suffixes
+var byteSizeUnits = []struct {
+	unit   string
+	suffix string
+}{
+	{unit: "kb", suffix: "Ki"},
+	{unit: "mb", suffix: "Mi"},
+	{unit: "gb", suffix: "Gi"},
+	{unit: "tb", suffix: "Ti"},
+	{unit: "pb", suffix: "Pi"},
+	{unit: "b", suffix: ""},
+}
+
+// ParseDiskWatermark parses a disk watermark setting given as a percentage (e.g. 85%),
+// a ratio (e.g. 0.85) or a byte size (e.g. 500mb)
+func ParseDiskWatermark(value string) (DiskWatermark, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if strings.HasSuffix(value, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return DiskWatermark{}, fmt.Errorf("invalid disk watermark percentage %q: %w", value, err)
+		}
+		return DiskWatermark{Percentage: &percentage}, nil
+	}
+
+	if ratio, err := strconv.ParseFloat(value, 64); err == nil {
+		percentage := ratio * 100
+		return DiskWatermark{Percentage: &percentage}, nil
+	}
+
+	for _, size := range byteSizeUnits {
+		if !strings.HasSuffix(value, size.unit) {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(strings.TrimSuffix(value, size.unit) + size.suffix)
+		if err != nil {
+			return DiskWatermark{}, fmt.Errorf("invalid disk watermark byte size %q: %w", value, err)
+		}
+		return DiskWatermark{Quantity: &quantity}, nil
+	}
+	return DiskWatermark{}, fmt.Errorf("invalid disk watermark %q", value)
+}
+
+// NodesStatsResponse is the response of the nodes stats API keyed by node ID
+type NodesStatsResponse struct {
+	Nodes map[string]NodeStats `json:"nodes,omitempty"`
+}
+
+type NodeStats struct {
+	Name string      `json:"name,omitempty"`
+	FS   NodeFSStats `json:"fs,omitempty"`
+}
+
+type NodeFSStats struct {
+	Total NodeFSTotalStats `json:"total,omitempty"`
+}
+
+type NodeFSTotalStats struct {
+	TotalInBytes     int64 `json:"total_in_bytes"`
+	FreeInBytes      int64 `json:"free_in_bytes"`
+	AvailableInBytes int64 `json:"available_in_bytes"`
+}
+
+// IndicesSettingsResponse is the response of the get index settings API keyed by index name
+type IndicesSettingsResponse map[string]Index
+
+// AcknowledgedResponse is the response of the APIs acknowledging a change
+type AcknowledgedResponse struct {
+	Acknowledged bool `json:"acknowledged"`
+}
+
+// FlushResponse is the response of the flush API
+type FlushResponse struct {
+	Shards ShardsResult `json:"_shards"`
+}
+
+type ShardsResult struct {
+	Total      int32 `json:"total"`
+	Successful int32 `json:"successful"`
+	Failed     int32 `json:"failed"`
+}
+
 type StatsNodesResponse struct {
 	Nodes StatsNode `json:"nodes,omitempty"`
 }
@@ -192,3 +489,89 @@ type StatsNode struct {
 	Versions []string       `json:"versions,omitempty"`
 	Count    map[string]int `json:"count,omitempty"`
 }
+
+// SnapshotRepository is the type and settings of a registered snapshot repository
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// SnapshotRepositoriesResponse is the response of the get repository API keyed by repository name
+type SnapshotRepositoriesResponse map[string]SnapshotRepository
+
+type CreateSnapshotRequest struct {
+	Indices            string `json:"indices,omitempty"`
+	IgnoreUnavailable  bool   `json:"ignore_unavailable"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+}
+
+type SnapshotsResponse struct {
+	Snapshots []SnapshotInfo `json:"snapshots"`
+}
+
+type SnapshotInfo struct {
+	Snapshot          string   `json:"snapshot"`
+	UUID              string   `json:"uuid,omitempty"`
+	State             string   `json:"state"`
+	Indices           []string `json:"indices,omitempty"`
+	StartTimeInMillis int64    `json:"start_time_in_millis,omitempty"`
+	EndTimeInMillis   int64    `json:"end_time_in_millis,omitempty"`
+}
+
+type RestoreSnapshotRequest struct {
+	Indices            string `json:"indices,omitempty"`
+	IgnoreUnavailable  bool   `json:"ignore_unavailable"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	IncludeAliases     bool   `json:"include_aliases"`
+	RenamePattern      string `json:"rename_pattern,omitempty"`
+	RenameReplacement  string `json:"rename_replacement,omitempty"`
+}
+
+type CatRecoveryResponses []CatRecoveryResponse
+
+type CatRecoveryResponse struct {
+	Index      string `json:"index,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Stage      string `json:"stage,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Snapshot   string `json:"snapshot,omitempty"`
+}
+
+// RemoteClusterSettings are the persistent cluster settings of a remote cluster. A nil
+// seed list and flag remove the remote cluster.
+type RemoteClusterSettings struct {
+	Seeds           []string `json:"seeds"`
+	SkipUnavailable *bool    `json:"skip_unavailable"`
+}
+
+// RemoteInfoResponse is the response of the remote cluster info API keyed by remote cluster name
+type RemoteInfoResponse map[string]RemoteInfo
+
+type RemoteInfo struct {
+	Seeds             []string `json:"seeds,omitempty"`
+	Connected         bool     `json:"connected"`
+	NumNodesConnected int32    `json:"num_nodes_connected"`
+	SkipUnavailable   bool     `json:"skip_unavailable"`
+}
+
+// SecurityConfigResponse is the response of the security config API of the security plugin
+type SecurityConfigResponse struct {
+	Config SecurityConfig `json:"config"`
+}
+
+// SecurityConfig is the config of the security plugin. Its dynamic settings are kept as
+// returned to update them without dropping the ones unknown to the operator.
+type SecurityConfig struct {
+	Dynamic map[string]interface{} `json:"dynamic"`
+}
+
+// InternalUsersResponse is the response of the internal users API keyed by user name
+type InternalUsersResponse map[string]InternalUser
+
+// InternalUser is a user of the internal users database of the security plugin. The
+// password is never returned by the cluster.
+type InternalUser struct {
+	Password     string   `json:"password,omitempty"`
+	BackendRoles []string `json:"backend_roles"`
+	Description  string   `json:"description,omitempty"`
+}
@@ -0,0 +1,175 @@
+// Package cron parses schedules in the cron format of the minute, hour, day of month, month
+// and day of week fields (e.g. 0 2 * * 6) and computes their activations in UTC.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// maxSearch bounds the search for the next activation, which covers schedules only active on
+// the 29th of February
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+var (
+	monthNames   = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	{name: "day of week", min: 0, max: 7, names: weekdayNames},
+}
+
+// Schedule is a parsed cron schedule
+type Schedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// days and weekdays are restricted by a value other than *. If both are, a day
+	// matching either of them is active.
+	anyDay, anyWeekday bool
+}
+
+// Parse returns the schedule of the cron expression
+func Parse(expression string) (*Schedule, error) {
+	parts := strings.Fields(expression)
+	if len(parts) != len(fields) {
+		return nil, kverrors.New("a cron schedule requires the fields minute, hour, day of month, month and day of week",
+			"schedule", expression)
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		value, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, kverrors.Wrap(err, "invalid cron schedule", "schedule", expression)
+		}
+		bits[i] = value
+	}
+
+	// Sunday is both 0 and 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Schedule{
+		minutes:    bits[0],
+		hours:      bits[1],
+		days:       bits[2],
+		months:     bits[3],
+		weekdays:   bits[4],
+		anyDay:     strings.HasPrefix(parts[2], "*"),
+		anyWeekday: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// Next returns the first activation of the schedule after the time, or the zero time if the
+// schedule is never active
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	end := t.Add(maxSearch)
+
+	for t.Before(end) {
+		if !s.isActiveDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) isActiveDay(t time.Time) bool {
+	if s.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// parseField returns the bits of the values of a comma separated list of values, ranges
+// (e.g. 1-5) and steps (e.g. */15 or 0-30/10)
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		rangeValue, stepValue, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepValue); err != nil || step < 1 {
+				return 0, kverrors.New("invalid step", "field", f.name, "value", item)
+			}
+		}
+
+		start, end := f.min, f.max
+		if rangeValue != "*" {
+			startValue, endValue, isRange := strings.Cut(rangeValue, "-")
+
+			var err error
+			if start, err = parseValue(startValue, f); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(endValue, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = f.max
+			}
+			if start > end {
+				return 0, kverrors.New("invalid range", "field", f.name, "value", item)
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(value, name) {
+			return i, nil
+		}
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i < f.min || i > f.max {
+		return 0, kverrors.New("value out of range", "field", f.name, "value", value, "min", f.min, "max", f.max)
+	}
+	return i, nil
+}
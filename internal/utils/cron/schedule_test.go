@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2022, time.August, 10, 14, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		exp      time.Time
+	}{
+		{schedule: "* * * * *", exp: time.Date(2022, time.August, 10, 14, 31, 0, 0, time.UTC)},
+		{schedule: "*/15 * * * *", exp: time.Date(2022, time.August, 10, 14, 45, 0, 0, time.UTC)},
+		{schedule: "0 2 * * *", exp: time.Date(2022, time.August, 11, 2, 0, 0, 0, time.UTC)},
+		{schedule: "0 2 * * 6", exp: time.Date(2022, time.August, 13, 2, 0, 0, 0, time.UTC)},
+		{schedule: "0 2 * * sun", exp: time.Date(2022, time.August, 14, 2, 0, 0, 0, time.UTC)},
+		{schedule: "0 2 * * 7", exp: time.Date(2022, time.August, 14, 2, 0, 0, 0, time.UTC)},
+		{schedule: "30 22 * * 1-5", exp: time.Date(2022, time.August, 10, 22, 30, 0, 0, time.UTC)},
+		{schedule: "0 0 1 jan *", exp: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 29 2 *", exp: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// either the day of month or the day of week
+		{schedule: "0 0 1 * 5", exp: time.Date(2022, time.August, 12, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0,12 15-20/5 * *", exp: time.Date(2022, time.August, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		schedule, err := Parse(test.schedule)
+		if err != nil {
+			t.Fatalf("%q: failed to parse: %v", test.schedule, err)
+		}
+		if got := schedule.Next(now); !got.Equal(test.exp) {
+			t.Errorf("%q: Exp. next activation %s but got %s", test.schedule, test.exp, got)
+		}
+	}
+}
+
+func TestScheduleNeverActive(t *testing.T) {
+	schedule, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Exp. no activation on the 31st of February but got %s", got)
+	}
+}
+
+func TestParseInvalidSchedules(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@daily",
+	} {
+		if _, err := Parse(schedule); err == nil {
+			t.Errorf("%q: Exp. an error", schedule)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
+)
+
+// +kubebuilder:webhook:path=/mutate-logging-openshift-io-v1-elasticsearch,mutating=true,failurePolicy=fail,sideEffects=None,groups=logging.openshift.io,resources=elasticsearches,verbs=create;update,versions=v1,name=melasticsearch.logging.openshift.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-logging-openshift-io-v1-elasticsearch,mutating=false,failurePolicy=fail,sideEffects=None,groups=logging.openshift.io,resources=elasticsearches,verbs=create;update,versions=v1,name=velasticsearch.logging.openshift.io,admissionReviewVersions=v1
+
+// ElasticsearchWebhook defaults and validates the Elasticsearch resources before they are
+// persisted, so that invalid specs are rejected instead of being reported in the status
+type ElasticsearchWebhook struct{}
+
+// Default sets the defaults of an Elasticsearch resource
+func (w *ElasticsearchWebhook) Default(_ context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*loggingv1.Elasticsearch)
+	if !ok {
+		return kverrors.New("expected an Elasticsearch resource", "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	elasticsearch.SetDefaults(cluster)
+	return nil
+}
+
+// ValidateCreate validates a new Elasticsearch resource
+func (w *ElasticsearchWebhook) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*loggingv1.Elasticsearch)
+	if !ok {
+		return kverrors.New("expected an Elasticsearch resource", "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+
+	errs := validateSpec(cluster)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(loggingv1.GroupVersion.WithKind("Elasticsearch").GroupKind(), cluster.Name, errs)
+}
+
+// ValidateUpdate validates the changes to an Elasticsearch resource
+func (w *ElasticsearchWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	old, ok := oldObj.(*loggingv1.Elasticsearch)
+	if !ok {
+		return kverrors.New("expected an Elasticsearch resource", "kind", oldObj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	cluster, ok := newObj.(*loggingv1.Elasticsearch)
+	if !ok {
+		return kverrors.New("expected an Elasticsearch resource", "kind", newObj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	// the finalizers are removed from clusters being deleted whatever their spec
+	if cluster.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// only the changes are rejected, so that a resource persisted before its spec became
+	// invalid can still be updated
+	errs := changedErrors(validateSpec(old), validateSpec(cluster))
+	errs = append(errs, elasticsearch.ValidateSpecUpdate(old, cluster)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(loggingv1.GroupVersion.WithKind("Elasticsearch").GroupKind(), cluster.Name, errs)
+}
+
+func validateSpec(cluster *loggingv1.Elasticsearch) field.ErrorList {
+	errs := elasticsearch.ValidateSpec(cluster)
+	return append(errs, indexmanagement.ValidateSpec(cluster)...)
+}
+
+// changedErrors returns the errors of the updated spec which the old spec did not have
+func changedErrors(old, updated field.ErrorList) field.ErrorList {
+	existing := map[string]bool{}
+	for _, err := range old {
+		existing[err.Error()] = true
+	}
+	errs := field.ErrorList{}
+	for _, err := range updated {
+		if !existing[err.Error()] {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ValidateDelete accepts the deletion of any Elasticsearch resource
+func (w *ElasticsearchWebhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
@@ -0,0 +1,73 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// +kubebuilder:webhook:path=/mutate-logging-openshift-io-v1-kibana,mutating=true,failurePolicy=fail,sideEffects=None,groups=logging.openshift.io,resources=kibanas,verbs=create;update,versions=v1,name=mkibana.logging.openshift.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-logging-openshift-io-v1-kibana,mutating=false,failurePolicy=fail,sideEffects=None,groups=logging.openshift.io,resources=kibanas,verbs=create;update,versions=v1,name=vkibana.logging.openshift.io,admissionReviewVersions=v1
+
+// KibanaWebhook defaults and validates the Kibana resources before they are persisted
+type KibanaWebhook struct{}
+
+// Default sets the defaults of a Kibana resource
+func (w *KibanaWebhook) Default(_ context.Context, obj runtime.Object) error {
+	kibana, ok := obj.(*loggingv1.Kibana)
+	if !ok {
+		return kverrors.New("expected a Kibana resource", "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	if kibana.Spec.ManagementState == "" {
+		kibana.Spec.ManagementState = loggingv1.ManagementStateManaged
+	}
+	return nil
+}
+
+// ValidateCreate validates a new Kibana resource
+func (w *KibanaWebhook) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	kibana, ok := obj.(*loggingv1.Kibana)
+	if !ok {
+		return kverrors.New("expected a Kibana resource", "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	return validateKibana(kibana)
+}
+
+// ValidateUpdate validates the changes to a Kibana resource
+func (w *KibanaWebhook) ValidateUpdate(_ context.Context, _, newObj runtime.Object) error {
+	kibana, ok := newObj.(*loggingv1.Kibana)
+	if !ok {
+		return kverrors.New("expected a Kibana resource", "kind", newObj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	if kibana.DeletionTimestamp != nil {
+		return nil
+	}
+	return validateKibana(kibana)
+}
+
+// ValidateDelete accepts the deletion of any Kibana resource
+func (w *KibanaWebhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func validateKibana(kibana *loggingv1.Kibana) error {
+	errs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if kibana.Spec.Replicas < 0 {
+		errs = append(errs, field.Invalid(specPath.Child("replicas"), kibana.Spec.Replicas, "must be greater than or equal to 0"))
+	}
+	if ref := kibana.Spec.ElasticsearchRef; ref != nil && ref.Name == "" {
+		errs = append(errs, field.Required(specPath.Child("elasticsearchRef", "name"), "the name of the Elasticsearch cluster is required"))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(loggingv1.GroupVersion.WithKind("Kibana").GroupKind(), kibana.Name, errs)
+}
@@ -0,0 +1,34 @@
+// Package webhooks provides the admission webhooks of the Elasticsearch and Kibana
+// resources, which the manager serves on its webhook server
+package webhooks
+
+import (
+	"github.com/ViaQ/logerr/v2/kverrors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// SetupWithManager registers the defaulting and validating webhooks with the manager
+func SetupWithManager(mgr ctrl.Manager) error {
+	esWebhook := &ElasticsearchWebhook{}
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&loggingv1.Elasticsearch{}).
+		WithDefaulter(esWebhook).
+		WithValidator(esWebhook).
+		Complete()
+	if err != nil {
+		return kverrors.Wrap(err, "failed to register the Elasticsearch webhooks")
+	}
+
+	kibanaWebhook := &KibanaWebhook{}
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&loggingv1.Kibana{}).
+		WithDefaulter(kibanaWebhook).
+		WithValidator(kibanaWebhook).
+		Complete()
+	if err != nil {
+		return kverrors.Wrap(err, "failed to register the Kibana webhooks")
+	}
+	return nil
+}
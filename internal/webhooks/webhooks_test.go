@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+func TestElasticsearchWebhookRejectsInvalidSpecs(t *testing.T) {
+	cluster := &loggingv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch"},
+		Spec: loggingv1.ElasticsearchSpec{
+			Nodes: []loggingv1.ElasticsearchNode{
+				{
+					Roles:     []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleData, loggingv1.ElasticsearchRoleMaster},
+					NodeCount: 1,
+					GenUUID:   pointer.String("abc"),
+				},
+			},
+		},
+	}
+
+	w := &ElasticsearchWebhook{}
+	if err := w.Default(context.TODO(), cluster); err != nil {
+		t.Fatalf("Exp. no error when defaulting but got %v", err)
+	}
+	if err := w.ValidateCreate(context.TODO(), cluster); err != nil {
+		t.Errorf("Exp. the defaulted cluster to be valid but got %v", err)
+	}
+
+	invalid := cluster.DeepCopy()
+	invalid.Spec.RedundancyPolicy = loggingv1.FullRedundancy
+	invalid.Spec.IndexManagement = &loggingv1.IndexManagementSpec{
+		Mappings: []loggingv1.IndexManagementPolicyMappingSpec{
+			{Name: "app", PolicyRef: "undefined"},
+		},
+	}
+	err := w.ValidateUpdate(context.TODO(), cluster, invalid)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("Exp. an invalid error but got %v", err)
+	}
+	if causes := err.(*apierrors.StatusError).ErrStatus.Details.Causes; len(causes) != 2 {
+		t.Errorf("Exp. the redundancy policy and the mapping to be rejected but got %v", causes)
+	}
+}
+
+func TestElasticsearchWebhookAcceptsUnchangedInvalidSpecs(t *testing.T) {
+	old := &loggingv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch"},
+		Spec: loggingv1.ElasticsearchSpec{
+			ManagementState:  loggingv1.ManagementStateManaged,
+			RedundancyPolicy: loggingv1.FullRedundancy,
+			Nodes: []loggingv1.ElasticsearchNode{
+				{
+					Roles:     []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleData, loggingv1.ElasticsearchRoleMaster},
+					NodeCount: 1,
+					GenUUID:   pointer.String("abc"),
+				},
+			},
+		},
+	}
+
+	w := &ElasticsearchWebhook{}
+	if err := w.ValidateCreate(context.TODO(), old); !apierrors.IsInvalid(err) {
+		t.Fatalf("Exp. the redundancy policy to be rejected on create but got %v", err)
+	}
+
+	updated := old.DeepCopy()
+	updated.Labels = map[string]string{"team": "logging"}
+	if err := w.ValidateUpdate(context.TODO(), old, updated); err != nil {
+		t.Errorf("Exp. the update leaving the invalid fields unchanged to be accepted but got %v", err)
+	}
+
+	updated.Spec.Nodes[0].Roles = append(updated.Spec.Nodes[0].Roles, loggingv1.ElasticsearchRoleClient, loggingv1.ElasticsearchRoleCoordinating)
+	err := w.ValidateUpdate(context.TODO(), old, updated)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("Exp. an invalid error but got %v", err)
+	}
+	if causes := err.(*apierrors.StatusError).ErrStatus.Details.Causes; len(causes) != 1 {
+		t.Errorf("Exp. only the changed roles to be rejected but got %v", causes)
+	}
+}
+
+func TestKibanaWebhookRejectsInvalidSpecs(t *testing.T) {
+	kibana := &loggingv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Name: "kibana"},
+		Spec: loggingv1.KibanaSpec{
+			Replicas:         -1,
+			ElasticsearchRef: &loggingv1.ElasticsearchReference{},
+		},
+	}
+
+	w := &KibanaWebhook{}
+	if err := w.Default(context.TODO(), kibana); err != nil {
+		t.Fatalf("Exp. no error when defaulting but got %v", err)
+	}
+	if kibana.Spec.ManagementState != loggingv1.ManagementStateManaged {
+		t.Errorf("Exp. the kibana to be managed by default but was %q", kibana.Spec.ManagementState)
+	}
+
+	err := w.ValidateCreate(context.TODO(), kibana)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("Exp. an invalid error but got %v", err)
+	}
+	if causes := err.(*apierrors.StatusError).ErrStatus.Details.Causes; len(causes) != 2 {
+		t.Errorf("Exp. the replicas and the elasticsearchRef to be rejected but got %v", causes)
+	}
+}
@@ -10,10 +10,14 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -26,7 +30,10 @@ import (
 
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	controllers "github.com/openshift/elasticsearch-operator/controllers/logging"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/runner"
 	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	"github.com/openshift/elasticsearch-operator/internal/snapshot"
+	"github.com/openshift/elasticsearch-operator/internal/webhooks"
 	"github.com/openshift/elasticsearch-operator/version"
 
 	"github.com/ViaQ/logerr/v2/log"
@@ -51,6 +58,15 @@ func init() {
 }
 
 func main() {
+	// The index management cronjobs run the operator image with the runner subcommand
+	if len(os.Args) > 1 && os.Args[1] == runner.Command {
+		os.Exit(runner.Main(os.Args[2:]))
+	}
+	// The snapshot cronjob runs the operator image with the snapshot subcommand
+	if len(os.Args) > 1 && os.Args[1] == snapshot.Command {
+		os.Exit(snapshot.Main(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
@@ -91,6 +107,7 @@ func main() {
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "d471c3b1.openshift.io",
 		Logger:                 ll,
+		NewCache:               newCache(),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -113,6 +130,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Kibana")
 		os.Exit(1)
 	}
+	if err = (&controllers.ElasticsearchRestoreReconciler{
+		Client: mgr.GetClient(),
+		Log:    logger.WithName("controllers").WithName("ElasticsearchRestore"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchRestore")
+		os.Exit(1)
+	}
 	if err = (&controllers.SecretReconciler{
 		Client: mgr.GetClient(),
 		Log:    logger.WithName("controllers").WithName("Secret"),
@@ -121,6 +146,21 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Secret")
 		os.Exit(1)
 	}
+	if err = (&controllers.ZoneReconciler{
+		Client: mgr.GetClient(),
+		Log:    logger.WithName("controllers").WithName("Zone"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Zone")
+		os.Exit(1)
+	}
+	// The webhooks are disabled when running the operator outside of the cluster
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooks.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhooks")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -169,6 +209,26 @@ func registerProfiler(m ctrl.Manager) error {
 	return nil
 }
 
+// newCache returns a cache holding only the pods of the components managed by the operator,
+// instead of all the pods watched by the zone controller
+func newCache() cache.NewCacheFunc {
+	components, err := labels.NewRequirement("component", selection.In, []string{
+		"elasticsearch",
+		"kibana",
+		"indexManagement",
+		"snapshot",
+	})
+	utilruntime.Must(err)
+
+	return cache.BuilderWithOptions(cache.Options{
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.Pod{}: {
+				Label: labels.NewSelector().Add(*components),
+			},
+		},
+	})
+}
+
 // getWatchNamespace get the namespace name of the scoped operator
 // - https://sdk.operatorframework.io/docs/building-operators/golang/operator-scope/#configuring-namespace-scoped-operators
 func getWatchNamespace() (string, error) {
@@ -10,7 +10,6 @@ echo "kube rbac proxy: ${IMAGE_KUBE_RBAC_PROXY}"
 echo "elastic6: ${IMAGE_ELASTICSEARCH6}"
 echo "elasticsearch proxy: ${IMAGE_ELASTICSEARCH_PROXY}"
 echo "kibana: ${IMAGE_LOGGING_KIBANA6}"
-echo "curator5: ${IMAGE_LOGGING_CURATOR5}"
 
 echo "In namespace: ${ELASTICSEARCH_OPERATOR_NAMESPACE}"
 
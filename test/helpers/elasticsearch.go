@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/ViaQ/logerr/v2/kverrors"
@@ -105,7 +106,7 @@ func NewFakeElasticsearchClient(cluster, namespace string, k8sClient client.Clie
 }
 
 func NewFakeSendRequestFn(chatter *FakeElasticsearchChatter) esclient.FnEsSendRequest {
-	return func(log logr.Logger, cluster, namespace string, payload *esclient.EsRequest, client client.Client) {
+	return func(ctx context.Context, log logr.Logger, cluster, namespace string, payload *esclient.EsRequest, client client.Client) error {
 		chatter.recordRequest(payload)
 		if val, found := chatter.GetResponse(payload.URI); found {
 			payload.Error = val.Error
@@ -117,5 +118,6 @@ func NewFakeSendRequestFn(chatter *FakeElasticsearchChatter) esclient.FnEsSendRe
 				"uri", payload.URI,
 				"payload", payload)
 		}
+		return payload.Error
 	}
 }
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
-	"strconv"
-	"strings"
 	"testing"
 	"time"
 
@@ -354,22 +352,18 @@ func WaitForIndexReplicas(t *testing.T, kubeclient kubernetes.Interface, namespa
 		}
 
 		// for each index -- check replica count
-		for index, health := range indexHealth {
-			if numberOfReplicas := parseString("settings.index.number_of_replicas", health.(map[string]interface{})); numberOfReplicas != "" {
-				currentReplicas, err := strconv.ParseInt(numberOfReplicas, 10, 32)
-				if err != nil {
-					return false, err
-				}
-
-				if int32(currentReplicas) == replicas {
-					continue
-				}
-
-				t.Logf("Index %s did not have correct replica count (%d/%d)", index, currentReplicas, replicas)
-				return false, nil
-			} else {
+		for index, settings := range indexHealth {
+			if settings.Settings == nil || settings.Settings.Index == nil {
 				return false, nil
 			}
+
+			currentReplicas := settings.Settings.Index.NumberOfReplicas
+			if currentReplicas == replicas {
+				continue
+			}
+
+			t.Logf("Index %s did not have correct replica count (%d/%d)", index, currentReplicas, replicas)
+			return false, nil
 		}
 
 		return true, nil
@@ -396,33 +390,3 @@ func getMockedSecret(clusterName, namespace string) *corev1.Secret {
 		},
 	)
 }
-
-func parseString(path string, interfaceMap map[string]interface{}) string {
-	value := walkInterfaceMap(path, interfaceMap)
-
-	if parsedString, ok := value.(string); ok {
-		return parsedString
-	} else {
-		return ""
-	}
-}
-
-func walkInterfaceMap(path string, interfaceMap map[string]interface{}) interface{} {
-	current := interfaceMap
-	keys := strings.Split(path, ".")
-	keyCount := len(keys)
-
-	for index, key := range keys {
-		if current[key] != nil {
-			if index+1 < keyCount {
-				current = current[key].(map[string]interface{})
-			} else {
-				return current[key]
-			}
-		} else {
-			return nil
-		}
-	}
-
-	return nil
-}